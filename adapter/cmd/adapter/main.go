@@ -29,6 +29,13 @@ import (
 
 func main() {
 
+	if len(os.Args) > 1 && os.Args[1] == adapter.RenderCommand {
+		if err := adapter.RunRenderCommand(os.Args[2:]); err != nil {
+			logger.Fatal("Error rendering the router and enforcer resources. ", err)
+		}
+		return
+	}
+
 	var file string
 	if len(os.Args) > 1 {
 		file = os.Args[1]
@@ -65,6 +65,14 @@ var defaultConfig = &Config{
 			MaxRetryCount:      20,
 			ArtifactsDirectory: "/home/wso2/git-artifacts",
 		},
+		ArtifactsWatcher: artifactsWatcher{
+			Enabled:                  true,
+			DebounceIntervalInMillis: 2000,
+		},
+		GracefulShutdown: gracefulShutdown{
+			TimeoutInSeconds:            30,
+			ReadinessDrainTimeInSeconds: 5,
+		},
 	},
 	Envoy: envoy{
 		ListenerHost:                     "0.0.0.0",
@@ -75,19 +83,25 @@ var defaultConfig = &Config{
 		ClusterTimeoutInSeconds:          20,
 		EnforcerResponseTimeoutInSeconds: 20,
 		UseRemoteAddress:                 false,
+		XffNumTrustedHops:                0,
+		FaultInjection: faultInjection{
+			AllowedEnvironments: []string{},
+		},
 		KeyStore: keystore{
 			KeyPath:  "/home/wso2/security/keystore/mg.key",
 			CertPath: "/home/wso2/security/keystore/mg.pem",
 		},
 		SystemHost: "localhost",
 		Cors: globalCors{
-			Enabled:      true,
-			AllowOrigins: []string{"*"},
-			AllowMethods: []string{"GET", "PUT", "POST", "DELETE", "PATCH", "OPTIONS"},
+			Enabled:            true,
+			AllowOrigins:       []string{"*"},
+			AllowOriginRegexes: []string{},
+			AllowMethods:       []string{"GET", "PUT", "POST", "DELETE", "PATCH", "OPTIONS"},
 			AllowHeaders: []string{"authorization", "Access-Control-Allow-Origin", "Content-Type", "SOAPAction", "apikey",
 				"testKey", "Internal-Key"},
 			AllowCredentials: false,
 			ExposeHeaders:    []string{},
+			MaxAge:           0,
 		},
 		Upstream: envoyUpstream{
 			TLS: upstreamTLS{
@@ -99,6 +113,8 @@ var defaultConfig = &Config{
 				TrustedCertPath:        "/etc/ssl/certs/ca-certificates.crt",
 				VerifyHostName:         true,
 				DisableSslVerification: false,
+				ClientCertPath:         "",
+				ClientKeyPath:          "",
 			},
 			Timeouts: upstreamTimeout{
 				MaxRouteTimeoutInSeconds:  60,
@@ -124,12 +140,23 @@ var defaultConfig = &Config{
 				HpackTableSize:       4096,
 				MaxConcurrentStreams: 2147483647,
 			},
+			Proxy: upstreamProxy{
+				Enabled:              false,
+				Host:                 "",
+				Port:                 3128,
+				Username:             "",
+				Password:             "",
+				NoProxy:              []string{"localhost", "127.0.0.1"},
+				TunnelPortRangeStart: 15000,
+				TunnelPortRangeEnd:   15999,
+			},
 		},
 		Downstream: envoyDownstream{
 			TLS: downstreamTLS{
 				TrustedCertPath: "/etc/ssl/certs/ca-certificates.crt",
 				MTLSAPIsEnabled: false,
 			},
+			PreserveHeaderCase: false,
 		},
 		Connection: connection{
 			Timeouts: connectionTimeouts{
@@ -325,6 +352,12 @@ var defaultConfig = &Config{
 			QueueSizePerPool:      1000,
 			PauseTimeAfterFailure: 5,
 		},
+		DeploymentStatusQueue: deploymentStatusQueue{
+			Directory:      "/home/wso2/deployment-status",
+			InitialBackoff: 5,
+			MaxBackoff:     300,
+			MaxAttempts:    20,
+		},
 	},
 	GlobalAdapter: globalAdapter{
 		Enabled:              false,
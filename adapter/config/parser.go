@@ -40,6 +40,10 @@ var (
 	adapterConfig       *Config
 	defaultVhost        map[string]string
 	e                   error
+	// configPath overrides the location of the configuration file if it is set
+	configPath string
+	// mutexForConfig guards the adapterConfig, which is replaced when the configuration file is reloaded
+	mutexForConfig sync.RWMutex
 )
 
 // DefaultGatewayName represents the name of the default gateway
@@ -74,7 +78,7 @@ const (
 func ReadConfigs() (*Config, error) {
 	onceConfigRead.Do(func() {
 		adapterConfig = defaultConfig
-		_, err := os.Stat(pkgconf.GetMgwHome() + relativeConfigPath)
+		_, err := os.Stat(GetConfigPath())
 		if err != nil {
 			loggerConfig.ErrorC(logging.ErrorDetails{
 				Message:   fmt.Sprintf("Configuration file not found : %s", err.Error()),
@@ -82,7 +86,7 @@ func ReadConfigs() (*Config, error) {
 				ErrorCode: 1000,
 			})
 		}
-		content, readErr := ioutil.ReadFile(pkgconf.GetMgwHome() + relativeConfigPath)
+		content, readErr := ioutil.ReadFile(GetConfigPath())
 		if readErr != nil {
 			loggerConfig.ErrorC(logging.ErrorDetails{
 				Message:   fmt.Sprintf("Error reading configurations : %s", readErr.Error()),
@@ -101,24 +105,43 @@ func ReadConfigs() (*Config, error) {
 			return
 		}
 
-		adapterConfig.resolveDeprecatedProperties()
-		pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(adapterConfig.Adapter)).Elem(), "Adapter", true)
-		pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(adapterConfig.ControlPlane)).Elem(), "ControlPlane", true)
-		pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(adapterConfig.Envoy)).Elem(), "Router", true)
-		pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(adapterConfig.GlobalAdapter)).Elem(), "GlobalAdapter", true)
-		pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(adapterConfig.Enforcer)).Elem(), "Enforcer", false)
-		pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(adapterConfig.Analytics)).Elem(), "Analytics", false)
+		adapterConfig.resolveConfigs()
+		if validationErr := adapterConfig.validate(); validationErr != nil {
+			loggerConfig.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Invalid configurations : %s", validationErr.Error()),
+				Severity:  logging.BLOCKER,
+				ErrorCode: 1003,
+			})
+			e = validationErr
+		}
 	})
+	mutexForConfig.RLock()
+	defer mutexForConfig.RUnlock()
 	return adapterConfig, e
 }
 
+// resolveConfigs resolves the deprecated properties and the environment variable based values of the configuration.
+func (config *Config) resolveConfigs() {
+	config.resolveDeprecatedProperties()
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(config.Adapter)).Elem(), "Adapter", true)
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(config.ControlPlane)).Elem(), "ControlPlane", true)
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(config.Envoy)).Elem(), "Router", true)
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(config.GlobalAdapter)).Elem(), "GlobalAdapter", true)
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(config.Enforcer)).Elem(), "Enforcer", false)
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(config.Analytics)).Elem(), "Analytics", false)
+}
+
 // SetConfig sets the given configuration to the adapter configuration
 func SetConfig(conf *Config) {
+	mutexForConfig.Lock()
+	defer mutexForConfig.Unlock()
 	adapterConfig = conf
 }
 
 // SetDefaultConfig sets the default configuration to the adapter configuration
 func SetDefaultConfig() {
+	mutexForConfig.Lock()
+	defer mutexForConfig.Unlock()
 	adapterConfig = defaultConfig
 }
 
@@ -168,6 +191,20 @@ func GetLogConfigPath() (string, error) {
 	return pkgconf.GetLogConfigPath()
 }
 
+// GetConfigPath returns the file location of the adapter configuration file
+func GetConfigPath() string {
+	if configPath != "" {
+		return configPath
+	}
+	return pkgconf.GetMgwHome() + relativeConfigPath
+}
+
+// SetConfigPath sets the file location of the adapter configuration file, instead of the default location
+// relative to the "MGW_HOME" variable. It needs to be called before reading the configurations.
+func SetConfigPath(path string) {
+	configPath = path
+}
+
 // GetMgwHome reads the MGW_HOME environmental variable and returns the value.
 // This represent the directory where the distribution is located.
 // If the env variable is not present, the directory from which the executable is triggered will be assigned.
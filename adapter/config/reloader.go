@@ -0,0 +1,149 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+
+	toml "github.com/pelletier/go-toml"
+)
+
+// initialDefaultConfig is a copy of the default configuration taken before it is overridden by the configuration
+// file, which is used as the base configuration when the configuration file is reloaded.
+var initialDefaultConfig *Config
+
+// mutexForConfigReload serializes the reloads of the configuration file.
+var mutexForConfigReload sync.Mutex
+
+func init() {
+	var err error
+	if initialDefaultConfig, err = copyConfig(defaultConfig); err != nil {
+		loggerConfig.Errorf("Error while copying the default configuration : %v", err)
+		initialDefaultConfig = defaultConfig
+	}
+}
+
+// ConfigChanges represents the changes of the adapter configuration found when reloading the configuration file.
+type ConfigChanges struct {
+	// Router is true if a configuration used to generate the router resources is changed
+	Router bool
+	// Enforcer is true if a configuration sent to the enforcer is changed
+	Enforcer bool
+	// RestartRequired is the list of changed configurations which are applied only after a restart
+	RestartRequired []string
+}
+
+// HasChanges returns true if any configuration is changed.
+func (changes *ConfigChanges) HasChanges() bool {
+	return changes.Router || changes.Enforcer || len(changes.RestartRequired) > 0
+}
+
+// ReloadConfigs reads the configuration file again and applies the changed configurations which can be updated
+// at runtime (router, enforcer, analytics and tracing configurations). The configuration returned by ReadConfigs
+// is not modified; a new configuration with the changes applied replaces it, so that the configuration read by
+// the other goroutines does not change while it is being used. The rest of the changed configurations are not
+// applied, and they are listed in the returned changes as they require a restart.
+func ReloadConfigs() (*ConfigChanges, error) {
+	mutexForConfigReload.Lock()
+	defer mutexForConfigReload.Unlock()
+
+	content, err := ioutil.ReadFile(GetConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("error reading configurations : %v", err)
+	}
+	newConfig, err := copyConfig(initialDefaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err = toml.Unmarshal(content, newConfig); err != nil {
+		return nil, fmt.Errorf("error parsing the configurations : %v", err)
+	}
+	newConfig.resolveConfigs()
+	if err = newConfig.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configurations : %v", err)
+	}
+
+	currentConfig, _ := ReadConfigs()
+	updatedConfig, err := copyConfig(currentConfig)
+	if err != nil {
+		return nil, err
+	}
+	changes := &ConfigChanges{}
+	if !reflect.DeepEqual(currentConfig.Envoy, newConfig.Envoy) {
+		changes.Router = true
+		updatedConfig.Envoy = newConfig.Envoy
+	}
+	// The enforcer, analytics and tracing configurations are used by both the enforcer and the router.
+	if !reflect.DeepEqual(currentConfig.Enforcer, newConfig.Enforcer) {
+		changes.Router, changes.Enforcer = true, true
+		updatedConfig.Enforcer = newConfig.Enforcer
+	}
+	if !reflect.DeepEqual(currentConfig.Analytics, newConfig.Analytics) {
+		changes.Router, changes.Enforcer = true, true
+		updatedConfig.Analytics = newConfig.Analytics
+	}
+	if !reflect.DeepEqual(currentConfig.Tracing, newConfig.Tracing) {
+		changes.Router, changes.Enforcer = true, true
+		updatedConfig.Tracing = newConfig.Tracing
+	}
+	// The graceful shutdown configuration is read only when the adapter is shutting down.
+	updatedConfig.Adapter.GracefulShutdown = newConfig.Adapter.GracefulShutdown
+	changes.RestartRequired = append(changes.RestartRequired,
+		getChangedFields("adapter", updatedConfig.Adapter, newConfig.Adapter)...)
+	if !reflect.DeepEqual(currentConfig.ControlPlane, newConfig.ControlPlane) {
+		changes.RestartRequired = append(changes.RestartRequired, "controlPlane")
+	}
+	if !reflect.DeepEqual(currentConfig.GlobalAdapter, newConfig.GlobalAdapter) {
+		changes.RestartRequired = append(changes.RestartRequired, "globalAdapter")
+	}
+	SetConfig(updatedConfig)
+	return changes, nil
+}
+
+// getChangedFields returns the names of the fields of the given configuration sections which are not equal,
+// prefixed with the name of the section.
+func getChangedFields(section string, currentSection, newSection interface{}) []string {
+	var changedFields []string
+	currentValue := reflect.ValueOf(currentSection)
+	newValue := reflect.ValueOf(newSection)
+	for i := 0; i < currentValue.NumField(); i++ {
+		if !reflect.DeepEqual(currentValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			fieldName := currentValue.Type().Field(i).Name
+			changedFields = append(changedFields, section+"."+strings.ToLower(fieldName[:1])+fieldName[1:])
+		}
+	}
+	return changedFields
+}
+
+// copyConfig returns a deep copy of the given configuration.
+func copyConfig(config *Config) (*Config, error) {
+	copied := &Config{}
+	content, err := json.Marshal(config)
+	if err == nil {
+		err = json.Unmarshal(content, copied)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while copying the configuration : %v", err)
+	}
+	return copied, nil
+}
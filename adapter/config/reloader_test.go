@@ -0,0 +1,146 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	toml "github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/assert"
+)
+
+const initialConfigContent = `
+[adapter]
+  artifactsDirectory = "/home/wso2/artifacts"
+[router]
+  listenerPort = 9090
+`
+
+const updatedConfigContent = `
+[adapter]
+  artifactsDirectory = "/home/wso2/new-artifacts"
+[adapter.gracefulShutdown]
+  timeoutInSeconds = 45
+[router]
+  listenerPort = 9095
+`
+
+const invalidErrorResponseConfigContent = `
+[router]
+  listenerPort = 9095
+[[router.errorResponses]]
+  flags = ["UF"]
+  statusCode = 700
+  body = "upstream failure"
+`
+
+func TestReloadConfigs(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	writeConfigFile(t, configFile, initialConfigContent)
+	SetConfigPath(configFile)
+	defer SetConfigPath("")
+	// The configuration file is read once before the current configuration is set.
+	_, _ = ReadConfigs()
+	initialConfig, err := copyConfig(initialDefaultConfig)
+	assert.Nil(t, err, "Error while copying the default configuration.")
+	assert.Nil(t, toml.Unmarshal([]byte(initialConfigContent), initialConfig), "Error while parsing the configuration.")
+	initialConfig.resolveConfigs()
+	SetConfig(initialConfig)
+	defer SetDefaultConfig()
+	initialTimeout := initialConfig.Adapter.GracefulShutdown.TimeoutInSeconds
+
+	changes, err := ReloadConfigs()
+	assert.Nil(t, err, "Error while reloading the configuration.")
+	assert.False(t, changes.HasChanges(), "No changes should be found without modifying the configuration file.")
+
+	writeConfigFile(t, configFile, updatedConfigContent)
+	changes, err = ReloadConfigs()
+	assert.Nil(t, err, "Error while reloading the configuration.")
+	assert.True(t, changes.Router, "Router configuration change should be found.")
+	assert.False(t, changes.Enforcer, "Enforcer configuration should not be changed.")
+	assert.Equal(t, []string{"adapter.artifactsDirectory"}, changes.RestartRequired,
+		"Only the adapter configurations which are not applied at runtime should require a restart.")
+
+	reloadedConfig, _ := ReadConfigs()
+	assert.NotSame(t, initialConfig, reloadedConfig, "Reloaded configuration should replace the current configuration.")
+	assert.Equal(t, uint32(9095), reloadedConfig.Envoy.ListenerPort, "Router configuration should be applied.")
+	assert.Equal(t, 45, reloadedConfig.Adapter.GracefulShutdown.TimeoutInSeconds,
+		"Graceful shutdown configuration should be applied.")
+	assert.Equal(t, "/home/wso2/artifacts", reloadedConfig.Adapter.ArtifactsDirectory,
+		"Configurations requiring a restart should not be applied.")
+	assert.Equal(t, uint32(9090), initialConfig.Envoy.ListenerPort,
+		"Configuration read before the reload should not be modified.")
+	assert.Equal(t, initialTimeout, initialConfig.Adapter.GracefulShutdown.TimeoutInSeconds,
+		"Configuration read before the reload should not be modified.")
+
+	writeConfigFile(t, configFile, "[router\n")
+	_, err = ReloadConfigs()
+	assert.NotNil(t, err, "Reloading an invalid configuration file should fail.")
+	currentConfig, _ := ReadConfigs()
+	assert.Same(t, reloadedConfig, currentConfig, "Current configuration should remain if the reload fails.")
+
+	writeConfigFile(t, configFile, invalidErrorResponseConfigContent)
+	_, err = ReloadConfigs()
+	assert.NotNil(t, err, "Reloading a configuration with an invalid error response template should fail.")
+	currentConfig, _ = ReadConfigs()
+	assert.Same(t, reloadedConfig, currentConfig, "Current configuration should remain if the validation fails.")
+}
+
+func TestGetChangedFields(t *testing.T) {
+	type section struct {
+		Name        string
+		Port        uint32
+		Hosts       []string
+		SubSections map[string]string
+	}
+	current := section{Name: "default", Port: 9090, Hosts: []string{"localhost"}}
+
+	tests := []struct {
+		newSection    section
+		changedFields []string
+		message       string
+	}{
+		{
+			newSection: section{Name: "default", Port: 9090, Hosts: []string{"localhost"}},
+			message:    `No changes`,
+		},
+		{
+			newSection:    section{Name: "default", Port: 9095, Hosts: []string{"localhost", "example.com"}},
+			changedFields: []string{"adapter.port", "adapter.hosts"},
+			message:       `Changed fields`,
+		},
+		{
+			newSection: section{Name: "default", Port: 9090, Hosts: []string{"localhost"},
+				SubSections: map[string]string{"key": "value"}},
+			changedFields: []string{"adapter.subSections"},
+			message:       `Changed map field`,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.changedFields, getChangedFields("adapter", current, test.newSection), test.message)
+	}
+}
+
+func writeConfigFile(t *testing.T, path, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Error while writing the configuration file : %v", err)
+	}
+}
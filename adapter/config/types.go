@@ -95,6 +95,10 @@ type adapter struct {
 	ArtifactsDirectory string
 	// SourceControl represents the configuration related to the repository where the api artifacts are stored
 	SourceControl sourceControl
+	// ArtifactsWatcher represents the configuration related to watching the changes of the mounted api artifacts
+	ArtifactsWatcher artifactsWatcher
+	// GracefulShutdown represents the configuration related to shutting down the adapter on a termination signal
+	GracefulShutdown gracefulShutdown
 }
 
 // Envoy Listener Component related configurations.
@@ -114,6 +118,9 @@ type envoy struct {
 	Connection                       connection
 	PayloadPassingToEnforcer         payloadPassingToEnforcer
 	UseRemoteAddress                 bool
+	XffNumTrustedHops                uint32
+	ErrorResponses                   []errorResponse
+	FaultInjection                   faultInjection
 }
 
 type connectionTimeouts struct {
@@ -138,6 +145,9 @@ type enforcer struct {
 	RestServer   restServer
 	Filters      []filter
 	Metrics      metrics
+	// CustomPolicyActions are the operation policy actions handled by the custom filters, in addition to the
+	// policy actions supported by Choreo Connect.
+	CustomPolicyActions []customPolicyAction
 }
 
 type server struct {
@@ -200,14 +210,59 @@ type sourceControl struct {
 	Repository repository
 }
 
+// Mounted api artifacts watcher configurations
+type artifactsWatcher struct {
+	// Enabled whether the changes of the api artifacts directory should be applied without a restart
+	Enabled bool
+	// DebounceIntervalInMillis is the time the directory should not be modified before the changes are applied
+	DebounceIntervalInMillis int
+}
+
+// Graceful shutdown configurations
+type gracefulShutdown struct {
+	// TimeoutInSeconds is the maximum time the adapter waits for the components to stop before exiting
+	TimeoutInSeconds int
+	// ReadinessDrainTimeInSeconds is the time the adapter waits after withdrawing the readiness endpoint,
+	// for the router to stop receiving new traffic
+	ReadinessDrainTimeInSeconds int
+}
+
 // Global CORS configurations
 type globalCors struct {
-	Enabled          bool
-	AllowOrigins     []string
-	AllowMethods     []string
-	AllowHeaders     []string
-	AllowCredentials bool
-	ExposeHeaders    []string
+	Enabled bool
+	// AllowOrigins may contain the wildcard "*" in place of a part of the host or the port (eg: https://*.example.com)
+	AllowOrigins []string
+	// AllowOriginRegexes are regular expressions matched against the whole origin
+	AllowOriginRegexes []string
+	AllowMethods       []string
+	AllowHeaders       []string
+	AllowCredentials   bool
+	ExposeHeaders      []string
+	// MaxAge is the number of seconds a preflight response can be cached. It is not sent if it is zero.
+	MaxAge int
+}
+
+// Custom error response returned by the router instead of the default error body
+type errorResponse struct {
+	// Environments (gateway labels) the template is applied to. Applied to all environments if empty.
+	Environments []string
+	// Flags are the envoy response flags (eg: UAEX, UF, NR) the template is selected for
+	Flags []string
+	// Accept is the media type expected in the Accept header of the request. Matches any request if empty.
+	Accept string
+	// StatusCode of the response. The default status code of the flag is used if not provided.
+	StatusCode uint32
+	// ContentType of the response
+	ContentType string
+	// Body template of the response
+	Body string
+}
+
+// Fault injection (FAULT_INJECTION policy) configurations
+type faultInjection struct {
+	// Environments (gateway labels) the fault injection policies are allowed to be deployed in.
+	// Fault injection is not allowed in any environment if empty, and allowed in all environments if "*".
+	AllowedEnvironments []string
 }
 
 // Router to enforcer request body passing configurations
@@ -227,12 +282,16 @@ type envoyUpstream struct {
 	DNS      upstreamDNS
 	Retry    upstreamRetry
 	HTTP2    upstreamHTTP2Options
+	Proxy    upstreamProxy
 }
 
 // Envoy Downstream Related Configurations
 type envoyDownstream struct {
 	// DownstreamTLS related Configuration
 	TLS downstreamTLS
+	// PreserveHeaderCase records the case of the HTTP/1 request headers, so that the case is preserved when
+	// the requests are sent to the endpoints.
+	PreserveHeaderCase bool
 }
 
 type downstreamTLS struct {
@@ -247,6 +306,10 @@ type upstreamTLS struct {
 	TrustedCertPath        string
 	VerifyHostName         bool
 	DisableSslVerification bool
+	// ClientCertPath and ClientKeyPath are the default client certificate and key presented to the endpoints
+	// which require mutual TLS. The certificates of the router keystore are presented if they are not provided.
+	ClientCertPath string
+	ClientKeyPath  string
 }
 
 type upstreamTimeout struct {
@@ -272,6 +335,25 @@ type upstreamHTTP2Options struct {
 	MaxConcurrentStreams uint32
 }
 
+// upstreamProxy holds the HTTP CONNECT proxy through which the upstream endpoints are reached. These are the
+// defaults of all the APIs, and can be overridden at API level and at endpoint level.
+type upstreamProxy struct {
+	Enabled  bool
+	Host     string
+	Port     uint32
+	Username string
+	Password string
+	// NoProxy is the list of the endpoint hosts reached directly. An entry starting with "." or "*." matches
+	// the subdomains, and "*" matches all the hosts.
+	NoProxy []string
+	// TunnelPortRangeStart is the first port of the loopback listeners of the router used to tunnel the
+	// upstream connections through the proxies. A port is allocated per each proxied endpoint host and port.
+	TunnelPortRangeStart uint32
+	// TunnelPortRangeEnd is the last port of the loopback listeners of the router used to tunnel the upstream
+	// connections through the proxies.
+	TunnelPortRangeEnd uint32
+}
+
 type upstreamRetry struct {
 	MaxRetryCount        uint32
 	BaseIntervalInMillis uint32
@@ -317,6 +399,22 @@ type tokenService struct {
 	ConsumerKeyClaim     string
 	CertificateFilePath  string
 	ClaimMapping         []claimMapping
+	// RouterValidation validates the tokens of the issuer at the router, without calling the enforcer.
+	RouterValidation routerJWTValidation
+}
+
+// routerJWTValidation holds the configurations to validate the tokens of an issuer using the jwt_authn filter of
+// the router. The tokens are validated only for the APIs which do not require the enforcer, as the subscriptions,
+// throttling and the revocation of the tokens are not validated by the router.
+type routerJWTValidation struct {
+	// Enabled validates the tokens of the issuer at the router for all the eligible APIs. Otherwise, the tokens are
+	// validated at the router only for the APIs enabled via the x-wso2-router-jwt-validation extension.
+	Enabled bool
+	// CertificateFilePath is the path of the public certificate of the issuer within the adapter, used when the
+	// JWKS URL is not provided.
+	CertificateFilePath string
+	// Audiences are the allowed audiences of the tokens. The audience is not validated if empty.
+	Audiences []string
 }
 
 type throttlingConfig struct {
@@ -486,6 +584,26 @@ type controlPlane struct {
 	BrokerConnectionParameters brokerConnectionParameters
 	HTTPClient                 httpClient
 	RequestWorkerPool          requestWorkerPool
+	DeploymentStatusQueue      deploymentStatusQueue
+
+	// RevisionDeployFailureEndpoint is the resource path of the control plane, relative to the ServiceURL, to which
+	// the failed revision deployments are sent. The failures are not sent to the control plane if it is empty.
+	RevisionDeployFailureEndpoint string
+}
+
+// deploymentStatusQueue holds the configurations of the queue which delivers the revision deployment statuses
+// to the control plane.
+type deploymentStatusQueue struct {
+	// Directory is the directory where the undelivered deployment statuses are persisted.
+	// The deployment statuses are kept only in memory if it is empty.
+	Directory string
+	// InitialBackoff is the time in seconds to wait before the first retry. It is doubled after each retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum time in seconds to wait between two retries.
+	MaxBackoff time.Duration
+	// MaxAttempts is the maximum number of attempts to deliver a deployment status, after which it is moved to the
+	// dead letter directory. The deployment statuses are retried until they are delivered if it is not positive.
+	MaxAttempts int
 }
 
 type requestWorkerPool struct {
@@ -531,6 +649,18 @@ type filter struct {
 	ConfigProperties map[string]string
 }
 
+// customPolicyAction declares an operation policy action which is not supported by Choreo Connect out of the box.
+// The policies using the action are always passed to the enforcer.
+type customPolicyAction struct {
+	// Action is the policy action name used in the operation policy definitions
+	Action string
+	// RequiredParams are the parameters which should be available in the policy definitions
+	RequiredParams []string
+	// FilterClass is the class name of the enforcer filter which handles the policy action. Required, as the
+	// custom policy actions are only applied by the enforcer.
+	FilterClass string
+}
+
 type httpClient struct {
 	RequestTimeOut time.Duration
 }
@@ -0,0 +1,82 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errorResponseFlags are the envoy response flags for which the router returns an error response.
+// It needs to be in sync with the default error responses of the router.
+var errorResponseFlags = map[string]bool{
+	"NR": true, "UAEX": true, "UF": true, "UT": true, "UO": true, "URX": true, "NC": true, "UH": true,
+	"UR": true, "UC": true, "LR": true, "IH": true, "SI": true, "DPE": true, "UPE": true, "UMSDR": true,
+}
+
+// validate validates the router and the enforcer configurations which are used while generating the resources
+// of the gateway, so that an invalid configuration fails the startup or the reload.
+func (config *Config) validate() error {
+	for i, errorResponse := range config.Envoy.ErrorResponses {
+		if err := errorResponse.validate(); err != nil {
+			return fmt.Errorf("invalid router.errorResponses[%d] : %v", i, err)
+		}
+	}
+	customActions := make(map[string]bool)
+	for i, customAction := range config.Enforcer.CustomPolicyActions {
+		if err := customAction.validate(); err != nil {
+			return fmt.Errorf("invalid enforcer.customPolicyActions[%d] : %v", i, err)
+		}
+		if customActions[customAction.Action] {
+			return fmt.Errorf("invalid enforcer.customPolicyActions[%d] : duplicate action %q", i, customAction.Action)
+		}
+		customActions[customAction.Action] = true
+	}
+	return nil
+}
+
+// validate validates the error response template. An invalid status code or flag is rejected by envoy,
+// and the listener of the gateway environment is not updated.
+func (errorResponse *errorResponse) validate() error {
+	if len(errorResponse.Flags) == 0 {
+		return errors.New("at least one response flag is required")
+	}
+	for _, flag := range errorResponse.Flags {
+		if !errorResponseFlags[flag] {
+			return fmt.Errorf("unsupported response flag %q", flag)
+		}
+	}
+	if errorResponse.Body == "" {
+		return errors.New("body is required")
+	}
+	if errorResponse.StatusCode != 0 && (errorResponse.StatusCode < 200 || errorResponse.StatusCode > 599) {
+		return fmt.Errorf("invalid status code %d", errorResponse.StatusCode)
+	}
+	return nil
+}
+
+// validate validates the custom policy action. The enforcer cannot apply a policy without the filter class.
+func (customAction *customPolicyAction) validate() error {
+	if customAction.Action == "" {
+		return errors.New("action is required")
+	}
+	if customAction.FilterClass == "" {
+		return fmt.Errorf("filterClass is required for the action %q", customAction.Action)
+	}
+	return nil
+}
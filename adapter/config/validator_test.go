@@ -0,0 +1,113 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateErrorResponses(t *testing.T) {
+	tests := []struct {
+		errorResponse errorResponse
+		isValid       bool
+		message       string
+	}{
+		{
+			errorResponse: errorResponse{Flags: []string{"UF", "UH"}, StatusCode: 503, Body: "upstream failure"},
+			isValid:       true,
+			message:       `Valid template`,
+		},
+		{
+			errorResponse: errorResponse{Flags: []string{"UAEX"}, Body: "%RESPONSE_CODE%"},
+			isValid:       true,
+			message:       `Valid template without the status code`,
+		},
+		{
+			errorResponse: errorResponse{StatusCode: 503, Body: "upstream failure"},
+			message:       `Template without flags`,
+		},
+		{
+			errorResponse: errorResponse{Flags: []string{"UF", "XX"}, Body: "upstream failure"},
+			message:       `Template with an unsupported flag`,
+		},
+		{
+			errorResponse: errorResponse{Flags: []string{"UF"}, StatusCode: 503},
+			message:       `Template without a body`,
+		},
+		{
+			errorResponse: errorResponse{Flags: []string{"UF"}, StatusCode: 700, Body: "upstream failure"},
+			message:       `Template with an invalid status code`,
+		},
+	}
+
+	for _, test := range tests {
+		config := &Config{}
+		config.Envoy.ErrorResponses = []errorResponse{test.errorResponse}
+		err := config.validate()
+		if test.isValid {
+			assert.Nil(t, err, test.message)
+		} else {
+			assert.NotNil(t, err, test.message)
+		}
+	}
+}
+
+func TestValidateCustomPolicyActions(t *testing.T) {
+	tests := []struct {
+		customActions []customPolicyAction
+		isValid       bool
+		message       string
+	}{
+		{
+			customActions: []customPolicyAction{
+				{Action: "CUSTOM_VALIDATION", RequiredParams: []string{"headerName"}, FilterClass: "org.wso2.custom.ValidationFilter"},
+				{Action: "CUSTOM_LOGGING", FilterClass: "org.wso2.custom.LoggingFilter"},
+			},
+			isValid: true,
+			message: `Valid custom policy actions`,
+		},
+		{
+			customActions: []customPolicyAction{{FilterClass: "org.wso2.custom.ValidationFilter"}},
+			message:       `Custom policy action without the action name`,
+		},
+		{
+			customActions: []customPolicyAction{{Action: "CUSTOM_VALIDATION", RequiredParams: []string{"headerName"}}},
+			message:       `Custom policy action without the filter class`,
+		},
+		{
+			customActions: []customPolicyAction{
+				{Action: "CUSTOM_VALIDATION", FilterClass: "org.wso2.custom.ValidationFilter"},
+				{Action: "CUSTOM_VALIDATION", FilterClass: "org.wso2.custom.LoggingFilter"},
+			},
+			message: `Duplicate custom policy actions`,
+		},
+	}
+
+	for _, test := range tests {
+		config := &Config{}
+		config.Enforcer.CustomPolicyActions = test.customActions
+		err := config.validate()
+		if test.isValid {
+			assert.Nil(t, err, test.message)
+		} else {
+			assert.NotNil(t, err, test.message)
+		}
+	}
+}
@@ -33,6 +33,7 @@ import (
 	routercb "github.com/wso2/product-microgateway/adapter/internal/discovery/xds/routercallbacks"
 	"github.com/wso2/product-microgateway/adapter/internal/ga"
 	"github.com/wso2/product-microgateway/adapter/internal/messaging"
+	"github.com/wso2/product-microgateway/adapter/internal/notifier"
 	"github.com/wso2/product-microgateway/adapter/pkg/adapter"
 	apiservice "github.com/wso2/product-microgateway/adapter/pkg/discovery/api/wso2/discovery/service/api"
 	configservice "github.com/wso2/product-microgateway/adapter/pkg/discovery/api/wso2/discovery/service/config"
@@ -52,6 +53,8 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"syscall"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/wso2/product-microgateway/adapter/config"
@@ -76,13 +79,26 @@ var (
 	alsPort     uint
 
 	mode string
+
+	renderFlags        = flag.NewFlagSet(RenderCommand, flag.ExitOnError)
+	renderConfigPath   string
+	renderArtifactsDir string
+	renderLabel        string
+	renderOutputDir    string
+	renderFormat       string
+	renderBootstrap    bool
 )
 
 const (
 	ads          = "ads"
 	amqpProtocol = "amqp"
+	// configMapDataDir is the symlink swapped by kubernetes when a ConfigMap volume is updated
+	configMapDataDir = "..data"
 )
 
+// RenderCommand is the command to render the router and enforcer resources to files without starting the adapter.
+const RenderCommand = "render"
+
 func init() {
 	flag.BoolVar(&debug, "debug", true, "Use debug logging")
 	flag.BoolVar(&onlyLogging, "onlyLogging", false, "Only demo AccessLogging Service")
@@ -90,6 +106,14 @@ func init() {
 	flag.UintVar(&gatewayPort, "gateway", 18001, "Management server port for HTTP gateway")
 	flag.UintVar(&alsPort, "als", 18090, "Accesslog server port")
 	flag.StringVar(&mode, "ads", ads, "Management server type (ads, xds, rest)")
+
+	renderFlags.StringVar(&renderConfigPath, "config", "", "Path of the adapter configuration file (defaults to $MGW_HOME/conf/config.toml)")
+	renderFlags.StringVar(&renderArtifactsDir, "artifacts", "", "Artifacts directory containing the API projects within the apis directory "+
+		"(defaults to adapter.artifactsDirectory of the configuration)")
+	renderFlags.StringVar(&renderLabel, "label", config.DefaultGatewayName, "Gateway environment label to render the resources for")
+	renderFlags.StringVar(&renderOutputDir, "out", ".", "Directory to write the rendered resources to")
+	renderFlags.StringVar(&renderFormat, "format", renderFormatYAML, "Format of the rendered resources (yaml, json)")
+	renderFlags.BoolVar(&renderBootstrap, "bootstrap", false, "Render an Envoy bootstrap with the static resources as well")
 }
 
 const grpcMaxConcurrentStreams = 1000000
@@ -98,7 +122,7 @@ func runManagementServer(conf *config.Config, server xdsv3.Server, enforcerServe
 	enforcerAppDsSrv wso2_server.Server, enforcerAPIDsSrv wso2_server.Server, enforcerAppPolicyDsSrv wso2_server.Server,
 	enforcerSubPolicyDsSrv wso2_server.Server, enforcerAppKeyMappingDsSrv wso2_server.Server,
 	enforcerKeyManagerDsSrv wso2_server.Server, enforcerRevokedTokenDsSrv wso2_server.Server,
-	enforcerThrottleDataDsSrv wso2_server.Server, port uint) {
+	enforcerThrottleDataDsSrv wso2_server.Server, port uint) *grpc.Server {
 	var grpcOptions []grpc.ServerOption
 	grpcOptions = append(grpcOptions, grpc.MaxConcurrentStreams(grpcMaxConcurrentStreams))
 	publicKeyLocation, privateKeyLocation, truststoreLocation := tlsutils.GetKeyLocations()
@@ -169,12 +193,13 @@ func runManagementServer(conf *config.Config, server xdsv3.Server, enforcerServe
 			})
 		}
 	}()
+	return grpcServer
 }
 
 // Run starts the XDS server and Rest API server.
 func Run(conf *config.Config) {
 	sig := make(chan os.Signal, 2)
-	signal.Notify(sig, os.Interrupt)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
 	// TODO: (VirajSalaka) Support the REST API Configuration via flags only if it is a valid requirement
 	flag.Parse()
 
@@ -196,6 +221,18 @@ func Run(conf *config.Config) {
 		})
 	}
 
+	// config watcher. The directory is watched, as the file is replaced instead of being written by the kubernetes
+	// ConfigMap updates and by some editors, which stops the watch of the file.
+	watcherConf, _ := fsnotify.NewWatcher()
+	configPath := config.GetConfigPath()
+	if errC = watcherConf.Add(filepath.Dir(configPath)); errC != nil {
+		logger.LoggerMgw.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error watching the adapter configs. Changes are applied only after a restart. %v", errC.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 1111,
+		})
+	}
+
 	logger.LoggerMgw.Info("Starting adapter ....")
 	cache := xds.GetXdsCache()
 	enforcerCache := xds.GetEnforcerCache()
@@ -210,18 +247,18 @@ func Run(conf *config.Config) {
 	enforcerThrottleDataCache := xds.GetEnforcerThrottleDataCache()
 
 	srv := xdsv3.NewServer(ctx, cache, &routercb.Callbacks{})
-	enforcerXdsSrv := wso2_server.NewServer(ctx, enforcerCache, &enforcerCallbacks.Callbacks{})
-	enforcerSdsSrv := wso2_server.NewServer(ctx, enforcerSubscriptionCache, &enforcerCallbacks.Callbacks{})
-	enforcerAppDsSrv := wso2_server.NewServer(ctx, enforcerApplicationCache, &enforcerCallbacks.Callbacks{})
-	enforcerAPIDsSrv := wso2_server.NewServer(ctx, enforcerAPICache, &enforcerCallbacks.Callbacks{})
-	enforcerAppPolicyDsSrv := wso2_server.NewServer(ctx, enforcerApplicationPolicyCache, &enforcerCallbacks.Callbacks{})
-	enforcerSubPolicyDsSrv := wso2_server.NewServer(ctx, enforcerSubscriptionPolicyCache, &enforcerCallbacks.Callbacks{})
-	enforcerAppKeyMappingDsSrv := wso2_server.NewServer(ctx, enforcerApplicationKeyMappingCache, &enforcerCallbacks.Callbacks{})
-	enforcerKeyManagerDsSrv := wso2_server.NewServer(ctx, enforcerKeyManagerCache, &enforcerCallbacks.Callbacks{})
-	enforcerRevokedTokenDsSrv := wso2_server.NewServer(ctx, enforcerRevokedTokenCache, &enforcerCallbacks.Callbacks{})
-	enforcerThrottleDataDsSrv := wso2_server.NewServer(ctx, enforcerThrottleDataCache, &enforcerCallbacks.Callbacks{})
-
-	runManagementServer(conf, srv, enforcerXdsSrv, enforcerSdsSrv, enforcerAppDsSrv, enforcerAPIDsSrv,
+	enforcerXdsSrv := wso2_server.NewServer(ctx, enforcerCache, enforcerCallbacks.NewCallbacks(xds.EnforcerCacheName))
+	enforcerSdsSrv := wso2_server.NewServer(ctx, enforcerSubscriptionCache, enforcerCallbacks.NewCallbacks(xds.EnforcerSubscriptionCacheName))
+	enforcerAppDsSrv := wso2_server.NewServer(ctx, enforcerApplicationCache, enforcerCallbacks.NewCallbacks(xds.EnforcerApplicationCacheName))
+	enforcerAPIDsSrv := wso2_server.NewServer(ctx, enforcerAPICache, enforcerCallbacks.NewCallbacks(xds.EnforcerAPICacheName))
+	enforcerAppPolicyDsSrv := wso2_server.NewServer(ctx, enforcerApplicationPolicyCache, enforcerCallbacks.NewCallbacks(xds.EnforcerApplicationPolicyCacheName))
+	enforcerSubPolicyDsSrv := wso2_server.NewServer(ctx, enforcerSubscriptionPolicyCache, enforcerCallbacks.NewCallbacks(xds.EnforcerSubscriptionPolicyCacheName))
+	enforcerAppKeyMappingDsSrv := wso2_server.NewServer(ctx, enforcerApplicationKeyMappingCache, enforcerCallbacks.NewCallbacks(xds.EnforcerApplicationKeyMappingCacheName))
+	enforcerKeyManagerDsSrv := wso2_server.NewServer(ctx, enforcerKeyManagerCache, enforcerCallbacks.NewCallbacks(xds.EnforcerKeyManagerCacheName))
+	enforcerRevokedTokenDsSrv := wso2_server.NewServer(ctx, enforcerRevokedTokenCache, enforcerCallbacks.NewCallbacks(xds.EnforcerRevokedTokenCacheName))
+	enforcerThrottleDataDsSrv := wso2_server.NewServer(ctx, enforcerThrottleDataCache, enforcerCallbacks.NewCallbacks(xds.EnforcerThrottleDataCacheName))
+
+	grpcServer := runManagementServer(conf, srv, enforcerXdsSrv, enforcerSdsSrv, enforcerAppDsSrv, enforcerAPIDsSrv,
 		enforcerAppPolicyDsSrv, enforcerSubPolicyDsSrv, enforcerAppKeyMappingDsSrv, enforcerKeyManagerDsSrv,
 		enforcerRevokedTokenDsSrv, enforcerThrottleDataDsSrv, port)
 
@@ -262,6 +299,10 @@ func Run(conf *config.Config) {
 
 	eventHubEnabled := conf.ControlPlane.Enabled
 	if eventHubEnabled {
+		if conf.ControlPlane.SendRevisionUpdate {
+			// Deliver the revision acknowledgements which were not delivered before the restart.
+			notifier.StartDeploymentStatusQueue()
+		}
 		if !gaEnabled {
 			// Load subscription data when GA is disabled.
 			eventhub.LoadSubscriptionData(conf, nil)
@@ -293,7 +334,7 @@ func Run(conf *config.Config) {
 				return
 			}
 		} else {
-			_, err := api.ProcessMountedAPIProjects()
+			artifactsMap, err := api.ProcessMountedAPIProjects()
 			if err != nil {
 				logger.LoggerMgw.ErrorC(logging.ErrorDetails{
 					Message:   fmt.Sprintf("Readiness probe is not set as local api artifacts processing has failed. %v", err.Error()),
@@ -302,6 +343,15 @@ func Run(conf *config.Config) {
 				})
 				return
 			}
+			if conf.Adapter.ArtifactsWatcher.Enabled {
+				if err = api.WatchMountedAPIProjects(artifactsMap); err != nil {
+					logger.LoggerMgw.ErrorC(logging.ErrorDetails{
+						Message:   fmt.Sprintf("Error while watching the api artifacts directory. %v", err.Error()),
+						Severity:  logging.MAJOR,
+						ErrorCode: 1109,
+					})
+				}
+			}
 		}
 		// We need to deploy the readiness probe when eventhub is disabled
 		xds.DeployReadinessAPI(envs)
@@ -318,17 +368,116 @@ OUTER:
 				config.ClearLogConfigInstance()
 				logger.UpdateLoggers()
 			}
+		case c := <-watcherConf.Events:
+			if isConfigFileChange(c, configPath) {
+				logger.LoggerMgw.Info("Loading updated config file...")
+				reloadConfigs()
+			}
 		case s := <-sig:
 			switch s {
-			case os.Interrupt:
-				logger.LoggerMgw.Info("Shutting down...")
+			case os.Interrupt, syscall.SIGTERM:
+				logger.LoggerMgw.Infof("Received %v signal. Shutting down...", s)
 				break OUTER
 			}
 		}
 	}
+	// The graceful shutdown configuration could be changed after the adapter is started.
+	latestConf, _ := config.ReadConfigs()
+	shutdown(latestConf, envs, grpcServer)
 	logger.LoggerMgw.Info("Bye!")
 }
 
+// isConfigFileChange checks whether the event of the config directory writes or replaces the config file. The
+// creation of the ..data symlink of a kubernetes ConfigMap volume replaces all the files of the volume.
+func isConfigFileChange(event fsnotify.Event, configPath string) bool {
+	if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+		return false
+	}
+	return filepath.Clean(event.Name) == filepath.Clean(configPath) || filepath.Base(event.Name) == configMapDataDir
+}
+
+// reloadConfigs applies the changes of the adapter configuration file without a restart. The enforcer configuration
+// is pushed to the enforcer and the router resources are regenerated based on the changed configurations.
+func reloadConfigs() {
+	changes, err := config.ReloadConfigs()
+	if err != nil {
+		logger.LoggerMgw.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error reloading the adapter configs. %v", err.Error()),
+			Severity:  logging.MAJOR,
+			ErrorCode: 1112,
+		})
+		return
+	}
+	if !changes.HasChanges() {
+		logger.LoggerMgw.Info("No changes found in the adapter configs")
+		return
+	}
+	conf, _ := config.ReadConfigs()
+	if changes.Enforcer {
+		xds.UpdateEnforcerConfig(conf)
+		logger.LoggerMgw.Info("Applied the enforcer config changes")
+	}
+	if changes.Router {
+		xds.RegenerateEnvoyResources()
+		logger.LoggerMgw.Info("Applied the router config changes")
+	}
+	if len(changes.RestartRequired) > 0 {
+		logger.LoggerMgw.Warnf("Changes of the adapter configs %v are applied only after a restart",
+			strings.Join(changes.RestartRequired, ", "))
+	}
+}
+
+// shutdown stops the adapter components within the configured graceful shutdown timeout. The readiness endpoint
+// is withdrawn first, so that no new traffic is routed to the gateway while the components are being stopped.
+func shutdown(conf *config.Config, envs []string, grpcServer *grpc.Server) {
+	shutdownConf := conf.Adapter.GracefulShutdown
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(shutdownConf.TimeoutInSeconds)*time.Second)
+	defer cancel()
+
+	xds.WithdrawReadinessAPI(envs)
+	select {
+	case <-time.After(time.Duration(shutdownConf.ReadinessDrainTimeInSeconds) * time.Second):
+	case <-ctx.Done():
+	}
+
+	if conf.Adapter.Server.Enabled {
+		if err := restserver.StopRestServer(ctx); err != nil {
+			logShutdownError("REST API server", err)
+		}
+	}
+	if conf.ControlPlane.Enabled {
+		if err := messaging.StopEvents(); err != nil {
+			logShutdownError("event listeners", err)
+		}
+		if err := notifier.FlushPendingAcks(ctx); err != nil {
+			logShutdownError("revision acknowledgements", err)
+		}
+	}
+
+	// The xDS streams of the router and the enforcer are long lived. Hence the server is force stopped if the
+	// streams are not closed by the clients before the timeout.
+	grpcServerStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcServerStopped)
+	}()
+	select {
+	case <-grpcServerStopped:
+		logger.LoggerMgw.Info("XDS GRPC server is stopped")
+	case <-ctx.Done():
+		grpcServer.Stop()
+		logger.LoggerMgw.Info("XDS GRPC server is force stopped as the graceful shutdown timeout exceeded")
+	}
+}
+
+func logShutdownError(component string, err error) {
+	logger.LoggerMgw.ErrorC(logging.ErrorDetails{
+		Message:   fmt.Sprintf("Error while stopping the %v on shutdown. %v", component, err.Error()),
+		Severity:  logging.MINOR,
+		ErrorCode: 1110,
+	})
+}
+
 // fetch APIs from control plane during the server start up and push them
 // to the router and enforcer components.
 func fetchAPIsOnStartUp(conf *config.Config, apiUUIDList []string) {
@@ -0,0 +1,75 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsConfigFileChange(t *testing.T) {
+	configDir := filepath.FromSlash("/home/wso2/conf")
+	configPath := filepath.Join(configDir, "config.toml")
+	tests := []struct {
+		event    fsnotify.Event
+		isChange bool
+		message  string
+	}{
+		{
+			event:    fsnotify.Event{Name: configPath, Op: fsnotify.Write},
+			isChange: true,
+			message:  "Writing the config file should be a change",
+		},
+		{
+			event:    fsnotify.Event{Name: configPath, Op: fsnotify.Write | fsnotify.Chmod},
+			isChange: true,
+			message:  "Writing the config file along with other operations should be a change",
+		},
+		{
+			event:    fsnotify.Event{Name: configPath, Op: fsnotify.Create},
+			isChange: true,
+			message:  "Replacing the config file by renaming another file should be a change",
+		},
+		{
+			event:    fsnotify.Event{Name: filepath.Join(configDir, "..data"), Op: fsnotify.Create},
+			isChange: true,
+			message:  "Swapping the ..data symlink of a ConfigMap volume should be a change",
+		},
+		{
+			event:    fsnotify.Event{Name: configPath, Op: fsnotify.Remove},
+			isChange: false,
+			message:  "Removing the config file should not be a change",
+		},
+		{
+			event:    fsnotify.Event{Name: configPath, Op: fsnotify.Chmod},
+			isChange: false,
+			message:  "Changing the permissions of the config file should not be a change",
+		},
+		{
+			event:    fsnotify.Event{Name: filepath.Join(configDir, "log_config.toml"), Op: fsnotify.Write},
+			isChange: false,
+			message:  "Writing another file of the config directory should not be a change",
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.isChange, isConfigFileChange(test.event, configPath), test.message)
+	}
+}
@@ -0,0 +1,302 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/ghodss/yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/wso2/product-microgateway/adapter/config"
+	"github.com/wso2/product-microgateway/adapter/internal/api"
+	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
+	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+)
+
+const (
+	renderFormatYAML = "yaml"
+	renderFormatJSON = "json"
+
+	renderedVersion         = "1"
+	routerAdminPort         = 9000
+	routerNodeCluster       = "default_cluster"
+	bootstrapFileNamePrefix = "bootstrap"
+)
+
+// RunRenderCommand generates the router resources (listeners, routes, clusters, endpoints and the upstream client
+// certificate secrets) and the enforcer API resources of a label for the given configuration and API projects, and
+// writes them to files without starting the adapter. Each file contains a discovery response, which can be used as a
+// file based xDS config source of the router. If requested, an Envoy bootstrap with the listeners (along with the
+// routes), the clusters and the secrets as static resources is written as well. args are the command line arguments following the render command.
+func RunRenderCommand(args []string) error {
+	if err := renderFlags.Parse(args); err != nil {
+		return err
+	}
+	if renderFormat != renderFormatYAML && renderFormat != renderFormatJSON {
+		return fmt.Errorf("unsupported format %q. The format should be either %v or %v", renderFormat,
+			renderFormatYAML, renderFormatJSON)
+	}
+	if renderConfigPath != "" {
+		config.SetConfigPath(renderConfigPath)
+	}
+	conf, err := config.ReadConfigs()
+	if err != nil {
+		return err
+	}
+	// The artifacts directory flag only applies to this command, hence the shared config is left unchanged.
+	artifactsDirectory := conf.Adapter.ArtifactsDirectory
+	if renderArtifactsDir != "" {
+		artifactsDirectory = renderArtifactsDir
+	}
+
+	artifactsMap, err := api.ProcessAPIProjectsInArtifactsDirectory(artifactsDirectory)
+	if err != nil {
+		return err
+	}
+	logger.LoggerMgw.Infof("Rendering the resources of %v API projects for the label %v", len(artifactsMap), renderLabel)
+
+	xds.GenerateGlobalClusters(renderLabel)
+	listeners, clusters, routes, endpoints, apis := xds.GenerateEnvoyResoucesForLabel(renderLabel)
+	secrets := xds.GetUpstreamClientCertSecrets(clusters)
+	if err = os.MkdirAll(renderOutputDir, os.ModePerm); err != nil {
+		return err
+	}
+	renderedResources := []struct {
+		name      string
+		resources []types.Resource
+	}{
+		{"listeners", listeners},
+		{"routes", routes},
+		{"clusters", clusters},
+		{"endpoints", endpoints},
+		{"secrets", secrets},
+		{"apis", apis},
+	}
+	for _, rendered := range renderedResources {
+		response, err := getDiscoveryResponse(rendered.resources)
+		if err != nil {
+			return fmt.Errorf("error rendering the %v : %v", rendered.name, err)
+		}
+		if err = writeRenderedResource(rendered.name, response); err != nil {
+			return err
+		}
+	}
+
+	if renderBootstrap {
+		bootstrap, err := getStaticBootstrap(renderLabel, listeners, clusters, routes, secrets)
+		if err != nil {
+			return fmt.Errorf("error rendering the bootstrap : %v", err)
+		}
+		if err = writeRenderedResource(bootstrapFileNamePrefix, bootstrap); err != nil {
+			return err
+		}
+	}
+	logger.LoggerMgw.Infof("Rendered the resources of the label %v to %v", renderLabel, renderOutputDir)
+	return nil
+}
+
+// getDiscoveryResponse returns a discovery response containing the given resources.
+func getDiscoveryResponse(resources []types.Resource) (*discoveryv3.DiscoveryResponse, error) {
+	response := &discoveryv3.DiscoveryResponse{
+		VersionInfo: renderedVersion,
+		Resources:   make([]*anypb.Any, 0, len(resources)),
+	}
+	for _, resource := range resources {
+		resourceAny, err := anypb.New(resource)
+		if err != nil {
+			return nil, err
+		}
+		response.TypeUrl = resourceAny.TypeUrl
+		response.Resources = append(response.Resources, resourceAny)
+	}
+	return response, nil
+}
+
+// getStaticBootstrap returns an Envoy bootstrap which contains the given listeners, clusters and secrets as static
+// resources. As the route configurations cannot be static resources, they are added to the http connection managers
+// of the listeners instead of fetching them via RDS. The clusters refer to the static secrets instead of fetching
+// them via ADS, as the bootstrap does not have an ADS config source.
+func getStaticBootstrap(label string, listeners, clusters, routes,
+	secrets []types.Resource) (*bootstrapv3.Bootstrap, error) {
+	routeConfigs := make(map[string]*routev3.RouteConfiguration)
+	for _, route := range routes {
+		if routeConfig, ok := route.(*routev3.RouteConfiguration); ok {
+			routeConfigs[routeConfig.Name] = routeConfig
+		}
+	}
+
+	staticResources := &bootstrapv3.Bootstrap_StaticResources{}
+	for _, resource := range listeners {
+		listener, ok := resource.(*listenerv3.Listener)
+		if !ok {
+			continue
+		}
+		listener = proto.Clone(listener).(*listenerv3.Listener)
+		for _, filterChain := range listener.FilterChains {
+			for _, filter := range filterChain.Filters {
+				if err := setStaticRouteConfig(filter, routeConfigs); err != nil {
+					return nil, err
+				}
+			}
+		}
+		staticResources.Listeners = append(staticResources.Listeners, listener)
+	}
+	for _, resource := range clusters {
+		cluster, ok := resource.(*clusterv3.Cluster)
+		if !ok {
+			continue
+		}
+		cluster = proto.Clone(cluster).(*clusterv3.Cluster)
+		if err := setStaticSecretConfigs(cluster); err != nil {
+			return nil, err
+		}
+		staticResources.Clusters = append(staticResources.Clusters, cluster)
+	}
+	for _, resource := range secrets {
+		if secret, ok := resource.(*tlsv3.Secret); ok {
+			staticResources.Secrets = append(staticResources.Secrets, secret)
+		}
+	}
+
+	// The runtime layers are the same as the ones of the default router bootstrap.
+	deprecationLayer, _ := structpb.NewStruct(map[string]interface{}{"re2.max_program_size.error_level": 1000})
+	maxConnectionsLayer, _ := structpb.NewStruct(map[string]interface{}{
+		"overload.global_downstream_max_connections": 2147483647,
+	})
+	return &bootstrapv3.Bootstrap{
+		Node: &corev3.Node{
+			Id:      label,
+			Cluster: routerNodeCluster,
+		},
+		Admin: &bootstrapv3.Admin{
+			Address: &corev3.Address{
+				Address: &corev3.Address_SocketAddress{
+					SocketAddress: &corev3.SocketAddress{
+						Address: "0.0.0.0",
+						PortSpecifier: &corev3.SocketAddress_PortValue{
+							PortValue: routerAdminPort,
+						},
+					},
+				},
+			},
+		},
+		LayeredRuntime: &bootstrapv3.LayeredRuntime{
+			Layers: []*bootstrapv3.RuntimeLayer{
+				{
+					Name:           "deprecation",
+					LayerSpecifier: &bootstrapv3.RuntimeLayer_StaticLayer{StaticLayer: deprecationLayer},
+				},
+				{
+					Name:           "globalMaxConnections",
+					LayerSpecifier: &bootstrapv3.RuntimeLayer_StaticLayer{StaticLayer: maxConnectionsLayer},
+				},
+			},
+		},
+		StaticResources: staticResources,
+	}, nil
+}
+
+// setStaticRouteConfig replaces the RDS config of the given http connection manager filter with the route
+// configuration it refers to.
+func setStaticRouteConfig(filter *listenerv3.Filter, routeConfigs map[string]*routev3.RouteConfiguration) error {
+	if filter.Name != wellknown.HTTPConnectionManager || filter.GetTypedConfig() == nil {
+		return nil
+	}
+	manager := &hcmv3.HttpConnectionManager{}
+	if err := filter.GetTypedConfig().UnmarshalTo(manager); err != nil {
+		return err
+	}
+	rds := manager.GetRds()
+	if rds == nil {
+		return nil
+	}
+	routeConfig, found := routeConfigs[rds.RouteConfigName]
+	if !found {
+		return fmt.Errorf("route configuration %v is not found", rds.RouteConfigName)
+	}
+	manager.RouteSpecifier = &hcmv3.HttpConnectionManager_RouteConfig{RouteConfig: routeConfig}
+	managerAny, err := anypb.New(manager)
+	if err != nil {
+		return err
+	}
+	filter.ConfigType = &listenerv3.Filter_TypedConfig{TypedConfig: managerAny}
+	return nil
+}
+
+// setStaticSecretConfigs removes the SDS config sources of the secrets referred by the upstream TLS contexts of the
+// given cluster, so that the secrets are resolved from the static secrets of the bootstrap.
+func setStaticSecretConfigs(cluster *clusterv3.Cluster) error {
+	transportSockets := []*corev3.TransportSocket{cluster.GetTransportSocket()}
+	for _, transportSocketMatch := range cluster.GetTransportSocketMatches() {
+		transportSockets = append(transportSockets, transportSocketMatch.GetTransportSocket())
+	}
+	for _, transportSocket := range transportSockets {
+		upstreamTLSContext := &tlsv3.UpstreamTlsContext{}
+		if transportSocket.GetTypedConfig() == nil ||
+			transportSocket.GetTypedConfig().UnmarshalTo(upstreamTLSContext) != nil ||
+			len(upstreamTLSContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs()) == 0 {
+			continue
+		}
+		for _, sdsSecretConfig := range upstreamTLSContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs() {
+			sdsSecretConfig.SdsConfig = nil
+		}
+		upstreamTLSContextAny, err := anypb.New(upstreamTLSContext)
+		if err != nil {
+			return err
+		}
+		transportSocket.ConfigType = &corev3.TransportSocket_TypedConfig{TypedConfig: upstreamTLSContextAny}
+	}
+	return nil
+}
+
+// writeRenderedResource writes the given resource to a file with the given name in the output directory,
+// in the requested format.
+func writeRenderedResource(name string, resource proto.Message) error {
+	content, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(resource)
+	if err != nil {
+		return fmt.Errorf("error marshalling the %v : %v", name, err)
+	}
+	if renderFormat == renderFormatYAML {
+		if content, err = yaml.JSONToYAML(content); err != nil {
+			return fmt.Errorf("error converting the %v to yaml : %v", name, err)
+		}
+	}
+	filePath := filepath.Join(renderOutputDir, name+"."+renderFormat)
+	if err = ioutil.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("error writing the %v to %v : %v", name, filePath, err)
+	}
+	logger.LoggerMgw.Debugf("Rendered the %v to %v", name, filePath)
+	return nil
+}
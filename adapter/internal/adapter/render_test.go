@@ -0,0 +1,223 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/wso2/product-microgateway/adapter/config"
+)
+
+// updateGoldenFiles rewrites the golden files with the rendered output, when the rendered resources are
+// intentionally changed (go test ./internal/adapter -run Render -update).
+var updateGoldenFiles = flag.Bool("update", false, "update the golden files of the render tests")
+
+var generatedResourceID = regexp.MustCompile(`- id: [0-9a-f-]{36}`)
+
+const resourceIDPlaceholder = "- id: <generated>"
+
+func TestRunRenderCommand(t *testing.T) {
+	renderDir := config.GetMgwHome() + "/../adapter/test-resources/render"
+	outputDir := t.TempDir()
+	conf, _ := config.ReadConfigs()
+	artifactsDirectory := conf.Adapter.ArtifactsDirectory
+
+	err := RunRenderCommand([]string{"-artifacts", filepath.Join(renderDir, "artifacts"), "-out", outputDir,
+		"-format", renderFormatYAML, "-bootstrap"})
+	assert.Nil(t, err, "Error while rendering the resources.")
+
+	updatedConf, _ := config.ReadConfigs()
+	assert.Equal(t, artifactsDirectory, updatedConf.Adapter.ArtifactsDirectory,
+		"The artifacts directory of the adapter configuration should not be changed.")
+	for _, name := range []string{"listeners", "routes", "clusters", "endpoints", "secrets", "apis",
+		bootstrapFileNamePrefix} {
+		assertGoldenFile(t, filepath.Join(outputDir, name+".yaml"), filepath.Join(renderDir, "golden", name+".yaml"))
+	}
+}
+
+func TestRunRenderCommandWithUnsupportedFormat(t *testing.T) {
+	err := RunRenderCommand([]string{"-out", t.TempDir(), "-format", "xml"})
+	assert.NotNil(t, err, "Rendering should fail for an unsupported format.")
+	renderFormat = renderFormatYAML
+}
+
+func TestGetStaticBootstrap(t *testing.T) {
+	renderDir := config.GetMgwHome() + "/../adapter/test-resources/render"
+	listener, err := getRDSListener("listener", "routes")
+	assert.Nil(t, err, "Error while creating the listener.")
+	routeConfig := &routev3.RouteConfiguration{
+		Name: "routes",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "default",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "pets",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/pets"},
+						},
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: "petstore"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cluster := &clusterv3.Cluster{Name: "petstore"}
+	mtlsCluster, err := getMTLSCluster("petstore_mtls", "UpstreamClientCertSecret_petstore")
+	assert.Nil(t, err, "Error while creating the mTLS cluster.")
+	secret := &tlsv3.Secret{
+		Name: "UpstreamClientCertSecret_petstore",
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{
+					Specifier: &corev3.DataSource_Filename{Filename: "/home/wso2/security/petstore.crt"},
+				},
+				PrivateKey: &corev3.DataSource{
+					Specifier: &corev3.DataSource_Filename{Filename: "/home/wso2/security/petstore.key"},
+				},
+			},
+		},
+	}
+
+	bootstrap, err := getStaticBootstrap("Default", []types.Resource{listener},
+		[]types.Resource{cluster, mtlsCluster}, []types.Resource{routeConfig}, []types.Resource{secret})
+	assert.Nil(t, err, "Error while creating the bootstrap.")
+	assert.Equal(t, []*tlsv3.Secret{secret}, bootstrap.GetStaticResources().GetSecrets(),
+		"The secrets should be static resources of the bootstrap.")
+	upstreamTLSContext := &tlsv3.UpstreamTlsContext{}
+	assert.Nil(t, bootstrap.GetStaticResources().GetClusters()[1].GetTransportSocket().GetTypedConfig().
+		UnmarshalTo(upstreamTLSContext))
+	sdsSecretConfig := upstreamTLSContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs()[0]
+	assert.Equal(t, secret.Name, sdsSecretConfig.GetName(), "The cluster should refer to the static secret.")
+	assert.Nil(t, sdsSecretConfig.GetSdsConfig(), "The secret should not be fetched via ADS.")
+	outputDir := t.TempDir()
+	renderOutputDir = outputDir
+	defer func() { renderOutputDir = "." }()
+	assert.Nil(t, writeRenderedResource("static_bootstrap", bootstrap), "Error while writing the bootstrap.")
+	assertGoldenFile(t, filepath.Join(outputDir, "static_bootstrap.yaml"),
+		filepath.Join(renderDir, "golden", "static_bootstrap.yaml"))
+
+	manager := &hcmv3.HttpConnectionManager{}
+	assert.Nil(t, listener.FilterChains[0].Filters[0].GetTypedConfig().UnmarshalTo(manager))
+	assert.NotNil(t, manager.GetRds(), "The listener of the rendered resources should not be modified.")
+	assert.Nil(t, mtlsCluster.GetTransportSocket().GetTypedConfig().UnmarshalTo(upstreamTLSContext))
+	assert.NotNil(t, upstreamTLSContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs()[0].GetSdsConfig(),
+		"The cluster of the rendered resources should not be modified.")
+}
+
+func TestSetStaticRouteConfigWithUnknownRouteConfig(t *testing.T) {
+	listener, err := getRDSListener("listener", "unknown")
+	assert.Nil(t, err, "Error while creating the listener.")
+	err = setStaticRouteConfig(listener.FilterChains[0].Filters[0], map[string]*routev3.RouteConfiguration{})
+	assert.NotNil(t, err, "An unknown route configuration should not be accepted.")
+}
+
+// getRDSListener returns a listener with an http connection manager fetching the given route configuration via RDS.
+func getRDSListener(name, routeConfigName string) (*listenerv3.Listener, error) {
+	manager := &hcmv3.HttpConnectionManager{
+		StatPrefix: "ingress_http",
+		RouteSpecifier: &hcmv3.HttpConnectionManager_Rds{
+			Rds: &hcmv3.Rds{RouteConfigName: routeConfigName},
+		},
+	}
+	managerAny, err := anypb.New(manager)
+	if err != nil {
+		return nil, err
+	}
+	return &listenerv3.Listener{
+		Name: name,
+		FilterChains: []*listenerv3.FilterChain{
+			{
+				Filters: []*listenerv3.Filter{
+					{
+						Name:       wellknown.HTTPConnectionManager,
+						ConfigType: &listenerv3.Filter_TypedConfig{TypedConfig: managerAny},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// getMTLSCluster returns a cluster with an upstream TLS context fetching the given client certificate via ADS.
+func getMTLSCluster(name, secretName string) (*clusterv3.Cluster, error) {
+	upstreamTLSContext := &tlsv3.UpstreamTlsContext{
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			TlsCertificateSdsSecretConfigs: []*tlsv3.SdsSecretConfig{{
+				Name: secretName,
+				SdsConfig: &corev3.ConfigSource{
+					ConfigSourceSpecifier: &corev3.ConfigSource_Ads{
+						Ads: &corev3.AggregatedConfigSource{},
+					},
+					ResourceApiVersion: corev3.ApiVersion_V3,
+				},
+			}},
+		},
+	}
+	upstreamTLSContextAny, err := anypb.New(upstreamTLSContext)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterv3.Cluster{
+		Name: name,
+		TransportSocket: &corev3.TransportSocket{
+			Name:       wellknown.TransportSocketTls,
+			ConfigType: &corev3.TransportSocket_TypedConfig{TypedConfig: upstreamTLSContextAny},
+		},
+	}, nil
+}
+
+// assertGoldenFile compares the rendered file with the golden file.
+func assertGoldenFile(t *testing.T, renderedFile, goldenFile string) {
+	rendered, err := ioutil.ReadFile(renderedFile)
+	if !assert.Nil(t, err, "Error while reading the rendered file %v.", renderedFile) {
+		return
+	}
+	if *updateGoldenFiles {
+		assert.Nil(t, ioutil.WriteFile(goldenFile, rendered, 0644), "Error while updating the golden file.")
+		return
+	}
+	golden, err := ioutil.ReadFile(goldenFile)
+	if !assert.Nil(t, err, "Error while reading the golden file %v.", goldenFile) {
+		return
+	}
+	// The resource IDs are generated for each render.
+	assert.Equal(t, generatedResourceID.ReplaceAllString(string(golden), resourceIDPlaceholder),
+		generatedResourceID.ReplaceAllString(string(rendered), resourceIDPlaceholder),
+		"The rendered file %v does not match the golden file.", filepath.Base(renderedFile))
+}
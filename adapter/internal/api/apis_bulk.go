@@ -0,0 +1,198 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	apiModel "github.com/wso2/product-microgateway/adapter/internal/api/models"
+	xds "github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
+	"github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
+)
+
+// APIProjectFile is an API project zip archive of a bulk deployment.
+type APIProjectFile struct {
+	Name    string
+	Content []byte
+}
+
+// BulkAPIError is the error of an API of a failed bulk deployment or undeployment.
+type BulkAPIError struct {
+	// API is the name of the API project file or the name and version of the API.
+	API string
+	// Invalid is true if the API project or the selector is invalid, and false if applying it failed.
+	Invalid bool
+	Err     error
+}
+
+func (e *BulkAPIError) Error() string {
+	if e.API == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v : %v", e.API, e.Err.Error())
+}
+
+var errBulkOperationFailed = errors.New("bulk operation failed")
+
+// ApplyAPIProjectsInStandaloneMode deploys the given API projects as a single transaction. A project file can
+// also be a zip archive of API project zip archives. All the projects are extracted and validated before any of
+// them is deployed. If deploying any of the projects fails, none of them are deployed and the errors of the
+// failed projects are returned.
+func ApplyAPIProjectsInStandaloneMode(projectFiles []APIProjectFile, override *bool) []*BulkAPIError {
+	var apiErrors []*BulkAPIError
+	var projectNames []string
+	var apiProjects []model.ProjectAPI
+	for _, projectFile := range expandAPIProjectBundles(projectFiles) {
+		apiProject, err := extractAPIProject(projectFile.Content)
+		if err != nil {
+			apiErrors = append(apiErrors, &BulkAPIError{API: projectFile.Name, Invalid: true, Err: err})
+			continue
+		}
+		setDefaultDeployment(&apiProject)
+		projectNames = append(projectNames, projectFile.Name)
+		apiProjects = append(apiProjects, apiProject)
+	}
+	apiErrors = append(apiErrors, validateUniqueAPIDeployments(projectNames, apiProjects)...)
+	if len(apiErrors) > 0 {
+		return apiErrors
+	}
+	if len(apiProjects) == 0 {
+		return []*BulkAPIError{{Invalid: true, Err: errors.New("no API projects found")}}
+	}
+
+	overrideValue := override != nil && *override
+	err := xds.ExecuteAPIBatch(func(batch *xds.APIBatch) error {
+		for i, apiProject := range apiProjects {
+			if err := deployAPIProject(batch, apiProject, overrideValue); err != nil {
+				apiErrors = append(apiErrors, &BulkAPIError{API: projectNames[i], Err: err})
+			}
+		}
+		if len(apiErrors) > 0 {
+			return errBulkOperationFailed
+		}
+		return nil
+	})
+	if err != nil && len(apiErrors) == 0 {
+		apiErrors = append(apiErrors, &BulkAPIError{Err: err})
+	}
+	if len(apiErrors) == 0 {
+		loggers.LoggerAPI.Infof("Deployed %v APIs with the bulk deployment", len(apiProjects))
+	}
+	return apiErrors
+}
+
+// UndeployAPIsInStandaloneMode undeploys the APIs matching the given selectors as a single transaction.
+// If undeploying any of the APIs fails, none of them are undeployed and the errors of the failed selectors
+// are returned.
+func UndeployAPIsInStandaloneMode(selectors []*apiModel.APISelector, organizationID string) []*BulkAPIError {
+	var apiErrors []*BulkAPIError
+	err := xds.ExecuteAPIBatch(func(batch *xds.APIBatch) error {
+		for _, selector := range selectors {
+			if selector == nil || selector.APIName == nil || selector.Version == nil {
+				apiErrors = append(apiErrors, &BulkAPIError{Invalid: true, Err: errors.New("API name and version are required")})
+				continue
+			}
+			err := batch.DeleteAPIs(selector.Vhost, *selector.APIName, *selector.Version, selector.Environments, organizationID)
+			if err != nil {
+				apiErrors = append(apiErrors, &BulkAPIError{API: *selector.APIName + ":" + *selector.Version, Err: err})
+			}
+		}
+		if len(apiErrors) > 0 {
+			return errBulkOperationFailed
+		}
+		return nil
+	})
+	if err != nil && len(apiErrors) == 0 {
+		apiErrors = append(apiErrors, &BulkAPIError{Err: err})
+	}
+	if len(apiErrors) == 0 {
+		loggers.LoggerAPI.Infof("Undeployed %v APIs with the bulk undeployment", len(selectors))
+	}
+	return apiErrors
+}
+
+// validateUniqueAPIDeployments checks that an API is not deployed to the same vhost by more than one project.
+func validateUniqueAPIDeployments(projectNames []string, apiProjects []model.ProjectAPI) []*BulkAPIError {
+	var apiErrors []*BulkAPIError
+	deployedProjects := make(map[string]string)
+	for i, apiProject := range apiProjects {
+		apiYaml := apiProject.APIYaml.Data
+		for _, deployment := range apiProject.Deployments {
+			key := strings.Join([]string{apiYaml.OrganizationID, deployment.DeploymentVhost, apiYaml.Name, apiYaml.Version}, ":")
+			if existingProject, found := deployedProjects[key]; found && existingProject != projectNames[i] {
+				apiErrors = append(apiErrors, &BulkAPIError{
+					API:     projectNames[i],
+					Invalid: true,
+					Err: fmt.Errorf("API %v:%v is deployed to the vhost %v by the API project %v as well",
+						apiYaml.Name, apiYaml.Version, deployment.DeploymentVhost, existingProject),
+				})
+				break
+			}
+			deployedProjects[key] = projectNames[i]
+		}
+	}
+	return apiErrors
+}
+
+// expandAPIProjectBundles replaces the zip archives which only contain zip archives (API project bundles)
+// with the API projects within them.
+func expandAPIProjectBundles(projectFiles []APIProjectFile) []APIProjectFile {
+	var expandedFiles []APIProjectFile
+	for _, projectFile := range projectFiles {
+		zipReader, err := zip.NewReader(bytes.NewReader(projectFile.Content), int64(len(projectFile.Content)))
+		if err != nil || !isAPIProjectBundle(zipReader) {
+			expandedFiles = append(expandedFiles, projectFile)
+			continue
+		}
+		for _, file := range zipReader.File {
+			if file.FileInfo().IsDir() {
+				continue
+			}
+			content, err := readZipFile(file)
+			if err != nil {
+				loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+					Message:   fmt.Sprintf("Error occurred while reading the file : %v %v", file.Name, err.Error()),
+					Severity:  logging.MAJOR,
+					ErrorCode: 1229,
+				})
+			}
+			expandedFiles = append(expandedFiles, APIProjectFile{Name: projectFile.Name + "/" + file.Name, Content: content})
+		}
+	}
+	return expandedFiles
+}
+
+func isAPIProjectBundle(zipReader *zip.Reader) bool {
+	hasProjects := false
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if !strings.HasSuffix(file.Name, zipExt) {
+			return false
+		}
+		hasProjects = true
+	}
+	return hasProjects
+}
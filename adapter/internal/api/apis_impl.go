@@ -30,6 +30,7 @@ import (
 
 	"github.com/wso2/product-microgateway/adapter/config"
 	apiModel "github.com/wso2/product-microgateway/adapter/internal/api/models"
+	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/api_collection"
 	xds "github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
 	"github.com/wso2/product-microgateway/adapter/internal/loggers"
 	"github.com/wso2/product-microgateway/adapter/internal/notifier"
@@ -61,6 +62,8 @@ func extractAPIProject(payload []byte) (apiProject model.ProjectAPI, err error)
 	// TODO: (VirajSalaka) this won't support for distributed openAPI definition
 	apiProject.UpstreamCerts = make(map[string][]byte)
 	apiProject.EndpointCerts = make(map[string]string)
+	apiProject.EndpointClientCerts = make(map[string]model.EndpointCertificate)
+	apiProject.UpstreamClientKeys = make(map[string][]byte)
 	apiProject.Policies = make(map[string]model.PolicyContainer)
 	apiProject.DownstreamCerts = make(map[string][]byte)
 	for _, file := range zipReader.File {
@@ -89,7 +92,15 @@ func extractAPIProject(payload []byte) (apiProject model.ProjectAPI, err error)
 // ProcessMountedAPIProjects iterates through the api artifacts directory and apply the projects located within the directory.
 func ProcessMountedAPIProjects() (artifactsMap map[string]model.ProjectAPI, err error) {
 	conf, _ := config.ReadConfigs()
-	apisDirName := filepath.FromSlash(conf.Adapter.ArtifactsDirectory + "/" + apisArtifactDir)
+	return ProcessAPIProjectsInArtifactsDirectory(conf.Adapter.ArtifactsDirectory)
+}
+
+// ProcessAPIProjectsInArtifactsDirectory iterates through the api directory of the given artifacts directory and
+// apply the projects located within the directory.
+func ProcessAPIProjectsInArtifactsDirectory(artifactsDirectory string) (artifactsMap map[string]model.ProjectAPI,
+	err error) {
+	conf, _ := config.ReadConfigs()
+	apisDirName := filepath.FromSlash(artifactsDirectory + "/" + apisArtifactDir)
 	files, err := ioutil.ReadDir((apisDirName))
 	if err != nil {
 		loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
@@ -109,88 +120,132 @@ func ProcessMountedAPIProjects() (artifactsMap map[string]model.ProjectAPI, err
 	artifactsMap = make(map[string]model.ProjectAPI)
 
 	for _, apiProjectFile := range files {
-		// Ignore processing dot files and directories
-		if strings.HasPrefix(apiProjectFile.Name(), ".") {
+		if !isAPIProjectFile(apiProjectFile) {
 			continue
 		}
+		apiProject, err := processMountedAPIProject(apisDirName, apiProjectFile)
+		if err != nil {
+			continue
+		}
+		artifactsMap[apiProjectFile.Name()] = apiProject
+	}
+	return artifactsMap, nil
+}
 
-		if apiProjectFile.IsDir() {
-			apiProject := model.ProjectAPI{
-				EndpointCerts: make(map[string]string),
-				UpstreamCerts: make(map[string][]byte),
-				Policies:      make(map[string]model.PolicyContainer),
-			}
-			err = filepath.Walk(filepath.FromSlash(apisDirName+"/"+apiProjectFile.Name()), func(path string, info os.FileInfo, err error) error {
-
-				if !info.IsDir() {
-					fileContent, err := ioutil.ReadFile(path)
-					if err != nil {
-						return err
-					}
-					return processFileInsideProject(&apiProject, fileContent, path)
-				}
-				return nil
-			})
-			if err != nil {
-				loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
-					Message:   fmt.Sprintf("Error while processing api artifact - %s during startup : %s", apiProjectFile.Name(), err.Error()),
-					Severity:  logging.MAJOR,
-					ErrorCode: 1207,
-				})
-				continue
-			}
-			err = apiProject.APIYaml.ValidateAPIType()
-			if err != nil {
-				loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
-					Message:   fmt.Sprintf("Error while validating the API type - %s during startup : %s", apiProjectFile.Name(), err.Error()),
-					Severity:  logging.MAJOR,
-					ErrorCode: 1208,
-				})
-				continue
-			}
+// isAPIProjectFile checks whether the given file inside the api artifacts directory is an API project,
+// which is either a directory or a zip file. Dot files and directories are ignored.
+func isAPIProjectFile(apiProjectFile os.FileInfo) bool {
+	if strings.HasPrefix(apiProjectFile.Name(), ".") {
+		return false
+	}
+	return apiProjectFile.IsDir() || strings.HasSuffix(apiProjectFile.Name(), zipExt)
+}
 
-			overrideValue := true
-			apiProject, err = validateAndUpdateXds(apiProject, &overrideValue)
+// processMountedAPIProject reads the API project (directory or zip file) located within the api artifacts
+// directory and applies it.
+func processMountedAPIProject(apisDirName string, apiProjectFile os.FileInfo) (model.ProjectAPI, error) {
+	if apiProjectFile.IsDir() {
+		apiProject := model.ProjectAPI{
+			EndpointCerts:       make(map[string]string),
+			EndpointClientCerts: make(map[string]model.EndpointCertificate),
+			UpstreamCerts:       make(map[string][]byte),
+			UpstreamClientKeys:  make(map[string][]byte),
+			Policies:            make(map[string]model.PolicyContainer),
+		}
+		err := filepath.Walk(filepath.FromSlash(apisDirName+"/"+apiProjectFile.Name()), func(path string, info os.FileInfo, err error) error {
 			if err != nil {
-				loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
-					Message:   fmt.Sprintf("Error while processing(validate and update xds) api artifact - %s during startup : %v", apiProjectFile.Name(), err.Error()),
-					Severity:  logging.MAJOR,
-					ErrorCode: 1209,
-				})
-				continue
+				return err
 			}
-			artifactsMap[apiProjectFile.Name()] = apiProject
-			continue
-		} else if !strings.HasSuffix(apiProjectFile.Name(), zipExt) {
-			continue
+			if !info.IsDir() {
+				fileContent, err := ioutil.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				return processFileInsideProject(&apiProject, fileContent, path)
+			}
+			return nil
+		})
+		if err != nil {
+			loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Error while processing api artifact - %s : %s", apiProjectFile.Name(), err.Error()),
+				Severity:  logging.MAJOR,
+				ErrorCode: 1207,
+			})
+			return apiProject, err
 		}
-		data, err := ioutil.ReadFile(filepath.FromSlash(apisDirName + "/" + apiProjectFile.Name()))
+		err = apiProject.APIYaml.ValidateAPIType()
 		if err != nil {
 			loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error while reading api artifact - %s during startup : %v", apiProjectFile.Name(), err.Error()),
+				Message:   fmt.Sprintf("Error while validating the API type - %s : %s", apiProjectFile.Name(), err.Error()),
 				Severity:  logging.MAJOR,
-				ErrorCode: 1210,
+				ErrorCode: 1208,
 			})
-			continue
+			return apiProject, err
 		}
 
-		// logger.LoggerMgw.Debugf("API artifact  - %s is read successfully.", file.Name())
-		overrideAPIParam := true
-		apiProject, err := ApplyAPIProjectInStandaloneMode(data, &overrideAPIParam)
+		overrideValue := true
+		apiProject, err = validateAndUpdateXds(apiProject, &overrideValue)
 		if err != nil {
 			loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error while processing(apply api project in standalone mode) api artifact - %s during startup : %v", apiProjectFile.Name(), err.Error()),
+				Message:   fmt.Sprintf("Error while processing(validate and update xds) api artifact - %s : %v", apiProjectFile.Name(), err.Error()),
 				Severity:  logging.MAJOR,
-				ErrorCode: 1211,
+				ErrorCode: 1209,
 			})
-			continue
 		}
-		artifactsMap[apiProjectFile.Name()] = apiProject
+		return apiProject, err
 	}
-	return artifactsMap, nil
+
+	data, err := ioutil.ReadFile(filepath.FromSlash(apisDirName + "/" + apiProjectFile.Name()))
+	if err != nil {
+		loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error while reading api artifact - %s : %v", apiProjectFile.Name(), err.Error()),
+			Severity:  logging.MAJOR,
+			ErrorCode: 1210,
+		})
+		return model.ProjectAPI{}, err
+	}
+
+	overrideAPIParam := true
+	apiProject, err := ApplyAPIProjectInStandaloneMode(data, &overrideAPIParam)
+	if err != nil {
+		loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error while processing(apply api project in standalone mode) api artifact - %s : %v", apiProjectFile.Name(), err.Error()),
+			Severity:  logging.MAJOR,
+			ErrorCode: 1211,
+		})
+	}
+	return apiProject, err
 }
 
 func validateAndUpdateXds(apiProject model.ProjectAPI, override *bool) (updatedAPIProject model.ProjectAPI, err error) {
+	setDefaultDeployment(&apiProject)
+	if err = deployAPIProject(xdsAPIDeployer{}, apiProject, override != nil && *override); err != nil {
+		return updatedAPIProject, err
+	}
+	return apiProject, nil
+}
+
+// apiDeployer applies API projects to the xds caches, either directly or within a batch.
+type apiDeployer interface {
+	IsAPIExist(vhost, uuid, apiName, apiVersion, organizationID string) bool
+	UpdateAPI(vhost string, apiProject model.ProjectAPI, environments []string) error
+}
+
+// xdsAPIDeployer applies the API projects to the xds caches directly.
+type xdsAPIDeployer struct{}
+
+func (xdsAPIDeployer) IsAPIExist(vhost, uuid, apiName, apiVersion, organizationID string) bool {
+	return xds.IsAPIExist(vhost, uuid, apiName, apiVersion, organizationID)
+}
+
+func (xdsAPIDeployer) UpdateAPI(vhost string, apiProject model.ProjectAPI, environments []string) error {
+	_, err := xds.UpdateAPI(vhost, apiProject, environments)
+	return err
+}
+
+// deployAPIProject deploys the API project to the vhosts and environments of its deployments. The deployment
+// is rejected if the API already exists in at least one of the vhosts, unless the API is overridden.
+func deployAPIProject(deployer apiDeployer, apiProject model.ProjectAPI, override bool) (err error) {
 	apiYaml := apiProject.APIYaml.Data
 
 	// handle panic
@@ -201,56 +256,41 @@ func validateAndUpdateXds(apiProject model.ProjectAPI, override *bool) (updatedA
 		}
 	}()
 
-	var overrideValue bool
-	if override == nil {
-		overrideValue = false
-	} else {
-		overrideValue = *override
-	}
-
-	// when deployment-environments is missing in the API Project, definition we deploy to default
-	// environment
-	if apiProject.Deployments == nil {
-		vhost, _, _ := config.GetDefaultVhost(config.DefaultGatewayName)
-		deployment := model.Deployment{
-			DisplayOnDevportal:    true,
-			DeploymentEnvironment: config.DefaultGatewayName,
-			DeploymentVhost:       vhost,
-		}
-		apiProject.Deployments = []model.Deployment{deployment}
-	}
-
-	if !overrideValue {
-		// if the API already exists in at least one of the vhosts, break deployment of the API
-		exists := false
+	if !override {
 		for _, deployment := range apiProject.Deployments {
-			if xds.IsAPIExist(deployment.DeploymentVhost, apiYaml.ID, apiYaml.Name, apiYaml.Version, apiYaml.OrganizationID) {
-				exists = true
-				break
+			if deployer.IsAPIExist(deployment.DeploymentVhost, apiYaml.ID, apiYaml.Name, apiYaml.Version,
+				apiYaml.OrganizationID) {
+				loggers.LoggerAPI.Infof("Error creating new API. API %v:%v already exists.",
+					apiYaml.Name, apiYaml.Version)
+				return errors.New(constants.AlreadyExists)
 			}
 		}
-
-		if exists {
-			loggers.LoggerAPI.Infof("Error creating new API. API %v:%v already exists.",
-				apiYaml.Name, apiYaml.Version)
-			return updatedAPIProject, errors.New(constants.AlreadyExists)
-		}
 	}
 	vhostToEnvsMap := make(map[string][]string)
 	for _, environment := range apiProject.Deployments {
 		vhostToEnvsMap[environment.DeploymentVhost] =
 			append(vhostToEnvsMap[environment.DeploymentVhost], environment.DeploymentEnvironment)
 	}
-
-	// Updating cache one API by one API, if one API failed to update cache continue with others.
 	for vhost, environments := range vhostToEnvsMap {
-		_, err = xds.UpdateAPI(vhost, apiProject, environments)
-		if err != nil {
-			return
+		if err = deployer.UpdateAPI(vhost, apiProject, environments); err != nil {
+			return err
 		}
 	}
-	updatedAPIProject = apiProject
-	return updatedAPIProject, nil
+	return nil
+}
+
+// setDefaultDeployment sets the default environment as the deployment of the API project,
+// when deployment-environments is missing in the API Project.
+func setDefaultDeployment(apiProject *model.ProjectAPI) {
+	if apiProject.Deployments == nil {
+		vhost, _, _ := config.GetDefaultVhost(config.DefaultGatewayName)
+		deployment := model.Deployment{
+			DisplayOnDevportal:    true,
+			DeploymentEnvironment: config.DefaultGatewayName,
+			DeploymentVhost:       vhost,
+		}
+		apiProject.Deployments = []model.Deployment{deployment}
+	}
 }
 
 // ApplyAPIProjectFromAPIM accepts an apictl project (as a byte array), list of vhosts with respective environments
@@ -315,6 +355,9 @@ func ApplyAPIProjectFromAPIM(
 		// first update the API for vhost
 		deployedRevision, err := xds.UpdateAPI(vhost, apiProject, allEnvironments)
 		if err != nil {
+			notifier.SendRevisionDeployFailure([]*notifier.FailedAPIRevision{
+				notifier.UpdateFailedRevisions(apiYaml.ID, apiYaml.RevisionID, environments, vhost, err.Error()),
+			})
 			return deployedRevisionList, fmt.Errorf("%v:%v with UUID \"%v\"", apiYaml.Name, apiYaml.Version, apiYaml.ID)
 		}
 		if deployedRevision != nil {
@@ -346,16 +389,59 @@ func ApplyAPIProjectInStandaloneMode(payload []byte, override *bool) (apiProject
 }
 
 // ListApis calls the ListApis method in xds_server.go
-func ListApis(query *string, limit *int64, organizationID string) *apiModel.APIMeta {
-	var apiType string
-	if query != nil {
-		queryPair := strings.Split(*query, ":")
-		if queryPair[0] == apiTypeFilterKey {
-			apiType = strings.ToUpper(queryPair[1])
-			return xds.ListApis(apiType, organizationID, limit)
+func ListApis(params api_collection.GetApisParams, organizationID string) *apiModel.APIMeta {
+	query := xds.APIListQuery{
+		Limit:          params.Limit,
+		SortDescending: params.SortOrder != nil && *params.SortOrder == sortOrderDescending,
+	}
+	if params.Query != nil {
+		queryPair := strings.Split(*params.Query, ":")
+		if queryPair[0] == apiTypeFilterKey && len(queryPair) > 1 {
+			query.APIType = strings.ToUpper(queryPair[1])
 		}
 	}
-	return xds.ListApis("", organizationID, limit)
+	if params.Offset != nil {
+		query.Offset = *params.Offset
+	}
+	if params.SortBy != nil {
+		query.SortBy = *params.SortBy
+	}
+	if params.Name != nil {
+		query.Name = *params.Name
+	}
+	if params.Context != nil {
+		query.Context = *params.Context
+	}
+	if params.Vhost != nil {
+		query.Vhost = *params.Vhost
+	}
+	if params.Environment != nil {
+		query.Environment = *params.Environment
+	}
+	if params.LifeCycleStatus != nil {
+		query.LifeCycleStatus = *params.LifeCycleStatus
+	}
+	if params.Organization != nil {
+		organizationID = *params.Organization
+	}
+	return xds.ListApis(query, organizationID)
+}
+
+// SimulateRoute calls the SimulateRoute method in route_simulator.go. The routes of the default gateway environment
+// are used if the environment is not provided.
+func SimulateRoute(request *apiModel.RouteSimulationRequest) (*apiModel.RouteSimulationResult, error) {
+	if request.Environment == "" {
+		request.Environment = config.DefaultGatewayName
+	}
+	result, err := xds.SimulateRoute(request.Environment, request)
+	if err != nil && err.Error() != constants.NotFound {
+		loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error occurred while simulating the route of the request %v %v : %v", *request.Method, *request.Path, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 1230,
+		})
+	}
+	return result, err
 }
 
 func readZipFile(zf *zip.File) ([]byte, error) {
@@ -366,3 +452,13 @@ func readZipFile(zf *zip.File) ([]byte, error) {
 	defer f.Close()
 	return ioutil.ReadAll(f)
 }
+
+// GetXdsStatus calls the GetXdsStatus method in xds_status.go. The nodes of all the gateway environments are returned
+// if the environment is not provided.
+func GetXdsStatus(environment *string) *apiModel.XdsStatus {
+	label := ""
+	if environment != nil {
+		label = *environment
+	}
+	return xds.GetXdsStatus(label)
+}
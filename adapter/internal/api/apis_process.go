@@ -45,10 +45,13 @@ const (
 	clientCertDir              string = "Client-certificates"
 	interceptorCertDir         string = "Endpoint-certificates/interceptors"
 	policiesDir                string = "Policies"
+	errorsDir                  string = "Errors"
 	policyDefFileExtension     string = ".gotmpl"
 	crtExtension               string = ".crt"
 	pemExtension               string = ".pem"
+	keyExtension               string = ".key"
 	apiTypeFilterKey           string = "type"
+	sortOrderDescending        string = "desc"
 	apiTypeYamlKey             string = "type"
 	lifeCycleStatus            string = "lifeCycleStatus"
 	securityScheme             string = "securityScheme"
@@ -134,9 +137,27 @@ func processFileInsideProject(apiProject *model.ProjectAPI, fileContent []byte,
 				return err
 			} else if endpointCertificates != nil && len(endpointCertificates.Data) > 0 {
 				for _, val := range endpointCertificates.Data {
-					apiProject.EndpointCerts[val.Endpoint] = val.Certificate
+					if val.Certificate != "" {
+						apiProject.EndpointCerts[val.Endpoint] = val.Certificate
+					}
+					if val.ClientCertificate != "" || val.ClientKey != "" {
+						apiProject.EndpointClientCerts[val.Endpoint] = val
+					}
 				}
 			}
+		} else if strings.HasSuffix(fileName, keyExtension) {
+			if !tlsutils.IsPrivateKey(fileContent) {
+				loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+					Message:   fmt.Sprintf("Provided client key: %v is not in the PEM file format. ", fileName),
+					Severity:  logging.MINOR,
+					ErrorCode: 1231,
+				})
+				return errors.New("client key Validation Error")
+			}
+			if fileNameArray := strings.Split(fileName, string(os.PathSeparator)); len(fileNameArray) > 0 {
+				keyFileName := fileNameArray[len(fileNameArray)-1]
+				apiProject.UpstreamClientKeys[keyFileName] = fileContent
+			}
 		} else if strings.HasSuffix(fileName, crtExtension) || strings.HasSuffix(fileName, pemExtension) {
 			if !tlsutils.IsPublicCertificate(fileContent) {
 				loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
@@ -215,6 +236,21 @@ func processFileInsideProject(apiProject *model.ProjectAPI, fileContent []byte,
 		}
 		apiProject.APIYaml = apiYaml
 
+		// Error response templates
+	} else if strings.Contains(fileName, errorsDir+string(os.PathSeparator)) &&
+		(strings.HasSuffix(fileName, yamlExt) || strings.HasSuffix(fileName, jsonExt)) {
+		errorResponses := &model.ErrorResponsesDetails{}
+		if err := yaml.Unmarshal(fileContent, errorResponses); err != nil {
+			loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Error parsing content of error response templates %q for the API %s:%s : %v",
+					fileName, apiProject.APIYaml.Data.Name, apiProject.APIYaml.Data.Version, err.Error()),
+				Severity:  logging.MINOR,
+				ErrorCode: 1225,
+			})
+			return err
+		}
+		apiProject.ErrorResponses = append(apiProject.ErrorResponses, errorResponses.Data...)
+
 		// API policies
 	} else if strings.Contains(fileName, policiesDir+string(os.PathSeparator)) { // handle "./Policy" dir
 		// handle policy spec and def
@@ -0,0 +1,270 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wso2/product-microgateway/adapter/config"
+	xds "github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
+	"github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
+)
+
+// WatchMountedAPIProjects watches the api artifacts directory and applies the changes of the API projects
+// located within the directory. The artifactsMap contains the projects applied at the startup, mapped by
+// the name of the project directory or zip file.
+//
+// The changes are applied after the directory is not modified for the configured debounce interval, so that
+// a project copied file by file is applied once. Only the modified projects are redeployed and the APIs of
+// the removed projects are undeployed. A change to a dot file or directory (eg: the ..data symlink of a
+// kubernetes ConfigMap volume) causes all the projects to be reapplied.
+func WatchMountedAPIProjects(artifactsMap map[string]model.ProjectAPI) error {
+	conf, _ := config.ReadConfigs()
+	apisDirName := filepath.FromSlash(conf.Adapter.ArtifactsDirectory + "/" + apisArtifactDir)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err = addWatchRecursively(watcher, apisDirName); err != nil {
+		watcher.Close()
+		return err
+	}
+	debounceInterval := time.Duration(conf.Adapter.ArtifactsWatcher.DebounceIntervalInMillis) * time.Millisecond
+	loggers.LoggerAPI.Infof("Watching the api artifacts directory %v for changes", apisDirName)
+	go watchAPIProjectChanges(watcher, apisDirName, debounceInterval, artifactsMap)
+	return nil
+}
+
+func watchAPIProjectChanges(watcher *fsnotify.Watcher, apisDirName string, debounceInterval time.Duration,
+	artifactsMap map[string]model.ProjectAPI) {
+	defer watcher.Close()
+	changedProjects := make(map[string]bool)
+	debounceTimer := time.NewTimer(debounceInterval)
+	debounceTimer.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			projectName := getAPIProjectName(apisDirName, event.Name)
+			if projectName == "" {
+				continue
+			}
+			loggers.LoggerAPI.Debugf("Api artifacts directory change detected : %v", event)
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				// Sub directories need to be watched explicitly, as the watcher is not recursive.
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err = addWatchRecursively(watcher, event.Name); err != nil {
+						loggers.LoggerAPI.Errorf("Error while watching the directory %v : %v", event.Name, err.Error())
+					}
+				}
+			}
+			changedProjects[projectName] = true
+			debounceTimer.Reset(debounceInterval)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Error while watching the api artifacts directory : %v", err.Error()),
+				Severity:  logging.MINOR,
+				ErrorCode: 1226,
+			})
+		case <-debounceTimer.C:
+			applyAPIProjectChanges(apisDirName, changedProjects, artifactsMap)
+			changedProjects = make(map[string]bool)
+		}
+	}
+}
+
+// applyAPIProjectChanges redeploys the changed API projects which are available in the api artifacts directory
+// and undeploys the APIs of the changed projects which are no longer available.
+func applyAPIProjectChanges(apisDirName string, changedProjects map[string]bool, artifactsMap map[string]model.ProjectAPI) {
+	files, err := os.ReadDir(apisDirName)
+	if err != nil {
+		loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error while reading the api artifacts directory : %v", err.Error()),
+			Severity:  logging.MAJOR,
+			ErrorCode: 1227,
+		})
+		return
+	}
+	reapplyAll := false
+	for projectName := range changedProjects {
+		if strings.HasPrefix(projectName, ".") {
+			reapplyAll = true
+			break
+		}
+	}
+
+	availableProjects := make(map[string]os.FileInfo)
+	for _, file := range files {
+		// Stat is used instead of the dir entry info to follow the symlinks of the mounted volumes.
+		info, err := os.Stat(filepath.Join(apisDirName, file.Name()))
+		if err != nil || !isAPIProjectFile(info) {
+			continue
+		}
+		availableProjects[file.Name()] = info
+	}
+
+	// The stale deployments are undeployed after all the projects are applied, as the API of a project may be
+	// owned by another project now (eg: a renamed project file).
+	var staleProjects []staleProject
+	for projectName, info := range availableProjects {
+		if !reapplyAll && !changedProjects[projectName] {
+			continue
+		}
+		loggers.LoggerAPI.Infof("Applying the changes of the api artifact %v", projectName)
+		apiProject, err := processMountedAPIProject(apisDirName, info)
+		if err != nil {
+			// The previously deployed API remains until the artifact is fixed.
+			continue
+		}
+		if oldProject, found := artifactsMap[projectName]; found {
+			staleProjects = append(staleProjects, staleProject{oldProject, getStaleDeployments(oldProject, apiProject)})
+		}
+		artifactsMap[projectName] = apiProject
+	}
+
+	for projectName, apiProject := range artifactsMap {
+		if _, found := availableProjects[projectName]; found {
+			continue
+		}
+		loggers.LoggerAPI.Infof("Undeploying the API %v:%v as the api artifact %v is removed",
+			apiProject.APIYaml.Data.Name, apiProject.APIYaml.Data.Version, projectName)
+		staleProjects = append(staleProjects, staleProject{apiProject, apiProject.Deployments})
+		delete(artifactsMap, projectName)
+	}
+
+	for _, stale := range staleProjects {
+		undeployAPI(stale.apiProject, getUnownedDeployments(stale.apiProject, stale.deployments, artifactsMap))
+	}
+}
+
+// staleProject is an API project along with the deployments of it which are no longer applicable.
+type staleProject struct {
+	apiProject  model.ProjectAPI
+	deployments []model.Deployment
+}
+
+// getUnownedDeployments returns the given deployments of the API project which are not owned by any of the
+// current projects, ie: no current project deploys the same API (organization, name and version) to the
+// same vhost and environment.
+func getUnownedDeployments(apiProject model.ProjectAPI, deployments []model.Deployment,
+	artifactsMap map[string]model.ProjectAPI) []model.Deployment {
+	apiYaml := apiProject.APIYaml.Data
+	ownedDeployments := make(map[model.Deployment]bool)
+	for _, currentProject := range artifactsMap {
+		currentAPI := currentProject.APIYaml.Data
+		if currentAPI.Name != apiYaml.Name || currentAPI.Version != apiYaml.Version ||
+			currentAPI.OrganizationID != apiYaml.OrganizationID {
+			continue
+		}
+		for _, deployment := range currentProject.Deployments {
+			ownedDeployments[model.Deployment{DeploymentVhost: deployment.DeploymentVhost,
+				DeploymentEnvironment: deployment.DeploymentEnvironment}] = true
+		}
+	}
+	var unownedDeployments []model.Deployment
+	for _, deployment := range deployments {
+		if !ownedDeployments[model.Deployment{DeploymentVhost: deployment.DeploymentVhost,
+			DeploymentEnvironment: deployment.DeploymentEnvironment}] {
+			unownedDeployments = append(unownedDeployments, deployment)
+		}
+	}
+	return unownedDeployments
+}
+
+// undeployAPI deletes the API of the project from the given deployments (vhosts and environments).
+func undeployAPI(apiProject model.ProjectAPI, deployments []model.Deployment) {
+	apiYaml := apiProject.APIYaml.Data
+	vhostToEnvsMap := make(map[string][]string)
+	for _, environment := range deployments {
+		vhostToEnvsMap[environment.DeploymentVhost] =
+			append(vhostToEnvsMap[environment.DeploymentVhost], environment.DeploymentEnvironment)
+	}
+	for vhost, environments := range vhostToEnvsMap {
+		if err := xds.DeleteAPIs(vhost, apiYaml.Name, apiYaml.Version, environments, apiYaml.OrganizationID); err != nil {
+			loggers.LoggerAPI.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Error while undeploying the API %v:%v from the vhost %v : %v", apiYaml.Name,
+					apiYaml.Version, vhost, err.Error()),
+				Severity:  logging.MAJOR,
+				ErrorCode: 1228,
+			})
+		}
+	}
+}
+
+// getStaleDeployments returns the deployments of the old project which are not overridden by the new project.
+// All the deployments are stale if the API name, version or organization is changed, otherwise the deployments
+// in the vhost and environment pairs the API is no longer deployed to.
+func getStaleDeployments(oldProject, newProject model.ProjectAPI) []model.Deployment {
+	oldAPI, newAPI := oldProject.APIYaml.Data, newProject.APIYaml.Data
+	if oldAPI.Name != newAPI.Name || oldAPI.Version != newAPI.Version || oldAPI.OrganizationID != newAPI.OrganizationID {
+		return oldProject.Deployments
+	}
+	// An API removed from an environment should be undeployed even if it remains in the same vhost.
+	newDeployments := make(map[model.Deployment]bool)
+	for _, deployment := range newProject.Deployments {
+		newDeployments[model.Deployment{DeploymentVhost: deployment.DeploymentVhost,
+			DeploymentEnvironment: deployment.DeploymentEnvironment}] = true
+	}
+	var staleDeployments []model.Deployment
+	for _, deployment := range oldProject.Deployments {
+		if !newDeployments[model.Deployment{DeploymentVhost: deployment.DeploymentVhost,
+			DeploymentEnvironment: deployment.DeploymentEnvironment}] {
+			staleDeployments = append(staleDeployments, deployment)
+		}
+	}
+	return staleDeployments
+}
+
+// getAPIProjectName returns the name of the API project (top level directory or zip file inside the api
+// artifacts directory) the given path belongs to. An empty string is returned for the artifacts directory.
+func getAPIProjectName(apisDirName, path string) string {
+	relativePath, err := filepath.Rel(apisDirName, path)
+	if err != nil {
+		return ""
+	}
+	relativePath = filepath.ToSlash(relativePath)
+	if relativePath == "." || relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+		return ""
+	}
+	return strings.Split(relativePath, "/")[0]
+}
+
+// addWatchRecursively adds the given directory and all the sub directories to the watcher.
+func addWatchRecursively(watcher *fsnotify.Watcher, dirName string) error {
+	return filepath.Walk(dirName, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
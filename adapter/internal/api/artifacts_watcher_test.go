@@ -0,0 +1,136 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/product-microgateway/adapter/config"
+	xds "github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+)
+
+func TestGetStaleDeployments(t *testing.T) {
+	prodDefault := model.Deployment{DeploymentVhost: "localhost", DeploymentEnvironment: "Default"}
+	prodSecondary := model.Deployment{DeploymentVhost: "localhost", DeploymentEnvironment: "Secondary"}
+	sandDefault := model.Deployment{DeploymentVhost: "sand.localhost", DeploymentEnvironment: "Default"}
+
+	tests := []struct {
+		oldDeployments   []model.Deployment
+		newDeployments   []model.Deployment
+		newVersion       string
+		staleDeployments []model.Deployment
+		message          string
+	}{
+		{
+			oldDeployments: []model.Deployment{prodDefault, sandDefault},
+			newDeployments: []model.Deployment{prodDefault, sandDefault},
+			message:        `Same deployments`,
+		},
+		{
+			oldDeployments:   []model.Deployment{prodDefault, sandDefault},
+			newDeployments:   []model.Deployment{prodDefault},
+			staleDeployments: []model.Deployment{sandDefault},
+			message:          `Removed from a vhost`,
+		},
+		{
+			oldDeployments:   []model.Deployment{prodDefault, prodSecondary},
+			newDeployments:   []model.Deployment{prodDefault},
+			staleDeployments: []model.Deployment{prodSecondary},
+			message:          `Removed from an environment of the same vhost`,
+		},
+		{
+			oldDeployments: []model.Deployment{prodDefault},
+			newDeployments: []model.Deployment{{DisplayOnDevportal: true, DeploymentVhost: "localhost",
+				DeploymentEnvironment: "Default"}},
+			message: `Only the devportal visibility is changed`,
+		},
+		{
+			oldDeployments:   []model.Deployment{prodDefault, sandDefault},
+			newDeployments:   []model.Deployment{prodDefault, sandDefault},
+			newVersion:       "2.0.0",
+			staleDeployments: []model.Deployment{prodDefault, sandDefault},
+			message:          `Version is changed`,
+		},
+	}
+
+	for _, test := range tests {
+		oldProject, newProject := model.ProjectAPI{}, model.ProjectAPI{}
+		oldProject.APIYaml.Data.Name, oldProject.APIYaml.Data.Version = "PetStore", "1.0.0"
+		newProject.APIYaml.Data.Name, newProject.APIYaml.Data.Version = "PetStore", "1.0.0"
+		if test.newVersion != "" {
+			newProject.APIYaml.Data.Version = test.newVersion
+		}
+		oldProject.Deployments, newProject.Deployments = test.oldDeployments, test.newDeployments
+		assert.Equal(t, test.staleDeployments, getStaleDeployments(oldProject, newProject), test.message)
+	}
+}
+
+func TestApplyAPIProjectChangesWithRenamedProject(t *testing.T) {
+	projectDir := config.GetMgwHome() + "/../adapter/test-resources/render/artifacts/apis/PetStore"
+	artifactsDir := t.TempDir()
+	apisDirName := filepath.Join(artifactsDir, apisArtifactDir)
+	err := copyDirectory(projectDir, filepath.Join(apisDirName, "PetStore"))
+	assert.Nil(t, err, "Error while copying the API project.")
+
+	artifactsMap, err := ProcessAPIProjectsInArtifactsDirectory(artifactsDir)
+	assert.Nil(t, err, "Error while processing the API projects.")
+	assert.Contains(t, artifactsMap, "PetStore")
+	apiYaml := artifactsMap["PetStore"].APIYaml.Data
+	deployment := artifactsMap["PetStore"].Deployments[0]
+	assert.True(t, xds.IsAPIExist(deployment.DeploymentVhost, apiYaml.ID, apiYaml.Name, apiYaml.Version,
+		apiYaml.OrganizationID), "The API should be deployed.")
+
+	err = os.Rename(filepath.Join(apisDirName, "PetStore"), filepath.Join(apisDirName, "PetStoreRenamed"))
+	assert.Nil(t, err, "Error while renaming the API project.")
+	applyAPIProjectChanges(apisDirName, map[string]bool{"PetStore": true, "PetStoreRenamed": true}, artifactsMap)
+	assert.NotContains(t, artifactsMap, "PetStore")
+	assert.Contains(t, artifactsMap, "PetStoreRenamed")
+	assert.True(t, xds.IsAPIExist(deployment.DeploymentVhost, apiYaml.ID, apiYaml.Name, apiYaml.Version,
+		apiYaml.OrganizationID), "The API of the renamed project should remain deployed.")
+
+	err = os.RemoveAll(filepath.Join(apisDirName, "PetStoreRenamed"))
+	assert.Nil(t, err, "Error while removing the API project.")
+	applyAPIProjectChanges(apisDirName, map[string]bool{"PetStoreRenamed": true}, artifactsMap)
+	assert.Empty(t, artifactsMap)
+	assert.False(t, xds.IsAPIExist(deployment.DeploymentVhost, apiYaml.ID, apiYaml.Name, apiYaml.Version,
+		apiYaml.OrganizationID), "The API of the removed project should be undeployed.")
+}
+
+func copyDirectory(source, destination string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(destination, relativePath), os.ModePerm)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(destination, relativePath), content, info.Mode())
+	})
+}
@@ -41,9 +41,18 @@ type APIMetaListItem struct {
 	// context
 	Context string `json:"context,omitempty"`
 
+	// Time the API was last deployed (RFC 3339)
+	DeployedTime string `json:"deployedTime,omitempty"`
+
 	// gateway envs
 	GatewayEnvs []string `json:"gateway-envs"`
 
+	// revision Id
+	RevisionID int64 `json:"revisionId,omitempty"`
+
+	// uuid
+	UUID string `json:"uuid,omitempty"`
+
 	// version
 	Version string `json:"version,omitempty"`
 
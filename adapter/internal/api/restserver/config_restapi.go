@@ -18,15 +18,18 @@
 package restserver
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 
 	// enable profiling endpoints
 	_ "net/http/pprof"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/loads"
@@ -41,9 +44,12 @@ import (
 	apiServer "github.com/wso2/product-microgateway/adapter/internal/api"
 	"github.com/wso2/product-microgateway/adapter/internal/api/models"
 	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations"
+	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/api_bulk"
 	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/api_collection"
 	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/api_individual"
 	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/authorization"
+	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/route_simulation"
+	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/xds_status"
 	"github.com/wso2/product-microgateway/adapter/internal/auth"
 	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
 	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
@@ -52,6 +58,9 @@ import (
 
 var (
 	mgwConfig *config.Config
+	// restServer is the running REST API server, which is stopped by the adapter on shutdown
+	restServer      *http.Server
+	restServerMutex sync.Mutex
 )
 
 //go:generate swagger generate server --target ../../api --name Restapi --spec ../../../../resources/adminAPI.yaml --server-package restserver --principal models.Principal
@@ -165,7 +174,7 @@ func configureAPI(api *operations.RestapiAPI) http.Handler {
 	api.APICollectionGetApisHandler = api_collection.GetApisHandlerFunc(func(
 		params api_collection.GetApisParams, principal *models.Principal) middleware.Responder {
 
-		return api_collection.NewGetApisOK().WithPayload(apiServer.ListApis(params.Query, params.Limit, tenantDomain))
+		return api_collection.NewGetApisOK().WithPayload(apiServer.ListApis(params, tenantDomain))
 	})
 	api.APIIndividualPostApisHandler = api_individual.PostApisHandlerFunc(func(
 		params api_individual.PostApisParams, principal *models.Principal) middleware.Responder {
@@ -198,6 +207,99 @@ func configureAPI(api *operations.RestapiAPI) http.Handler {
 		return api_individual.NewPostApisOK()
 	})
 
+	// Handlers for /apis/bulk-deploy and /apis/bulk-undeploy
+	api.APIBulkPostApisBulkDeployHandler = api_bulk.PostApisBulkDeployHandlerFunc(func(
+		params api_bulk.PostApisBulkDeployParams, principal *models.Principal) middleware.Responder {
+
+		conf, _ := config.ReadConfigs()
+
+		if conf.ControlPlane.Enabled {
+			errCode := int64(400)
+			errMsg := "When control plane is enabled, APIs cannot be directly deployed to the adapter. Using apictl, still you can import APIs to APIM instead."
+			err := models.Error{
+				Code:    &errCode,
+				Message: &errMsg,
+			}
+			logger.LoggerAPI.Info(errMsg)
+			return api_bulk.NewPostApisBulkDeployBadRequest().WithPayload(&err)
+		}
+
+		projectFiles, err := readAPIProjectFiles(params.HTTPRequest)
+		if err != nil {
+			errCode := int64(400)
+			errMsg := "Error while reading the API project files. " + err.Error()
+			return api_bulk.NewPostApisBulkDeployBadRequest().WithPayload(&models.Error{Code: &errCode, Message: &errMsg})
+		}
+		apiErrors := apiServer.ApplyAPIProjectsInStandaloneMode(projectFiles, params.Override)
+		if len(apiErrors) == 0 {
+			return api_bulk.NewPostApisBulkDeployOK()
+		}
+		statusCode, payload := getBulkOperationError(apiErrors, "None of the APIs are deployed.")
+		switch statusCode {
+		case http.StatusBadRequest:
+			return api_bulk.NewPostApisBulkDeployBadRequest().WithPayload(payload)
+		case http.StatusConflict:
+			return api_bulk.NewPostApisBulkDeployConflict().WithPayload(payload)
+		default:
+			return api_bulk.NewPostApisBulkDeployInternalServerError().WithPayload(payload)
+		}
+	})
+	api.APIBulkPostApisBulkUndeployHandler = api_bulk.PostApisBulkUndeployHandlerFunc(func(
+		params api_bulk.PostApisBulkUndeployParams, principal *models.Principal) middleware.Responder {
+
+		conf, _ := config.ReadConfigs()
+
+		if conf.ControlPlane.Enabled {
+			errCode := int64(400)
+			errMsg := "When control plane is enabled, APIs cannot be directly undeployed from the adapter. Using apictl, still you can undeploy an API in APIM instead."
+			err := models.Error{
+				Code:        &errCode,
+				Description: "Bad request",
+				Message:     &errMsg,
+			}
+			logger.LoggerAPI.Info(errMsg)
+			return api_bulk.NewPostApisBulkUndeployBadRequest().WithPayload(&err)
+		}
+
+		apiErrors := apiServer.UndeployAPIsInStandaloneMode(params.Apis.Apis, tenantDomain)
+		if len(apiErrors) == 0 {
+			return api_bulk.NewPostApisBulkUndeployOK()
+		}
+		statusCode, payload := getBulkOperationError(apiErrors, "None of the APIs are undeployed.")
+		switch statusCode {
+		case http.StatusBadRequest:
+			return api_bulk.NewPostApisBulkUndeployBadRequest().WithPayload(payload)
+		case http.StatusNotFound:
+			return api_bulk.NewPostApisBulkUndeployNotFound().WithPayload(payload)
+		default:
+			return api_bulk.NewPostApisBulkUndeployInternalServerError().WithPayload(payload)
+		}
+	})
+
+	api.RouteSimulationPostRoutesSimulateHandler = route_simulation.PostRoutesSimulateHandlerFunc(func(
+		params route_simulation.PostRoutesSimulateParams, principal *models.Principal) middleware.Responder {
+
+		result, err := apiServer.SimulateRoute(params.Request)
+		if err != nil {
+			if err.Error() == constants.NotFound {
+				errCode := int64(404)
+				errMsg := fmt.Sprintf("No routes found for the environment %v", params.Request.Environment)
+				return route_simulation.NewPostRoutesSimulateNotFound().WithPayload(&models.Error{
+					Code:        &errCode,
+					Description: "Not found",
+					Message:     &errMsg,
+				})
+			}
+			return route_simulation.NewPostRoutesSimulateInternalServerError()
+		}
+		return route_simulation.NewPostRoutesSimulateOK().WithPayload(result)
+	})
+
+	api.XdsStatusGetXdsStatusHandler = xds_status.GetXdsStatusHandlerFunc(func(
+		params xds_status.GetXdsStatusParams, principal *models.Principal) middleware.Responder {
+		return xds_status.NewGetXdsStatusOK().WithPayload(apiServer.GetXdsStatus(params.Environment))
+	})
+
 	api.PreServerShutdown = func() {}
 
 	api.ServerShutdown = func() {}
@@ -205,6 +307,58 @@ func configureAPI(api *operations.RestapiAPI) http.Handler {
 	return setupGlobalMiddleware(api.Serve(setupMiddlewares))
 }
 
+// readAPIProjectFiles reads all the API project files (file parts named "file") of a multipart request.
+func readAPIProjectFiles(request *http.Request) ([]apiServer.APIProjectFile, error) {
+	if request.MultipartForm == nil {
+		return nil, errors.New(400, "no API project files found")
+	}
+	var projectFiles []apiServer.APIProjectFile
+	for _, fileHeader := range request.MultipartForm.File["file"] {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		projectFiles = append(projectFiles, apiServer.APIProjectFile{Name: fileHeader.Filename, Content: content})
+	}
+	return projectFiles, nil
+}
+
+// getBulkOperationError returns the status code and the error payload of a failed bulk operation. Invalid APIs
+// take precedence over conflicting and missing APIs, which take precedence over the other failures.
+func getBulkOperationError(apiErrors []*apiServer.BulkAPIError, message string) (int, *models.Error) {
+	statusCode := http.StatusInternalServerError
+	errorList := make([]*models.ErrorListItem, 0, len(apiErrors))
+	for _, apiError := range apiErrors {
+		apiStatusCode := http.StatusInternalServerError
+		switch {
+		case apiError.Invalid:
+			apiStatusCode = http.StatusBadRequest
+		case apiError.Err.Error() == constants.AlreadyExists,
+			strings.HasPrefix(apiError.Err.Error(), "An API exists with the same basepath"):
+			apiStatusCode = http.StatusConflict
+		case apiError.Err.Error() == constants.NotFound:
+			apiStatusCode = http.StatusNotFound
+		}
+		if statusCode == http.StatusInternalServerError || apiStatusCode == http.StatusBadRequest {
+			statusCode = apiStatusCode
+		}
+		code := strconv.Itoa(apiStatusCode)
+		errMsg := apiError.Error()
+		errorList = append(errorList, &models.ErrorListItem{Code: &code, Message: &errMsg})
+	}
+	errCode := int64(statusCode)
+	return statusCode, &models.Error{
+		Code:    &errCode,
+		Message: &message,
+		Error:   errorList,
+	}
+}
+
 // The TLS configuration before HTTPS server starts.
 func configureTLS(tlsConfig *tls.Config) {
 	publicKeyLocation, privateKeyLocation, _ := tlsutils.GetKeyLocations()
@@ -234,6 +388,8 @@ func setupGlobalMiddleware(handler http.Handler) http.Handler {
 }
 
 // StartRestServer starts the listener which is used to fetch the requests sent from apictl.
+// The http server is owned by the adapter instead of the generated server, as the generated server stops
+// serving on the termination signals, which are handled by the adapter to withdraw the readiness endpoint first.
 func StartRestServer(config *config.Config) {
 	mgwConfig = config
 	swaggerSpec, err := loads.Embedded(SwaggerJSON, FlatSwaggerJSON)
@@ -246,13 +402,7 @@ func StartRestServer(config *config.Config) {
 	}
 
 	api := operations.NewRestapiAPI(swaggerSpec)
-	server := NewServer(api)
-	defer server.Shutdown()
-
-	server.ConfigureAPI()
-	server.TLSHost = mgwConfig.Adapter.Server.Host
-	port, err := strconv.Atoi(mgwConfig.Adapter.Server.Port)
-	if err != nil {
+	if _, err := strconv.Atoi(mgwConfig.Adapter.Server.Port); err != nil {
 		logger.LoggerAPI.ErrorC(logging.ErrorDetails{
 			Message:   fmt.Sprintf("The provided port value for the REST Api Server :%v is not an integer. %v", mgwConfig.Adapter.Server.Port, err.Error()),
 			Severity:  logging.BLOCKER,
@@ -260,19 +410,20 @@ func StartRestServer(config *config.Config) {
 		})
 		return
 	}
-	server.TLSPort = port
-
-	// handle server interruption
-	go func() {
-		select {
-		case _ = <-server.interrupt:
-			logger.LoggerAPI.Info("Rest server is interrupted. Update health status of RestService")
-			health.RestService.SetStatus(false)
-		case _ = <-server.shutdown:
-			logger.LoggerAPI.Info("Rest server is shutdown. Update health status of RestService")
-			health.RestService.SetStatus(false)
-		}
-	}()
+	server := &http.Server{
+		Handler:   configureAPI(api),
+		TLSConfig: getTLSConfig(),
+	}
+	if len(server.TLSConfig.Certificates) == 0 {
+		logger.LoggerAPI.Fatal("No certificate was configured for the TLS of the REST API server")
+		return
+	}
+	listener, err := tls.Listen("tcp", net.JoinHostPort(mgwConfig.Adapter.Server.Host, mgwConfig.Adapter.Server.Port),
+		server.TLSConfig)
+	if err != nil {
+		logger.LoggerAPI.Fatal(err)
+		return
+	}
 
 	// expose new port for serving pprof based go profiling endpoints
 	go func() {
@@ -280,8 +431,59 @@ func StartRestServer(config *config.Config) {
 	}()
 
 	health.RestService.SetStatus(true)
-	if err := server.Serve(); err != nil {
+	logger.LoggerAPI.Infof("Serving restapi at https://%s", listener.Addr())
+	err = serveRestServer(server, listener)
+	logger.LoggerAPI.Info("Rest server is shutdown. Update health status of RestService")
+	health.RestService.SetStatus(false)
+	if err != nil {
 		logger.LoggerAPI.Fatal(err)
-		health.RestService.SetStatus(false)
 	}
 }
+
+// getTLSConfig returns the TLS configuration of the REST API server, which is the same as the configuration
+// of the generated server.
+func getTLSConfig() *tls.Config {
+	tlsConfig := &tls.Config{
+		CurvePreferences: []tls.CurveID{tls.CurveP256},
+		NextProtos:       []string{"h2", "http/1.1"},
+		MinVersion:       tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		},
+	}
+	configureTLS(tlsConfig)
+	return tlsConfig
+}
+
+// serveRestServer registers the server to be stopped by StopRestServer, and serves the requests accepted by the
+// listener until the server is stopped.
+func serveRestServer(server *http.Server, listener net.Listener) error {
+	restServerMutex.Lock()
+	restServer = server
+	restServerMutex.Unlock()
+	if err := server.Serve(listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// StopRestServer stops accepting new requests to the REST API server and waits until the ongoing requests are
+// completed, or the context is done.
+func StopRestServer(ctx context.Context) error {
+	restServerMutex.Lock()
+	server := restServer
+	restServerMutex.Unlock()
+	if server == nil {
+		return nil
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("rest server is not stopped : %v", err)
+	}
+	logger.LoggerAPI.Info("Rest server is stopped")
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package restserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopRestServer(t *testing.T) {
+	restServerMutex.Lock()
+	restServer = nil
+	restServerMutex.Unlock()
+	assert.Nil(t, StopRestServer(context.Background()), "Stopping a server which is not started should not fail.")
+
+	requestStarted, releaseRequest := make(chan struct{}), make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requestStarted)
+		<-releaseRequest
+		w.WriteHeader(http.StatusOK)
+	})}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error while listening : %v", err)
+	}
+	url := "http://" + listener.Addr().String()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- serveRestServer(server, listener)
+	}()
+
+	responseStatus := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responseStatus <- 0
+			return
+		}
+		resp.Body.Close()
+		responseStatus <- resp.StatusCode
+	}()
+	<-requestStarted
+
+	// The server is not stopped until the ongoing request is completed.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.NotNil(t, StopRestServer(ctx), "Server should not be stopped while a request is ongoing.")
+	select {
+	case err := <-serveErr:
+		assert.Nil(t, err, "Server should stop serving without an error.")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server should stop accepting new requests once it is stopped.")
+	}
+	_, err = http.Get(url)
+	assert.NotNil(t, err, "New requests should not be accepted after the server is stopped.")
+
+	close(releaseRequest)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, StopRestServer(ctx), "Server should be stopped once the ongoing request is completed.")
+	assert.Equal(t, http.StatusOK, <-responseStatus, "Ongoing request should be completed.")
+}
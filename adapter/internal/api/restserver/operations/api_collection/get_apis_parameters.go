@@ -48,6 +48,25 @@ type GetApisParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
 
+	/*Optional - Filter APIs whose context contains the given value (case insensitive)
+
+	  Max Length: 255
+	  In: query
+	*/
+	Context *string
+	/*Optional - Filter APIs deployed to the given gateway environment
+
+	  Max Length: 255
+	  In: query
+	*/
+	Environment *string
+	/*Optional - Filter APIs by the lifecycle status (eg: PUBLISHED, PROTOTYPED)
+
+	  Max Length: 50
+	  Pattern: ^[a-zA-Z_]*$
+	  In: query
+	*/
+	LifeCycleStatus *string
 	/*Number of APIs (APIMeta objects to return)
 
 	  Maximum: 1e+08
@@ -55,6 +74,25 @@ type GetApisParams struct {
 	  In: query
 	*/
 	Limit *int64
+	/*Optional - Filter APIs whose name contains the given value (case insensitive)
+
+	  Max Length: 255
+	  In: query
+	*/
+	Name *string
+	/*Number of APIs to skip before the returned APIs
+
+	  Maximum: 1e+08
+	  Minimum: 0
+	  In: query
+	*/
+	Offset *int64
+	/*Optional - Organization of the APIs. The organization the adapter is connected to is used by default
+
+	  Max Length: 255
+	  In: query
+	*/
+	Organization *string
 	/*Optional - Condition to filter APIs. Currently only filtering
 	by API type (HTTP or WebSocket) is supported.
 	"type:http" for HTTP type
@@ -65,6 +103,24 @@ type GetApisParams struct {
 	  In: query
 	*/
 	Query *string
+	/*Optional - Field to sort the APIs by. APIs are sorted by name, version and vhost by default
+
+	  Enum: [apiName version context]
+	  In: query
+	*/
+	SortBy *string
+	/*Optional - Order of the sorted APIs. Ascending by default
+
+	  Enum: [asc desc]
+	  In: query
+	*/
+	SortOrder *string
+	/*Optional - Filter APIs deployed to the given virtual host
+
+	  Max Length: 255
+	  In: query
+	*/
+	Vhost *string
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -78,21 +134,166 @@ func (o *GetApisParams) BindRequest(r *http.Request, route *middleware.MatchedRo
 
 	qs := runtime.Values(r.URL.Query())
 
+	qContext, qhkContext, _ := qs.GetOK("context")
+	if err := o.bindContext(qContext, qhkContext, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qEnvironment, qhkEnvironment, _ := qs.GetOK("environment")
+	if err := o.bindEnvironment(qEnvironment, qhkEnvironment, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qLifeCycleStatus, qhkLifeCycleStatus, _ := qs.GetOK("lifeCycleStatus")
+	if err := o.bindLifeCycleStatus(qLifeCycleStatus, qhkLifeCycleStatus, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qLimit, qhkLimit, _ := qs.GetOK("limit")
 	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
 		res = append(res, err)
 	}
 
+	qName, qhkName, _ := qs.GetOK("name")
+	if err := o.bindName(qName, qhkName, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qOffset, qhkOffset, _ := qs.GetOK("offset")
+	if err := o.bindOffset(qOffset, qhkOffset, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qOrganization, qhkOrganization, _ := qs.GetOK("organization")
+	if err := o.bindOrganization(qOrganization, qhkOrganization, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qQuery, qhkQuery, _ := qs.GetOK("query")
 	if err := o.bindQuery(qQuery, qhkQuery, route.Formats); err != nil {
 		res = append(res, err)
 	}
+
+	qSortBy, qhkSortBy, _ := qs.GetOK("sortBy")
+	if err := o.bindSortBy(qSortBy, qhkSortBy, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qSortOrder, qhkSortOrder, _ := qs.GetOK("sortOrder")
+	if err := o.bindSortOrder(qSortOrder, qhkSortOrder, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qVhost, qhkVhost, _ := qs.GetOK("vhost")
+	if err := o.bindVhost(qVhost, qhkVhost, route.Formats); err != nil {
+		res = append(res, err)
+	}
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+// bindContext binds and validates parameter Context from query.
+func (o *GetApisParams) bindContext(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.Context = &raw
+
+	if err := o.validateContext(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateContext carries on validations for parameter Context
+func (o *GetApisParams) validateContext(formats strfmt.Registry) error {
+
+	if err := validate.MaxLength("context", "query", *o.Context, 255); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindEnvironment binds and validates parameter Environment from query.
+func (o *GetApisParams) bindEnvironment(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.Environment = &raw
+
+	if err := o.validateEnvironment(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateEnvironment carries on validations for parameter Environment
+func (o *GetApisParams) validateEnvironment(formats strfmt.Registry) error {
+
+	if err := validate.MaxLength("environment", "query", *o.Environment, 255); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindLifeCycleStatus binds and validates parameter LifeCycleStatus from query.
+func (o *GetApisParams) bindLifeCycleStatus(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.LifeCycleStatus = &raw
+
+	if err := o.validateLifeCycleStatus(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateLifeCycleStatus carries on validations for parameter LifeCycleStatus
+func (o *GetApisParams) validateLifeCycleStatus(formats strfmt.Registry) error {
+
+	if err := validate.MaxLength("lifeCycleStatus", "query", *o.LifeCycleStatus, 50); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("lifeCycleStatus", "query", *o.LifeCycleStatus, `^[a-zA-Z_]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // bindLimit binds and validates parameter Limit from query.
 func (o *GetApisParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
@@ -134,6 +335,111 @@ func (o *GetApisParams) validateLimit(formats strfmt.Registry) error {
 	return nil
 }
 
+// bindName binds and validates parameter Name from query.
+func (o *GetApisParams) bindName(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.Name = &raw
+
+	if err := o.validateName(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateName carries on validations for parameter Name
+func (o *GetApisParams) validateName(formats strfmt.Registry) error {
+
+	if err := validate.MaxLength("name", "query", *o.Name, 255); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindOffset binds and validates parameter Offset from query.
+func (o *GetApisParams) bindOffset(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("offset", "query", "int64", raw)
+	}
+	o.Offset = &value
+
+	if err := o.validateOffset(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateOffset carries on validations for parameter Offset
+func (o *GetApisParams) validateOffset(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("offset", "query", *o.Offset, 0, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("offset", "query", *o.Offset, 1e+08, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindOrganization binds and validates parameter Organization from query.
+func (o *GetApisParams) bindOrganization(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.Organization = &raw
+
+	if err := o.validateOrganization(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateOrganization carries on validations for parameter Organization
+func (o *GetApisParams) validateOrganization(formats strfmt.Registry) error {
+
+	if err := validate.MaxLength("organization", "query", *o.Organization, 255); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // bindQuery binds and validates parameter Query from query.
 func (o *GetApisParams) bindQuery(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
@@ -169,3 +475,99 @@ func (o *GetApisParams) validateQuery(formats strfmt.Registry) error {
 
 	return nil
 }
+
+// bindSortBy binds and validates parameter SortBy from query.
+func (o *GetApisParams) bindSortBy(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.SortBy = &raw
+
+	if err := o.validateSortBy(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSortBy carries on validations for parameter SortBy
+func (o *GetApisParams) validateSortBy(formats strfmt.Registry) error {
+
+	if err := validate.EnumCase("sortBy", "query", *o.SortBy, []interface{}{"apiName", "version", "context"}, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindSortOrder binds and validates parameter SortOrder from query.
+func (o *GetApisParams) bindSortOrder(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.SortOrder = &raw
+
+	if err := o.validateSortOrder(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSortOrder carries on validations for parameter SortOrder
+func (o *GetApisParams) validateSortOrder(formats strfmt.Registry) error {
+
+	if err := validate.EnumCase("sortOrder", "query", *o.SortOrder, []interface{}{"asc", "desc"}, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindVhost binds and validates parameter Vhost from query.
+func (o *GetApisParams) bindVhost(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.Vhost = &raw
+
+	if err := o.validateVhost(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateVhost carries on validations for parameter Vhost
+func (o *GetApisParams) validateVhost(formats strfmt.Registry) error {
+
+	if err := validate.MaxLength("vhost", "query", *o.Vhost, 255); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -30,8 +30,17 @@ import (
 
 // GetApisURL generates an URL for the get apis operation
 type GetApisURL struct {
-	Limit *int64
-	Query *string
+	Context         *string
+	Environment     *string
+	LifeCycleStatus *string
+	Limit           *int64
+	Name            *string
+	Offset          *int64
+	Organization    *string
+	Query           *string
+	SortBy          *string
+	SortOrder       *string
+	Vhost           *string
 
 	_basePath string
 	// avoid unkeyed usage
@@ -67,6 +76,30 @@ func (o *GetApisURL) Build() (*url.URL, error) {
 
 	qs := make(url.Values)
 
+	var contextQ string
+	if o.Context != nil {
+		contextQ = *o.Context
+	}
+	if contextQ != "" {
+		qs.Set("context", contextQ)
+	}
+
+	var environmentQ string
+	if o.Environment != nil {
+		environmentQ = *o.Environment
+	}
+	if environmentQ != "" {
+		qs.Set("environment", environmentQ)
+	}
+
+	var lifeCycleStatusQ string
+	if o.LifeCycleStatus != nil {
+		lifeCycleStatusQ = *o.LifeCycleStatus
+	}
+	if lifeCycleStatusQ != "" {
+		qs.Set("lifeCycleStatus", lifeCycleStatusQ)
+	}
+
 	var limitQ string
 	if o.Limit != nil {
 		limitQ = swag.FormatInt64(*o.Limit)
@@ -75,6 +108,30 @@ func (o *GetApisURL) Build() (*url.URL, error) {
 		qs.Set("limit", limitQ)
 	}
 
+	var nameQ string
+	if o.Name != nil {
+		nameQ = *o.Name
+	}
+	if nameQ != "" {
+		qs.Set("name", nameQ)
+	}
+
+	var offsetQ string
+	if o.Offset != nil {
+		offsetQ = swag.FormatInt64(*o.Offset)
+	}
+	if offsetQ != "" {
+		qs.Set("offset", offsetQ)
+	}
+
+	var organizationQ string
+	if o.Organization != nil {
+		organizationQ = *o.Organization
+	}
+	if organizationQ != "" {
+		qs.Set("organization", organizationQ)
+	}
+
 	var queryQ string
 	if o.Query != nil {
 		queryQ = *o.Query
@@ -83,6 +140,30 @@ func (o *GetApisURL) Build() (*url.URL, error) {
 		qs.Set("query", queryQ)
 	}
 
+	var sortByQ string
+	if o.SortBy != nil {
+		sortByQ = *o.SortBy
+	}
+	if sortByQ != "" {
+		qs.Set("sortBy", sortByQ)
+	}
+
+	var sortOrderQ string
+	if o.SortOrder != nil {
+		sortOrderQ = *o.SortOrder
+	}
+	if sortOrderQ != "" {
+		qs.Set("sortOrder", sortOrderQ)
+	}
+
+	var vhostQ string
+	if o.Vhost != nil {
+		vhostQ = *o.Vhost
+	}
+	if vhostQ != "" {
+		qs.Set("vhost", vhostQ)
+	}
+
 	_result.RawQuery = qs.Encode()
 
 	return &_result, nil
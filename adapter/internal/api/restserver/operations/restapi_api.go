@@ -35,9 +35,12 @@ import (
 	"github.com/go-openapi/swag"
 
 	"github.com/wso2/product-microgateway/adapter/internal/api/models"
+	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/api_bulk"
 	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/api_collection"
 	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/api_individual"
 	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/authorization"
+	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/route_simulation"
+	"github.com/wso2/product-microgateway/adapter/internal/api/restserver/operations/xds_status"
 )
 
 // NewRestapiAPI creates a new Restapi instance
@@ -72,9 +75,21 @@ func NewRestapiAPI(spec *loads.Document) *RestapiAPI {
 		APIIndividualPostApisHandler: api_individual.PostApisHandlerFunc(func(params api_individual.PostApisParams, principal *models.Principal) middleware.Responder {
 			return middleware.NotImplemented("operation api_individual.PostApis has not yet been implemented")
 		}),
+		APIBulkPostApisBulkDeployHandler: api_bulk.PostApisBulkDeployHandlerFunc(func(params api_bulk.PostApisBulkDeployParams, principal *models.Principal) middleware.Responder {
+			return middleware.NotImplemented("operation api_bulk.PostApisBulkDeploy has not yet been implemented")
+		}),
+		APIBulkPostApisBulkUndeployHandler: api_bulk.PostApisBulkUndeployHandlerFunc(func(params api_bulk.PostApisBulkUndeployParams, principal *models.Principal) middleware.Responder {
+			return middleware.NotImplemented("operation api_bulk.PostApisBulkUndeploy has not yet been implemented")
+		}),
 		AuthorizationPostOauth2TokenHandler: authorization.PostOauth2TokenHandlerFunc(func(params authorization.PostOauth2TokenParams) middleware.Responder {
 			return middleware.NotImplemented("operation authorization.PostOauth2Token has not yet been implemented")
 		}),
+		RouteSimulationPostRoutesSimulateHandler: route_simulation.PostRoutesSimulateHandlerFunc(func(params route_simulation.PostRoutesSimulateParams, principal *models.Principal) middleware.Responder {
+			return middleware.NotImplemented("operation route_simulation.PostRoutesSimulate has not yet been implemented")
+		}),
+		XdsStatusGetXdsStatusHandler: xds_status.GetXdsStatusHandlerFunc(func(params xds_status.GetXdsStatusParams, principal *models.Principal) middleware.Responder {
+			return middleware.NotImplemented("operation xds_status.GetXdsStatus has not yet been implemented")
+		}),
 
 		// Applies when the Authorization header is set with the Basic scheme
 		BasicAuthAuth: func(user string, pass string) (*models.Principal, error) {
@@ -142,8 +157,16 @@ type RestapiAPI struct {
 	APICollectionGetApisHandler api_collection.GetApisHandler
 	// APIIndividualPostApisHandler sets the operation handler for the post apis operation
 	APIIndividualPostApisHandler api_individual.PostApisHandler
+	// APIBulkPostApisBulkDeployHandler sets the operation handler for the post apis bulk deploy operation
+	APIBulkPostApisBulkDeployHandler api_bulk.PostApisBulkDeployHandler
+	// APIBulkPostApisBulkUndeployHandler sets the operation handler for the post apis bulk undeploy operation
+	APIBulkPostApisBulkUndeployHandler api_bulk.PostApisBulkUndeployHandler
 	// AuthorizationPostOauth2TokenHandler sets the operation handler for the post oauth2 token operation
 	AuthorizationPostOauth2TokenHandler authorization.PostOauth2TokenHandler
+	// RouteSimulationPostRoutesSimulateHandler sets the operation handler for the post routes simulate operation
+	RouteSimulationPostRoutesSimulateHandler route_simulation.PostRoutesSimulateHandler
+	// XdsStatusGetXdsStatusHandler sets the operation handler for the get xds status operation
+	XdsStatusGetXdsStatusHandler xds_status.GetXdsStatusHandler
 
 	// ServeError is called when an error is received, there is a default handler
 	// but you can set your own with this
@@ -240,9 +263,21 @@ func (o *RestapiAPI) Validate() error {
 	if o.APIIndividualPostApisHandler == nil {
 		unregistered = append(unregistered, "api_individual.PostApisHandler")
 	}
+	if o.APIBulkPostApisBulkDeployHandler == nil {
+		unregistered = append(unregistered, "api_bulk.PostApisBulkDeployHandler")
+	}
+	if o.APIBulkPostApisBulkUndeployHandler == nil {
+		unregistered = append(unregistered, "api_bulk.PostApisBulkUndeployHandler")
+	}
 	if o.AuthorizationPostOauth2TokenHandler == nil {
 		unregistered = append(unregistered, "authorization.PostOauth2TokenHandler")
 	}
+	if o.RouteSimulationPostRoutesSimulateHandler == nil {
+		unregistered = append(unregistered, "route_simulation.PostRoutesSimulateHandler")
+	}
+	if o.XdsStatusGetXdsStatusHandler == nil {
+		unregistered = append(unregistered, "xds_status.GetXdsStatusHandler")
+	}
 
 	if len(unregistered) > 0 {
 		return fmt.Errorf("missing registration: %s", strings.Join(unregistered, ", "))
@@ -363,7 +398,23 @@ func (o *RestapiAPI) initHandlerCache() {
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
+	o.handlers["POST"]["/apis/bulk-deploy"] = api_bulk.NewPostApisBulkDeploy(o.context, o.APIBulkPostApisBulkDeployHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/apis/bulk-undeploy"] = api_bulk.NewPostApisBulkUndeploy(o.context, o.APIBulkPostApisBulkUndeployHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
 	o.handlers["POST"]["/oauth2/token"] = authorization.NewPostOauth2Token(o.context, o.AuthorizationPostOauth2TokenHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/routes/simulate"] = route_simulation.NewPostRoutesSimulate(o.context, o.RouteSimulationPostRoutesSimulateHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/xds/status"] = xds_status.NewGetXdsStatus(o.context, o.XdsStatusGetXdsStatusHandler)
 }
 
 // Serve creates a http handler to serve the API over HTTP
@@ -0,0 +1,218 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package xds
+
+import (
+	"fmt"
+	"sort"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+
+	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/internal/notifier"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/envoyconf"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
+)
+
+// activeAPIBatch is the batch being applied. The caches of the labels updated while a batch is active are
+// updated when the batch is committed. It is accessed only while holding the mutexForInternalMapUpdate.
+var activeAPIBatch *APIBatch
+
+// APIBatch is a set of API deployments and undeployments applied as a single transaction.
+// The APIs of a batch must be updated only through the batch, within ExecuteAPIBatch.
+type APIBatch struct {
+	labels map[string]struct{}
+}
+
+// internalMapsSnapshot is a copy of the internal maps updated by API deployments and undeployments, along
+// with the snapshot tracking state of the API changes, used to roll back a failed batch.
+type internalMapsSnapshot struct {
+	apiUUIDToGatewayToVhosts    map[string]map[string]string
+	apiToVhostsMap              map[string]map[string]struct{}
+	orgIDAPIMgwSwaggerMap       map[string]map[string]model.MgwSwagger
+	orgIDOpenAPIEnvoyMap        map[string]map[string][]string
+	orgIDOpenAPIRoutesMap       map[string]map[string][]*routev3.Route
+	orgIDOpenAPIClustersMap     map[string]map[string][]*clusterv3.Cluster
+	orgIDOpenAPIEndpointsMap    map[string]map[string][]*corev3.Address
+	orgIDOpenAPIEnforcerApisMap map[string]map[string]types.Resource
+	orgIDvHostBasepathMap       map[string]map[string]string
+	reverseAPINameVersionMap    map[string]string
+	upstreamClientCertSecrets   map[string]*tlsv3.Secret
+	upstreamProxyTunnels        envoyconf.UpstreamProxyTunnelsSnapshot
+	pendingAPIChanges           map[string]*apiChange
+	revisionChanges             map[*notifier.DeployedAPIRevision]*apiChange
+	// changeRevisions holds the revisions deployed by the pending changes, as a change made before the batch
+	// could be assigned a revision deployed within the batch.
+	changeRevisions map[*apiChange]*notifier.DeployedAPIRevision
+}
+
+// ExecuteAPIBatch applies the API deployments and undeployments done by the given function as a single
+// transaction. The caches of each label affected by the batch are updated once, with a single snapshot version,
+// after all the changes are applied. If the function returns an error, the internal maps are rolled back to the
+// state before the batch and the caches are not updated.
+func ExecuteAPIBatch(apply func(batch *APIBatch) error) error {
+	mutexForInternalMapUpdate.Lock()
+	defer mutexForInternalMapUpdate.Unlock()
+
+	snapshot := takeInternalMapsSnapshot()
+	batch := &APIBatch{labels: make(map[string]struct{})}
+	activeAPIBatch = batch
+	defer func() {
+		activeAPIBatch = nil
+		if r := recover(); r != nil {
+			snapshot.restore()
+			panic(r)
+		}
+	}()
+
+	if err := apply(batch); err != nil {
+		snapshot.restore()
+		logger.LoggerXds.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Rolled back the API batch as applying it failed : %v", err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 1417,
+		})
+		return err
+	}
+	activeAPIBatch = nil
+
+	labels := make([]string, 0, len(batch.labels))
+	for label := range batch.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	logger.LoggerXds.Infof("Updating the Xds Cache of the labels %v with the API batch", labels)
+	updateXdsCacheOnAPIAdd([]string{}, labels)
+	return nil
+}
+
+// UpdateAPI adds or updates the API in the given vhost and environments as a part of the batch.
+func (batch *APIBatch) UpdateAPI(vHost string, apiProject model.ProjectAPI, environments []string) error {
+	_, err := updateAPI(vHost, apiProject, environments)
+	return err
+}
+
+// DeleteAPIs deletes the API from the given vhost and environments as a part of the batch.
+func (batch *APIBatch) DeleteAPIs(vhost, apiName, version string, environments []string, organizationID string) error {
+	return deleteAPIs(vhost, apiName, version, environments, organizationID)
+}
+
+// IsAPIExist checks whether the API exists, including the changes done by the batch so far.
+func (batch *APIBatch) IsAPIExist(vhost, uuid, apiName, apiVersion, organizationID string) bool {
+	return IsAPIExist(vhost, uuid, apiName, apiVersion, organizationID)
+}
+
+func (batch *APIBatch) addLabels(oldLabels, newLabels []string) {
+	for _, label := range oldLabels {
+		batch.labels[label] = void
+	}
+	for _, label := range newLabels {
+		batch.labels[label] = void
+	}
+}
+
+func takeInternalMapsSnapshot() *internalMapsSnapshot {
+	reverseMap := make(map[string]string, len(reverseAPINameVersionMap))
+	for key, value := range reverseAPINameVersionMap {
+		reverseMap[key] = value
+	}
+	mutexForUpstreamClientCertSecrets.RLock()
+	secrets := make(map[string]*tlsv3.Secret, len(upstreamClientCertSecrets))
+	for name, secret := range upstreamClientCertSecrets {
+		secrets[name] = secret
+	}
+	mutexForUpstreamClientCertSecrets.RUnlock()
+
+	mutexForSnapshotTracking.Lock()
+	pendingChanges := make(map[string]*apiChange, len(pendingAPIChanges))
+	changeRevisions := make(map[*apiChange]*notifier.DeployedAPIRevision, len(pendingAPIChanges))
+	for key, change := range pendingAPIChanges {
+		pendingChanges[key] = change
+		changeRevisions[change] = change.deployedRevision
+	}
+	trackedRevisions := make(map[*notifier.DeployedAPIRevision]*apiChange, len(revisionChanges))
+	for revision, change := range revisionChanges {
+		trackedRevisions[revision] = change
+	}
+	mutexForSnapshotTracking.Unlock()
+
+	return &internalMapsSnapshot{
+		apiUUIDToGatewayToVhosts:    copyNestedMap(apiUUIDToGatewayToVhosts),
+		apiToVhostsMap:              copyNestedMap(apiToVhostsMap),
+		orgIDAPIMgwSwaggerMap:       copyNestedMap(orgIDAPIMgwSwaggerMap),
+		orgIDOpenAPIEnvoyMap:        copyNestedMap(orgIDOpenAPIEnvoyMap),
+		orgIDOpenAPIRoutesMap:       copyNestedMap(orgIDOpenAPIRoutesMap),
+		orgIDOpenAPIClustersMap:     copyNestedMap(orgIDOpenAPIClustersMap),
+		orgIDOpenAPIEndpointsMap:    copyNestedMap(orgIDOpenAPIEndpointsMap),
+		orgIDOpenAPIEnforcerApisMap: copyNestedMap(orgIDOpenAPIEnforcerApisMap),
+		orgIDvHostBasepathMap:       copyNestedMap(orgIDvHostBasepathMap),
+		reverseAPINameVersionMap:    reverseMap,
+		upstreamClientCertSecrets:   secrets,
+		upstreamProxyTunnels:        envoyconf.TakeUpstreamProxyTunnelsSnapshot(),
+		pendingAPIChanges:           pendingChanges,
+		revisionChanges:             trackedRevisions,
+		changeRevisions:             changeRevisions,
+	}
+}
+
+func (snapshot *internalMapsSnapshot) restore() {
+	apiUUIDToGatewayToVhosts = snapshot.apiUUIDToGatewayToVhosts
+	apiToVhostsMap = snapshot.apiToVhostsMap
+	orgIDAPIMgwSwaggerMap = snapshot.orgIDAPIMgwSwaggerMap
+	orgIDOpenAPIEnvoyMap = snapshot.orgIDOpenAPIEnvoyMap
+	orgIDOpenAPIRoutesMap = snapshot.orgIDOpenAPIRoutesMap
+	orgIDOpenAPIClustersMap = snapshot.orgIDOpenAPIClustersMap
+	orgIDOpenAPIEndpointsMap = snapshot.orgIDOpenAPIEndpointsMap
+	orgIDOpenAPIEnforcerApisMap = snapshot.orgIDOpenAPIEnforcerApisMap
+	orgIDvHostBasepathMap = snapshot.orgIDvHostBasepathMap
+	reverseAPINameVersionMap = snapshot.reverseAPINameVersionMap
+
+	mutexForUpstreamClientCertSecrets.Lock()
+	upstreamClientCertSecrets = snapshot.upstreamClientCertSecrets
+	mutexForUpstreamClientCertSecrets.Unlock()
+	envoyconf.RestoreUpstreamProxyTunnels(snapshot.upstreamProxyTunnels)
+
+	// The changes recorded within the batch are dropped, while the changes recorded before the batch remain
+	// pending to be included in the next cache update.
+	mutexForSnapshotTracking.Lock()
+	defer mutexForSnapshotTracking.Unlock()
+	for change, revision := range snapshot.changeRevisions {
+		change.deployedRevision = revision
+	}
+	pendingAPIChanges = snapshot.pendingAPIChanges
+	revisionChanges = snapshot.revisionChanges
+}
+
+// copyNestedMap copies the given map along with the inner maps. The values of the inner maps are not copied,
+// as they are replaced (not modified) when the APIs are updated.
+func copyNestedMap[V any](source map[string]map[string]V) map[string]map[string]V {
+	copied := make(map[string]map[string]V, len(source))
+	for key, innerMap := range source {
+		copiedInnerMap := make(map[string]V, len(innerMap))
+		for innerKey, value := range innerMap {
+			copiedInnerMap[innerKey] = value
+		}
+		copied[key] = copiedInnerMap
+	}
+	return copied
+}
@@ -19,7 +19,9 @@
 package common
 
 import (
+	"sort"
 	"sync"
+	"time"
 
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 )
@@ -83,6 +85,155 @@ func (nodeQueue *NodeQueue) IsNewNode(nodeIdentifier string) bool {
 	return nodeQueue.checkEntryAndMoveToEnd(nodeIdentifier)
 }
 
+var (
+	streamRegistriesLock sync.Mutex
+	streamRegistries     = make(map[string]*StreamRegistry)
+)
+
+// StreamRegistry keeps track of the nodes connected via the xDS streams of a cache, along with the versions sent
+// to and accepted by the nodes per resource type.
+type StreamRegistry struct {
+	lock    *sync.Mutex
+	streams map[int64]*streamInfo
+}
+
+type streamInfo struct {
+	label           string
+	nodeIdentifier  string
+	lastRequestTime time.Time
+	resources       map[string]*ResourceStatus
+}
+
+// StreamStatus is the status of an xDS stream.
+type StreamStatus struct {
+	StreamID        int64
+	Label           string
+	NodeIdentifier  string
+	LastRequestTime time.Time
+	// Resources holds the status of each resource type requested on the stream. (type URL -> status)
+	Resources map[string]ResourceStatus
+}
+
+// ResourceStatus is the status of a resource type requested on an xDS stream.
+type ResourceStatus struct {
+	SentVersion     string
+	AcceptedVersion string
+	// NackMessage is the error message of the last version rejected by the node, until a version is accepted.
+	NackMessage string
+}
+
+// GenerateStreamRegistry creates an empty instance of StreamRegistry.
+func GenerateStreamRegistry() *StreamRegistry {
+	return &StreamRegistry{
+		lock:    &sync.Mutex{},
+		streams: make(map[int64]*streamInfo),
+	}
+}
+
+// GetStreamRegistry returns the StreamRegistry of the given cache. The registry is created if it does not exist.
+func GetStreamRegistry(cacheName string) *StreamRegistry {
+	streamRegistriesLock.Lock()
+	defer streamRegistriesLock.Unlock()
+	registry, found := streamRegistries[cacheName]
+	if !found {
+		registry = GenerateStreamRegistry()
+		streamRegistries[cacheName] = registry
+	}
+	return registry
+}
+
+func (registry *StreamRegistry) getStream(streamID int64) *streamInfo {
+	stream, found := registry.streams[streamID]
+	if !found {
+		stream = &streamInfo{resources: make(map[string]*ResourceStatus)}
+		registry.streams[streamID] = stream
+	}
+	return stream
+}
+
+func (stream *streamInfo) getResource(typeURL string) *ResourceStatus {
+	resource, found := stream.resources[typeURL]
+	if !found {
+		resource = &ResourceStatus{}
+		stream.resources[typeURL] = resource
+	}
+	return resource
+}
+
+// RecordRequest records the discovery request received on the stream from the node. errorMessage is empty if the
+// node did not reject the last version sent to it.
+func (registry *StreamRegistry) RecordRequest(streamID int64, label, nodeIdentifier, typeURL, acceptedVersion,
+	errorMessage string) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	stream := registry.getStream(streamID)
+	stream.label = label
+	stream.nodeIdentifier = nodeIdentifier
+	stream.lastRequestTime = time.Now()
+	resource := stream.getResource(typeURL)
+	resource.AcceptedVersion = acceptedVersion
+	resource.NackMessage = errorMessage
+}
+
+// SetSentVersion records the version of the given resource type sent on the stream.
+func (registry *StreamRegistry) SetSentVersion(streamID int64, typeURL, version string) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.getStream(streamID).getResource(typeURL).SentVersion = version
+}
+
+// GetSentVersion returns the last version of the given resource type sent on the stream.
+func (registry *StreamRegistry) GetSentVersion(streamID int64, typeURL string) string {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	if stream, found := registry.streams[streamID]; found {
+		if resource, found := stream.resources[typeURL]; found {
+			return resource.SentVersion
+		}
+	}
+	return ""
+}
+
+// RemoveStream removes the closed stream and returns the label and the node identifier of the node which was
+// connected via the stream. found is false if no request was received on the stream.
+func (registry *StreamRegistry) RemoveStream(streamID int64) (label, nodeIdentifier string, found bool) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	stream, found := registry.streams[streamID]
+	if !found {
+		return "", "", false
+	}
+	delete(registry.streams, streamID)
+	return stream.label, stream.nodeIdentifier, stream.nodeIdentifier != ""
+}
+
+// GetStreamStatuses returns the status of the streams on which requests are received, ordered by the stream ID.
+func (registry *StreamRegistry) GetStreamStatuses() []StreamStatus {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	statuses := make([]StreamStatus, 0, len(registry.streams))
+	for streamID, stream := range registry.streams {
+		if stream.nodeIdentifier == "" {
+			continue
+		}
+		status := StreamStatus{
+			StreamID:        streamID,
+			Label:           stream.label,
+			NodeIdentifier:  stream.nodeIdentifier,
+			LastRequestTime: stream.lastRequestTime,
+			Resources:       make(map[string]ResourceStatus, len(stream.resources)),
+		}
+		for typeURL, resource := range stream.resources {
+			status.Resources[typeURL] = *resource
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].StreamID < statuses[j].StreamID
+	})
+	return statuses
+}
+
 // GetNodeIdentifier constructs the nodeIdentifier from discovery request's node property, label:<instanceIdentifierProperty>
 func GetNodeIdentifier(request *discovery.DiscoveryRequest) string {
 	metadataMap := request.Node.Metadata.AsMap()
@@ -66,6 +66,36 @@ func TestCheckEntryAndSwapToEnd(t *testing.T) {
 	assert.False(t, isNewAddition, "isNewAddition flag is not correct.")
 }
 
+func TestStreamRegistry(t *testing.T) {
+	streamRegistry := GenerateStreamRegistry()
+	streamRegistry.SetSentVersion(1, "routes", "v1")
+	streamRegistry.RecordRequest(1, "Default", "Default:router1", "routes", "", "invalid route")
+	streamRegistry.SetSentVersion(1, "routes", "v2")
+	assert.Equal(t, "v2", streamRegistry.GetSentVersion(1, "routes"), "sent version mismatch")
+	assert.Equal(t, "", streamRegistry.GetSentVersion(1, "clusters"), "sent version mismatch")
+	assert.Equal(t, "", streamRegistry.GetSentVersion(2, "routes"), "sent version mismatch")
+
+	streamRegistry.SetSentVersion(2, "apis", "v1")
+	statuses := streamRegistry.GetStreamStatuses()
+	assert.Equal(t, 1, len(statuses), "streams without requests should not be listed")
+	assert.Equal(t, ResourceStatus{SentVersion: "v2", AcceptedVersion: "", NackMessage: "invalid route"},
+		statuses[0].Resources["routes"], "resource status mismatch")
+	streamRegistry.RecordRequest(1, "Default", "Default:router1", "routes", "v2", "")
+	statuses = streamRegistry.GetStreamStatuses()
+	assert.Equal(t, ResourceStatus{SentVersion: "v2", AcceptedVersion: "v2"}, statuses[0].Resources["routes"],
+		"resource status mismatch")
+
+	label, nodeIdentifier, found := streamRegistry.RemoveStream(1)
+	assert.True(t, found, "node of the stream is not found")
+	assert.Equal(t, "Default", label, "label mismatch")
+	assert.Equal(t, "Default:router1", nodeIdentifier, "node identifier mismatch")
+	assert.Equal(t, "", streamRegistry.GetSentVersion(1, "routes"), "sent version of the removed stream mismatch")
+
+	_, _, found = streamRegistry.RemoveStream(2)
+	assert.False(t, found, "node is found for a stream without requests")
+	assert.Same(t, GetStreamRegistry("router"), GetStreamRegistry("router"), "registry of the cache mismatch")
+}
+
 func generateNodeArray(length int) []string {
 	array := []string{}
 	for i := 0; i < length; i++ {
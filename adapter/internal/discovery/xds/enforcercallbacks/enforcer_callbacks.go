@@ -22,8 +22,10 @@ import (
 	"fmt"
 
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
 	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds/common"
 	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/pkg/discovery/protocol/resource/v3"
 	"github.com/wso2/product-microgateway/adapter/pkg/logging"
 )
 
@@ -33,8 +35,16 @@ func init() {
 	nodeQueueInstance = common.GenerateNodeQueue()
 }
 
-// Callbacks is used to debug the xds server related communication.
+// Callbacks is used to debug the xds server related communication, and to keep track of the streams of the cache.
 type Callbacks struct {
+	streamRegistry *common.StreamRegistry
+}
+
+// NewCallbacks creates the callbacks of the xds server which serves the given enforcer cache.
+func NewCallbacks(cacheName string) *Callbacks {
+	return &Callbacks{
+		streamRegistry: common.GetStreamRegistry(cacheName),
+	}
 }
 
 // Report logs the fetches and requests.
@@ -49,6 +59,7 @@ func (cb *Callbacks) OnStreamOpen(_ context.Context, id int64, typ string) error
 // OnStreamClosed prints debug logs
 func (cb *Callbacks) OnStreamClosed(id int64) {
 	logger.LoggerEnforcerXdsCallbacks.Debugf("stream %d closed\n", id)
+	cb.streamRegistry.RemoveStream(id)
 }
 
 // OnStreamRequest prints debug logs
@@ -60,25 +71,28 @@ func (cb *Callbacks) OnStreamRequest(id int64, request *discovery.DiscoveryReque
 	}
 	logger.LoggerEnforcerXdsCallbacks.Debugf("stream request on stream id: %d, from node: %s, version: %s, for type: %s",
 		id, nodeIdentifier, request.GetVersionInfo(), request.GetTypeUrl())
+	cb.streamRegistry.RecordRequest(id, request.GetNode().GetId(), nodeIdentifier, request.GetTypeUrl(),
+		request.GetVersionInfo(), request.GetErrorDetail().GetMessage())
 	if request.ErrorDetail != nil {
 		logger.LoggerEnforcerXdsCallbacks.ErrorC(logging.ErrorDetails{
 			Message:   fmt.Sprintf("Stream request for type %s on stream id: %d Error: %s", request.GetTypeUrl(), id, request.ErrorDetail.Message),
 			Severity:  logging.CRITICAL,
 			ErrorCode: 1400,
 		})
+		// The APIs rejected by the enforcer are rolled back.
+		if resource.APIType == request.GetTypeUrl() {
+			requestEvent := xds.NewRequestEvent()
+			requestEvent.IsError = true
+			requestEvent.IsEnforcer = true
+			requestEvent.Label = request.GetNode().GetId()
+			requestEvent.Node = nodeIdentifier
+			requestEvent.TypeURL = request.GetTypeUrl()
+			requestEvent.Version = request.GetVersionInfo()
+			requestEvent.RejectedVersion = cb.streamRegistry.GetSentVersion(id, request.GetTypeUrl())
+			requestEvent.ErrorMessage = request.ErrorDetail.Message
+			xds.GetRequestEventChannel() <- requestEvent
+		}
 	}
-	// TODO: (VirajSalaka) Remove the commented logic once the fallback is implemented.
-	// requestEventChannel := xds.GetRequestEventChannel()
-	// if resource.APIType == request.GetTypeUrl() {
-	// 	requestEvent := xds.NewRequestEvent()
-	// 	if request.ErrorDetail != nil {
-	// 		logger.LoggerEnforcerXdsCallbacks.Errorf("stream request on stream id: %d Error: %s", id, request.ErrorDetail.Message)
-	// 		requestEvent.IsError = true
-	// 	}
-	// 	requestEvent.Node = request.GetNode().GetId()
-	// 	requestEvent.Version = request.VersionInfo
-	// 	requestEventChannel <- requestEvent
-	// }
 	return nil
 }
 
@@ -88,6 +102,7 @@ func (cb *Callbacks) OnStreamResponse(context context.Context, id int64, request
 	nodeIdentifier := common.GetNodeIdentifier(request)
 	logger.LoggerEnforcerXdsCallbacks.Debugf("stream response on stream id: %d node: %s for type: %s version: %s",
 		id, nodeIdentifier, request.GetTypeUrl(), response.GetVersionInfo())
+	cb.streamRegistry.SetSentVersion(id, response.GetTypeUrl(), response.GetVersionInfo())
 }
 
 // OnFetchRequest prints debug logs
@@ -0,0 +1,263 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package xds
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"google.golang.org/protobuf/proto"
+
+	apiModel "github.com/wso2/product-microgateway/adapter/internal/api/models"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/constants"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/envoyconf"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+)
+
+// policy flows reported by the route simulation
+const (
+	requestPolicyFlow  = "request"
+	responsePolicyFlow = "response"
+	faultPolicyFlow    = "fault"
+)
+
+// SimulateRoute evaluates the given request against the route configuration of the label, the same way the router
+// does, and returns the route which serves the request along with the policies and the interceptors applied to it.
+// As the enforcer sets the cluster header of an API request based on the key type and the router reselects the
+// route afterwards, the request is matched again with the cluster header of the requested key type.
+// The routes which match the dynamic metadata set by the enforcer are reported as not evaluable, since the request
+// may be served by one of them instead of the matched route.
+// If the label does not have a route configuration, a NotFound error is returned.
+func SimulateRoute(label string, request *apiModel.RouteSimulationRequest) (*apiModel.RouteSimulationResult, error) {
+	routeConfig, found := getRouteConfigSnapshot(label)
+	if !found {
+		return nil, errors.New(constants.NotFound)
+	}
+
+	path, rawQuery := *request.Path, ""
+	if queryIndex := strings.Index(path, "?"); queryIndex >= 0 {
+		path, rawQuery = path[:queryIndex], path[queryIndex+1:]
+	}
+	queryParams := make(map[string]string)
+	if parsedQuery, err := url.ParseQuery(rawQuery); err == nil {
+		for name, values := range parsedQuery {
+			queryParams[name] = values[0]
+		}
+	}
+	for name, value := range request.Query {
+		queryParams[name] = value
+	}
+	matchRequest := &envoyconf.RouteMatchRequest{
+		Host:    *request.Host,
+		Method:  strings.ToUpper(*request.Method),
+		Path:    path,
+		Headers: request.Headers,
+		Query:   queryParams,
+	}
+
+	matched := false
+	result := &apiModel.RouteSimulationResult{Matched: &matched}
+	virtualHost := envoyconf.FindVirtualHost(routeConfig, matchRequest.Host)
+	if virtualHost == nil {
+		result.Reason = fmt.Sprintf("None of the virtual hosts match the host %v", matchRequest.Host)
+		return result, nil
+	}
+	result.VirtualHost = virtualHost.GetName()
+
+	route, mismatches, notEvaluable := envoyconf.MatchRoute(virtualHost, matchRequest)
+	result.NotEvaluable = notEvaluable
+	if route == nil {
+		result.Reason = fmt.Sprintf("None of the routes of the virtual host %v match the request", virtualHost.GetName())
+		result.Mismatches = mismatches
+		return result, nil
+	}
+	routeContext := envoyconf.GetRouteContext(route)
+	if routeContext != nil {
+		keyType := request.KeyType
+		if keyType == "" {
+			keyType = apiModel.RouteSimulationRequestKeyTypePRODUCTION
+		}
+		matchRequest.ClusterName = routeContext.ProdClusterName
+		if keyType == apiModel.RouteSimulationRequestKeyTypeSANDBOX {
+			matchRequest.ClusterName = routeContext.SandClusterName
+		}
+		if matchRequest.ClusterName == "" {
+			result.Reason = fmt.Sprintf("The API %v:%v does not have %v endpoints", routeContext.APIName,
+				routeContext.APIVersion, strings.ToLower(keyType))
+			return result, nil
+		}
+		result.KeyType = keyType
+
+		route, mismatches, notEvaluable = envoyconf.MatchRoute(virtualHost, matchRequest)
+		result.NotEvaluable = notEvaluable
+		if route == nil {
+			result.Reason = fmt.Sprintf("None of the routes of the virtual host %v match the request after the "+
+				"enforcer sets the cluster %v", virtualHost.GetName(), matchRequest.ClusterName)
+			result.Mismatches = mismatches
+			return result, nil
+		}
+		routeContext = envoyconf.GetRouteContext(route)
+	}
+
+	rewrittenPath, err := envoyconf.GetRewrittenPath(route, path)
+	if err != nil {
+		return nil, fmt.Errorf("error while rewriting the path %v : %v", path, err)
+	}
+	if rawQuery != "" {
+		rewrittenPath += "?" + rawQuery
+	}
+	matched = true
+	result.RouteName = route.GetName()
+	result.PathRegex = route.GetMatch().GetSafeRegex().GetRegex()
+	result.Cluster = envoyconf.GetRouteCluster(route, matchRequest)
+	result.RewrittenPath = rewrittenPath
+	if routeContext != nil {
+		result.APIName = routeContext.APIName
+		result.APIVersion = routeContext.APIVersion
+		result.ResourcePath = routeContext.ResourcePath
+		setSimulatedOperationDetails(result, label, routeContext, matchRequest.Method)
+	}
+	return result, nil
+}
+
+// getRouteConfigSnapshot returns a copy of the route configuration of the label, so that the routes can be
+// matched without blocking the API deployments, which update the route configuration in place.
+func getRouteConfigSnapshot(label string) (*routev3.RouteConfiguration, bool) {
+	mutexForInternalMapUpdate.Lock()
+	defer mutexForInternalMapUpdate.Unlock()
+	routeConfig, found := envoyRouteConfigMap[label]
+	if !found {
+		return nil, false
+	}
+	return proto.Clone(routeConfig).(*routev3.RouteConfiguration), true
+}
+
+// setSimulatedOperationDetails sets the policies and the interceptors of the operation served by the route.
+func setSimulatedOperationDetails(result *apiModel.RouteSimulationResult, label string,
+	routeContext *envoyconf.RouteContext, method string) {
+	mutexForInternalMapUpdate.Lock()
+	defer mutexForInternalMapUpdate.Unlock()
+	mgwSwagger, resource, operation := getRouteOperation(label, routeContext, method)
+	if operation == nil {
+		return
+	}
+	policies := operation.GetPolicies()
+	for _, flow := range []struct {
+		name     string
+		policies model.PolicyList
+	}{
+		{requestPolicyFlow, policies.Request},
+		{responsePolicyFlow, policies.Response},
+		{faultPolicyFlow, policies.Fault},
+	} {
+		for _, policy := range flow.policies {
+			result.Policies = append(result.Policies, &apiModel.RouteSimulationPolicy{
+				Flow:          flow.name,
+				PolicyName:    policy.PolicyName,
+				PolicyVersion: policy.PolicyVersion,
+				Action:        policy.Action,
+				Parameters:    policy.Parameters,
+			})
+		}
+	}
+	result.RequestInterceptor = getSimulatedInterceptor(mgwSwagger, resource, operation, true)
+	result.ResponseInterceptor = getSimulatedInterceptor(mgwSwagger, resource, operation, false)
+}
+
+// getRouteOperation returns the API, the resource and the operation served by the route.
+// The caller must hold the mutexForInternalMapUpdate.
+func getRouteOperation(label string, routeContext *envoyconf.RouteContext, method string) (*model.MgwSwagger,
+	*model.Resource, *model.Operation) {
+	for organizationID, apis := range orgIDAPIMgwSwaggerMap {
+		for apiIdentifier, mgwSwagger := range apis {
+			vhost, err := ExtractVhostFromAPIIdentifier(apiIdentifier)
+			if err != nil || vhost != routeContext.VHost || mgwSwagger.GetTitle() != routeContext.APIName ||
+				mgwSwagger.GetVersion() != routeContext.APIVersion ||
+				!arrayContains(orgIDOpenAPIEnvoyMap[organizationID][apiIdentifier], label) {
+				continue
+			}
+			for _, resource := range mgwSwagger.GetResources() {
+				if resource.GetPath() != routeContext.ResourcePath {
+					continue
+				}
+				for _, operation := range resource.GetMethod() {
+					if strings.EqualFold(operation.GetMethod(), method) {
+						return &mgwSwagger, resource, operation
+					}
+				}
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// getSimulatedInterceptor returns the request or response interceptor of the operation, resolved in the same
+// order of precedence as the routes are created.
+func getSimulatedInterceptor(mgwSwagger *model.MgwSwagger, resource *model.Resource, operation *model.Operation,
+	isIn bool) *apiModel.RouteSimulationInterceptor {
+	extensionName := constants.XWso2ResponseInterceptor
+	if isIn {
+		extensionName = constants.XWso2RequestInterceptor
+	}
+	apiInterceptor := mgwSwagger.GetInterceptor(mgwSwagger.GetVendorExtensions(), extensionName,
+		envoyconf.APILevelInterceptor)
+	resourceInterceptor := mgwSwagger.GetInterceptor(resource.GetVendorExtensions(), extensionName,
+		envoyconf.ResourceLevelInterceptor)
+	operationInterceptors := mgwSwagger.GetOperationInterceptors(apiInterceptor, resourceInterceptor,
+		[]*model.Operation{operation}, isIn)
+	interceptor, found := operationInterceptors[strings.ToUpper(operation.GetMethod())]
+	if !found || !interceptor.Enable {
+		return nil
+	}
+	simulatedInterceptor := &apiModel.RouteSimulationInterceptor{
+		Level: interceptor.Level,
+		// The interceptor timeouts are in seconds.
+		RequestTimeoutInSeconds: int64(interceptor.RequestTimeout),
+		Includes:                []string{},
+	}
+	if len(interceptor.EndpointCluster.Endpoints) > 0 {
+		endpoint := interceptor.EndpointCluster.Endpoints[0]
+		simulatedInterceptor.Endpoint = endpoint.RawURL
+		if simulatedInterceptor.Endpoint == "" {
+			simulatedInterceptor.Endpoint = fmt.Sprintf("%v://%v:%v%v", endpoint.URLType, endpoint.Host, endpoint.Port,
+				endpoint.Basepath)
+		}
+	}
+	if includes := interceptor.Includes; includes != nil {
+		for _, include := range []struct {
+			name     string
+			included bool
+		}{
+			{"invocation_context", includes.InvocationContext},
+			{"request_headers", includes.RequestHeaders},
+			{"request_body", includes.RequestBody},
+			{"request_trailers", includes.RequestTrailer},
+			{"response_headers", includes.ResponseHeaders},
+			{"response_body", includes.ResponseBody},
+			{"response_trailers", includes.ResponseTrailers},
+		} {
+			if include.included {
+				simulatedInterceptor.Includes = append(simulatedInterceptor.Includes, include.name)
+			}
+		}
+	}
+	return simulatedInterceptor
+}
@@ -0,0 +1,116 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package xds
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/wso2/product-microgateway/adapter/config"
+	apiModel "github.com/wso2/product-microgateway/adapter/internal/api/models"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/constants"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/envoyconf"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+)
+
+const (
+	simulationLabel          = "simulation"
+	simulationDefaultCluster = "carbon.super_default-endpoints_xwso2cluster_localhost_SwaggerPetstore1.0.0"
+	simulationTenantACluster = "carbon.super_tenant-a-endpoints_xwso2cluster_localhost_SwaggerPetstore1.0.0"
+)
+
+func TestSimulateRoute(t *testing.T) {
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_content_routing.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	if err != nil {
+		t.Fatalf("error while reading the openapi file %v: %v", openapiFilePath, err)
+	}
+	var mgwSwagger model.MgwSwagger
+	if err = mgwSwagger.GetMgwSwagger(openapiByteArr); err != nil {
+		t.Fatalf("error while creating the MgwSwagger: %v", err)
+	}
+	routes, _, _ := envoyconf.CreateRoutesWithClusters(mgwSwagger, nil, nil, "localhost", "carbon.super")
+	envoyRouteConfigMap = map[string]*routev3.RouteConfiguration{
+		simulationLabel: {
+			VirtualHosts: []*routev3.VirtualHost{
+				{Name: "localhost", Domains: []string{"localhost", "localhost:*"}, Routes: routes},
+			},
+		},
+	}
+	defer func() { envoyRouteConfigMap = make(map[string]*routev3.RouteConfiguration) }()
+
+	if _, err = SimulateRoute("unknown", newRouteSimulationRequest("localhost", "GET", "/petstore/1.0.0/pets")); err == nil ||
+		err.Error() != constants.NotFound {
+		t.Errorf("expected a NotFound error for a label without routes but found %v", err)
+	}
+
+	// The request matches the header and the query parameter conditions of the first routing rule.
+	request := newRouteSimulationRequest("localhost:9095", "GET", "/petstore/1.0.0/pets?region=eu-west")
+	request.Headers = map[string]string{"X-Tenant": "a"}
+	result := simulateRoute(t, request)
+	if !*result.Matched || result.Cluster != simulationTenantACluster {
+		t.Errorf("expected the request to be routed to %v but found %v (%v)", simulationTenantACluster,
+			result.Cluster, result.Reason)
+	}
+	if len(result.NotEvaluable) != 0 {
+		t.Errorf("expected all the routes before the matched route to be evaluated but found %v",
+			result.NotEvaluable)
+	}
+
+	// The claim based routing rule cannot be evaluated, hence the request falls back to the default route, while
+	// the claim based route is reported as not evaluable instead of a mismatch.
+	result = simulateRoute(t, newRouteSimulationRequest("localhost", "GET", "/petstore/1.0.0/pets"))
+	if !*result.Matched || result.Cluster != simulationDefaultCluster {
+		t.Errorf("expected the request to be routed to %v but found %v (%v)", simulationDefaultCluster,
+			result.Cluster, result.Reason)
+	}
+	if result.KeyType != apiModel.RouteSimulationRequestKeyTypePRODUCTION {
+		t.Errorf("expected the production key type but found %v", result.KeyType)
+	}
+	if len(result.NotEvaluable) != 1 || !strings.Contains(result.NotEvaluable[0], "dynamic metadata") {
+		t.Errorf("expected the claim based route to be reported as not evaluable but found %v",
+			result.NotEvaluable)
+	}
+	if len(result.Mismatches) != 0 {
+		t.Errorf("expected no mismatches for a matched request but found %v", result.Mismatches)
+	}
+
+	result = simulateRoute(t, newRouteSimulationRequest("localhost", "GET", "/petstore/1.0.0/owners"))
+	if *result.Matched || len(result.Mismatches) != len(routes) {
+		t.Errorf("expected a mismatch for each route but found %v", result.Mismatches)
+	}
+
+	result = simulateRoute(t, newRouteSimulationRequest("foo.com", "GET", "/petstore/1.0.0/pets"))
+	if *result.Matched || result.VirtualHost != "" {
+		t.Errorf("expected no virtual host to match the host but found %v", result.VirtualHost)
+	}
+}
+
+func simulateRoute(t *testing.T, request *apiModel.RouteSimulationRequest) *apiModel.RouteSimulationResult {
+	result, err := SimulateRoute(simulationLabel, request)
+	if err != nil {
+		t.Fatalf("unexpected error while simulating the route: %v", err)
+	}
+	return result
+}
+
+func newRouteSimulationRequest(host, method, path string) *apiModel.RouteSimulationRequest {
+	return &apiModel.RouteSimulationRequest{Host: &host, Method: &method, Path: &path}
+}
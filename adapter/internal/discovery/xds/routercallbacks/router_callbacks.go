@@ -22,15 +22,19 @@ import (
 	"fmt"
 
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
 	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds/common"
 	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
 	"github.com/wso2/product-microgateway/adapter/pkg/logging"
 )
 
 var nodeQueueInstance *common.NodeQueue
+var streamRegistry *common.StreamRegistry
 
 func init() {
 	nodeQueueInstance = common.GenerateNodeQueue()
+	streamRegistry = common.GetStreamRegistry(xds.RouterCacheName)
 }
 
 // Callbacks is used to debug the xds server related communication.
@@ -46,9 +50,16 @@ func (cb *Callbacks) OnStreamOpen(_ context.Context, id int64, typ string) error
 	return nil
 }
 
-// OnStreamClosed prints debug logs
+// OnStreamClosed prints debug logs and notifies the xds server that the node is disconnected
 func (cb *Callbacks) OnStreamClosed(id int64) {
 	logger.LoggerRouterXdsCallbacks.Debugf("stream %d closed\n", id)
+	if label, nodeIdentifier, found := streamRegistry.RemoveStream(id); found {
+		requestEvent := xds.NewRequestEvent()
+		requestEvent.IsDisconnected = true
+		requestEvent.Label = label
+		requestEvent.Node = nodeIdentifier
+		xds.GetRequestEventChannel() <- requestEvent
+	}
 }
 
 // OnStreamRequest prints debug logs
@@ -60,6 +71,13 @@ func (cb *Callbacks) OnStreamRequest(id int64, request *discovery.DiscoveryReque
 	}
 	logger.LoggerRouterXdsCallbacks.Debugf("stream request on stream id: %d, from node: %s, version: %s, for type: %s",
 		id, nodeIdentifier, request.VersionInfo, request.TypeUrl)
+	streamRegistry.RecordRequest(id, request.GetNode().GetId(), nodeIdentifier, request.GetTypeUrl(),
+		request.GetVersionInfo(), request.GetErrorDetail().GetMessage())
+	requestEvent := xds.NewRequestEvent()
+	requestEvent.Label = request.GetNode().GetId()
+	requestEvent.Node = nodeIdentifier
+	requestEvent.TypeURL = request.GetTypeUrl()
+	requestEvent.Version = request.GetVersionInfo()
 	if request.ErrorDetail != nil {
 		logger.LoggerRouterXdsCallbacks.ErrorC(logging.ErrorDetails{
 			Message: fmt.Sprintf("Stream request for type %s on stream id: %d, from node: %s, Error: %s", request.GetTypeUrl(),
@@ -67,6 +85,14 @@ func (cb *Callbacks) OnStreamRequest(id int64, request *discovery.DiscoveryReque
 			Severity:  logging.CRITICAL,
 			ErrorCode: 1401,
 		})
+		// The APIs rejected by the router are rolled back.
+		requestEvent.IsError = true
+		requestEvent.RejectedVersion = streamRegistry.GetSentVersion(id, request.GetTypeUrl())
+		requestEvent.ErrorMessage = request.ErrorDetail.Message
+		xds.GetRequestEventChannel() <- requestEvent
+	} else if request.GetTypeUrl() == resource.RouteType {
+		// The snapshot versions are accepted once the route configuration is accepted.
+		xds.GetRequestEventChannel() <- requestEvent
 	}
 	return nil
 }
@@ -77,6 +103,7 @@ func (cb *Callbacks) OnStreamResponse(context context.Context, id int64, request
 	nodeIdentifier := common.GetNodeIdentifier(request)
 	logger.LoggerRouterXdsCallbacks.Debugf("stream response on stream id: %d, to node: %s, version: %s, for type: %v", id,
 		nodeIdentifier, response.VersionInfo, response.TypeUrl)
+	streamRegistry.SetSentVersion(id, response.GetTypeUrl(), response.GetVersionInfo())
 }
 
 // OnFetchRequest prints debug logs
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -32,6 +33,8 @@ import (
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	envoy_cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 
@@ -89,12 +92,17 @@ var (
 
 	reverseAPINameVersionMap map[string]string
 
+	// Upstream client certificate secrets referenced by the clusters, delivered to the router via SDS
+	upstreamClientCertSecrets         map[string]*tlsv3.Secret // secret name -> secret
+	mutexForUpstreamClientCertSecrets sync.RWMutex
+
 	// Envoy Label as map key
 	envoyUpdateVersionMap  map[string]int64                       // GW-Label -> XDS version map
 	envoyListenerConfigMap map[string][]*listenerv3.Listener      // GW-Label -> Listener Configuration map
 	envoyRouteConfigMap    map[string]*routev3.RouteConfiguration // GW-Label -> Routes Configuration map
 	envoyClusterConfigMap  map[string][]*clusterv3.Cluster        // GW-Label -> Global Cluster Configuration map
 	envoyEndpointConfigMap map[string][]*corev3.Address           // GW-Label -> Global Endpoint Configuration map
+	envoyErrorResponseMap  map[string][]*hcmv3.ResponseMapper     // GW-Label -> API level error response mappers map
 
 	// Common Enforcer Label as map key
 	enforcerConfigMap                map[string][]types.Resource
@@ -156,6 +164,7 @@ func init() {
 	envoyRouteConfigMap = make(map[string]*routev3.RouteConfiguration)
 	envoyClusterConfigMap = make(map[string][]*clusterv3.Cluster)
 	envoyEndpointConfigMap = make(map[string][]*corev3.Address)
+	envoyErrorResponseMap = make(map[string][]*hcmv3.ResponseMapper)
 
 	orgIDAPIMgwSwaggerMap = make(map[string]map[string]model.MgwSwagger)       // organizationID -> Vhost:API_UUID -> MgwSwagger struct map
 	orgIDOpenAPIEnvoyMap = make(map[string]map[string][]string)                // organizationID -> Vhost:API_UUID -> Envoy Label Array map
@@ -166,6 +175,7 @@ func init() {
 	orgIDvHostBasepathMap = make(map[string]map[string]string)
 
 	reverseAPINameVersionMap = make(map[string]string)
+	upstreamClientCertSecrets = make(map[string]*tlsv3.Secret)
 
 	enforcerConfigMap = make(map[string][]types.Resource)
 	enforcerKeyManagerMap = make(map[string][]types.Resource)
@@ -247,8 +257,31 @@ func DeployReadinessAPI(envs []string) {
 	}
 }
 
+// WithdrawReadinessAPI removes the readiness endpoint from the router, so that the gateway is no longer
+// considered ready while the adapter is shutting down. The internal maps are locked, so that an API update in
+// progress does not add the readiness endpoint back with its resources.
+func WithdrawReadinessAPI(envs []string) {
+	mutexForInternalMapUpdate.Lock()
+	defer mutexForInternalMapUpdate.Unlock()
+	logger.LoggerXds.Infof("Withdrawing the readiness endpoint...")
+	isReady = false
+	for _, env := range envs {
+		listeners, clusters, routes, endpoints, apis := GenerateEnvoyResoucesForLabel(env)
+		UpdateXdsCacheWithLock(env, endpoints, clusters, routes, listeners)
+		UpdateEnforcerApis(env, apis, "")
+	}
+}
+
 // UpdateAPI updates the Xds Cache when OpenAPI Json content is provided
 func UpdateAPI(vHost string, apiProject model.ProjectAPI, environments []string) (*notifier.DeployedAPIRevision, error) {
+	mutexForInternalMapUpdate.Lock()
+	defer mutexForInternalMapUpdate.Unlock()
+	return updateAPI(vHost, apiProject, environments)
+}
+
+// updateAPI updates the internal maps and the Xds Cache for the given API project.
+// The caller must hold the mutexForInternalMapUpdate.
+func updateAPI(vHost string, apiProject model.ProjectAPI, environments []string) (*notifier.DeployedAPIRevision, error) {
 	var mgwSwagger model.MgwSwagger
 	var deployedRevision *notifier.DeployedAPIRevision
 	var err error
@@ -324,10 +357,20 @@ func UpdateAPI(vHost string, apiProject model.ProjectAPI, environments []string)
 		}
 		mgwSwagger.SanitizeAPISecurity(isYamlAPIKey, isYamlOauth, isYamlMutualssl, isYamlMutualsslMandatory, isYamlOauthBasicAuthAPIKeyMandatory)
 		mgwSwagger.SetOperationPolicies(apiProject)
+		if !isFaultInjectionAllowed(environments) && mgwSwagger.RemoveFaultInjectionPolicies() {
+			logger.LoggerXds.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Ignoring the %v policies of the API %s:%s as fault injection is not allowed in the environments %v",
+					constants.FaultInjectionAction, apiYaml.Name, apiYaml.Version, environments),
+				Severity:  logging.MINOR,
+				ErrorCode: 1416,
+			})
+		}
 	}
 	mgwSwagger.SetXWso2AuthHeader(apiYaml.AuthorizationHeader)
 	mgwSwagger.SetEnvLabelProperties(apiEnvProps)
+	mgwSwagger.SetErrorResponses(apiProject.ErrorResponses)
 	mgwSwagger.OrganizationID = apiYaml.OrganizationID
+	mgwSwagger.DeployedTime = time.Now()
 	organizationID := apiYaml.OrganizationID
 	apiHashValue := generateHashValue(apiYaml.Name, apiYaml.Version)
 
@@ -393,15 +436,13 @@ func UpdateAPI(vHost string, apiProject model.ProjectAPI, environments []string)
 	reverseAPINameVersionMap[GenerateIdentifierForAPIWithoutVhost(apiYaml.Name, apiYaml.Version)] = uniqueIdentifier
 	apiIdentifier := GenerateIdentifierForAPIWithUUID(vHost, uniqueIdentifier)
 
-	mutexForInternalMapUpdate.Lock()
-	defer mutexForInternalMapUpdate.Unlock()
-
 	// -------- Begin updating maps
 
 	err = addBasepathToMap(mgwSwagger, organizationID, vHost, apiIdentifier)
 	if err != nil {
 		return nil, err
 	}
+	change := recordAPIChange(organizationID, apiIdentifier)
 
 	// Get the map from organizationID map.
 	if _, ok := orgIDAPIMgwSwaggerMap[organizationID]; ok {
@@ -432,30 +473,13 @@ func UpdateAPI(vHost string, apiProject model.ProjectAPI, environments []string)
 	}
 	updateVhostInternalMaps(apiYaml.ID, apiYaml.Name, apiYaml.Version, vHost, newLabels)
 
-	// create cert map for API
-	certMap := make(map[string][]byte)
-	interceptCertMap := make(map[string][]byte)
-	if len(apiProject.EndpointCerts) > 0 && len(apiProject.UpstreamCerts) > 0 {
-		for url, certFile := range apiProject.EndpointCerts {
-			if certBytes, found := apiProject.UpstreamCerts[certFile]; found {
-				certMap[url] = certBytes
-				interceptCertMap[url] = certBytes
-				delete(apiProject.UpstreamCerts, certFile)
-			} else {
-				logger.LoggerXds.ErrorC(logging.ErrorDetails{
-					Message:   fmt.Sprintf("Certificate file %v not found for the url %v", certFile, url),
-					Severity:  logging.MAJOR,
-					ErrorCode: 1406,
-				})
-			}
-		}
-	}
-	newLineByteArray := []byte("\n")
-	for _, certBytes := range apiProject.UpstreamCerts {
-		certMap["default"] = append(certMap["default"], certBytes...)
-		certMap["default"] = append(certMap["default"], newLineByteArray...)
-	}
-	interceptCertMap["default"] = apiProject.InterceptorCerts
+	certMap, interceptCertMap, upstreamClientCerts := getUpstreamCerts(apiProject)
+	mgwSwagger.SetUpstreamClientCerts(upstreamClientCerts)
+	mgwSwagger.SetTrustedCerts(certMap, interceptCertMap)
+	// The MgwSwagger is stored again as the certificates are set after it is stored.
+	orgIDAPIMgwSwaggerMap[organizationID][apiIdentifier] = mgwSwagger
+	// The client certificate secrets of the previous revision of the API are not required anymore.
+	pruneUpstreamClientCertSecrets()
 
 	routes, clusters, endpoints := oasParser.GetRoutesClustersEndpoints(mgwSwagger, certMap,
 		interceptCertMap, vHost, organizationID)
@@ -475,6 +499,8 @@ func UpdateAPI(vHost string, apiProject model.ProjectAPI, environments []string)
 		clustersMap[apiIdentifier] = clusters
 		orgIDOpenAPIClustersMap[organizationID] = clustersMap
 	}
+	// The tunnels of the endpoints of the previous revision of the API are not required anymore.
+	pruneUpstreamProxyTunnels()
 
 	if _, ok := orgIDOpenAPIEndpointsMap[organizationID]; ok {
 		orgIDOpenAPIEndpointsMap[organizationID][apiIdentifier] = endpoints
@@ -498,6 +524,7 @@ func UpdateAPI(vHost string, apiProject model.ProjectAPI, environments []string)
 		// send updated revision to control plane
 		deployedRevision = notifier.UpdateDeployedRevisions(apiYaml.ID, apiYaml.RevisionID, environments,
 			vHost)
+		setDeployedRevision(change, deployedRevision)
 	}
 	if svcdiscovery.IsServiceDiscoveryEnabled {
 		startConsulServiceDiscovery(organizationID) //consul service discovery starting point
@@ -579,11 +606,16 @@ func addBasepathToMap(mgwSwagger model.MgwSwagger, organizationID, vHost, apiIde
 
 // DeleteAPIs deletes an API, its resources and updates the caches of given environments
 func DeleteAPIs(vhost, apiName, version string, environments []string, organizationID string) error {
-	apiNameVersionID := GenerateIdentifierForAPIWithoutVhost(apiName, version)
-	apiNameVersionHashedID := reverseAPINameVersionMap[apiNameVersionID]
-
 	mutexForInternalMapUpdate.Lock()
 	defer mutexForInternalMapUpdate.Unlock()
+	return deleteAPIs(vhost, apiName, version, environments, organizationID)
+}
+
+// deleteAPIs deletes an API from the given vhost (or all the vhosts if the vhost is empty) and environments.
+// The caller must hold the mutexForInternalMapUpdate.
+func deleteAPIs(vhost, apiName, version string, environments []string, organizationID string) error {
+	apiNameVersionID := GenerateIdentifierForAPIWithoutVhost(apiName, version)
+	apiNameVersionHashedID := reverseAPINameVersionMap[apiNameVersionID]
 
 	vhosts, found := apiToVhostsMap[apiNameVersionHashedID]
 	if !found {
@@ -748,6 +780,7 @@ func deleteAPI(apiIdentifier string, environments []string, organizationID strin
 		logger.LoggerXds.Infof("Unable to delete API: %v from Organization: %v. API Does not exist.", apiIdentifier, organizationID)
 		return errors.New(constants.NotFound)
 	}
+	recordAPIChange(organizationID, apiIdentifier)
 
 	existingLabels := orgIDOpenAPIEnvoyMap[organizationID][apiIdentifier]
 	toBeDelEnvs, toBeKeptEnvs := getEnvironmentsToBeDeleted(existingLabels, environments)
@@ -780,6 +813,7 @@ func cleanMapResources(apiIdentifier string, organizationID string, toBeDelEnvs
 	delete(orgIDOpenAPIClustersMap[organizationID], apiIdentifier)
 	delete(orgIDOpenAPIEndpointsMap[organizationID], apiIdentifier)
 	delete(orgIDOpenAPIEnforcerApisMap[organizationID], apiIdentifier)
+	pruneUpstreamProxyTunnels()
 
 	//updateXdsCacheOnAPIAdd is called after cleaning maps of routes, clusters, endpoints, enforcerAPIs.
 	//Therefore resources that belongs to the deleting API do not exist. Caches updated only with
@@ -789,6 +823,7 @@ func cleanMapResources(apiIdentifier string, organizationID string, toBeDelEnvs
 	deleteBasepathForVHost(organizationID, apiIdentifier)
 	delete(orgIDOpenAPIEnvoyMap[organizationID], apiIdentifier)  //delete labels
 	delete(orgIDAPIMgwSwaggerMap[organizationID], apiIdentifier) //delete mgwSwagger
+	pruneUpstreamClientCertSecrets()
 	//TODO: (SuKSW) clean any remaining in label wise maps, if this is the last API of that label
 	logger.LoggerXds.Infof("Deleted API %v of organization %v", apiIdentifier, organizationID)
 }
@@ -803,6 +838,21 @@ func deleteBasepathForVHost(organizationID, apiIdentifier string) {
 	}
 }
 
+// isFaultInjectionAllowed checks whether the fault injection policies are allowed in all the given environments.
+func isFaultInjectionAllowed(environments []string) bool {
+	conf, _ := config.ReadConfigs()
+	allowedEnvironments := conf.Envoy.FaultInjection.AllowedEnvironments
+	if arrayContains(allowedEnvironments, "*") {
+		return true
+	}
+	for _, environment := range environments {
+		if !arrayContains(allowedEnvironments, environment) {
+			return false
+		}
+	}
+	return true
+}
+
 func arrayContains(a []string, x string) bool {
 	for _, n := range a {
 		if x == n {
@@ -833,12 +883,21 @@ func mergeResourceArrays(resourceArrays [][]types.Resource) []types.Resource {
 // Old labels refers to the previously assigned labels
 // New labels refers to the the updated labels
 func updateXdsCacheOnAPIAdd(oldLabels []string, newLabels []string) bool {
+	if activeAPIBatch != nil {
+		// the caches of the labels are updated once the batch is committed
+		activeAPIBatch.addLabels(oldLabels, newLabels)
+		return false
+	}
 	revisionStatus := false
+	// The changes are tracked with the snapshot versions, to roll them back if the snapshots are rejected.
+	changes := takePendingAPIChanges()
+	defer completeAPIChanges(changes)
 	// TODO: (VirajSalaka) check possible optimizations, Since the number of labels are low by design it should not be an issue
 	for _, newLabel := range newLabels {
 		listeners, clusters, routes, endpoints, apis := GenerateEnvoyResoucesForLabel(newLabel)
-		UpdateEnforcerApis(newLabel, apis, "")
-		success := UpdateXdsCacheWithLock(newLabel, endpoints, clusters, routes, listeners)
+		version := fmt.Sprint(rand.Intn(maxRandomInt))
+		UpdateEnforcerApis(newLabel, apis, version)
+		success := updateXdsCacheWithLock(newLabel, version, changes, endpoints, clusters, routes, listeners)
 		logger.LoggerXds.Debugf("Xds Cache is updated for the newly added label : %v", newLabel)
 		if success {
 			// if even one label was updated with latest revision, we take the revision as deployed.
@@ -850,8 +909,9 @@ func updateXdsCacheOnAPIAdd(oldLabels []string, newLabels []string) bool {
 	for _, oldLabel := range oldLabels {
 		if !arrayContains(newLabels, oldLabel) {
 			listeners, clusters, routes, endpoints, apis := GenerateEnvoyResoucesForLabel(oldLabel)
-			UpdateEnforcerApis(oldLabel, apis, "")
-			UpdateXdsCacheWithLock(oldLabel, endpoints, clusters, routes, listeners)
+			version := fmt.Sprint(rand.Intn(maxRandomInt))
+			UpdateEnforcerApis(oldLabel, apis, version)
+			updateXdsCacheWithLock(oldLabel, version, changes, endpoints, clusters, routes, listeners)
 			logger.LoggerXds.Debugf("Xds Cache is updated for the already existing label : %v", oldLabel)
 		}
 	}
@@ -866,6 +926,7 @@ func GenerateEnvoyResoucesForLabel(label string) ([]types.Resource, []types.Reso
 	var vhostToRouteArrayMap = make(map[string][]*routev3.Route)
 	var endpointArray []*corev3.Address
 	var apis []types.Resource
+	var apiErrorResponses []apiErrorResponseMappers
 
 	for organizationID, entityMap := range orgIDOpenAPIEnvoyMap {
 		for apiKey, labels := range entityMap {
@@ -882,6 +943,13 @@ func GenerateEnvoyResoucesForLabel(label string) ([]types.Resource, []types.Reso
 				isDefaultVersion := false
 				if enforcerAPISwagger, ok := orgIDAPIMgwSwaggerMap[organizationID][apiKey]; ok {
 					isDefaultVersion = enforcerAPISwagger.IsDefaultVersion
+					if len(enforcerAPISwagger.GetXWso2ErrorResponses()) > 0 {
+						apiErrorResponses = append(apiErrorResponses, apiErrorResponseMappers{
+							apiKey:   organizationID + apiKeyFieldSeparator + apiKey,
+							basePath: enforcerAPISwagger.GetXWso2Basepath(),
+							mappers:  oasParser.GetAPIErrorResponseMappers(enforcerAPISwagger, vhost),
+						})
+					}
 				} else {
 					// If the mgwSwagger is not found, proceed with other APIs. (Unreachable condition at this point)
 					// If that happens, there is no purpose in processing clusters too.
@@ -932,18 +1000,30 @@ func GenerateEnvoyResoucesForLabel(label string) ([]types.Resource, []types.Reso
 		vhostToRouteArrayMap[systemHost] = append(vhostToRouteArrayMap[systemHost], readynessEndpoint)
 	}
 
+	errorResponseMappers := sortAPIErrorResponseMappers(apiErrorResponses)
 	listenerArray, listenerFound := envoyListenerConfigMap[label]
 	routesConfig, routesConfigFound := envoyRouteConfigMap[label]
 	if !listenerFound && !routesConfigFound {
-		listenerArray, routesConfig = oasParser.GetProductionListenerAndRouteConfig(vhostToRouteArrayMap)
+		listenerArray, routesConfig = oasParser.GetProductionListenerAndRouteConfig(label, vhostToRouteArrayMap, errorResponseMappers)
 		envoyListenerConfigMap[label] = listenerArray
 		envoyRouteConfigMap[label] = routesConfig
 	} else {
 		// If the routesConfig exists, the listener exists too
 		oasParser.UpdateRoutesConfig(routesConfig, vhostToRouteArrayMap)
+		// The listener is regenerated only if the API level error responses are changed, to avoid draining
+		// the downstream connections for each API deployment.
+		if !isSameErrorResponseMappers(envoyErrorResponseMap[label], errorResponseMappers) {
+			listenerArray = oasParser.GetProductionListeners(label, errorResponseMappers)
+			envoyListenerConfigMap[label] = listenerArray
+		}
 	}
+	envoyErrorResponseMap[label] = errorResponseMappers
 	clusterArray = append(clusterArray, envoyClusterConfigMap[label]...)
 	endpointArray = append(endpointArray, envoyEndpointConfigMap[label]...)
+	// The tunnel listeners are not stored with the listeners of the label, as they depend on the deployed APIs.
+	proxyClusters, tunnelListeners := envoyconf.GetUpstreamProxyTunnelResources(clusterArray)
+	clusterArray = append(clusterArray, proxyClusters...)
+	listenerArray = append(append([]*listenerv3.Listener{}, listenerArray...), tunnelListeners...)
 	endpoints, clusters, listeners, routeConfigs := oasParser.GetCacheResources(endpointArray, clusterArray, listenerArray, routesConfig)
 	return endpoints, clusters, listeners, routeConfigs, apis
 }
@@ -955,16 +1035,65 @@ func GenerateGlobalClusters(label string) {
 	envoyEndpointConfigMap[label] = endpoints
 }
 
+// RegenerateEnvoyResources regenerates the listeners, the route configurations and the global clusters of all the
+// labels, along with the routes and the clusters of the deployed APIs, from the current configuration, and updates
+// the Xds caches of the labels. This is used to apply the configuration changes done at runtime.
+func RegenerateEnvoyResources() {
+	mutexForInternalMapUpdate.Lock()
+	defer mutexForInternalMapUpdate.Unlock()
+	regenerateAPIResources()
+	labels := make([]string, 0, len(envoyListenerConfigMap))
+	for label := range envoyListenerConfigMap {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		delete(envoyListenerConfigMap, label)
+		delete(envoyRouteConfigMap, label)
+		GenerateGlobalClusters(label)
+		listeners, clusters, routes, endpoints, apis := GenerateEnvoyResoucesForLabel(label)
+		UpdateXdsCacheWithLock(label, endpoints, clusters, routes, listeners)
+		UpdateEnforcerApis(label, apis, "")
+		logger.LoggerXds.Infof("Regenerated the router resources of the label %v", label)
+	}
+}
+
+// regenerateAPIResources generates the routes, clusters and endpoints of all the APIs again, as they depend on
+// the router configuration (eg: CORS and upstream timeouts). The caller must hold the mutexForInternalMapUpdate.
+func regenerateAPIResources() {
+	for organizationID, mgwSwaggerMap := range orgIDAPIMgwSwaggerMap {
+		for apiIdentifier, mgwSwagger := range mgwSwaggerMap {
+			vHost, err := ExtractVhostFromAPIIdentifier(apiIdentifier)
+			if err != nil {
+				logger.LoggerXds.ErrorC(logging.ErrorDetails{
+					Message: fmt.Sprintf("Error while regenerating the router resources of the API %v : %v",
+						apiIdentifier, err.Error()),
+					Severity:  logging.MAJOR,
+					ErrorCode: 1422,
+				})
+				continue
+			}
+			upstreamCerts, interceptorCerts := mgwSwagger.GetTrustedCerts()
+			routes, clusters, endpoints := oasParser.GetRoutesClustersEndpoints(mgwSwagger, upstreamCerts,
+				interceptorCerts, vHost, organizationID)
+			orgIDOpenAPIRoutesMap[organizationID][apiIdentifier] = routes
+			orgIDOpenAPIClustersMap[organizationID][apiIdentifier] = clusters
+			orgIDOpenAPIEndpointsMap[organizationID][apiIdentifier] = endpoints
+		}
+	}
+	pruneUpstreamProxyTunnels()
+}
+
 //use UpdateXdsCacheWithLock to avoid race conditions
-func updateXdsCache(label string, endpoints []types.Resource, clusters []types.Resource, routes []types.Resource, listeners []types.Resource) bool {
-	version := rand.Intn(maxRandomInt)
+func updateXdsCache(label string, version string, endpoints []types.Resource, clusters []types.Resource, routes []types.Resource, listeners []types.Resource) bool {
 	// TODO: (VirajSalaka) kept same version for all the resources as we are using simple cache implementation.
 	// Will be updated once decide to move to incremental XDS
-	snap, errNewSnap := envoy_cachev3.NewSnapshot(fmt.Sprint(version), map[envoy_resource.Type][]types.Resource{
+	snap, errNewSnap := envoy_cachev3.NewSnapshot(version, map[envoy_resource.Type][]types.Resource{
 		envoy_resource.EndpointType: endpoints,
 		envoy_resource.ClusterType:  clusters,
 		envoy_resource.ListenerType: listeners,
 		envoy_resource.RouteType:    routes,
+		envoy_resource.SecretType:   GetUpstreamClientCertSecrets(clusters),
 	})
 	if errNewSnap != nil {
 		logger.LoggerXds.ErrorC(logging.ErrorDetails{
@@ -985,10 +1114,141 @@ func updateXdsCache(label string, endpoints []types.Resource, clusters []types.R
 		})
 		return false
 	}
-	logger.LoggerXds.Infof("New Router cache updated for the label: " + label + " version: " + fmt.Sprint(version))
+	logger.LoggerXds.Infof("New Router cache updated for the label: " + label + " version: " + version)
 	return true
 }
 
+// addUpstreamClientCertSecret registers an upstream client certificate secret, so that it can be
+// included in the router snapshots of the clusters referring it.
+func addUpstreamClientCertSecret(secret *tlsv3.Secret) {
+	mutexForUpstreamClientCertSecrets.Lock()
+	defer mutexForUpstreamClientCertSecrets.Unlock()
+	upstreamClientCertSecrets[secret.Name] = secret
+}
+
+// getUpstreamCerts returns the trusted CA certificates of the endpoints and the interceptors of the API project, and
+// the SDS secret names of the client certificates of the endpoints. The client certificate secrets are registered
+// to be included in the router snapshots.
+func getUpstreamCerts(apiProject model.ProjectAPI) (certMap map[string][]byte, interceptCertMap map[string][]byte,
+	upstreamClientCerts map[string]string) {
+	certMap = make(map[string][]byte)
+	interceptCertMap = make(map[string][]byte)
+	// certificates of specific endpoints and client certificates are not trusted as the default CA certificates
+	nonDefaultCertFiles := make(map[string]struct{})
+	if len(apiProject.EndpointCerts) > 0 && len(apiProject.UpstreamCerts) > 0 {
+		for url, certFile := range apiProject.EndpointCerts {
+			if certBytes, found := apiProject.UpstreamCerts[certFile]; found {
+				certMap[url] = certBytes
+				interceptCertMap[url] = certBytes
+				nonDefaultCertFiles[certFile] = void
+			} else {
+				logger.LoggerXds.ErrorC(logging.ErrorDetails{
+					Message:   fmt.Sprintf("Certificate file %v not found for the url %v", certFile, url),
+					Severity:  logging.MAJOR,
+					ErrorCode: 1406,
+				})
+			}
+		}
+	}
+	upstreamClientCerts = make(map[string]string)
+	for url, clientCert := range apiProject.EndpointClientCerts {
+		nonDefaultCertFiles[clientCert.ClientCertificate] = void
+		certBytes, certFound := apiProject.UpstreamCerts[clientCert.ClientCertificate]
+		keyBytes, keyFound := apiProject.UpstreamClientKeys[clientCert.ClientKey]
+		if !certFound || !keyFound {
+			logger.LoggerXds.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Client certificate file %v or client key file %v not found for the url %v",
+					clientCert.ClientCertificate, clientCert.ClientKey, url),
+				Severity:  logging.MAJOR,
+				ErrorCode: 1420,
+			})
+			continue
+		}
+		secret := envoyconf.CreateUpstreamClientCertSecret(certBytes, keyBytes)
+		addUpstreamClientCertSecret(secret)
+		upstreamClientCerts[url] = secret.Name
+	}
+
+	newLineByteArray := []byte("\n")
+	for certFile, certBytes := range apiProject.UpstreamCerts {
+		if _, found := nonDefaultCertFiles[certFile]; found {
+			continue
+		}
+		certMap["default"] = append(certMap["default"], certBytes...)
+		certMap["default"] = append(certMap["default"], newLineByteArray...)
+	}
+	interceptCertMap["default"] = apiProject.InterceptorCerts
+	return certMap, interceptCertMap, upstreamClientCerts
+}
+
+// pruneUpstreamClientCertSecrets removes the upstream client certificate secrets which are not referred by any of the
+// deployed APIs.
+func pruneUpstreamClientCertSecrets() {
+	referredSecrets := make(map[string]struct{})
+	for _, apiMgwSwaggerMap := range orgIDAPIMgwSwaggerMap {
+		for _, mgwSwagger := range apiMgwSwaggerMap {
+			for _, secretName := range mgwSwagger.GetUpstreamClientCerts() {
+				referredSecrets[secretName] = void
+			}
+		}
+	}
+	mutexForUpstreamClientCertSecrets.Lock()
+	defer mutexForUpstreamClientCertSecrets.Unlock()
+	for secretName := range upstreamClientCertSecrets {
+		if _, found := referredSecrets[secretName]; !found {
+			delete(upstreamClientCertSecrets, secretName)
+		}
+	}
+}
+
+// pruneUpstreamProxyTunnels releases the upstream proxy tunnels which are not referred by the clusters of any of the
+// deployed APIs. The caller must hold the mutexForInternalMapUpdate.
+func pruneUpstreamProxyTunnels() {
+	clusters := make([]*clusterv3.Cluster, 0)
+	for _, apiClustersMap := range orgIDOpenAPIClustersMap {
+		for _, apiClusters := range apiClustersMap {
+			clusters = append(clusters, apiClusters...)
+		}
+	}
+	envoyconf.PruneUpstreamProxyTunnels(clusters)
+}
+
+// GetUpstreamClientCertSecrets returns the upstream client certificate secrets referred by the given clusters.
+func GetUpstreamClientCertSecrets(clusters []types.Resource) []types.Resource {
+	mutexForUpstreamClientCertSecrets.RLock()
+	defer mutexForUpstreamClientCertSecrets.RUnlock()
+	secrets := make([]types.Resource, 0)
+	addedSecrets := make(map[string]struct{})
+	for _, resource := range clusters {
+		cluster, ok := resource.(*clusterv3.Cluster)
+		if !ok {
+			continue
+		}
+		for _, secretName := range envoyconf.GetClusterSecretNames(cluster) {
+			if _, added := addedSecrets[secretName]; added {
+				continue
+			}
+			var secret *tlsv3.Secret
+			if envoyconf.IsDefaultUpstreamClientCertSecret(secretName) {
+				secret = envoyconf.CreateDefaultUpstreamClientCertSecret()
+			} else {
+				secret = upstreamClientCertSecrets[secretName]
+			}
+			if secret == nil {
+				logger.LoggerXds.ErrorC(logging.ErrorDetails{
+					Message:   fmt.Sprintf("Upstream client certificate secret %v not found", secretName),
+					Severity:  logging.MAJOR,
+					ErrorCode: 1421,
+				})
+				continue
+			}
+			addedSecrets[secretName] = void
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
 // UpdateEnforcerConfig Sets new update to the enforcer's configuration
 func UpdateEnforcerConfig(configFile *config.Config) {
 	// TODO: (Praminda) handle labels
@@ -1197,48 +1457,110 @@ func UpdateEnforcerApplicationKeyMappings(applicationKeyMappings *subscription.A
 // UpdateXdsCacheWithLock uses mutex and lock to avoid different go routines updating XDS at the same time
 func UpdateXdsCacheWithLock(label string, endpoints []types.Resource, clusters []types.Resource, routes []types.Resource,
 	listeners []types.Resource) bool {
+	return updateXdsCacheWithLock(label, fmt.Sprint(rand.Intn(maxRandomInt)), nil, endpoints, clusters, routes, listeners)
+}
+
+// updateXdsCacheWithLock updates the Xds cache of the label with the given snapshot version, and tracks the version
+// along with the API changes included in it.
+func updateXdsCacheWithLock(label string, version string, changes []*apiChange, endpoints []types.Resource,
+	clusters []types.Resource, routes []types.Resource, listeners []types.Resource) bool {
 	mutexForXdsUpdate.Lock()
 	defer mutexForXdsUpdate.Unlock()
-	return updateXdsCache(label, endpoints, clusters, routes, listeners)
+	if !updateXdsCache(label, version, endpoints, clusters, routes, listeners) {
+		return false
+	}
+	trackSnapshotVersion(label, version, changes)
+	return true
+}
+
+// APIListQuery holds the filtering, sorting and pagination parameters of the API list.
+// Name and Context are matched as case insensitive substrings, while the rest of the filters are matched exactly.
+type APIListQuery struct {
+	APIType         string
+	Name            string
+	Context         string
+	Vhost           string
+	Environment     string
+	LifeCycleStatus string
+	// SortBy is one of apiName, version or context. APIs are sorted by the name, version and vhost by default.
+	SortBy         string
+	SortDescending bool
+	Offset         int64
+	Limit          *int64
 }
 
 // ListApis returns a list of objects that holds info about each API
-func ListApis(apiType string, organizationID string, limit *int64) *apiModel.APIMeta {
-	var limitValue int
-	if limit == nil {
-		limitValue = len(orgIDAPIMgwSwaggerMap[organizationID])
-	} else {
-		limitValue = int(*limit)
-	}
+func ListApis(query APIListQuery, organizationID string) *apiModel.APIMeta {
 	var apisArray []*apiModel.APIMetaListItem
-	i := 0
 	for apiIdentifier, mgwSwagger := range orgIDAPIMgwSwaggerMap[organizationID] {
-		if i == limitValue {
-			break
+		vhost := "ERROR"
+		if vh, err := ExtractVhostFromAPIIdentifier(apiIdentifier); err == nil {
+			vhost = vh
 		}
-		if apiType == "" || mgwSwagger.GetAPIType() == apiType {
-			var apiMetaListItem apiModel.APIMetaListItem
-			apiMetaListItem.APIName = mgwSwagger.GetTitle()
-			apiMetaListItem.Version = mgwSwagger.GetVersion()
-			apiMetaListItem.APIType = mgwSwagger.GetAPIType()
-			apiMetaListItem.Context = mgwSwagger.GetXWso2Basepath()
-			apiMetaListItem.GatewayEnvs = orgIDOpenAPIEnvoyMap[organizationID][apiIdentifier]
-			vhost := "ERROR"
-			if vh, err := ExtractVhostFromAPIIdentifier(apiIdentifier); err == nil {
-				vhost = vh
-			}
-			apiMetaListItem.Vhost = vhost
-			apisArray = append(apisArray, &apiMetaListItem)
-			i++
+		gatewayEnvs := orgIDOpenAPIEnvoyMap[organizationID][apiIdentifier]
+		if (query.APIType != "" && mgwSwagger.GetAPIType() != query.APIType) ||
+			(query.Name != "" && !strings.Contains(strings.ToLower(mgwSwagger.GetTitle()), strings.ToLower(query.Name))) ||
+			(query.Context != "" && !strings.Contains(strings.ToLower(mgwSwagger.GetXWso2Basepath()), strings.ToLower(query.Context))) ||
+			(query.Vhost != "" && vhost != query.Vhost) ||
+			(query.Environment != "" && !arrayContains(gatewayEnvs, query.Environment)) ||
+			(query.LifeCycleStatus != "" && !strings.EqualFold(mgwSwagger.LifecycleStatus, query.LifeCycleStatus)) {
+			continue
 		}
+		var apiMetaListItem apiModel.APIMetaListItem
+		apiMetaListItem.APIName = mgwSwagger.GetTitle()
+		apiMetaListItem.Version = mgwSwagger.GetVersion()
+		apiMetaListItem.APIType = mgwSwagger.GetAPIType()
+		apiMetaListItem.Context = mgwSwagger.GetXWso2Basepath()
+		apiMetaListItem.GatewayEnvs = gatewayEnvs
+		apiMetaListItem.Vhost = vhost
+		apiMetaListItem.UUID = mgwSwagger.GetID()
+		apiMetaListItem.RevisionID = int64(mgwSwagger.RevisionID)
+		if !mgwSwagger.DeployedTime.IsZero() {
+			apiMetaListItem.DeployedTime = mgwSwagger.DeployedTime.Format(time.RFC3339)
+		}
+		apisArray = append(apisArray, &apiMetaListItem)
 	}
+	sortAPIMetaList(apisArray, query.SortBy, query.SortDescending)
+
 	var apiMetaObject apiModel.APIMeta
-	apiMetaObject.Total = int64(len(orgIDAPIMgwSwaggerMap[organizationID]))
+	apiMetaObject.Total = int64(len(apisArray))
+	if query.Offset >= int64(len(apisArray)) {
+		apisArray = []*apiModel.APIMetaListItem{}
+	} else {
+		apisArray = apisArray[query.Offset:]
+	}
+	if query.Limit != nil && *query.Limit < int64(len(apisArray)) {
+		apisArray = apisArray[:*query.Limit]
+	}
 	apiMetaObject.Count = int64(len(apisArray))
 	apiMetaObject.List = apisArray
 	return &apiMetaObject
 }
 
+// sortAPIMetaList sorts the APIs by the given field. The name, version and vhost are used to break the ties
+// in order, so that the order of the list is stable across the requests.
+func sortAPIMetaList(apisArray []*apiModel.APIMetaListItem, sortBy string, descending bool) {
+	sortKeys := func(api *apiModel.APIMetaListItem) []string {
+		keys := []string{api.APIName, api.Version, api.Vhost, api.Context}
+		switch sortBy {
+		case "version":
+			keys = append([]string{api.Version}, keys...)
+		case "context":
+			keys = append([]string{api.Context}, keys...)
+		}
+		return keys
+	}
+	sort.SliceStable(apisArray, func(i, j int) bool {
+		keysI, keysJ := sortKeys(apisArray[i]), sortKeys(apisArray[j])
+		for k := range keysI {
+			if keysI[k] != keysJ[k] {
+				return (keysI[k] < keysJ[k]) != descending
+			}
+		}
+		return false
+	})
+}
+
 // IsAPIExist returns whether a given API exists
 func IsAPIExist(vhost, uuid, apiName, apiVersion, organizationID string) (exists bool) {
 	if uuid == "" {
@@ -17,9 +17,20 @@
 package xds
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds/common"
+	"github.com/wso2/product-microgateway/adapter/internal/notifier"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/envoyconf"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
 )
 
 func TestGetVhostOfAPI(t *testing.T) {
@@ -149,6 +160,481 @@ func TestGetAllEnvironments(t *testing.T) {
 	}
 }
 
+func TestListApis(t *testing.T) {
+	newMgwSwagger := func(uuid, name, version, context, apiType, lifeCycleStatus string) model.MgwSwagger {
+		var apiYaml model.APIYaml
+		apiYaml.Data.ID = uuid
+		apiYaml.Data.Name = name
+		apiYaml.Data.Version = version
+		apiYaml.Data.Context = context
+		apiYaml.Data.APIType = apiType
+		apiYaml.Data.LifeCycleStatus = lifeCycleStatus
+		var mgwSwagger model.MgwSwagger
+		_ = mgwSwagger.PopulateFromAPIYaml(apiYaml)
+		return mgwSwagger
+	}
+	orgIDAPIMgwSwaggerMap = map[string]map[string]model.MgwSwagger{
+		"org1": {
+			"org1.wso2.com:111": newMgwSwagger("111", "PetStore", "v1", "/pets", "HTTP", "PUBLISHED"),
+			"org1.wso2.com:222": newMgwSwagger("222", "PetStore", "v2", "/pets", "HTTP", "PROTOTYPED"),
+			"org1.foo.com:333":  newMgwSwagger("333", "Pizza", "v1", "/pizza", "HTTP", "PUBLISHED"),
+			"org1.wso2.com:444": newMgwSwagger("444", "Chat", "v1", "/chat", "WS", "PUBLISHED"),
+		},
+	}
+	orgIDOpenAPIEnvoyMap = map[string]map[string][]string{
+		"org1": {
+			"org1.wso2.com:111": {"Default"},
+			"org1.wso2.com:222": {"Default", "us-region"},
+			"org1.foo.com:333":  {"us-region"},
+			"org1.wso2.com:444": {"Default"},
+		},
+	}
+	limit := int64(2)
+	tests := []struct {
+		name  string
+		query APIListQuery
+		total int64
+		uuids []string
+	}{
+		{
+			name:  "List_all_sorted_by_name_and_version",
+			query: APIListQuery{},
+			total: 4,
+			uuids: []string{"444", "111", "222", "333"},
+		},
+		{
+			name:  "List_with_offset_and_limit",
+			query: APIListQuery{Offset: 1, Limit: &limit},
+			total: 4,
+			uuids: []string{"111", "222"},
+		},
+		{
+			name:  "List_with_offset_exceeding_total",
+			query: APIListQuery{Offset: 10},
+			total: 4,
+			uuids: []string{},
+		},
+		{
+			name:  "Sort_by_context_descending",
+			query: APIListQuery{SortBy: "context", SortDescending: true},
+			total: 4,
+			uuids: []string{"333", "222", "111", "444"},
+		},
+		{
+			name:  "Filter_by_name_substring_and_environment",
+			query: APIListQuery{Name: "pet", Environment: "us-region"},
+			total: 1,
+			uuids: []string{"222"},
+		},
+		{
+			name:  "Filter_by_context_substring_case_insensitive",
+			query: APIListQuery{Context: "/PIZ"},
+			total: 1,
+			uuids: []string{"333"},
+		},
+		{
+			name:  "Filter_by_type_vhost_and_lifecycle_status",
+			query: APIListQuery{APIType: "HTTP", Vhost: "org1.wso2.com", LifeCycleStatus: "published"},
+			total: 1,
+			uuids: []string{"111"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apiMeta := ListApis(test.query, "org1")
+			uuids := []string{}
+			for _, api := range apiMeta.List {
+				uuids = append(uuids, api.UUID)
+			}
+			if !reflect.DeepEqual(test.uuids, uuids) {
+				t.Errorf("expected APIs %v but found %v", test.uuids, uuids)
+			}
+			if apiMeta.Total != test.total {
+				t.Errorf("expected total %v but found %v", test.total, apiMeta.Total)
+			}
+			if apiMeta.Count != int64(len(test.uuids)) {
+				t.Errorf("expected count %v but found %v", len(test.uuids), apiMeta.Count)
+			}
+		})
+	}
+}
+
+func TestExecuteAPIBatch(t *testing.T) {
+	var apiYaml model.APIYaml
+	apiYaml.Data.Name = "PetStore"
+	apiYaml.Data.Version = "v1"
+	var mgwSwagger model.MgwSwagger
+	_ = mgwSwagger.PopulateFromAPIYaml(apiYaml)
+
+	apiID := GenerateHashedAPINameVersionIDWithoutVhost("PetStore", "v1")
+	apiIdentifier := GenerateIdentifierForAPIWithUUID("org1.wso2.com", apiID)
+	reverseAPINameVersionMap = map[string]string{GenerateIdentifierForAPIWithoutVhost("PetStore", "v1"): apiID}
+	apiToVhostsMap = map[string]map[string]struct{}{apiID: {"org1.wso2.com": void}}
+	apiUUIDToGatewayToVhosts = map[string]map[string]string{}
+	orgIDAPIMgwSwaggerMap = map[string]map[string]model.MgwSwagger{"org1": {apiIdentifier: mgwSwagger}}
+	orgIDOpenAPIEnvoyMap = map[string]map[string][]string{"org1": {apiIdentifier: {"Default"}}}
+
+	batchErr := errors.New("batch failed")
+	err := ExecuteAPIBatch(func(batch *APIBatch) error {
+		if err := batch.DeleteAPIs("", "PetStore", "v1", nil, "org1"); err != nil {
+			t.Fatalf("unexpected error while deleting the API: %v", err)
+		}
+		if batch.IsAPIExist("org1.wso2.com", "", "PetStore", "v1", "org1") {
+			t.Error("expected the API to be deleted within the batch")
+		}
+		if _, found := batch.labels["Default"]; !found {
+			t.Errorf("expected the label Default to be updated with the batch, but found %v", batch.labels)
+		}
+		return batchErr
+	})
+	if err != batchErr {
+		t.Errorf("expected the error of the batch but found %v", err)
+	}
+	if activeAPIBatch != nil {
+		t.Error("expected no active batch after the batch is rolled back")
+	}
+	if !IsAPIExist("org1.wso2.com", "", "PetStore", "v1", "org1") {
+		t.Error("expected the API to be restored after the batch is rolled back")
+	}
+	if labels := orgIDOpenAPIEnvoyMap["org1"][apiIdentifier]; !reflect.DeepEqual(labels, []string{"Default"}) {
+		t.Errorf("expected the labels of the API to be restored but found %v", labels)
+	}
+	if _, found := apiToVhostsMap[apiID]["org1.wso2.com"]; !found {
+		t.Errorf("expected the vhosts of the API to be restored but found %v", apiToVhostsMap[apiID])
+	}
+
+	err = ExecuteAPIBatch(func(batch *APIBatch) error {
+		return batch.DeleteAPIs("", "PetStore", "v1", nil, "org1")
+	})
+	if err != nil {
+		t.Errorf("unexpected error while committing the batch: %v", err)
+	}
+	if IsAPIExist("org1.wso2.com", "", "PetStore", "v1", "org1") {
+		t.Error("expected the API to be deleted after the batch is committed")
+	}
+}
+
+func TestExecuteAPIBatchRollbackOfSnapshotTracking(t *testing.T) {
+	var apiYaml model.APIYaml
+	apiYaml.Data.Name = "PetStore"
+	apiYaml.Data.Version = "v1"
+	var mgwSwagger model.MgwSwagger
+	_ = mgwSwagger.PopulateFromAPIYaml(apiYaml)
+
+	apiID := GenerateHashedAPINameVersionIDWithoutVhost("PetStore", "v1")
+	apiIdentifier := GenerateIdentifierForAPIWithUUID("org1.wso2.com", apiID)
+	reverseAPINameVersionMap = map[string]string{GenerateIdentifierForAPIWithoutVhost("PetStore", "v1"): apiID}
+	apiToVhostsMap = map[string]map[string]struct{}{apiID: {"org1.wso2.com": void}}
+	apiUUIDToGatewayToVhosts = map[string]map[string]string{}
+	orgIDAPIMgwSwaggerMap = map[string]map[string]model.MgwSwagger{"org1": {apiIdentifier: mgwSwagger}}
+	orgIDOpenAPIEnvoyMap = map[string]map[string][]string{"org1": {apiIdentifier: {"Default"}}}
+	orgIDvHostBasepathMap = map[string]map[string]string{}
+	upstreamClientCertSecrets = map[string]*tlsv3.Secret{"existing": {Name: "existing"}}
+	pendingAPIChanges = make(map[string]*apiChange)
+	revisionChanges = make(map[*notifier.DeployedAPIRevision]*apiChange)
+
+	// A change recorded before the batch, which is not yet included in a cache update.
+	existingChange := recordAPIChange("org2", "org2.wso2.com:existing")
+	existingRevision := notifier.UpdateDeployedRevisions("existing", 1, []string{"Default"}, "org2.wso2.com")
+	setDeployedRevision(existingChange, existingRevision)
+
+	batchErr := errors.New("batch failed")
+	batchRevision := notifier.UpdateDeployedRevisions(apiID, 2, []string{"Default"}, "org1.wso2.com")
+	err := ExecuteAPIBatch(func(batch *APIBatch) error {
+		if err := batch.DeleteAPIs("", "PetStore", "v1", nil, "org1"); err != nil {
+			t.Fatalf("unexpected error while deleting the API: %v", err)
+		}
+		if len(pendingAPIChanges) != 2 {
+			t.Errorf("expected the change of the batch to be recorded but found %v", pendingAPIChanges)
+		}
+		setDeployedRevision(pendingAPIChanges["org1"+apiKeyFieldSeparator+apiIdentifier], batchRevision)
+		setDeployedRevision(existingChange, batchRevision)
+		addUpstreamClientCertSecret(&tlsv3.Secret{Name: "added"})
+		return batchErr
+	})
+	if err != batchErr {
+		t.Errorf("expected the error of the batch but found %v", err)
+	}
+	if len(pendingAPIChanges) != 1 || pendingAPIChanges[existingChange.key] != existingChange {
+		t.Errorf("expected only the change recorded before the batch to remain pending but found %v",
+			pendingAPIChanges)
+	}
+	if existingChange.deployedRevision != existingRevision {
+		t.Error("expected the revision of the change recorded before the batch to be restored")
+	}
+	if len(revisionChanges) != 1 || revisionChanges[existingRevision] != existingChange {
+		t.Errorf("expected the revisions deployed within the batch to be discarded but found %v", revisionChanges)
+	}
+	if _, found := upstreamClientCertSecrets["added"]; found || len(upstreamClientCertSecrets) != 1 {
+		t.Errorf("expected the client certificate secrets to be restored but found %v", upstreamClientCertSecrets)
+	}
+	pendingAPIChanges = make(map[string]*apiChange)
+	revisionChanges = make(map[*notifier.DeployedAPIRevision]*apiChange)
+}
+
+func TestGetUpstreamCerts(t *testing.T) {
+	upstreamClientCertSecrets = make(map[string]*tlsv3.Secret)
+	apiProject := model.ProjectAPI{
+		UpstreamCerts: map[string][]byte{
+			"shared.crt":  []byte("shared"),
+			"ca.crt":      []byte("ca"),
+			"default.crt": []byte("default"),
+		},
+		UpstreamClientKeys: map[string][]byte{"client.key": []byte("key")},
+		// The client certificate of an endpoint is trusted as the CA certificate of another endpoint.
+		EndpointCerts: map[string]string{
+			"https://ca.wso2.com":     "ca.crt",
+			"https://shared.wso2.com": "shared.crt",
+		},
+		EndpointClientCerts: map[string]model.EndpointCertificate{
+			"https://mtls.wso2.com": {ClientCertificate: "shared.crt", ClientKey: "client.key"},
+		},
+		InterceptorCerts: []byte("interceptor"),
+	}
+
+	certMap, interceptCertMap, upstreamClientCerts := getUpstreamCerts(apiProject)
+	if string(certMap["https://shared.wso2.com"]) != "shared" || string(certMap["https://ca.wso2.com"]) != "ca" {
+		t.Errorf("expected the certificates of the endpoints to be trusted but found %v", certMap)
+	}
+	if string(certMap["default"]) != "default\n" {
+		t.Errorf("expected only the certificates not specific to an endpoint to be trusted by default but found %q",
+			certMap["default"])
+	}
+	if string(interceptCertMap["default"]) != "interceptor" || string(interceptCertMap["https://ca.wso2.com"]) != "ca" {
+		t.Errorf("expected the interceptor certificates to be trusted but found %v", interceptCertMap)
+	}
+	secretName := upstreamClientCerts["https://mtls.wso2.com"]
+	if secretName == "" || upstreamClientCertSecrets[secretName] == nil {
+		t.Errorf("expected the client certificate secret of the endpoint to be registered but found %v",
+			upstreamClientCerts)
+	}
+	if len(apiProject.UpstreamCerts) != 3 {
+		t.Errorf("expected the certificates of the API project not to be modified but found %v",
+			apiProject.UpstreamCerts)
+	}
+}
+
+func TestPruneUpstreamClientCertSecretsOnUndeploy(t *testing.T) {
+	newMgwSwagger := func(name string, upstreamClientCerts map[string]string) model.MgwSwagger {
+		var apiYaml model.APIYaml
+		apiYaml.Data.Name = name
+		apiYaml.Data.Version = "v1"
+		var mgwSwagger model.MgwSwagger
+		_ = mgwSwagger.PopulateFromAPIYaml(apiYaml)
+		mgwSwagger.SetUpstreamClientCerts(upstreamClientCerts)
+		return mgwSwagger
+	}
+	petStoreID := GenerateHashedAPINameVersionIDWithoutVhost("PetStore", "v1")
+	pizzaID := GenerateHashedAPINameVersionIDWithoutVhost("Pizza", "v1")
+	petStoreIdentifier := GenerateIdentifierForAPIWithUUID("org1.wso2.com", petStoreID)
+	pizzaIdentifier := GenerateIdentifierForAPIWithUUID("org1.wso2.com", pizzaID)
+	reverseAPINameVersionMap = map[string]string{
+		GenerateIdentifierForAPIWithoutVhost("PetStore", "v1"): petStoreID,
+		GenerateIdentifierForAPIWithoutVhost("Pizza", "v1"):    pizzaID,
+	}
+	apiToVhostsMap = map[string]map[string]struct{}{
+		petStoreID: {"org1.wso2.com": void},
+		pizzaID:    {"org1.wso2.com": void},
+	}
+	apiUUIDToGatewayToVhosts = map[string]map[string]string{}
+	orgIDAPIMgwSwaggerMap = map[string]map[string]model.MgwSwagger{"org1": {
+		petStoreIdentifier: newMgwSwagger("PetStore", map[string]string{
+			"https://pets.wso2.com":   "petstore-secret",
+			"https://shared.wso2.com": "shared-secret",
+		}),
+		pizzaIdentifier: newMgwSwagger("Pizza", map[string]string{"https://shared.wso2.com": "shared-secret"}),
+	}}
+	orgIDOpenAPIEnvoyMap = map[string]map[string][]string{"org1": {
+		petStoreIdentifier: {"Default"},
+		pizzaIdentifier:    {"Default"},
+	}}
+	orgIDvHostBasepathMap = map[string]map[string]string{}
+	upstreamClientCertSecrets = map[string]*tlsv3.Secret{
+		"petstore-secret": {Name: "petstore-secret"},
+		"shared-secret":   {Name: "shared-secret"},
+	}
+
+	if err := DeleteAPIs("", "PetStore", "v1", nil, "org1"); err != nil {
+		t.Fatalf("unexpected error while deleting the API: %v", err)
+	}
+	if _, found := upstreamClientCertSecrets["petstore-secret"]; found {
+		t.Errorf("expected the client certificate secret of the undeployed API to be removed but found %v",
+			upstreamClientCertSecrets)
+	}
+	if _, found := upstreamClientCertSecrets["shared-secret"]; !found {
+		t.Errorf("expected the client certificate secret of the remaining API to be kept but found %v",
+			upstreamClientCertSecrets)
+	}
+	pendingAPIChanges = make(map[string]*apiChange)
+	revisionChanges = make(map[*notifier.DeployedAPIRevision]*apiChange)
+}
+
+func TestReleaseUpstreamProxyTunnelsOnUndeploy(t *testing.T) {
+	getProxiedAPI := func(name, endpoint string) (model.MgwSwagger, []*clusterv3.Cluster) {
+		openAPI := fmt.Sprintf(`openapi: 3.0.0
+info:
+  title: %s
+  version: v1
+x-wso2-basePath: /%s
+x-wso2-production-endpoints:
+  urls:
+    - %s
+x-wso2-proxy:
+  host: proxy.wso2.com
+  port: 3128
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+`, name, strings.ToLower(name), endpoint)
+		var mgwSwagger model.MgwSwagger
+		if err := mgwSwagger.GetMgwSwagger([]byte(openAPI)); err != nil {
+			t.Fatalf("error while creating the MgwSwagger: %v", err)
+		}
+		_, clusters, _ := envoyconf.CreateRoutesWithClusters(mgwSwagger, nil, nil, "org1.wso2.com", "org1")
+		return mgwSwagger, clusters
+	}
+	petStore, petStoreClusters := getProxiedAPI("PetStore", "https://pets.wso2.com")
+	pizza, pizzaClusters := getProxiedAPI("Pizza", "https://pizza.wso2.com")
+	if _, listeners := envoyconf.GetUpstreamProxyTunnelResources(petStoreClusters); len(listeners) != 1 {
+		t.Fatalf("expected a tunnel for the endpoint of the API but found %v", listeners)
+	}
+
+	petStoreID := GenerateHashedAPINameVersionIDWithoutVhost("PetStore", "v1")
+	pizzaID := GenerateHashedAPINameVersionIDWithoutVhost("Pizza", "v1")
+	petStoreIdentifier := GenerateIdentifierForAPIWithUUID("org1.wso2.com", petStoreID)
+	pizzaIdentifier := GenerateIdentifierForAPIWithUUID("org1.wso2.com", pizzaID)
+	reverseAPINameVersionMap = map[string]string{
+		GenerateIdentifierForAPIWithoutVhost("PetStore", "v1"): petStoreID,
+		GenerateIdentifierForAPIWithoutVhost("Pizza", "v1"):    pizzaID,
+	}
+	apiToVhostsMap = map[string]map[string]struct{}{
+		petStoreID: {"org1.wso2.com": void},
+		pizzaID:    {"org1.wso2.com": void},
+	}
+	apiUUIDToGatewayToVhosts = map[string]map[string]string{}
+	orgIDAPIMgwSwaggerMap = map[string]map[string]model.MgwSwagger{"org1": {
+		petStoreIdentifier: petStore,
+		pizzaIdentifier:    pizza,
+	}}
+	orgIDOpenAPIEnvoyMap = map[string]map[string][]string{"org1": {
+		petStoreIdentifier: {"Default"},
+		pizzaIdentifier:    {"Default"},
+	}}
+	orgIDOpenAPIClustersMap = map[string]map[string][]*clusterv3.Cluster{"org1": {
+		petStoreIdentifier: petStoreClusters,
+		pizzaIdentifier:    pizzaClusters,
+	}}
+	orgIDvHostBasepathMap = map[string]map[string]string{}
+
+	if err := DeleteAPIs("", "PetStore", "v1", nil, "org1"); err != nil {
+		t.Fatalf("unexpected error while deleting the API: %v", err)
+	}
+	if _, listeners := envoyconf.GetUpstreamProxyTunnelResources(petStoreClusters); len(listeners) != 0 {
+		t.Errorf("expected the tunnel of the undeployed API to be released but found %v", listeners)
+	}
+	if _, listeners := envoyconf.GetUpstreamProxyTunnelResources(pizzaClusters); len(listeners) != 1 {
+		t.Errorf("expected the tunnel of the remaining API to be kept but found %v", listeners)
+	}
+	pendingAPIChanges = make(map[string]*apiChange)
+	revisionChanges = make(map[*notifier.DeployedAPIRevision]*apiChange)
+}
+
+func TestRollbackOnRejectedSnapshot(t *testing.T) {
+	var apiYaml model.APIYaml
+	apiYaml.Data.Name = "PetStore"
+	apiYaml.Data.Version = "v1"
+	var mgwSwagger model.MgwSwagger
+	_ = mgwSwagger.PopulateFromAPIYaml(apiYaml)
+
+	apiID := GenerateHashedAPINameVersionIDWithoutVhost("PetStore", "v1")
+	apiIdentifier := GenerateIdentifierForAPIWithUUID("org1.wso2.com", apiID)
+	reverseAPINameVersionMap = map[string]string{GenerateIdentifierForAPIWithoutVhost("PetStore", "v1"): apiID}
+	apiToVhostsMap = map[string]map[string]struct{}{apiID: {"org1.wso2.com": void}}
+	apiUUIDToGatewayToVhosts = map[string]map[string]string{}
+	orgIDAPIMgwSwaggerMap = map[string]map[string]model.MgwSwagger{"org1": {apiIdentifier: mgwSwagger}}
+	orgIDOpenAPIEnvoyMap = map[string]map[string][]string{"org1": {apiIdentifier: {"Default"}}}
+	orgIDvHostBasepathMap = map[string]map[string]string{}
+	labelSnapshotVersions = make(map[string][]*snapshotVersion)
+	routerAcceptedVersions = make(map[string]map[string]string)
+	revisionChanges = make(map[*notifier.DeployedAPIRevision]*apiChange)
+
+	// A router is connected to the label, hence the changes wait until the router accepts them.
+	handleRouterAccept("Default", "Default:router1", "")
+	if err := DeleteAPIs("", "PetStore", "v1", nil, "org1"); err != nil {
+		t.Fatalf("unexpected error while deleting the API: %v", err)
+	}
+	if IsAPIExist("org1.wso2.com", "", "PetStore", "v1", "org1") {
+		t.Fatal("expected the API to be deleted")
+	}
+	versions := labelSnapshotVersions["Default"]
+	if len(versions) == 0 || len(versions[0].changes) != 1 {
+		t.Fatalf("expected the change of the API to be tracked with the snapshot version but found %v", versions)
+	}
+	change := versions[0].changes[0]
+	if change.status != apiChangePending {
+		t.Errorf("expected the change to wait for the router but found the status %v", change.status)
+	}
+	deployedRevision := notifier.UpdateDeployedRevisions(apiID, 1, []string{"Default"}, "org1.wso2.com")
+	setDeployedRevision(change, deployedRevision)
+	SendRevisionUpdateAckOnAccept([]*notifier.DeployedAPIRevision{deployedRevision})
+	if !change.isReportRequested {
+		t.Error("expected the deployment status to be reported once the router accepts or rejects the change")
+	}
+
+	rejectedVersion := versions[len(versions)-1].version
+	handleRejectedSnapshot(RequestEvent{
+		IsError:         true,
+		Label:           "Default",
+		Node:            "Default:router1",
+		TypeURL:         "type.googleapis.com/envoy.config.route.v3.RouteConfiguration",
+		RejectedVersion: rejectedVersion,
+		ErrorMessage:    "invalid route",
+	})
+	if change.status != apiChangeRolledBack {
+		t.Errorf("expected the change to be rolled back but found the status %v", change.status)
+	}
+	if _, found := revisionChanges[deployedRevision]; found {
+		t.Error("expected the failed deployment of the revision to be reported")
+	}
+	if !IsAPIExist("org1.wso2.com", "", "PetStore", "v1", "org1") {
+		t.Error("expected the API to be restored after the snapshot is rejected")
+	}
+	if labels := orgIDOpenAPIEnvoyMap["org1"][apiIdentifier]; !reflect.DeepEqual(labels, []string{"Default"}) {
+		t.Errorf("expected the labels of the API to be restored but found %v", labels)
+	}
+	if _, found := apiToVhostsMap[apiID]["org1.wso2.com"]; !found {
+		t.Errorf("expected the vhosts of the API to be restored but found %v", apiToVhostsMap[apiID])
+	}
+	versions = labelSnapshotVersions["Default"]
+	if versions[len(versions)-1].version == rejectedVersion {
+		t.Error("expected the caches to be updated with the rolled back API")
+	}
+
+	// A rejected version which is not tracked does not roll back anything.
+	handleRejectedSnapshot(RequestEvent{IsError: true, Label: "Default", RejectedVersion: "unknown"})
+	if !IsAPIExist("org1.wso2.com", "", "PetStore", "v1", "org1") {
+		t.Error("expected the API to be unchanged when an untracked version is rejected")
+	}
+
+	trackedVersionCount := len(versions)
+	if err := DeleteAPIs("", "PetStore", "v1", nil, "org1"); err != nil {
+		t.Fatalf("unexpected error while deleting the API: %v", err)
+	}
+	versions = labelSnapshotVersions["Default"]
+	change = versions[trackedVersionCount].changes[0]
+	handleRouterAccept("Default", "Default:router1", versions[len(versions)-1].version)
+	if change.status != apiChangeAccepted {
+		t.Errorf("expected the change to be accepted but found the status %v", change.status)
+	}
+	if IsAPIExist("org1.wso2.com", "", "PetStore", "v1", "org1") {
+		t.Error("expected the API to remain deleted once the router accepts the snapshot")
+	}
+}
+
 func setupInternalMemoryMapsWithTestSamples() {
 	apiToVhostsMap = map[string]map[string]struct{}{
 		// The same API uuid is deployed in two org with two gateway environments
@@ -176,3 +662,67 @@ func setupInternalMemoryMapsWithTestSamples() {
 		},
 	}
 }
+
+func TestGetXdsCacheStatus(t *testing.T) {
+	const cacheName = "test-status-cache"
+	const typeURL = "type.googleapis.com/test.Resource"
+	lastWatchTime := time.Date(2022, 5, 10, 8, 30, 0, 0, time.UTC)
+	cacheInfo := xdsCacheInfo{
+		name: cacheName,
+		getStatusKeys: func() []string {
+			return []string{"Default", "us-region"}
+		},
+		getWatchStatus: func(label string) (int, time.Time) {
+			return 2, lastWatchTime
+		},
+		getVersion: func(label, typeURL string) string {
+			return "v2"
+		},
+	}
+
+	registry := common.GetStreamRegistry(cacheName)
+	// node-a is in sync on both of its streams.
+	registry.RecordRequest(1, "Default", "Default:node-a", typeURL, "v2", "")
+	registry.RecordRequest(2, "Default", "Default:node-a", typeURL, "v2", "")
+	// node-b rejected the latest version and holds the previous one.
+	registry.SetSentVersion(3, typeURL, "v2")
+	registry.RecordRequest(3, "Default", "Default:node-b", typeURL, "v1", "invalid resource")
+	registry.RecordRequest(4, "us-region", "us-region:node-c", typeURL, "v2", "")
+	// A stream without requests is not reported.
+	registry.SetSentVersion(5, typeURL, "v2")
+	defer func() {
+		for streamID := int64(1); streamID <= 5; streamID++ {
+			registry.RemoveStream(streamID)
+		}
+	}()
+
+	status := getXdsCacheStatus(cacheInfo, "")
+	if status.Name != cacheName || status.StreamCount != 4 {
+		t.Fatalf("unexpected cache status: name %v, stream count %v", status.Name, status.StreamCount)
+	}
+	if len(status.Labels) != 2 || status.Labels[0].Label != "Default" || status.Labels[0].NumWatches != 2 ||
+		status.Labels[0].LastWatchRequestTime != "2022-05-10T08:30:00Z" {
+		t.Fatalf("unexpected label status: %v", status.Labels)
+	}
+	if len(status.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, found %v", len(status.Nodes))
+	}
+	nodeA, nodeB := status.Nodes[0], status.Nodes[1]
+	if nodeA.NodeIdentifier != "Default:node-a" || nodeA.StreamCount != 2 || !nodeA.InSync {
+		t.Errorf("unexpected status of node-a: %+v", nodeA)
+	}
+	if nodeB.NodeIdentifier != "Default:node-b" || nodeB.InSync || len(nodeB.Resources) != 1 {
+		t.Fatalf("unexpected status of node-b: %+v", nodeB)
+	}
+	resource := nodeB.Resources[0]
+	if resource.TypeURL != typeURL || resource.CurrentVersion != "v2" || resource.SentVersion != "v2" ||
+		resource.AckedVersion != "v1" || resource.NackMessage != "invalid resource" {
+		t.Errorf("unexpected resource status of node-b: %+v", resource)
+	}
+
+	status = getXdsCacheStatus(cacheInfo, "us-region")
+	if status.StreamCount != 1 || len(status.Labels) != 1 || len(status.Nodes) != 1 ||
+		status.Nodes[0].NodeIdentifier != "us-region:node-c" {
+		t.Errorf("unexpected status of the us-region label: %+v", status)
+	}
+}
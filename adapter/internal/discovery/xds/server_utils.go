@@ -17,7 +17,11 @@
 package xds
 
 import (
+	"sort"
+
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"google.golang.org/protobuf/proto"
 )
 
 // getEnvironmentsToBeDeleted returns an slice of environments APIs to be u-deployed from
@@ -81,3 +85,39 @@ func updateVhostInternalMaps(uuid, name, version, vHost string, gwEnvs []string)
 	}
 	apiUUIDToGatewayToVhosts[uuid] = envToVhostMap
 }
+
+// apiErrorResponseMappers holds the local reply mappers generated for the custom error responses of an API.
+type apiErrorResponseMappers struct {
+	apiKey   string
+	basePath string
+	mappers  []*hcmv3.ResponseMapper
+}
+
+// sortAPIErrorResponseMappers orders the error response mappers of the APIs so that the mappers of the APIs with
+// longer basepaths are evaluated first. (/foo/v2 should be matched prior to /foo). The API key is used to keep
+// the order stable across the xds updates.
+func sortAPIErrorResponseMappers(apiErrorResponses []apiErrorResponseMappers) []*hcmv3.ResponseMapper {
+	sort.SliceStable(apiErrorResponses, func(i, j int) bool {
+		if len(apiErrorResponses[i].basePath) != len(apiErrorResponses[j].basePath) {
+			return len(apiErrorResponses[i].basePath) > len(apiErrorResponses[j].basePath)
+		}
+		return apiErrorResponses[i].apiKey < apiErrorResponses[j].apiKey
+	})
+	var mappers []*hcmv3.ResponseMapper
+	for _, apiErrorResponse := range apiErrorResponses {
+		mappers = append(mappers, apiErrorResponse.mappers...)
+	}
+	return mappers
+}
+
+func isSameErrorResponseMappers(existing, updated []*hcmv3.ResponseMapper) bool {
+	if len(existing) != len(updated) {
+		return false
+	}
+	for i := range existing {
+		if !proto.Equal(existing[i], updated[i]) {
+			return false
+		}
+	}
+	return true
+}
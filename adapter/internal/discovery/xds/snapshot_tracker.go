@@ -0,0 +1,514 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package xds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	envoy_resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+
+	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/internal/notifier"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
+)
+
+// maxTrackedSnapshotVersions is the maximum number of snapshot versions tracked per label.
+const maxTrackedSnapshotVersions int = 50
+
+type apiChangeStatus int
+
+const (
+	apiChangePending apiChangeStatus = iota
+	apiChangeAccepted
+	apiChangeRolledBack
+)
+
+var (
+	// mutexForSnapshotTracking guards the snapshot tracking state. If the mutexForInternalMapUpdate or the
+	// mutexForXdsUpdate is required along with it, they must be acquired first.
+	mutexForSnapshotTracking sync.Mutex
+
+	// pendingAPIChanges holds the changes of the APIs updated after the last cache update.
+	// (organizationID:Vhost:API_UUID -> API change)
+	pendingAPIChanges = make(map[string]*apiChange)
+	// labelSnapshotVersions holds the last snapshot versions pushed to each label, in the order they are pushed.
+	// (GW-Label -> snapshot versions)
+	labelSnapshotVersions = make(map[string][]*snapshotVersion)
+	// routerAcceptedVersions holds the last version accepted by each router connected to a label.
+	// (GW-Label -> node identifier -> version)
+	routerAcceptedVersions = make(map[string]map[string]string)
+	// revisionChanges maps the deployed API revisions to the changes which deployed them, until the deployment
+	// status of the revision is reported to the control plane.
+	revisionChanges = make(map[*notifier.DeployedAPIRevision]*apiChange)
+)
+
+// apiChange is a change done to an API in the internal maps, along with the state of the API before the change.
+type apiChange struct {
+	key            string
+	organizationID string
+	apiIdentifier  string
+	previousState  *apiState
+	status         apiChangeStatus
+	// unacceptedLabels are the labels of which the routers have not accepted a snapshot containing the change.
+	unacceptedLabels map[string]struct{}
+	// isTracked is true once the snapshots containing the change are pushed to all the labels.
+	isTracked        bool
+	deployedRevision *notifier.DeployedAPIRevision
+	// isReportRequested is true if the deployment status of the revision is to be reported as soon as it is known.
+	isReportRequested bool
+	failureMessage    string
+}
+
+// snapshotVersion is a snapshot version pushed to a label, along with the API changes first pushed with it.
+type snapshotVersion struct {
+	version string
+	changes []*apiChange
+	// isAccepted is true once a router of the label accepts the version or a later version.
+	isAccepted bool
+}
+
+// apiState is the state of an API in the internal maps.
+type apiState struct {
+	exists          bool
+	mgwSwagger      model.MgwSwagger
+	labels          []string
+	routes          []*routev3.Route
+	clusters        []*clusterv3.Cluster
+	endpoints       []*corev3.Address
+	enforcerAPI     types.Resource
+	gatewayToVhosts map[string]string
+	vhosts          map[string]struct{}
+}
+
+// recordAPIChange records the state of the API before it is changed. If the API is changed multiple times
+// before the caches are updated, the state before the first change is kept.
+// The caller must hold the mutexForInternalMapUpdate.
+func recordAPIChange(organizationID, apiIdentifier string) *apiChange {
+	key := organizationID + apiKeyFieldSeparator + apiIdentifier
+	mutexForSnapshotTracking.Lock()
+	defer mutexForSnapshotTracking.Unlock()
+	if change, found := pendingAPIChanges[key]; found {
+		return change
+	}
+	change := &apiChange{
+		key:              key,
+		organizationID:   organizationID,
+		apiIdentifier:    apiIdentifier,
+		previousState:    captureAPIState(organizationID, apiIdentifier),
+		status:           apiChangePending,
+		unacceptedLabels: make(map[string]struct{}),
+	}
+	pendingAPIChanges[key] = change
+	return change
+}
+
+// setDeployedRevision sets the revision deployed by the change, so that the deployment status of the revision
+// is reported once the routers accept or reject the change.
+func setDeployedRevision(change *apiChange, deployedRevision *notifier.DeployedAPIRevision) {
+	if change == nil || deployedRevision == nil {
+		return
+	}
+	mutexForSnapshotTracking.Lock()
+	defer mutexForSnapshotTracking.Unlock()
+	change.deployedRevision = deployedRevision
+	revisionChanges[deployedRevision] = change
+}
+
+// takePendingAPIChanges returns the changes of the APIs updated after the last cache update, and clears them.
+func takePendingAPIChanges() []*apiChange {
+	mutexForSnapshotTracking.Lock()
+	defer mutexForSnapshotTracking.Unlock()
+	keys := make([]string, 0, len(pendingAPIChanges))
+	for key := range pendingAPIChanges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	changes := make([]*apiChange, 0, len(keys))
+	for _, key := range keys {
+		changes = append(changes, pendingAPIChanges[key])
+	}
+	pendingAPIChanges = make(map[string]*apiChange)
+	return changes
+}
+
+// trackSnapshotVersion tracks the snapshot version pushed to the routers of the label, along with the API
+// changes included in a snapshot of the label for the first time. The caller must hold the mutexForXdsUpdate,
+// so that the versions are tracked in the order they are pushed.
+func trackSnapshotVersion(label, version string, changes []*apiChange) {
+	mutexForSnapshotTracking.Lock()
+	defer mutexForSnapshotTracking.Unlock()
+	if _, hasRouters := routerAcceptedVersions[label]; hasRouters {
+		for _, change := range changes {
+			change.unacceptedLabels[label] = void
+		}
+	}
+	versions := append(labelSnapshotVersions[label], &snapshotVersion{version: version, changes: changes})
+	if len(versions) > maxTrackedSnapshotVersions {
+		// The dropped versions are considered as accepted, so that the deployment status reports are not
+		// blocked by the routers which do not respond.
+		dropped := len(versions) - maxTrackedSnapshotVersions
+		go notifier.SendRevisionUpdateAck(acceptSnapshotVersions(label, versions[:dropped]))
+		versions = versions[dropped:]
+	}
+	labelSnapshotVersions[label] = versions
+}
+
+// completeAPIChanges marks that the snapshots containing the changes are pushed to all the labels. The changes
+// are accepted if none of the labels have routers to wait for.
+func completeAPIChanges(changes []*apiChange) {
+	mutexForSnapshotTracking.Lock()
+	defer mutexForSnapshotTracking.Unlock()
+	for _, change := range changes {
+		change.isTracked = true
+		if change.status == apiChangePending && len(change.unacceptedLabels) == 0 {
+			change.status = apiChangeAccepted
+		}
+	}
+}
+
+// SendRevisionUpdateAckOnAccept reports the given revisions as deployed to the control plane, once the routers
+// accept the snapshots containing them. The revisions of which the snapshots are rejected by the routers or the
+// enforcers are reported as failed.
+func SendRevisionUpdateAckOnAccept(deployedRevisionList []*notifier.DeployedAPIRevision) {
+	var acceptedRevisions []*notifier.DeployedAPIRevision
+	mutexForSnapshotTracking.Lock()
+	for _, deployedRevision := range deployedRevisionList {
+		change, found := revisionChanges[deployedRevision]
+		if !found {
+			acceptedRevisions = append(acceptedRevisions, deployedRevision)
+			continue
+		}
+		switch change.status {
+		case apiChangeAccepted:
+			delete(revisionChanges, deployedRevision)
+			acceptedRevisions = append(acceptedRevisions, deployedRevision)
+		case apiChangeRolledBack:
+			delete(revisionChanges, deployedRevision)
+			reportFailedDeployment(deployedRevision, change.failureMessage)
+		default:
+			change.isReportRequested = true
+		}
+	}
+	mutexForSnapshotTracking.Unlock()
+	notifier.SendRevisionUpdateAck(acceptedRevisions)
+}
+
+// handleRequestEvents handles the events published by the xds callbacks.
+func handleRequestEvents(requestEvents chan RequestEvent) {
+	for requestEvent := range requestEvents {
+		switch {
+		case requestEvent.IsDisconnected:
+			if !requestEvent.IsEnforcer {
+				handleRouterDisconnect(requestEvent.Label, requestEvent.Node)
+			}
+		case requestEvent.IsError:
+			handleRejectedSnapshot(requestEvent)
+		case !requestEvent.IsEnforcer && requestEvent.TypeURL == envoy_resource.RouteType:
+			handleRouterAccept(requestEvent.Label, requestEvent.Node, requestEvent.Version)
+		}
+	}
+}
+
+// handleRouterAccept marks the snapshot versions of the label up to the version accepted by the router as
+// accepted. The route configuration is the last resource type applied by the router for a snapshot version,
+// hence accepting it means the whole snapshot is accepted.
+func handleRouterAccept(label, node, version string) {
+	mutexForSnapshotTracking.Lock()
+	if _, found := routerAcceptedVersions[label]; !found {
+		routerAcceptedVersions[label] = make(map[string]string)
+	}
+	routerAcceptedVersions[label][node] = version
+	versions := labelSnapshotVersions[label]
+	index := indexOfSnapshotVersion(versions, version)
+	if index < 0 {
+		mutexForSnapshotTracking.Unlock()
+		return
+	}
+	acceptedRevisions := acceptSnapshotVersions(label, versions[:index+1])
+	mutexForSnapshotTracking.Unlock()
+	go notifier.SendRevisionUpdateAck(acceptedRevisions)
+}
+
+// handleRouterDisconnect stops waiting for the router to accept the snapshot versions of the label. If no
+// routers remain connected to the label, the tracked snapshot versions of the label are considered as accepted.
+func handleRouterDisconnect(label, node string) {
+	mutexForSnapshotTracking.Lock()
+	delete(routerAcceptedVersions[label], node)
+	if len(routerAcceptedVersions[label]) != 0 {
+		mutexForSnapshotTracking.Unlock()
+		return
+	}
+	delete(routerAcceptedVersions, label)
+	acceptedRevisions := acceptSnapshotVersions(label, labelSnapshotVersions[label])
+	mutexForSnapshotTracking.Unlock()
+	go notifier.SendRevisionUpdateAck(acceptedRevisions)
+}
+
+// acceptSnapshotVersions marks the changes of the given snapshot versions as accepted by the routers of the
+// label, and returns the revisions which are fully accepted and waiting to be reported.
+// The caller must hold the mutexForSnapshotTracking.
+func acceptSnapshotVersions(label string, versions []*snapshotVersion) []*notifier.DeployedAPIRevision {
+	var acceptedRevisions []*notifier.DeployedAPIRevision
+	for _, version := range versions {
+		if version.isAccepted {
+			continue
+		}
+		version.isAccepted = true
+		for _, change := range version.changes {
+			delete(change.unacceptedLabels, label)
+			if change.status != apiChangePending || !change.isTracked || len(change.unacceptedLabels) != 0 {
+				continue
+			}
+			change.status = apiChangeAccepted
+			if change.deployedRevision != nil && change.isReportRequested {
+				delete(revisionChanges, change.deployedRevision)
+				acceptedRevisions = append(acceptedRevisions, change.deployedRevision)
+			}
+		}
+	}
+	return acceptedRevisions
+}
+
+// handleRejectedSnapshot rolls back the APIs changed in the snapshot versions of the label after the last version
+// accepted by the node, up to the rejected version, and updates the caches with the rolled back APIs.
+func handleRejectedSnapshot(requestEvent RequestEvent) {
+	component := "router"
+	if requestEvent.IsEnforcer {
+		component = "enforcer"
+	}
+	mutexForInternalMapUpdate.Lock()
+	defer mutexForInternalMapUpdate.Unlock()
+
+	failureMessage := fmt.Sprintf("the %v %v rejected the snapshot version %v : %v", component, requestEvent.Node,
+		requestEvent.RejectedVersion, requestEvent.ErrorMessage)
+	changes, found := takeRejectedAPIChanges(requestEvent.Label, requestEvent.Version, requestEvent.RejectedVersion,
+		failureMessage)
+	if !found {
+		logger.LoggerXds.Debugf("Snapshot version %v rejected by the %v %v is not tracked. Hence no APIs are rolled back.",
+			requestEvent.RejectedVersion, component, requestEvent.Node)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	labelSet := make(map[string]struct{})
+	apiIdentifiers := make([]string, 0, len(changes))
+	for _, change := range changes {
+		for _, label := range orgIDOpenAPIEnvoyMap[change.organizationID][change.apiIdentifier] {
+			labelSet[label] = void
+		}
+		for _, label := range change.previousState.labels {
+			labelSet[label] = void
+		}
+		change.rollback()
+		apiIdentifiers = append(apiIdentifiers, change.apiIdentifier)
+	}
+	labels := make([]string, 0, len(labelSet))
+	for label := range labelSet {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	logger.LoggerXds.ErrorC(logging.ErrorDetails{
+		Message: fmt.Sprintf("Rolled back the APIs %v and updating the Xds Cache of the labels %v, as %v",
+			apiIdentifiers, labels, failureMessage),
+		Severity:  logging.MAJOR,
+		ErrorCode: 1418,
+	})
+	updateXdsCacheOnAPIAdd([]string{}, labels)
+}
+
+// takeRejectedAPIChanges marks the changes of the snapshot versions of the label after the accepted version, up to
+// the rejected version, as rolled back. The later changes of the same APIs are rolled back along with them.
+// Returns the earliest change of each API, which holds the state to roll back to. found is false if the rejected
+// version is not tracked.
+func takeRejectedAPIChanges(label, acceptedVersion, rejectedVersion, failureMessage string) (changes []*apiChange,
+	found bool) {
+	mutexForSnapshotTracking.Lock()
+	defer mutexForSnapshotTracking.Unlock()
+	versions := labelSnapshotVersions[label]
+	rejectedIndex := indexOfSnapshotVersion(versions, rejectedVersion)
+	if rejectedIndex < 0 {
+		return nil, false
+	}
+	// If the accepted version is not tracked (or it is a later version), all the tracked versions up to the
+	// rejected version are considered.
+	acceptedIndex := indexOfSnapshotVersion(versions[:rejectedIndex], acceptedVersion)
+	rejectedKeys := make(map[string]struct{})
+	for _, version := range versions[acceptedIndex+1 : rejectedIndex+1] {
+		for _, change := range version.changes {
+			if change.status == apiChangeRolledBack {
+				continue
+			}
+			if _, exists := rejectedKeys[change.key]; !exists {
+				rejectedKeys[change.key] = void
+				changes = append(changes, change)
+			}
+			change.markRolledBack(failureMessage)
+		}
+	}
+	for _, trackedVersions := range labelSnapshotVersions {
+		for _, version := range trackedVersions {
+			for _, change := range version.changes {
+				if _, exists := rejectedKeys[change.key]; exists && change.status != apiChangeRolledBack {
+					change.markRolledBack(failureMessage)
+				}
+			}
+		}
+	}
+	return changes, true
+}
+
+// markRolledBack marks the change as rolled back, and reports the deployment failure of the revision if it is
+// requested. The caller must hold the mutexForSnapshotTracking.
+func (change *apiChange) markRolledBack(failureMessage string) {
+	change.status = apiChangeRolledBack
+	change.failureMessage = failureMessage
+	if change.deployedRevision != nil && change.isReportRequested {
+		delete(revisionChanges, change.deployedRevision)
+		reportFailedDeployment(change.deployedRevision, failureMessage)
+	}
+}
+
+// reportFailedDeployment reports that the deployment of the revision failed to the control plane.
+func reportFailedDeployment(deployedRevision *notifier.DeployedAPIRevision, failureMessage string) {
+	environments := make([]string, 0, len(deployedRevision.EnvInfo))
+	failedRevision := &notifier.FailedAPIRevision{
+		APIID:      deployedRevision.APIID,
+		RevisionID: deployedRevision.RevisionID,
+		EnvInfo:    []notifier.FailedEnvInfo{},
+	}
+	for _, envInfo := range deployedRevision.EnvInfo {
+		environments = append(environments, envInfo.Name)
+		failedRevision.EnvInfo = append(failedRevision.EnvInfo, notifier.FailedEnvInfo{
+			Name:         envInfo.Name,
+			VHost:        envInfo.VHost,
+			ErrorMessage: failureMessage,
+		})
+	}
+	logger.LoggerXds.ErrorC(logging.ErrorDetails{
+		Message: fmt.Sprintf("Deployment of the revision %v of the API %v to the environments %v failed, as %v",
+			deployedRevision.RevisionID, deployedRevision.APIID, environments, failureMessage),
+		Severity:  logging.MAJOR,
+		ErrorCode: 1419,
+	})
+	go notifier.SendRevisionDeployFailure([]*notifier.FailedAPIRevision{failedRevision})
+}
+
+func indexOfSnapshotVersion(versions []*snapshotVersion, version string) int {
+	for index, snapshotVersion := range versions {
+		if snapshotVersion.version == version {
+			return index
+		}
+	}
+	return -1
+}
+
+// captureAPIState returns the current state of the API in the internal maps.
+func captureAPIState(organizationID, apiIdentifier string) *apiState {
+	state := &apiState{}
+	state.mgwSwagger, state.exists = orgIDAPIMgwSwaggerMap[organizationID][apiIdentifier]
+	if state.exists {
+		state.labels = append([]string{}, orgIDOpenAPIEnvoyMap[organizationID][apiIdentifier]...)
+		state.routes = orgIDOpenAPIRoutesMap[organizationID][apiIdentifier]
+		state.clusters = orgIDOpenAPIClustersMap[organizationID][apiIdentifier]
+		state.endpoints = orgIDOpenAPIEndpointsMap[organizationID][apiIdentifier]
+		state.enforcerAPI = orgIDOpenAPIEnforcerApisMap[organizationID][apiIdentifier]
+	}
+	uuid := getUUIDFromAPIIdentifier(apiIdentifier)
+	if gatewayToVhosts, found := apiUUIDToGatewayToVhosts[uuid]; found {
+		state.gatewayToVhosts = make(map[string]string, len(gatewayToVhosts))
+		for gateway, vhost := range gatewayToVhosts {
+			state.gatewayToVhosts[gateway] = vhost
+		}
+	}
+	if vhosts, found := apiToVhostsMap[uuid]; found {
+		state.vhosts = make(map[string]struct{}, len(vhosts))
+		for vhost := range vhosts {
+			state.vhosts[vhost] = void
+		}
+	}
+	return state
+}
+
+// rollback restores the state of the API in the internal maps to the state before the change.
+// The caller must hold the mutexForInternalMapUpdate.
+func (change *apiChange) rollback() {
+	organizationID := change.organizationID
+	apiIdentifier := change.apiIdentifier
+	state := change.previousState
+
+	deleteBasepathForVHost(organizationID, apiIdentifier)
+	if state.exists {
+		setNestedMapValue(orgIDAPIMgwSwaggerMap, organizationID, apiIdentifier, state.mgwSwagger)
+		setNestedMapValue(orgIDOpenAPIEnvoyMap, organizationID, apiIdentifier, state.labels)
+		setNestedMapValue(orgIDOpenAPIRoutesMap, organizationID, apiIdentifier, state.routes)
+		setNestedMapValue(orgIDOpenAPIClustersMap, organizationID, apiIdentifier, state.clusters)
+		setNestedMapValue(orgIDOpenAPIEndpointsMap, organizationID, apiIdentifier, state.endpoints)
+		if state.enforcerAPI != nil {
+			setNestedMapValue(orgIDOpenAPIEnforcerApisMap, organizationID, apiIdentifier, state.enforcerAPI)
+		} else {
+			delete(orgIDOpenAPIEnforcerApisMap[organizationID], apiIdentifier)
+		}
+		vHost, _ := ExtractVhostFromAPIIdentifier(apiIdentifier)
+		basepathKey := vHost + ":" + state.mgwSwagger.GetXWso2Basepath()
+		if _, found := orgIDvHostBasepathMap[organizationID][basepathKey]; !found {
+			setNestedMapValue(orgIDvHostBasepathMap, organizationID, basepathKey, apiIdentifier)
+		}
+	} else {
+		delete(orgIDAPIMgwSwaggerMap[organizationID], apiIdentifier)
+		delete(orgIDOpenAPIEnvoyMap[organizationID], apiIdentifier)
+		delete(orgIDOpenAPIRoutesMap[organizationID], apiIdentifier)
+		delete(orgIDOpenAPIClustersMap[organizationID], apiIdentifier)
+		delete(orgIDOpenAPIEndpointsMap[organizationID], apiIdentifier)
+		delete(orgIDOpenAPIEnforcerApisMap[organizationID], apiIdentifier)
+	}
+
+	uuid := getUUIDFromAPIIdentifier(apiIdentifier)
+	if state.gatewayToVhosts != nil {
+		apiUUIDToGatewayToVhosts[uuid] = state.gatewayToVhosts
+	} else {
+		delete(apiUUIDToGatewayToVhosts, uuid)
+	}
+	if state.vhosts != nil {
+		apiToVhostsMap[uuid] = state.vhosts
+	} else {
+		delete(apiToVhostsMap, uuid)
+	}
+}
+
+// setNestedMapValue sets the value in the inner map of the given key, creating the inner map if it does not exist.
+func setNestedMapValue[V any](nestedMap map[string]map[string]V, key, innerKey string, value V) {
+	if _, found := nestedMap[key]; !found {
+		nestedMap[key] = make(map[string]V)
+	}
+	nestedMap[key][innerKey] = value
+}
+
+// getUUIDFromAPIIdentifier returns the UUID of the API identifier (Vhost:API_UUID).
+func getUUIDFromAPIIdentifier(apiIdentifier string) string {
+	return apiIdentifier[strings.LastIndex(apiIdentifier, apiKeyFieldSeparator)+1:]
+}
@@ -28,11 +28,23 @@ import (
 	oasModel "github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
 )
 
-// RequestEvent is the event that is published by the xds callback (onStreamRequest)
+// RequestEvent is the event that is published by the xds callbacks (onStreamRequest and onStreamClosed)
 type RequestEvent struct {
+	// IsError is true if the node rejected the last version sent to it.
 	IsError bool
+	// Version is the last version accepted by the node.
 	Version string
-	Node    string
+	// Node is the identifier of the node (label:instanceIdentifier).
+	Node  string
+	Label string
+	// IsEnforcer is true if the node is an enforcer, and false if it is a router.
+	IsEnforcer bool
+	// IsDisconnected is true if the stream of the node is closed.
+	IsDisconnected bool
+	TypeURL        string
+	// RejectedVersion is the version rejected by the node, if IsError is true.
+	RejectedVersion string
+	ErrorMessage    string
 }
 
 // EnforcerAPIState Stores the last success state of the enforcer apis
@@ -54,6 +66,8 @@ type RouterResourceState struct {
 	Version                string
 }
 
+const requestEventChannelSize int = 100
+
 var (
 	lockForStateUpdate sync.Mutex
 	once               sync.Once
@@ -69,14 +83,16 @@ var (
 
 // NewRequestEvent create new change event
 func NewRequestEvent() RequestEvent {
-	return RequestEvent{false, "", ""}
+	return RequestEvent{}
 }
 
 // GetRequestEventChannel returns the state change channel.
-// RequestEventChannel should be a singleton object
+// RequestEventChannel should be a singleton object. The events published to the channel are handled by the
+// snapshot tracker.
 func GetRequestEventChannel() chan RequestEvent {
 	once.Do(func() {
-		RequestEventChannel = make(chan RequestEvent)
+		RequestEventChannel = make(chan RequestEvent, requestEventChannelSize)
+		go handleRequestEvents(RequestEventChannel)
 	})
 	return RequestEventChannel
 }
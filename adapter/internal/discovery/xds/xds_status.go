@@ -0,0 +1,206 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package xds
+
+import (
+	"sort"
+	"time"
+
+	envoy_cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	apiModel "github.com/wso2/product-microgateway/adapter/internal/api/models"
+	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds/common"
+	wso2_cache "github.com/wso2/product-microgateway/adapter/pkg/discovery/protocol/cache/v3"
+)
+
+// Names of the xDS caches, which are used to identify the stream registries of the xDS servers serving the caches.
+const (
+	RouterCacheName                        = "router"
+	EnforcerCacheName                      = "enforcer"
+	EnforcerSubscriptionCacheName          = "enforcer-subscriptions"
+	EnforcerApplicationCacheName           = "enforcer-applications"
+	EnforcerAPICacheName                   = "enforcer-apis"
+	EnforcerApplicationPolicyCacheName     = "enforcer-application-policies"
+	EnforcerSubscriptionPolicyCacheName    = "enforcer-subscription-policies"
+	EnforcerApplicationKeyMappingCacheName = "enforcer-application-key-mappings"
+	EnforcerKeyManagerCacheName            = "enforcer-key-managers"
+	EnforcerRevokedTokenCacheName          = "enforcer-revoked-tokens"
+	EnforcerThrottleDataCacheName          = "enforcer-throttle-data"
+)
+
+// xdsCacheInfo provides the status of an xDS cache, irrespective of whether it is a router cache or an enforcer cache.
+type xdsCacheInfo struct {
+	name string
+	// getStatusKeys returns the labels on which watches are opened.
+	getStatusKeys func() []string
+	// getWatchStatus returns the number of open watches and the time of the last watch request of the label.
+	getWatchStatus func(label string) (int, time.Time)
+	// getVersion returns the version of the resource type in the snapshot of the label.
+	getVersion func(label, typeURL string) string
+}
+
+func newRouterCacheInfo(name string, cache envoy_cachev3.SnapshotCache) xdsCacheInfo {
+	return xdsCacheInfo{
+		name:          name,
+		getStatusKeys: cache.GetStatusKeys,
+		getWatchStatus: func(label string) (int, time.Time) {
+			if statusInfo := cache.GetStatusInfo(label); statusInfo != nil {
+				return statusInfo.GetNumWatches(), statusInfo.GetLastWatchRequestTime()
+			}
+			return 0, time.Time{}
+		},
+		getVersion: func(label, typeURL string) string {
+			if snapshot, err := cache.GetSnapshot(label); err == nil {
+				return snapshot.GetVersion(typeURL)
+			}
+			return ""
+		},
+	}
+}
+
+func newEnforcerCacheInfo(name string, cache wso2_cache.SnapshotCache) xdsCacheInfo {
+	return xdsCacheInfo{
+		name:          name,
+		getStatusKeys: cache.GetStatusKeys,
+		getWatchStatus: func(label string) (int, time.Time) {
+			if statusInfo := cache.GetStatusInfo(label); statusInfo != nil {
+				return statusInfo.GetNumWatches(), statusInfo.GetLastWatchRequestTime()
+			}
+			return 0, time.Time{}
+		},
+		getVersion: func(label, typeURL string) string {
+			if snapshot, err := cache.GetSnapshot(label); err == nil {
+				return snapshot.GetVersion(typeURL)
+			}
+			return ""
+		},
+	}
+}
+
+func getXdsCaches() []xdsCacheInfo {
+	return []xdsCacheInfo{
+		newRouterCacheInfo(RouterCacheName, cache),
+		newEnforcerCacheInfo(EnforcerCacheName, enforcerCache),
+		newEnforcerCacheInfo(EnforcerSubscriptionCacheName, enforcerSubscriptionCache),
+		newEnforcerCacheInfo(EnforcerApplicationCacheName, enforcerApplicationCache),
+		newEnforcerCacheInfo(EnforcerAPICacheName, enforcerAPICache),
+		newEnforcerCacheInfo(EnforcerApplicationPolicyCacheName, enforcerApplicationPolicyCache),
+		newEnforcerCacheInfo(EnforcerSubscriptionPolicyCacheName, enforcerSubscriptionPolicyCache),
+		newEnforcerCacheInfo(EnforcerApplicationKeyMappingCacheName, enforcerApplicationKeyMappingCache),
+		newEnforcerCacheInfo(EnforcerKeyManagerCacheName, enforcerKeyManagerCache),
+		newEnforcerCacheInfo(EnforcerRevokedTokenCacheName, enforcerRevokedTokensCache),
+		newEnforcerCacheInfo(EnforcerThrottleDataCacheName, enforcerThrottleDataCache),
+	}
+}
+
+// GetXdsStatus returns the nodes connected to each xDS cache of the adapter, along with the versions of the resources
+// sent to and acknowledged by each node. If a label is given, only the nodes of the label are returned.
+func GetXdsStatus(label string) *apiModel.XdsStatus {
+	status := &apiModel.XdsStatus{Caches: []*apiModel.XdsCacheStatus{}}
+	for _, cacheInfo := range getXdsCaches() {
+		status.Caches = append(status.Caches, getXdsCacheStatus(cacheInfo, label))
+	}
+	return status
+}
+
+func getXdsCacheStatus(cacheInfo xdsCacheInfo, labelFilter string) *apiModel.XdsCacheStatus {
+	cacheStatus := &apiModel.XdsCacheStatus{
+		Name:   cacheInfo.name,
+		Labels: []*apiModel.XdsLabelStatus{},
+		Nodes:  []*apiModel.XdsNodeStatus{},
+	}
+
+	labels := cacheInfo.getStatusKeys()
+	sort.Strings(labels)
+	for _, label := range labels {
+		if labelFilter != "" && label != labelFilter {
+			continue
+		}
+		numWatches, lastWatchRequestTime := cacheInfo.getWatchStatus(label)
+		cacheStatus.Labels = append(cacheStatus.Labels, &apiModel.XdsLabelStatus{
+			Label:                label,
+			NumWatches:           int64(numWatches),
+			LastWatchRequestTime: formatStatusTime(lastWatchRequestTime),
+		})
+	}
+
+	// The streams opened by the same node are grouped, and the latest status of each resource type is reported.
+	nodeStatusMap := make(map[string]*apiModel.XdsNodeStatus)
+	nodeRequestTimes := make(map[string]time.Time)
+	nodeResources := make(map[string]map[string]common.ResourceStatus)
+	for _, stream := range common.GetStreamRegistry(cacheInfo.name).GetStreamStatuses() {
+		if labelFilter != "" && stream.Label != labelFilter {
+			continue
+		}
+		cacheStatus.StreamCount++
+		nodeKey := stream.Label + ":" + stream.NodeIdentifier
+		nodeStatus, found := nodeStatusMap[nodeKey]
+		if !found {
+			nodeStatus = &apiModel.XdsNodeStatus{
+				Label:          stream.Label,
+				NodeIdentifier: stream.NodeIdentifier,
+			}
+			nodeStatusMap[nodeKey] = nodeStatus
+			nodeResources[nodeKey] = make(map[string]common.ResourceStatus)
+			cacheStatus.Nodes = append(cacheStatus.Nodes, nodeStatus)
+		}
+		nodeStatus.StreamCount++
+		if stream.LastRequestTime.Before(nodeRequestTimes[nodeKey]) {
+			continue
+		}
+		nodeRequestTimes[nodeKey] = stream.LastRequestTime
+		for typeURL, resource := range stream.Resources {
+			nodeResources[nodeKey][typeURL] = resource
+		}
+	}
+
+	for nodeKey, nodeStatus := range nodeStatusMap {
+		nodeStatus.LastRequestTime = formatStatusTime(nodeRequestTimes[nodeKey])
+		nodeStatus.InSync = true
+		nodeStatus.Resources = []*apiModel.XdsResourceStatus{}
+		for typeURL, resource := range nodeResources[nodeKey] {
+			currentVersion := cacheInfo.getVersion(nodeStatus.Label, typeURL)
+			nodeStatus.Resources = append(nodeStatus.Resources, &apiModel.XdsResourceStatus{
+				TypeURL:        typeURL,
+				CurrentVersion: currentVersion,
+				SentVersion:    resource.SentVersion,
+				AckedVersion:   resource.AcceptedVersion,
+				NackMessage:    resource.NackMessage,
+			})
+			if resource.AcceptedVersion != currentVersion || resource.NackMessage != "" {
+				nodeStatus.InSync = false
+			}
+		}
+		sort.Slice(nodeStatus.Resources, func(i, j int) bool {
+			return nodeStatus.Resources[i].TypeURL < nodeStatus.Resources[j].TypeURL
+		})
+	}
+	sort.Slice(cacheStatus.Nodes, func(i, j int) bool {
+		if cacheStatus.Nodes[i].Label != cacheStatus.Nodes[j].Label {
+			return cacheStatus.Nodes[i].Label < cacheStatus.Nodes[j].Label
+		}
+		return cacheStatus.Nodes[i].NodeIdentifier < cacheStatus.Nodes[j].NodeIdentifier
+	})
+	return cacheStatus
+}
+
+func formatStatusTime(statusTime time.Time) string {
+	if statusTime.IsZero() {
+		return ""
+	}
+	return statusTime.UTC().Format(time.RFC3339)
+}
@@ -35,3 +35,9 @@ func ProcessEvents(config *config.Config) {
 	go handleThrottleData()
 	go handleTokenRevocation()
 }
+
+// StopEvents closes the connections to the message broker, so that no more events are consumed.
+func StopEvents() error {
+	msg.CloseAzureConsumers()
+	return msg.CloseJMSConnection()
+}
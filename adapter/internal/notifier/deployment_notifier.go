@@ -2,11 +2,11 @@ package notifier
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/wso2/product-microgateway/adapter/config"
 	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
@@ -23,6 +23,48 @@ const (
 	contentTypeHeader    string = "Content-Type"
 )
 
+// pendingAcks tracks the revision acknowledgements which are being sent to the control plane for the first time
+var pendingAcks ackTracker
+
+// ackTracker counts the acknowledgements being sent. Unlike a sync.WaitGroup, acknowledgements can be added
+// while waiting for the ongoing acknowledgements to complete.
+type ackTracker struct {
+	lock    sync.Mutex
+	pending int
+	// idle is closed when there are no pending acknowledgements
+	idle chan struct{}
+}
+
+func (tracker *ackTracker) add() {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	if tracker.pending == 0 {
+		tracker.idle = make(chan struct{})
+	}
+	tracker.pending++
+}
+
+func (tracker *ackTracker) done() {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	tracker.pending--
+	if tracker.pending == 0 {
+		close(tracker.idle)
+	}
+}
+
+// idleChannel returns a channel which is closed once there are no pending acknowledgements.
+func (tracker *ackTracker) idleChannel() <-chan struct{} {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	if tracker.pending == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle
+	}
+	return tracker.idle
+}
+
 //UpdateDeployedRevisions create the DeployedAPIRevision object
 func UpdateDeployedRevisions(apiID string, revisionID int, envs []string, vhost string) *DeployedAPIRevision {
 	revisions := &DeployedAPIRevision{
@@ -40,7 +82,26 @@ func UpdateDeployedRevisions(apiID string, revisionID int, envs []string, vhost
 	return revisions
 }
 
-//SendRevisionUpdateAck sends succeeded revision deployment acknowledgement to the control plane
+//UpdateFailedRevisions create the FailedAPIRevision object with the error details of each environment
+func UpdateFailedRevisions(apiID string, revisionID int, envs []string, vhost string, errorMessage string) *FailedAPIRevision {
+	revisions := &FailedAPIRevision{
+		APIID:      apiID,
+		RevisionID: revisionID,
+		EnvInfo:    []FailedEnvInfo{},
+	}
+	for _, env := range envs {
+		info := FailedEnvInfo{
+			Name:         env,
+			VHost:        vhost,
+			ErrorMessage: errorMessage,
+		}
+		revisions.EnvInfo = append(revisions.EnvInfo, info)
+	}
+	return revisions
+}
+
+//SendRevisionUpdateAck sends succeeded revision deployment acknowledgement to the control plane.
+//The acknowledgement is retried with the deployment status queue until it is delivered.
 func SendRevisionUpdateAck(deployedRevisionList []*DeployedAPIRevision) {
 	conf, _ := config.ReadConfigs()
 	cpConfigs := conf.ControlPlane
@@ -48,55 +109,30 @@ func SendRevisionUpdateAck(deployedRevisionList []*DeployedAPIRevision) {
 	if len(deployedRevisionList) < 1 || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate {
 		return
 	}
-
 	logger.LoggerNotifier.Debugf("Revision deployed message is sending to Control plane")
+	getDeploymentStatusQueue().enqueue(revisionDeployedStatus, deployedRevisionList)
+}
 
-	revisionEP := cpConfigs.ServiceURL
-	if strings.HasSuffix(revisionEP, "/") {
-		revisionEP += deployedRevisionEP
-	} else {
-		revisionEP += "/" + deployedRevisionEP
-	}
-
-	jsonValue, _ := json.Marshal(deployedRevisionList)
-
-	// Setting authorization header
-	basicAuth := authBasic + auth.GetBasicAuth(cpConfigs.Username, cpConfigs.Password)
+//SendRevisionDeployFailure sends failed revision deployments to the control plane, along with the error details of
+//each environment. The failures are retried with the deployment status queue until they are delivered. The failures
+//are not sent if the revision deploy failure endpoint of the control plane is not configured.
+func SendRevisionDeployFailure(failedRevisionList []*FailedAPIRevision) {
+	conf, _ := config.ReadConfigs()
+	cpConfigs := conf.ControlPlane
 
-	logger.LoggerNotifier.Debugf("Revision deployed message sending to Control plane: %v", string(jsonValue))
-
-	// Adding 3 retries for revision update sending
-	retries := 0
-	for retries < 3 {
-		retries++
-
-		req, _ := http.NewRequest("PATCH", revisionEP, bytes.NewBuffer(jsonValue))
-		req.Header.Set(authHeader, basicAuth)
-		req.Header.Set(contentTypeHeader, "application/json")
-		resp, err := tlsutils.InvokeControlPlane(req, cpConfigs.SkipSSLVerification)
-
-		success := true
-		if err != nil {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response from %v for attempt %v : %v", revisionEP, retries, err.Error()),
-				Severity:  logging.MAJOR,
-				ErrorCode: 2100,
-			})
-			success = false
-		}
-		if resp != nil && resp.StatusCode != http.StatusOK {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response status code %v from %v for attempt %v", resp.StatusCode, revisionEP, retries),
-				Severity:  logging.MINOR,
-				ErrorCode: 2101,
-			})
-			success = false
-		}
-		if success {
-			logger.LoggerNotifier.Infof("Revision deployed message sent to Control plane for attempt %v", retries)
-			break
+	if len(failedRevisionList) < 1 || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate {
+		return
+	}
+	if cpConfigs.RevisionDeployFailureEndpoint == "" {
+		for _, failedRevision := range failedRevisionList {
+			logger.LoggerNotifier.Debugf("Revision deployment failure of the API %v revision %v is not sent to Control "+
+				"plane as the revision deploy failure endpoint is not configured", failedRevision.APIID,
+				failedRevision.RevisionID)
 		}
+		return
 	}
+	logger.LoggerNotifier.Debugf("Revision deployment failed message is sending to Control plane")
+	getDeploymentStatusQueue().enqueue(revisionFailedStatus, failedRevisionList)
 }
 
 // SendRevisionUndeployAck - send the undeployed revision acknowledgement to control plane
@@ -106,50 +142,86 @@ func SendRevisionUndeployAck(apiUUID string, revisionUUID string, environment st
 	if apiUUID == "" || revisionUUID == "" || environment == "" || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate {
 		return
 	}
-	revisionEP := cpConfigs.ServiceURL
-	if strings.HasSuffix(revisionEP, "/") {
-		revisionEP += unDeployedRevisionEP
-	} else {
-		revisionEP += "/" + unDeployedRevisionEP
-	}
-
 	removedRevision := UnDeployedAPIRevision{
 		APIUUID:      apiUUID,
 		RevisionUUID: revisionUUID,
 		Environment:  environment,
 	}
+	getDeploymentStatusQueue().enqueue(revisionUndeployedStatus, removedRevision)
+}
 
-	jsonValue, _ := json.Marshal(removedRevision)
-	basicAuth := authBasic + auth.GetBasicAuth(cpConfigs.Username, cpConfigs.Password)
-	retries := 0
-	for retries < 3 {
-		retries++
-		req, _ := http.NewRequest("POST", revisionEP, bytes.NewBuffer(jsonValue))
-		req.Header.Set(authHeader, basicAuth)
-		req.Header.Set(contentTypeHeader, "application/json")
-		resp, err := tlsutils.InvokeControlPlane(req, cpConfigs.SkipSSLVerification)
-
-		success := true
-		if err != nil {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response from %s for attempt %d : %v", revisionEP, retries, err.Error()),
-				Severity:  logging.MAJOR,
-				ErrorCode: 2100,
-			})
-			success = false
-		}
-		if resp != nil && resp.StatusCode != http.StatusOK {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response status code %v from %s for attempt %d", resp.StatusCode, revisionEP, retries),
-				Severity:  logging.MINOR,
-				ErrorCode: 2101,
-			})
-			success = false
+// sendDeploymentStatus sends the deployment status message of the given kind to the control plane. An error wrapping
+// errStatusRejected is returned if the message is rejected by the control plane, hence it should not be retried.
+func sendDeploymentStatus(kind string, payload []byte) error {
+	conf, _ := config.ReadConfigs()
+	cpConfigs := conf.ControlPlane
+
+	method := http.MethodPatch
+	endpoint := deployedRevisionEP
+	switch kind {
+	case revisionFailedStatus:
+		endpoint = strings.TrimPrefix(cpConfigs.RevisionDeployFailureEndpoint, "/")
+		if endpoint == "" {
+			return fmt.Errorf("%w: the revision deploy failure endpoint is not configured", errStatusRejected)
 		}
-		if success {
-			logger.LoggerNotifier.Infof("Revision un-deployed message sent to Control plane for attempt %d", retries)
-			break
+	case revisionUndeployedStatus:
+		method = http.MethodPost
+		endpoint = unDeployedRevisionEP
+	}
+	revisionEP := cpConfigs.ServiceURL
+	if strings.HasSuffix(revisionEP, "/") {
+		revisionEP += endpoint
+	} else {
+		revisionEP += "/" + endpoint
+	}
+	logger.LoggerNotifier.Debugf("Revision %v message sending to Control plane: %v", kind, string(payload))
+
+	// Setting authorization header
+	basicAuth := authBasic + auth.GetBasicAuth(cpConfigs.Username, cpConfigs.Password)
+	req, _ := http.NewRequest(method, revisionEP, bytes.NewBuffer(payload))
+	req.Header.Set(authHeader, basicAuth)
+	req.Header.Set(contentTypeHeader, "application/json")
+	resp, err := tlsutils.InvokeControlPlane(req, cpConfigs.SkipSSLVerification)
+	if err != nil {
+		logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error response from %v : %v", revisionEP, err.Error()),
+			Severity:  logging.MAJOR,
+			ErrorCode: 2100,
+		})
+		return err
+	}
+	if resp != nil && resp.StatusCode != http.StatusOK {
+		logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error response status code %v from %v", resp.StatusCode, revisionEP),
+			Severity:  logging.MINOR,
+			ErrorCode: 2101,
+		})
+		if isRejectedStatusCode(resp.StatusCode) {
+			return fmt.Errorf("%w: error response status code %v from %v", errStatusRejected, resp.StatusCode,
+				revisionEP)
 		}
-		time.Sleep(2 * time.Second)
+		return fmt.Errorf("error response status code %v from %v", resp.StatusCode, revisionEP)
+	}
+	logger.LoggerNotifier.Infof("Revision %v message sent to Control plane", kind)
+	return nil
+}
+
+// isRejectedStatusCode returns true if the status code is a client error, other than the ones returned when the
+// request can be retried later.
+func isRejectedStatusCode(statusCode int) bool {
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError &&
+		statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests
+}
+
+// FlushPendingAcks waits until the first attempts of the revision acknowledgements being sent to the control plane
+// are completed, or the context is done. An error is returned if the acknowledgements are not completed before the
+// context is done. The undelivered acknowledgements remain in the deployment status queue.
+func FlushPendingAcks(ctx context.Context) error {
+	select {
+	case <-pendingAcks.idleChannel():
+		logger.LoggerNotifier.Info("Pending revision acknowledgements are sent to Control plane")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("revision acknowledgements are not sent to Control plane : %v", ctx.Err())
 	}
 }
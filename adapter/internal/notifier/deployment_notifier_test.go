@@ -0,0 +1,69 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlushPendingAcks(t *testing.T) {
+	assert.Nil(t, FlushPendingAcks(context.Background()), "Flushing without pending acks should not fail.")
+
+	pendingAcks.add()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.NotNil(t, FlushPendingAcks(ctx), "Flushing should time out while an ack is pending.")
+
+	flushed := make(chan error, 1)
+	go func() {
+		flushed <- FlushPendingAcks(context.Background())
+	}()
+	// Acks can be added and completed while flushing.
+	pendingAcks.add()
+	pendingAcks.done()
+	pendingAcks.done()
+	select {
+	case err := <-flushed:
+		assert.Nil(t, err, "Flushing should complete once the pending acks are done.")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Flushing should complete once the pending acks are done.")
+	}
+}
+
+func TestIsRejectedStatusCode(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		isRejected bool
+	}{
+		{statusCode: http.StatusBadRequest, isRejected: true},
+		{statusCode: http.StatusNotFound, isRejected: true},
+		{statusCode: http.StatusRequestTimeout, isRejected: false},
+		{statusCode: http.StatusTooManyRequests, isRejected: false},
+		{statusCode: http.StatusInternalServerError, isRejected: false},
+		{statusCode: http.StatusServiceUnavailable, isRejected: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.isRejected, isRejectedStatusCode(test.statusCode), "Status code %v mismatch.",
+			test.statusCode)
+	}
+}
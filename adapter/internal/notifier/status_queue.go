@@ -0,0 +1,380 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2/product-microgateway/adapter/config"
+	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
+)
+
+const (
+	revisionDeployedStatus   string = "deployed"
+	revisionFailedStatus     string = "failed"
+	revisionUndeployedStatus string = "undeployed"
+	statusMessageFileSuffix  string = ".json"
+	deadLetterDirectory      string = "dead-letter"
+)
+
+var (
+	statusQueue     *deploymentStatusQueue
+	statusQueueOnce sync.Once
+	// errStatusRejected is wrapped by the errors of the messages which are rejected by the control plane, hence
+	// are not retried.
+	errStatusRejected = errors.New("revision message is rejected by Control plane")
+)
+
+// statusMessage is a deployment status message to be delivered to the control plane.
+type statusMessage struct {
+	ID      string          `json:"id"`
+	Kind    string          `json:"kind"`
+	Payload json.RawMessage `json:"payload"`
+	// APIIDs are the UUIDs of the APIs the message is about. The messages of an API are delivered in order.
+	APIIDs      []string  `json:"apiIds,omitempty"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	// isPending is true until the first attempt to deliver the message is completed.
+	isPending bool
+}
+
+// deploymentStatusQueue delivers the deployment status messages to the control plane in the order they are
+// enqueued. The undelivered messages are retried with an exponential backoff, blocking only the messages of the
+// same APIs enqueued after them (eg: an undeployed message is not delivered before the deployed message of the
+// API), and they are persisted in the queue directory so that they are not lost when the adapter restarts.
+// The messages rejected by the control plane, or not delivered within the maximum attempts, are moved to the dead
+// letter directory.
+type deploymentStatusQueue struct {
+	lock           sync.Mutex
+	directory      string
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	maxAttempts    int
+	messages       []*statusMessage
+	sequence       int
+	wakeUp         chan struct{}
+}
+
+// StartDeploymentStatusQueue loads the deployment status messages persisted before the restart, and starts
+// delivering them to the control plane.
+func StartDeploymentStatusQueue() {
+	getDeploymentStatusQueue()
+}
+
+func getDeploymentStatusQueue() *deploymentStatusQueue {
+	statusQueueOnce.Do(func() {
+		conf, _ := config.ReadConfigs()
+		queueConf := conf.ControlPlane.DeploymentStatusQueue
+		statusQueue = &deploymentStatusQueue{
+			directory:      queueConf.Directory,
+			initialBackoff: queueConf.InitialBackoff * time.Second,
+			maxBackoff:     queueConf.MaxBackoff * time.Second,
+			maxAttempts:    queueConf.MaxAttempts,
+			wakeUp:         make(chan struct{}, 1),
+		}
+		if statusQueue.initialBackoff <= 0 {
+			statusQueue.initialBackoff = time.Second
+		}
+		if statusQueue.maxBackoff < statusQueue.initialBackoff {
+			statusQueue.maxBackoff = statusQueue.initialBackoff
+		}
+		statusQueue.load()
+		go statusQueue.deliverMessages(sendDeploymentStatus)
+	})
+	return statusQueue
+}
+
+// enqueue persists the deployment status message and notifies the queue to deliver it.
+func (queue *deploymentStatusQueue) enqueue(kind string, payload interface{}) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error while marshalling the revision %v message : %v", kind, err.Error()),
+			Severity:  logging.MAJOR,
+			ErrorCode: 2102,
+		})
+		return
+	}
+	queue.lock.Lock()
+	queue.sequence++
+	message := &statusMessage{
+		ID:          fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), queue.sequence),
+		Kind:        kind,
+		Payload:     payloadJSON,
+		APIIDs:      getAPIIDs(payload),
+		NextAttempt: time.Now(),
+		isPending:   true,
+	}
+	queue.messages = append(queue.messages, message)
+	queue.persist(message)
+	pendingAcks.add()
+	queue.lock.Unlock()
+	queue.notify()
+}
+
+func (queue *deploymentStatusQueue) notify() {
+	select {
+	case queue.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+// deliverMessages delivers the messages in the queue using the given send function, one at a time, in the order
+// they are enqueued. A message which is waiting for its next attempt only blocks the messages of the same APIs
+// enqueued after it.
+func (queue *deploymentStatusQueue) deliverMessages(send func(kind string, payload []byte) error) {
+	for {
+		message, wait := queue.nextMessage()
+		if message == nil && wait == 0 {
+			<-queue.wakeUp
+			continue
+		}
+		if message == nil {
+			timer := time.NewTimer(wait)
+			select {
+			case <-queue.wakeUp:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+		err := send(message.Kind, message.Payload)
+		queue.completeAttempt(message, err)
+	}
+}
+
+// nextMessage returns the first due message of the queue which is not blocked by an earlier message of the same
+// APIs, or the duration to wait until a message is due. The duration is zero if the queue is empty.
+func (queue *deploymentStatusQueue) nextMessage() (*statusMessage, time.Duration) {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+	var minWait time.Duration
+	blockedAPIIDs := make(map[string]bool)
+	for _, message := range queue.messages {
+		wait := time.Until(message.NextAttempt)
+		if wait <= 0 && !isBlocked(message, blockedAPIIDs) {
+			return message, 0
+		}
+		if wait > 0 && (minWait == 0 || wait < minWait) {
+			minWait = wait
+		}
+		for _, apiID := range message.APIIDs {
+			blockedAPIIDs[apiID] = true
+		}
+	}
+	return nil, minWait
+}
+
+func isBlocked(message *statusMessage, blockedAPIIDs map[string]bool) bool {
+	for _, apiID := range message.APIIDs {
+		if blockedAPIIDs[apiID] {
+			return true
+		}
+	}
+	return false
+}
+
+// getAPIIDs returns the UUIDs of the APIs of the deployment status message payload.
+func getAPIIDs(payload interface{}) []string {
+	var apiIDs []string
+	switch revisions := payload.(type) {
+	case []*DeployedAPIRevision:
+		for _, revision := range revisions {
+			apiIDs = append(apiIDs, revision.APIID)
+		}
+	case []*FailedAPIRevision:
+		for _, revision := range revisions {
+			apiIDs = append(apiIDs, revision.APIID)
+		}
+	case UnDeployedAPIRevision:
+		apiIDs = append(apiIDs, revisions.APIUUID)
+	}
+	return apiIDs
+}
+
+// completeAttempt removes the message from the queue if it is delivered, and moves it to the dead letter directory
+// if it is rejected or the maximum attempts are exceeded. Otherwise the next attempt of the message is scheduled with
+// an exponential backoff.
+func (queue *deploymentStatusQueue) completeAttempt(message *statusMessage, err error) {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+	if message.isPending {
+		message.isPending = false
+		pendingAcks.done()
+	}
+	message.Attempts++
+	if err == nil {
+		queue.remove(message)
+		queue.removePersisted(message)
+		return
+	}
+	if errors.Is(err, errStatusRejected) || (queue.maxAttempts > 0 && message.Attempts >= queue.maxAttempts) {
+		queue.remove(message)
+		queue.moveToDeadLetter(message, err)
+		return
+	}
+	backoff := queue.getBackoff(message.Attempts)
+	message.NextAttempt = time.Now().Add(backoff)
+	queue.persist(message)
+	logger.LoggerNotifier.Infof("Revision %v message is not delivered to Control plane for attempt %v. Retrying in %v",
+		message.Kind, message.Attempts, backoff)
+}
+
+// remove removes the message from the queue. The caller must hold the lock of the queue.
+func (queue *deploymentStatusQueue) remove(message *statusMessage) {
+	for i, queuedMessage := range queue.messages {
+		if queuedMessage == message {
+			queue.messages = append(queue.messages[:i], queue.messages[i+1:]...)
+			return
+		}
+	}
+}
+
+// getBackoff returns the backoff after the given number of failed attempts, which is doubled after each attempt
+// until it reaches the maximum backoff.
+func (queue *deploymentStatusQueue) getBackoff(attempts int) time.Duration {
+	backoff := queue.initialBackoff
+	for i := 1; i < attempts && backoff < queue.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > queue.maxBackoff {
+		backoff = queue.maxBackoff
+	}
+	return backoff
+}
+
+// load loads the messages persisted in the queue directory, in the order they were enqueued.
+func (queue *deploymentStatusQueue) load() {
+	if queue.directory == "" {
+		return
+	}
+	files, err := ioutil.ReadDir(queue.directory)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Error while reading the deployment status queue directory %v : %v", queue.directory, err.Error()),
+				Severity:  logging.MAJOR,
+				ErrorCode: 2103,
+			})
+		}
+		return
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), statusMessageFileSuffix) {
+			continue
+		}
+		filePath := filepath.Join(queue.directory, file.Name())
+		content, err := ioutil.ReadFile(filePath)
+		var message statusMessage
+		if err == nil {
+			err = json.Unmarshal(content, &message)
+		}
+		if err != nil {
+			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Error while loading the deployment status message %v : %v", filePath, err.Error()),
+				Severity:  logging.MAJOR,
+				ErrorCode: 2106,
+			})
+			continue
+		}
+		queue.messages = append(queue.messages, &message)
+	}
+	if len(queue.messages) > 0 {
+		logger.LoggerNotifier.Infof("Loaded %d undelivered revision messages from the deployment status queue",
+			len(queue.messages))
+	}
+}
+
+// persist writes the message to the queue directory. The caller must hold the lock of the queue.
+func (queue *deploymentStatusQueue) persist(message *statusMessage) {
+	if queue.directory == "" {
+		return
+	}
+	content, _ := json.Marshal(message)
+	err := os.MkdirAll(queue.directory, os.ModePerm)
+	if err == nil {
+		err = ioutil.WriteFile(queue.getFilePath(message), content, 0600)
+	}
+	if err != nil {
+		logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+			Message: fmt.Sprintf("Error while persisting the revision %v message in %v. The message is lost if the "+
+				"adapter restarts before it is delivered : %v", message.Kind, queue.directory, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2104,
+		})
+	}
+}
+
+// removePersisted removes the delivered message from the queue directory. The caller must hold the lock of the queue.
+func (queue *deploymentStatusQueue) removePersisted(message *statusMessage) {
+	if queue.directory == "" {
+		return
+	}
+	if err := os.Remove(queue.getFilePath(message)); err != nil && !os.IsNotExist(err) {
+		logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error while removing the delivered revision %v message : %v", message.Kind, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2107,
+		})
+	}
+}
+
+// moveToDeadLetter moves the undeliverable message from the queue directory to the dead letter directory, so that
+// it is not retried after a restart. The caller must hold the lock of the queue.
+func (queue *deploymentStatusQueue) moveToDeadLetter(message *statusMessage, err error) {
+	logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+		Message: fmt.Sprintf("Revision %v message %s is dropped after %v attempts : %v", message.Kind,
+			string(message.Payload), message.Attempts, err.Error()),
+		Severity:  logging.MAJOR,
+		ErrorCode: 2105,
+	})
+	if queue.directory == "" {
+		return
+	}
+	deadLetterPath := filepath.Join(queue.directory, deadLetterDirectory)
+	moveErr := os.MkdirAll(deadLetterPath, os.ModePerm)
+	if moveErr == nil {
+		moveErr = os.Rename(queue.getFilePath(message), filepath.Join(deadLetterPath, message.ID+statusMessageFileSuffix))
+	}
+	if moveErr != nil {
+		logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+			Message: fmt.Sprintf("Error while moving the revision %v message to the dead letter directory : %v",
+				message.Kind, moveErr.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2108,
+		})
+		queue.removePersisted(message)
+	}
+}
+
+func (queue *deploymentStatusQueue) getFilePath(message *statusMessage) string {
+	return filepath.Join(queue.directory, message.ID+statusMessageFileSuffix)
+}
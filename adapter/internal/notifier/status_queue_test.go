@@ -0,0 +1,231 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStatusQueue(directory string) *deploymentStatusQueue {
+	return &deploymentStatusQueue{
+		directory:      directory,
+		initialBackoff: time.Hour,
+		maxBackoff:     4 * time.Hour,
+		maxAttempts:    3,
+		wakeUp:         make(chan struct{}, 1),
+	}
+}
+
+func TestDeploymentStatusQueueGetBackoff(t *testing.T) {
+	queue := newTestStatusQueue("")
+	tests := []struct {
+		attempts int
+		backoff  time.Duration
+	}{
+		{attempts: 1, backoff: time.Hour},
+		{attempts: 2, backoff: 2 * time.Hour},
+		{attempts: 3, backoff: 4 * time.Hour},
+		{attempts: 4, backoff: 4 * time.Hour},
+		{attempts: 100, backoff: 4 * time.Hour},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.backoff, queue.getBackoff(test.attempts), "Backoff mismatch for %v attempts.",
+			test.attempts)
+	}
+}
+
+func TestDeploymentStatusQueueOrdering(t *testing.T) {
+	queue := newTestStatusQueue("")
+	queue.enqueue(revisionDeployedStatus, "first")
+	queue.enqueue(revisionUndeployedStatus, "second")
+	queue.enqueue(revisionDeployedStatus, "third")
+
+	message, _ := queue.nextMessage()
+	assert.Equal(t, `"first"`, string(message.Payload), "Messages should be delivered in the enqueued order.")
+	queue.completeAttempt(message, errors.New("connection refused"))
+	assert.Len(t, queue.messages, 3, "A failed message should remain in the queue.")
+	assert.Equal(t, time.Hour, time.Until(message.NextAttempt).Round(time.Hour),
+		"The next attempt should be scheduled after the initial backoff.")
+
+	// The failed message waiting for its next attempt does not block the messages enqueued after it.
+	message, _ = queue.nextMessage()
+	assert.Equal(t, `"second"`, string(message.Payload), "A failed message should not block the queue.")
+	queue.completeAttempt(message, nil)
+	message, _ = queue.nextMessage()
+	assert.Equal(t, `"third"`, string(message.Payload), "Messages should be delivered in the enqueued order.")
+	queue.completeAttempt(message, nil)
+
+	message, wait := queue.nextMessage()
+	assert.Nil(t, message, "The failed message should not be delivered before its next attempt.")
+	assert.True(t, wait > 0 && wait <= time.Hour, "The wait duration should be until the next attempt, but found %v.",
+		wait)
+	assert.Len(t, queue.messages, 1, "Only the failed message should remain in the queue.")
+}
+
+func TestDeploymentStatusQueueOrderingOfAPI(t *testing.T) {
+	queue := newTestStatusQueue("")
+	queue.enqueue(revisionDeployedStatus, []*DeployedAPIRevision{UpdateDeployedRevisions("api1", 1, []string{"Default"},
+		"localhost")})
+	queue.enqueue(revisionUndeployedStatus, UnDeployedAPIRevision{APIUUID: "api1", RevisionUUID: "1",
+		Environment: "Default"})
+	queue.enqueue(revisionDeployedStatus, []*DeployedAPIRevision{UpdateDeployedRevisions("api2", 1, []string{"Default"},
+		"localhost")})
+
+	message, _ := queue.nextMessage()
+	assert.Equal(t, []string{"api1"}, message.APIIDs, "Messages should be delivered in the enqueued order.")
+	assert.Equal(t, revisionDeployedStatus, message.Kind, "Messages should be delivered in the enqueued order.")
+	queue.completeAttempt(message, errors.New("connection refused"))
+	failedMessage := message
+
+	// The failed message of api1 blocks the later messages of api1, but not the messages of the other APIs.
+	message, _ = queue.nextMessage()
+	assert.Equal(t, []string{"api2"}, message.APIIDs, "A failed message should not block the messages of other APIs.")
+	queue.completeAttempt(message, nil)
+	message, wait := queue.nextMessage()
+	assert.Nil(t, message, "A message should not be delivered before the earlier message of the same API.")
+	assert.True(t, wait > 0 && wait <= time.Hour, "The wait duration should be until the next attempt, but found %v.",
+		wait)
+
+	failedMessage.NextAttempt = time.Now()
+	message, _ = queue.nextMessage()
+	assert.Equal(t, failedMessage, message, "The failed message should be retried before the later messages of the API.")
+	queue.completeAttempt(message, nil)
+	message, _ = queue.nextMessage()
+	assert.Equal(t, revisionUndeployedStatus, message.Kind, "The undeployed message should be delivered after the "+
+		"deployed message of the API.")
+}
+
+func TestDeploymentStatusQueueDeliverMessages(t *testing.T) {
+	queue := newTestStatusQueue("")
+	queue.initialBackoff = time.Millisecond
+	queue.maxBackoff = time.Millisecond
+	delivered := make(chan string, 10)
+	failed := false
+	go queue.deliverMessages(func(kind string, payload []byte) error {
+		if !failed {
+			failed = true
+			return errors.New("connection refused")
+		}
+		delivered <- string(payload)
+		return nil
+	})
+	queue.enqueue(revisionDeployedStatus, "first")
+	queue.enqueue(revisionDeployedStatus, "second")
+
+	var payloads []string
+	for len(payloads) < 2 {
+		select {
+		case payload := <-delivered:
+			payloads = append(payloads, payload)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Messages are not delivered, delivered messages: %v", payloads)
+		}
+	}
+	assert.ElementsMatch(t, []string{`"first"`, `"second"`}, payloads, "All the messages should be delivered.")
+}
+
+func TestDeploymentStatusQueueDeadLetter(t *testing.T) {
+	directory := t.TempDir()
+	queue := newTestStatusQueue(directory)
+	queue.enqueue(revisionFailedStatus, "rejected")
+	queue.enqueue(revisionDeployedStatus, "unreachable")
+
+	message, _ := queue.nextMessage()
+	queue.completeAttempt(message, fmt.Errorf("%w: error response status code 404", errStatusRejected))
+	assert.Len(t, queue.messages, 1, "A rejected message should not be retried.")
+	assert.FileExists(t, filepath.Join(directory, deadLetterDirectory, message.ID+statusMessageFileSuffix),
+		"A rejected message should be moved to the dead letter directory.")
+
+	message = queue.messages[0]
+	for i := 0; i < queue.maxAttempts; i++ {
+		assert.Len(t, queue.messages, 1, "A failed message should be retried until the maximum attempts.")
+		queue.completeAttempt(message, errors.New("connection refused"))
+	}
+	assert.Empty(t, queue.messages, "A failed message should not be retried after the maximum attempts.")
+	assert.FileExists(t, filepath.Join(directory, deadLetterDirectory, message.ID+statusMessageFileSuffix),
+		"A failed message should be moved to the dead letter directory after the maximum attempts.")
+	assert.NoFileExists(t, queue.getFilePath(message), "A dead letter message should not be retried after a restart.")
+
+	// The messages in the dead letter directory are not loaded after a restart.
+	restartedQueue := newTestStatusQueue(directory)
+	restartedQueue.load()
+	assert.Empty(t, restartedQueue.messages, "The dead letter messages should not be loaded.")
+}
+
+func TestDeploymentStatusQueuePersistAndLoad(t *testing.T) {
+	directory := t.TempDir()
+	queue := newTestStatusQueue(directory)
+	queue.enqueue(revisionDeployedStatus, []*DeployedAPIRevision{UpdateDeployedRevisions("api", 1, []string{"Default"},
+		"localhost")})
+	queue.enqueue(revisionUndeployedStatus, UnDeployedAPIRevision{APIUUID: "api2", RevisionUUID: "1",
+		Environment: "Default"})
+	queue.enqueue(revisionDeployedStatus, "delivered")
+	for range queue.messages {
+		message, _ := queue.nextMessage()
+		if string(message.Payload) == `"delivered"` {
+			queue.completeAttempt(message, nil)
+		} else {
+			queue.completeAttempt(message, errors.New("connection refused"))
+		}
+	}
+
+	restartedQueue := newTestStatusQueue(directory)
+	restartedQueue.load()
+	if assert.Len(t, restartedQueue.messages, 2, "The undelivered messages should be loaded.") {
+		for i, message := range restartedQueue.messages {
+			assert.Equal(t, queue.messages[i].ID, message.ID, "Messages should be loaded in the enqueued order.")
+			assert.Equal(t, queue.messages[i].Kind, message.Kind, "Message kind mismatch.")
+			assert.JSONEq(t, string(queue.messages[i].Payload), string(message.Payload), "Message payload mismatch.")
+			assert.Equal(t, queue.messages[i].APIIDs, message.APIIDs, "Message API IDs mismatch.")
+			assert.Equal(t, 1, message.Attempts, "Message attempts mismatch.")
+			assert.True(t, queue.messages[i].NextAttempt.Equal(message.NextAttempt), "Message next attempt mismatch.")
+			assert.False(t, message.isPending, "A loaded message should not be pending.")
+		}
+	}
+}
+
+func TestDeploymentStatusQueueLoadCorruptFiles(t *testing.T) {
+	directory := t.TempDir()
+	queue := newTestStatusQueue(directory)
+	queue.enqueue(revisionDeployedStatus, "valid")
+	message, _ := queue.nextMessage()
+	queue.completeAttempt(message, errors.New("connection refused"))
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(directory, "00000000000000000000-000001.json"), []byte("{corrupt"),
+		0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(directory, "README.txt"), []byte("not a message"), 0600))
+	assert.Nil(t, os.Mkdir(filepath.Join(directory, "nested.json"), os.ModePerm))
+
+	restartedQueue := newTestStatusQueue(directory)
+	restartedQueue.load()
+	if assert.Len(t, restartedQueue.messages, 1, "Only the valid message should be loaded.") {
+		assert.Equal(t, `"valid"`, string(restartedQueue.messages[0].Payload), "Message payload mismatch.")
+	}
+
+	missingQueue := newTestStatusQueue(filepath.Join(directory, "missing"))
+	missingQueue.load()
+	assert.Empty(t, missingQueue.messages, "No messages should be loaded from a missing directory.")
+}
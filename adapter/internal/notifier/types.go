@@ -19,3 +19,17 @@ type UnDeployedAPIRevision struct {
 	RevisionUUID string `json:"revisionUUID"`
 	Environment  string `json:"environment"`
 }
+
+// FailedAPIRevision represents Information of failed API revision deployment
+type FailedAPIRevision struct {
+	APIID      string          `json:"apiId"`
+	RevisionID int             `json:"revisionId"`
+	EnvInfo    []FailedEnvInfo `json:"envInfo"`
+}
+
+// FailedEnvInfo represents env Information of failed API revision deployment, along with the error details
+type FailedEnvInfo struct {
+	Name         string `json:"name"`
+	VHost        string `json:"vhost"`
+	ErrorMessage string `json:"errorMessage"`
+}
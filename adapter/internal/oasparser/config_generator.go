@@ -24,6 +24,7 @@ import (
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	"github.com/wso2/product-microgateway/adapter/config"
 	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
@@ -69,6 +70,10 @@ func GetGlobalClusters() ([]*clusterv3.Cluster, []*corev3.Address) {
 		}
 	}
 
+	jwksClusters, jwksEndpoints := envoyconf.CreateJWKSClusters(conf)
+	clusters = append(clusters, jwksClusters...)
+	endpoints = append(endpoints, jwksEndpoints...)
+
 	return clusters, endpoints
 }
 
@@ -78,14 +83,27 @@ func GetGlobalClusters() ([]*clusterv3.Cluster, []*corev3.Address) {
 // The provided set of envoy routes will be assigned under the virtual host
 //
 // The RouteConfiguration is named as "default"
-func GetProductionListenerAndRouteConfig(vhostToRouteArrayMap map[string][]*routev3.Route) ([]*listenerv3.Listener, *routev3.RouteConfiguration) {
-	listeners := envoy.CreateListenersWithRds()
+func GetProductionListenerAndRouteConfig(environment string, vhostToRouteArrayMap map[string][]*routev3.Route,
+	apiErrorResponseMappers []*hcmv3.ResponseMapper) ([]*listenerv3.Listener, *routev3.RouteConfiguration) {
+	listeners := envoy.CreateListenersWithRds(environment, apiErrorResponseMappers)
 	vHosts := envoy.CreateVirtualHosts(vhostToRouteArrayMap)
 	routeConfig := envoy.CreateRoutesConfigForRds(vHosts)
 
 	return listeners, routeConfig
 }
 
+// GetProductionListeners regenerates the listeners of the environment with the provided API level
+// error response mappers.
+func GetProductionListeners(environment string, apiErrorResponseMappers []*hcmv3.ResponseMapper) []*listenerv3.Listener {
+	return envoy.CreateListenersWithRds(environment, apiErrorResponseMappers)
+}
+
+// GetAPIErrorResponseMappers generates the local reply mappers for the custom error responses of the API
+// deployed in the given vhost.
+func GetAPIErrorResponseMappers(mgwSwagger model.MgwSwagger, vHost string) []*hcmv3.ResponseMapper {
+	return envoy.CreateAPIErrorResponseMappers(mgwSwagger.GetXWso2ErrorResponses(), vHost, mgwSwagger.GetXWso2Basepath())
+}
+
 // GetCacheResources converts the envoy endpoints, clusters, routes, and listener to
 // the resource type which is the format required for the Xds cache.
 //
@@ -285,8 +303,9 @@ func castPoliciesToEnforcerPolicies(policies []model.Policy) []*api.Policy {
 			}
 		}
 		enforcerPolicies = append(enforcerPolicies, &api.Policy{
-			Action:     policy.Action,
-			Parameters: parameterMap,
+			Action:      policy.Action,
+			Parameters:  parameterMap,
+			FilterClass: policy.FilterClass,
 		})
 	}
 	return enforcerPolicies
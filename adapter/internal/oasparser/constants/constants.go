@@ -35,7 +35,16 @@ const (
 	XWso2BasePath                     string = "x-wso2-basePath"
 	XWso2Label                        string = "x-wso2-label"
 	XWso2Cors                         string = "x-wso2-cors"
+	XWso2ErrorResponses               string = "x-wso2-error-responses"
+	XWso2IPFilter                     string = "x-wso2-ip-filter"
+	XWso2Routing                      string = "x-wso2-routing"
+	XWso2Mirror                       string = "x-wso2-mirror"
+	XWso2Timeout                      string = "x-wso2-timeout"
+	XWso2Retry                        string = "x-wso2-retry"
+	XWso2Proxy                        string = "x-wso2-proxy"
 	XWso2HTTP2BackendEnabled          string = "x-wso2-http2-backend-enabled"
+	XWso2RouterJWTValidation          string = "x-wso2-router-jwt-validation"
+	XWso2MaxRequestBodySize           string = "x-wso2-max-request-body-size"
 	XThrottlingTier                   string = "x-throttling-tier"
 	XWso2ThrottlingTier               string = "x-wso2-throttling-tier"
 	XAuthHeader                       string = "x-wso2-auth-header"
@@ -49,11 +58,28 @@ const (
 	XUriMapping                       string = "x-uri-mapping"
 )
 
+// condition types of the content based routing rules mentioned under x-wso2-routing
+const (
+	RoutingConditionHeader string = "header"
+	RoutingConditionQuery  string = "query"
+	RoutingConditionClaim  string = "claim"
+	RoutingConditionPath   string = "path"
+)
+
+// conditions on which the requests are retried, mentioned under x-wso2-retry
+const (
+	RetryOn5xx            string = "5xx"
+	RetryOnGatewayError   string = "gateway-error"
+	RetryOnReset          string = "reset"
+	RetryOnConnectFailure string = "connect-failure"
+)
+
 // cluster name prefixes
 const (
 	SandClustersConfigNamePrefix    string = "clusterSand"
 	ProdClustersConfigNamePrefix    string = "clusterProd"
 	XWso2EPClustersConfigNamePrefix string = "xwso2cluster"
+	MirrorClustersConfigNamePrefix  string = "clusterMirror"
 )
 
 // sub-property values and keys relevant for x-wso2-application security extension
@@ -80,6 +106,8 @@ const (
 	ClusterTimeout            string = "clusterTimeout"
 	RequestTimeout            string = "requestTimeout"
 	Includes                  string = "includes"
+	APILevelInterceptor       string = "api"
+	ResourceLevelInterceptor  string = "resource"
 	OperationLevelInterceptor string = "operation"
 )
 
@@ -98,6 +126,27 @@ const (
 	InterceptorServiceURL      string = "interceptorServiceURL"
 	InterceptorServiceIncludes string = "includes"
 	IncludeQueryParams         string = "includeQueryParams"
+	IPFilterAction             string = "IP_FILTER"
+	IPFilterAllow              string = "allow"
+	IPFilterDeny               string = "deny"
+	FaultInjectionAction       string = "FAULT_INJECTION"
+	FaultDelayDuration         string = "delayDuration"
+	FaultDelayPercentage       string = "delayPercentage"
+	FaultAbortStatusCode       string = "abortStatusCode"
+	FaultAbortPercentage       string = "abortPercentage"
+	FaultHeaderName            string = "headerName"
+	FaultHeaderValue           string = "headerValue"
+	SetHeaderAction            string = "SET_HEADER"
+	RemoveHeaderAction         string = "REMOVE_HEADER"
+	AddQueryAction             string = "ADD_QUERY"
+	RemoveQueryAction          string = "REMOVE_QUERY"
+	RenameQueryAction          string = "RENAME_QUERY"
+	HeaderName                 string = "headerName"
+	HeaderValue                string = "headerValue"
+	QueryParamName             string = "queryParamName"
+	QueryParamValue            string = "queryParamValue"
+	CurrentQueryParamName      string = "currentQueryParamName"
+	UpdatedQueryParamName      string = "updatedQueryParamName"
 )
 
 // Constants that occur as values in api.yaml
@@ -112,6 +161,13 @@ const (
 	InlineEndpointType    string = "INLINE"
 )
 
+// Upstream protocols of an endpoint cluster
+const (
+	UpstreamProtocolAuto  string = "auto"
+	UpstreamProtocolHTTP1 string = "h1"
+	UpstreamProtocolHTTP2 string = "h2"
+)
+
 // Constants used for version identification of API definitions
 const (
 	Swagger      string = "swagger"
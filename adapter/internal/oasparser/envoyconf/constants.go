@@ -32,6 +32,9 @@ const (
 	httpConManagerStartPrefix  string = "ingress_http"
 	extAuthzPerRouteName       string = "type.googleapis.com/envoy.extensions.filters.http.ext_authz.v3.ExtAuthzPerRoute"
 	luaPerRouteName            string = "type.googleapis.com/envoy.extensions.filters.http.lua.v3.LuaPerRoute"
+	rbacPerRouteName           string = "type.googleapis.com/envoy.extensions.filters.http.rbac.v3.RBACPerRoute"
+	faultPerRouteName          string = "type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"
+	bufferPerRouteName         string = "type.googleapis.com/envoy.extensions.filters.http.buffer.v3.BufferPerRoute"
 	mgwWebSocketFilterName     string = "envoy.filters.http.mgw_websocket"
 	mgwWebSocketWASMFilterName string = "envoy.filters.http.mgw_WASM_websocket"
 	mgwWASMVmID                string = "mgw_WASM_vm"
@@ -48,6 +51,54 @@ const (
 	defaultListenerSecretConfigName string = "DefaultListenerSecret"
 )
 
+// SDS secret names of the client certificates presented to the endpoints which require mutual TLS
+const (
+	defaultUpstreamClientCertSecretName string = "DefaultUpstreamClientCertSecret"
+	upstreamClientCertSecretNamePrefix  string = "UpstreamClientCertSecret_"
+)
+
+// upstream proxy related constants
+const (
+	upstreamProxyClusterNamePrefix string = "upstream_proxy_"
+	upstreamProxyTunnelNamePrefix  string = "upstream_proxy_tunnel_"
+	// upstreamProxyTunnelHost is the loopback address the tunnel listeners are bound to.
+	upstreamProxyTunnelHost         string = "127.0.0.1"
+	upstreamProxyTunnelMetadataKey  string = "wso2.upstream_proxy_tunnel"
+	upstreamProxyTunnelMetadataName string = "name"
+	proxyAuthorizationHeader        string = "Proxy-Authorization"
+)
+
+// router JWT validation related constants
+const (
+	jwtAuthnFilterName   string = "envoy.filters.http.jwt_authn"
+	jwtAuthnName         string = "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication"
+	jwtAuthnPerRouteName string = "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.PerRouteConfig"
+	jwksClusterPrefix    string = "wso2_cc_jwks_"
+	// jwtPayloadMetadataKey is the dynamic metadata key of the jwt_authn filter, which carries the validated
+	// token payload.
+	jwtPayloadMetadataKey string = "jwt_payload"
+	// routerJWTDefaultRequirement accepts the tokens of the issuers which enabled the router validation for all
+	// the APIs, while routerJWTAllRequirement accepts the tokens of all the issuers which could be validated at
+	// the router, for the APIs enabled via the x-wso2-router-jwt-validation extension.
+	routerJWTDefaultRequirement string = "router_jwt_default"
+	routerJWTAllRequirement     string = "router_jwt_all"
+	jwtScopeClaim               string = "scope"
+	jwtKeyTypeClaim             string = "keytype"
+	sandboxKeyType              string = "SANDBOX"
+	unlimitedThrottlingTier     string = "Unlimited"
+	bearerTokenPrefix           string = "Bearer "
+)
+
+// HTTP/1 header formatter related constants
+const (
+	preserveCaseHeaderFormatterName string = "envoy.http.stateful_header_formatters.preserve_case"
+)
+
+// unmatchedRequestMaxBytes is the listener level request body size limit of the buffer filter. As the buffer
+// filter is configured per route for all the routes, the limit applies only to the requests not matching any
+// route, which are rejected by the router without reading the body.
+const unmatchedRequestMaxBytes uint32 = 8192
+
 //cluster prefixes
 const (
 	xWso2EPClustersConfigNamePrefix     string = "xwso2cluster"
@@ -67,12 +118,24 @@ const (
 	apiNameContextExtension         string = "name"
 	prodClusterNameContextExtension string = "prodClusterName"
 	sandClusterNameContextExtension string = "sandClusterName"
-	retryPolicyRetriableStatusCodes string = "retriable-status-codes"
+	// routeTimeoutContextExtension and routeRetryPolicyContextExtension are set if the route has an operation level
+	// timeout or retry policy, which should not be overridden by the endpoint level configurations of the enforcer.
+	routeTimeoutContextExtension     string = "routeTimeout"
+	routeRetryPolicyContextExtension string = "routeRetryPolicy"
+	retryPolicyRetriableStatusCodes  string = "retriable-status-codes"
+	// routingClaimsContextExtension holds the comma separated JWT claims matched by the content based routing rules
+	// of the resource, which are added to the ext_authz dynamic metadata by the enforcer.
+	routingClaimsContextExtension string = "routingClaims"
 )
 
 const (
+	// previousHostsRetryPredicate denotes the retry host predicate which rejects the hosts already attempted.
+	previousHostsRetryPredicate string = "envoy.retry_host_predicates.previous_hosts"
 	// clusterHeaderName denotes the constant used for header based routing decisions.
 	clusterHeaderName string = "x-wso2-cluster-header"
+	// claimMetadataKeyPrefix denotes the prefix of the ext_authz dynamic metadata keys which carry
+	// the JWT claims used for content based routing.
+	claimMetadataKeyPrefix string = "x-wso2-claim-"
 	// upstreamServiceTimeHeader the header which is used to denote the upstream service time
 	upstreamServiceTimeHeader string = "x-envoy-upstream-service-time"
 	// xWso2requestInterceptor used to provide request interceptor details for api and resource level
@@ -93,6 +156,21 @@ const (
 	httpMethodHeader string = ":method"
 )
 
+// CORS related constants
+const (
+	corsRequestMethodHeader string = "access-control-request-method"
+	// originWildcardRegex is the regex a wildcard within an allowed origin is converted to.
+	originWildcardRegex string = "[a-zA-Z0-9.-]+"
+)
+
+// headers used to scope the custom error responses
+const (
+	pathHeaderName         string = ":path"
+	authorityHeaderName    string = ":authority"
+	originalPathHeaderName string = "x-envoy-original-path"
+	acceptHeaderName       string = "accept"
+)
+
 // Paths exposed from the router by default
 const (
 	healthPath  string = "/health"
@@ -120,3 +198,52 @@ const (
 	// Azure tracer's name
 	TracerTypeAzure = "azure"
 )
+
+// queryMutationLuaTemplate applies the static query parameter mutations of a route before calling the
+// envoy_on_request function of the route (if available). The query parameters are added replacing the query
+// parameters having the same name, and renamed without changing their order. The names of the query parameters
+// are decoded before comparing, as the backend decodes the encoded variants of a name (ex: api%5Fkey) as well.
+const queryMutationLuaTemplate = `
+local static_query_mutations = {{{ .QueryMutations }}
+}
+local function decode_query_component(value)
+	value = string.gsub(value, "%+", " ")
+	value = string.gsub(value, "%%(%x%x)", function(hex) return string.char(tonumber(hex, 16)) end)
+	return value
+end
+local envoy_on_request_without_query_mutation = envoy_on_request
+function envoy_on_request(request_handle)
+	local path = request_handle:headers():get(":path")
+	local query_params = {}
+	local query_index = string.find(path, "?", 1, true)
+	if query_index ~= nil then
+		for query_param in string.gmatch(string.sub(path, query_index + 1), "[^&]+") do
+			table.insert(query_params, query_param)
+		end
+		path = string.sub(path, 1, query_index - 1)
+	end
+	for _, mutation in ipairs(static_query_mutations) do
+		local mutation_name = decode_query_component(mutation.name)
+		for i = #query_params, 1, -1 do
+			local name = string.match(query_params[i], "^[^=]*")
+			if decode_query_component(name) == mutation_name then
+				if mutation.action == "RENAME_QUERY" then
+					query_params[i] = mutation.value .. string.sub(query_params[i], #name + 1)
+				else
+					table.remove(query_params, i)
+				end
+			end
+		end
+		if mutation.action == "ADD_QUERY" then
+			table.insert(query_params, mutation.name .. "=" .. mutation.value)
+		end
+	end
+	if #query_params > 0 then
+		path = path .. "?" .. table.concat(query_params, "&")
+	end
+	request_handle:headers():replace(":path", path)
+	if envoy_on_request_without_query_mutation ~= nil then
+		envoy_on_request_without_query_mutation(request_handle)
+	end
+end
+`
@@ -25,17 +25,28 @@ import (
 	"testing"
 	"time"
 
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
 	extAuthService "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	jwtauthnv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	rbacv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	tcpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	upstreams_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
 	envoy_type_matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/stretchr/testify/assert"
 	"github.com/wso2/product-microgateway/adapter/config"
 	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+	gopherlua "github.com/yuin/gopher-lua"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -243,6 +254,278 @@ func TestCreateRouteExtAuthzContext(t *testing.T) {
 		"Sandbox Cluster mismatch in route ext authz context.")
 }
 
+func TestCreateRouteIPFilter(t *testing.T) {
+	apiIPFilter := &model.IPFilterConfig{Allow: []string{"10.0.0.0/8"}}
+	operationIPFilter := &model.IPFilterConfig{Deny: []string{"192.168.1.10/32", "2001:db8::/32"}}
+
+	params := generateRouteCreateParamsForUnitTests("WSO2", "HTTP", "localhost", "/xWso2BasePath", "1.0.0",
+		"/basepath", "/resourcePath", []string{"GET", "POST"}, "prodCluster", "", nil, false)
+	routeWithoutIPFilter := createRoute(params)
+	assert.Nil(t, routeWithoutIPFilter.GetTypedPerFilterConfig()[wellknown.HTTPRoleBasedAccessControl],
+		"RBAC per route config should not be available without ip filters.")
+
+	params.ipFilters = map[string]*model.IPFilterConfig{"GET": apiIPFilter, "POST": apiIPFilter}
+	routeWithCommonIPFilter := createRoute(params)
+	assert.Nil(t, routeWithCommonIPFilter.Validate(), "Route validation failed.")
+	rbacPerRoute := &rbacv3.RBACPerRoute{}
+	err := ptypes.UnmarshalAny(routeWithCommonIPFilter.GetTypedPerFilterConfig()[wellknown.HTTPRoleBasedAccessControl], rbacPerRoute)
+	assert.Nilf(t, err, "Error while parsing RBACPerRoute config %v", rbacPerRoute)
+	policies := rbacPerRoute.GetRbac().GetRules().GetPolicies()
+	assert.Equal(t, 1, len(policies), "A single policy should be created for a common ip filter.")
+	assert.True(t, policies["ip-filter"].GetPermissions()[0].GetAny(), "Common ip filter should apply to any request.")
+	allowedIDs := policies["ip-filter"].GetPrincipals()[0].GetOrIds().GetIds()[0].GetNotId().GetOrIds().GetIds()
+	assert.Equal(t, "10.0.0.0", allowedIDs[0].GetRemoteIp().GetAddressPrefix(), "Allowed address prefix mismatch.")
+	assert.Equal(t, uint32(8), allowedIDs[0].GetRemoteIp().GetPrefixLen().GetValue(), "Allowed prefix length mismatch.")
+
+	params.ipFilters = map[string]*model.IPFilterConfig{"GET": apiIPFilter, "POST": operationIPFilter}
+	routeWithOperationIPFilter := createRoute(params)
+	assert.Nil(t, routeWithOperationIPFilter.Validate(), "Route validation failed.")
+	rbacPerRoute = &rbacv3.RBACPerRoute{}
+	err = ptypes.UnmarshalAny(routeWithOperationIPFilter.GetTypedPerFilterConfig()[wellknown.HTTPRoleBasedAccessControl], rbacPerRoute)
+	assert.Nilf(t, err, "Error while parsing RBACPerRoute config %v", rbacPerRoute)
+	policies = rbacPerRoute.GetRbac().GetRules().GetPolicies()
+	assert.Equal(t, 2, len(policies), "A policy should be created per method for different ip filters.")
+	assert.Equal(t, "POST", policies["ip-filter-POST"].GetPermissions()[0].GetHeader().GetStringMatch().GetExact(),
+		"Method mismatch in the ip filter permission.")
+	deniedIDs := policies["ip-filter-POST"].GetPrincipals()[0].GetOrIds().GetIds()
+	assert.Equal(t, 2, len(deniedIDs), "Only the denied ranges should be available when allow list is empty.")
+	assert.Equal(t, "2001:db8::", deniedIDs[1].GetRemoteIp().GetAddressPrefix(), "Denied address prefix mismatch.")
+}
+
+func TestCreateRouteFaultInjection(t *testing.T) {
+	params := generateRouteCreateParamsForUnitTests("WSO2", "HTTP", "localhost", "/xWso2BasePath", "1.0.0",
+		"/basepath", "/resourcePath", []string{"GET"}, "prodCluster", "", nil, false)
+	routeWithoutFault := createRoute(params)
+	assert.Nil(t, routeWithoutFault.GetTypedPerFilterConfig()[wellknown.Fault],
+		"Fault per route config should not be available without fault injection.")
+
+	delayPercentage, abortPercentage := float64(50), float64(10)
+	params.faultInjection = &model.FaultInjectionConfig{DelayDuration: 1500, DelayPercentage: &delayPercentage,
+		AbortStatusCode: 503, AbortPercentage: &abortPercentage, HeaderName: "X-Fault", HeaderValue: "enabled"}
+	routeWithFault := createRoute(params)
+	assert.Nil(t, routeWithFault.Validate(), "Route validation failed.")
+	fault := &faultv3.HTTPFault{}
+	err := ptypes.UnmarshalAny(routeWithFault.GetTypedPerFilterConfig()[wellknown.Fault], fault)
+	assert.Nilf(t, err, "Error while parsing HTTPFault config %v", fault)
+	assert.Nil(t, fault.Validate(), "Fault validation failed.")
+	assert.Equal(t, int64(1), fault.GetDelay().GetFixedDelay().GetSeconds(), "Delay duration mismatch.")
+	assert.Equal(t, int32(500000000), fault.GetDelay().GetFixedDelay().GetNanos(), "Delay duration mismatch.")
+	assert.Equal(t, uint32(500000), fault.GetDelay().GetPercentage().GetNumerator(), "Delay percentage mismatch.")
+	assert.Equal(t, uint32(503), fault.GetAbort().GetHttpStatus(), "Abort status code mismatch.")
+	assert.Equal(t, uint32(100000), fault.GetAbort().GetPercentage().GetNumerator(), "Abort percentage mismatch.")
+	assert.Equal(t, "x-fault", fault.GetHeaders()[0].GetName(), "Header name mismatch.")
+	assert.Equal(t, "enabled", fault.GetHeaders()[0].GetStringMatch().GetExact(), "Header value mismatch.")
+
+	// An explicit zero percentage turns the fault off instead of injecting it to all the requests.
+	zeroPercentage := float64(0)
+	params.faultInjection = &model.FaultInjectionConfig{DelayDuration: 1500, DelayPercentage: &zeroPercentage,
+		AbortStatusCode: 503}
+	routeWithFault = createRoute(params)
+	fault = &faultv3.HTTPFault{}
+	err = ptypes.UnmarshalAny(routeWithFault.GetTypedPerFilterConfig()[wellknown.Fault], fault)
+	assert.Nilf(t, err, "Error while parsing HTTPFault config %v", fault)
+	assert.Equal(t, uint32(0), fault.GetDelay().GetPercentage().GetNumerator(), "Delay percentage mismatch.")
+	assert.Equal(t, uint32(1000000), fault.GetAbort().GetPercentage().GetNumerator(), "Abort percentage mismatch.")
+}
+
+func TestCreateRouteRequestBodySizeLimit(t *testing.T) {
+	params := generateRouteCreateParamsForUnitTests("WSO2", "HTTP", "localhost", "/xWso2BasePath", "1.0.0",
+		"/basepath", "/resourcePath", []string{"POST"}, "prodCluster", "", nil, false)
+	// The buffer filter should be disabled for all the routes without a size limit, as the listener level
+	// configuration does not limit the request body size.
+	for _, route := range []*routev3.Route{createRoute(params), CreateTokenRoute(), CreateHealthEndpoint(),
+		CreateReadyEndpoint()} {
+		bufferPerRoute := &bufferv3.BufferPerRoute{}
+		err := ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.Buffer], bufferPerRoute)
+		assert.Nilf(t, err, "Error while parsing BufferPerRoute config %v", bufferPerRoute)
+		assert.True(t, bufferPerRoute.GetDisabled(), "Buffer filter should be disabled for the route "+route.GetName())
+	}
+
+	params.maxRequestBodySize = 2048
+	routeWithLimit := createRoute(params)
+	assert.Nil(t, routeWithLimit.Validate(), "Route validation failed.")
+	bufferPerRoute := &bufferv3.BufferPerRoute{}
+	err := ptypes.UnmarshalAny(routeWithLimit.GetTypedPerFilterConfig()[wellknown.Buffer], bufferPerRoute)
+	assert.Nilf(t, err, "Error while parsing BufferPerRoute config %v", bufferPerRoute)
+	assert.Nil(t, bufferPerRoute.Validate(), "BufferPerRoute validation failed.")
+	assert.Equal(t, uint32(2048), bufferPerRoute.GetBuffer().GetMaxRequestBytes().GetValue(),
+		"Max request bytes mismatch.")
+
+	// The oversized requests should be rejected before calling the enforcer.
+	var filterNames []string
+	for _, filter := range getHTTPFilters() {
+		filterNames = append(filterNames, filter.GetName())
+	}
+	bufferIndex := indexOf(filterNames, wellknown.Buffer)
+	assert.NotEqual(t, -1, bufferIndex, "Buffer filter should be available.")
+	assert.Less(t, bufferIndex, indexOf(filterNames, wellknown.HTTPExternalAuthorization),
+		"Buffer filter should be applied before the ext_authz filter.")
+}
+
+func TestBufferFilterForUnmatchedRequests(t *testing.T) {
+	params := generateRouteCreateParamsForUnitTests("WSO2", "HTTP", "localhost", "/xWso2BasePath", "1.0.0",
+		"/basepath", "/resourcePath", []string{"POST"}, "prodCluster", "", nil, false)
+	virtualHost := &routev3.VirtualHost{
+		Name:    "localhost",
+		Domains: []string{"localhost", "localhost:*"},
+		Routes:  []*routev3.Route{createRoute(params), CreateHealthEndpoint(), CreateReadyEndpoint()},
+	}
+	// Each route overrides the listener level buffer filter configuration.
+	for _, route := range virtualHost.GetRoutes() {
+		_, found := route.GetTypedPerFilterConfig()[wellknown.Buffer]
+		assert.True(t, found, "Buffer per route config should be available for the route "+route.GetName())
+	}
+
+	// The listener level configuration is applied to a request which does not match any route.
+	request := &RouteMatchRequest{Host: "localhost", Method: "POST", Path: "/unknown/resource"}
+	route, _, _ := MatchRoute(virtualHost, request)
+	assert.Nil(t, route, "Request should not match any route.")
+	bufferFilter := getBufferFilter()
+	buffer := &bufferv3.Buffer{}
+	err := ptypes.UnmarshalAny(bufferFilter.GetTypedConfig(), buffer)
+	assert.Nilf(t, err, "Error while parsing Buffer config %v", buffer)
+	assert.Nil(t, buffer.Validate(), "Buffer validation failed.")
+	assert.Equal(t, unmatchedRequestMaxBytes, buffer.GetMaxRequestBytes().GetValue(),
+		"Body of a request not matching any route should be limited.")
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestCreateRouteStaticMutations(t *testing.T) {
+	params := generateRouteCreateParamsForUnitTests("WSO2", "HTTP", "localhost", "/xWso2BasePath", "1.0.0",
+		"/basepath", "/resourcePath", []string{"GET"}, "prodCluster", "", nil, false)
+	params.staticMutations = &model.StaticMutations{
+		RequestHeadersToAdd:     []model.NameValuePair{{Name: "x-request-header", Value: "100%"}},
+		RequestHeadersToRemove:  []string{"x-removed-request-header"},
+		ResponseHeadersToAdd:    []model.NameValuePair{{Name: "x-response-header", Value: "bar"}},
+		ResponseHeadersToRemove: []string{"x-removed-response-header"},
+		QueryMutations: []model.QueryMutation{
+			{Action: "ADD_QUERY", Name: "foo", Value: "bar baz"},
+			{Action: "REMOVE_QUERY", Name: "api_key"},
+			{Action: "RENAME_QUERY", Name: "id", Value: "legacyId"},
+		},
+	}
+	route := createRoute(params)
+	assert.Nil(t, route.Validate(), "Route validation failed.")
+
+	assert.Len(t, route.GetRequestHeadersToAdd(), 1, "Request headers to add mismatch.")
+	assert.Equal(t, "x-request-header", route.GetRequestHeadersToAdd()[0].GetHeader().GetKey(), "Header name mismatch.")
+	assert.Equal(t, "100%%", route.GetRequestHeadersToAdd()[0].GetHeader().GetValue(),
+		"Header value should be escaped.")
+	assert.False(t, route.GetRequestHeadersToAdd()[0].GetAppend().GetValue(), "Header should be overridden.")
+	assert.Contains(t, route.GetRequestHeadersToRemove(), "x-removed-request-header",
+		"Request headers to remove mismatch.")
+	assert.Contains(t, route.GetRequestHeadersToRemove(), clusterHeaderName, "Cluster header should be removed.")
+	assert.Equal(t, "x-response-header", route.GetResponseHeadersToAdd()[0].GetHeader().GetKey(),
+		"Response header name mismatch.")
+	assert.Contains(t, route.GetResponseHeadersToRemove(), "x-removed-response-header",
+		"Response headers to remove mismatch.")
+
+	luaPerRoute := &lua.LuaPerRoute{}
+	err := ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.Lua], luaPerRoute)
+	assert.Nilf(t, err, "Error while parsing Lua per route config %v", luaPerRoute)
+	luaScript := luaPerRoute.GetSourceCode().GetInlineString()
+	// The encoded variants of the query parameter names should be mutated as well, since the backend decodes them.
+	pathMutations := map[string]string{
+		"/pets":                            "/pets?foo=bar+baz",
+		"/pets?foo=qux&name=tom":           "/pets?name=tom&foo=bar+baz",
+		"/pets?api_key=x&id=1&name=tom":    "/pets?legacyId=1&name=tom&foo=bar+baz",
+		"/pets?api%5Fkey=x&name=tom":       "/pets?name=tom&foo=bar+baz",
+		"/pets?api_ke%79=x&%69d=1":         "/pets?legacyId=1&foo=bar+baz",
+		"/pets?api%5fkey&%66oo=qux":        "/pets?foo=bar+baz",
+		"/pets?api_key_id=x&idx=1&api+key": "/pets?api_key_id=x&idx=1&api+key&foo=bar+baz",
+	}
+	for path, expectedPath := range pathMutations {
+		assert.Equal(t, expectedPath, runLuaRequestHandler(t, luaScript, path), "Mutated path mismatch for "+path)
+	}
+}
+
+// runLuaRequestHandler runs the envoy_on_request function of the Lua script for a request having the given path,
+// and returns the path of the request after the function is executed.
+func runLuaRequestHandler(t *testing.T, luaScript string, path string) string {
+	luaState := gopherlua.NewState()
+	defer luaState.Close()
+	luaState.SetGlobal("request_path", gopherlua.LString(path))
+	err := luaState.DoString(luaScript + `
+		local headers = {}
+		function headers:get(name)
+			return request_path
+		end
+		function headers:replace(name, value)
+			request_path = value
+		end
+		local request_handle = {}
+		function request_handle:headers()
+			return headers
+		end
+		envoy_on_request(request_handle)`)
+	assert.Nil(t, err, "Error while running the Lua script.")
+	return luaState.GetGlobal("request_path").String()
+}
+
+func TestSimulateRouteMatching(t *testing.T) {
+	prodParams := generateRouteCreateParamsForUnitTests("PetStore", "HTTP", "localhost", "/petstore", "1.0.0",
+		"/api", "/pets/{petId}", []string{"GET"}, "prodCluster", "sandCluster", nil, false)
+	sandParams := generateRouteCreateParamsForUnitTests("PetStore", "HTTP", "localhost", "/petstore", "1.0.0",
+		"/api", "/pets/{petId}", []string{"GET"}, "prodCluster", "sandCluster", nil, false)
+	sandParams.isSandbox = true
+	routeConfig := &routev3.RouteConfiguration{
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "localhost",
+				Domains: []string{"localhost", "localhost:*"},
+				Routes:  []*routev3.Route{createRoute(sandParams), createRoute(prodParams)},
+			},
+			{
+				Name:    "[::1]",
+				Domains: []string{"[::1]"},
+			},
+			{
+				Name:    "foo.com",
+				Domains: []string{"foo.com"},
+			},
+		},
+	}
+
+	assert.Nil(t, FindVirtualHost(routeConfig, "bar.com"), "Virtual host should not match an unknown host.")
+	assert.Equal(t, "foo.com", FindVirtualHost(routeConfig, "foo.com:9095").GetName(),
+		"Virtual host should match the host after stripping the port.")
+	assert.Equal(t, "[::1]", FindVirtualHost(routeConfig, "[::1]:9095").GetName(),
+		"Virtual host should match the IPv6 host after stripping the port.")
+	virtualHost := FindVirtualHost(routeConfig, "localhost:9095")
+	assert.NotNil(t, virtualHost, "Virtual host should match the host with a port.")
+
+	request := &RouteMatchRequest{Host: "localhost", Method: "GET", Path: "/petstore/pets/1", ClusterName: "prodCluster"}
+	route, mismatches, _ := MatchRoute(virtualHost, request)
+	assert.Equal(t, routeConfig.VirtualHosts[0].Routes[1], route, "Production route should be matched.")
+	assert.Empty(t, mismatches, "Mismatches should be empty for a matched route.")
+	assert.Equal(t, "prodCluster", GetRouteCluster(route, request), "Cluster mismatch.")
+	rewrittenPath, err := GetRewrittenPath(route, request.Path)
+	assert.Nil(t, err, "Error while rewriting the path.")
+	assert.Equal(t, "/api/pets/1", rewrittenPath, "Rewritten path mismatch.")
+	routeContext := GetRouteContext(route)
+	assert.Equal(t, "/pets/{petId}", routeContext.ResourcePath, "Resource path mismatch.")
+	assert.Equal(t, "sandCluster", routeContext.SandClusterName, "Sandbox cluster name mismatch.")
+
+	request.ClusterName = "sandCluster"
+	route, _, _ = MatchRoute(virtualHost, request)
+	assert.Equal(t, routeConfig.VirtualHosts[0].Routes[0], route, "Sandbox route should be matched.")
+	assert.Equal(t, "sandCluster", GetRouteCluster(route, request), "Cluster mismatch.")
+
+	request.Method = "POST"
+	route, mismatches, _ = MatchRoute(virtualHost, request)
+	assert.Nil(t, route, "No route should match a method which is not allowed.")
+	assert.Len(t, mismatches, 2, "Mismatches should be available for each route.")
+	assert.Contains(t, mismatches[1], "header :method does not match", "Mismatch reason mismatch.")
+}
+
 func TestGenerateTLSCert(t *testing.T) {
 	publicKeyPath := config.GetMgwHome() + "/adapter/security/localhost.pem"
 	privateKeyPath := config.GetMgwHome() + "/adapter/security/localhost.key"
@@ -559,9 +842,9 @@ func TestCreateUpstreamTLSContext(t *testing.T) {
 	}}
 
 	tlsCert := generateTLSCert(defaultMgwKeyPath, defaultMgwCertPath)
-	upstreamTLSContextWithCerts := createUpstreamTLSContext(certByteArr, hostNameAddress, false)
-	upstreamTLSContextWithoutCerts := createUpstreamTLSContext(nil, hostNameAddress, false)
-	upstreamTLSContextWithIP := createUpstreamTLSContext(certByteArr, hostNameAddressWithIP, false)
+	upstreamTLSContextWithCerts := createUpstreamTLSContext(certByteArr, "", hostNameAddress, false)
+	upstreamTLSContextWithoutCerts := createUpstreamTLSContext(nil, "", hostNameAddress, false)
+	upstreamTLSContextWithIP := createUpstreamTLSContext(certByteArr, "", hostNameAddressWithIP, false)
 
 	assert.NotEmpty(t, upstreamTLSContextWithCerts, "Upstream TLS Context should not be null when certs provided")
 	assert.NotEmpty(t, upstreamTLSContextWithCerts.CommonTlsContext, "CommonTLSContext should not be "+
@@ -597,6 +880,203 @@ func TestCreateUpstreamTLSContext(t *testing.T) {
 		"Upstream SAN type mismatch.")
 }
 
+func TestCreateUpstreamTLSContextWithClientCert(t *testing.T) {
+	address := &corev3.Address{Address: &corev3.Address_SocketAddress{
+		SocketAddress: &corev3.SocketAddress{
+			Address:  "abc.com",
+			Protocol: corev3.SocketAddress_TCP,
+			PortSpecifier: &corev3.SocketAddress_PortValue{
+				PortValue: uint32(2384),
+			},
+		},
+	}}
+	secret := CreateUpstreamClientCertSecret([]byte("client-cert"), []byte("client-key"))
+	assert.True(t, strings.HasPrefix(secret.GetName(), upstreamClientCertSecretNamePrefix), "Secret name mismatch.")
+	assert.Equal(t, secret.GetName(), CreateUpstreamClientCertSecret([]byte("client-cert"),
+		[]byte("client-key")).GetName(), "Secret name should be the same for the same certificate and key.")
+	assert.NotEqual(t, secret.GetName(), CreateUpstreamClientCertSecret([]byte("client-cert"),
+		[]byte("another-key")).GetName(), "Secret name should be different for a different key.")
+	assert.Equal(t, []byte("client-key"), secret.GetTlsCertificate().GetPrivateKey().GetInlineBytes(),
+		"Private key mismatch.")
+
+	upstreamTLSContext := createUpstreamTLSContext(nil, secret.GetName(), address, false)
+	assert.Empty(t, upstreamTLSContext.CommonTlsContext.TlsCertificates,
+		"Client certificate should not be embedded in the TLS context.")
+	sdsSecretConfigs := upstreamTLSContext.CommonTlsContext.GetTlsCertificateSdsSecretConfigs()
+	assert.Equal(t, 1, len(sdsSecretConfigs), "SDS secret config should be available.")
+	assert.Equal(t, secret.GetName(), sdsSecretConfigs[0].GetName(), "SDS secret name mismatch.")
+	assert.NotNil(t, sdsSecretConfigs[0].GetSdsConfig().GetAds(), "Secret should be delivered via ADS.")
+
+	cluster := &clusterv3.Cluster{Name: "test-cluster"}
+	marshalledTLSContext, _ := anypb.New(upstreamTLSContext)
+	cluster.TransportSocketMatches = []*clusterv3.Cluster_TransportSocketMatch{{
+		Name: "ts0",
+		TransportSocket: &corev3.TransportSocket{
+			Name:       transportSocketName,
+			ConfigType: &corev3.TransportSocket_TypedConfig{TypedConfig: marshalledTLSContext},
+		},
+	}}
+	assert.Equal(t, []string{secret.GetName()}, GetClusterSecretNames(cluster), "Cluster secret names mismatch.")
+
+	assert.Nil(t, CreateDefaultUpstreamClientCertSecret(),
+		"Default client certificate secret should not be available unless configured.")
+}
+
+func TestProcessEndpointsWithUpstreamProxy(t *testing.T) {
+	enabled := true
+	proxy := &model.ProxyConfig{Enabled: &enabled, Host: "proxy.example.com", Port: 3128, Username: "admin",
+		Password: "admin", NoProxy: []string{"localhost"}}
+	endpointCluster := &model.EndpointCluster{
+		Endpoints: []model.Endpoint{
+			{Host: "api.example.com", Port: 443, URLType: "https", RawURL: "https://api.example.com"},
+			{Host: "localhost", Port: 8080, URLType: "http", RawURL: "http://localhost:8080"},
+		},
+		EndpointType: "loadbalance",
+	}
+	cluster, _, err := processEndpoints("proxiedCluster", endpointCluster, nil, nil, proxy, 20, "")
+	assert.Nil(t, err, "Error while processing the proxied endpoints.")
+
+	lbEndpoints := cluster.GetLoadAssignment().GetEndpoints()
+	proxiedEndpoint := lbEndpoints[0].GetLbEndpoints()[0]
+	assert.Equal(t, upstreamProxyTunnelHost, proxiedEndpoint.GetEndpoint().GetAddress().GetSocketAddress().GetAddress(),
+		"Proxied endpoint should be pointed to the tunnel listener.")
+	assert.Equal(t, "api.example.com", proxiedEndpoint.GetEndpoint().GetHostname(),
+		"Endpoint host should be kept as the hostname.")
+	assert.Equal(t, "localhost", lbEndpoints[1].GetLbEndpoints()[0].GetEndpoint().GetAddress().GetSocketAddress().GetAddress(),
+		"Host in the no proxy list should be reached directly.")
+
+	upstreamTLSContext := &tlsv3.UpstreamTlsContext{}
+	err = ptypes.UnmarshalAny(cluster.GetTransportSocketMatches()[0].GetTransportSocket().GetTypedConfig(), upstreamTLSContext)
+	assert.Nil(t, err, "Error while parsing the upstream TLS context.")
+	assert.Equal(t, "api.example.com", upstreamTLSContext.GetSni(), "SNI should be the endpoint host.")
+
+	proxyClusters, tunnelListeners := GetUpstreamProxyTunnelResources([]*clusterv3.Cluster{cluster, cluster})
+	assert.Equal(t, 1, len(proxyClusters), "Proxy cluster count mismatch.")
+	assert.Equal(t, "proxy.example.com", proxyClusters[0].GetLoadAssignment().GetEndpoints()[0].GetLbEndpoints()[0].
+		GetEndpoint().GetAddress().GetSocketAddress().GetAddress(), "Proxy cluster address mismatch.")
+	assert.Equal(t, 1, len(tunnelListeners), "Tunnel listener count mismatch.")
+	assert.Equal(t, proxiedEndpoint.GetEndpoint().GetAddress().GetSocketAddress().GetPortValue(),
+		tunnelListeners[0].GetAddress().GetSocketAddress().GetPortValue(), "Tunnel listener port mismatch.")
+
+	tcpProxy := &tcpproxyv3.TcpProxy{}
+	err = ptypes.UnmarshalAny(tunnelListeners[0].GetFilterChains()[0].GetFilters()[0].GetTypedConfig(), tcpProxy)
+	assert.Nil(t, err, "Error while parsing the tcp proxy of the tunnel listener.")
+	assert.Equal(t, proxyClusters[0].GetName(), tcpProxy.GetCluster(), "Tunnel should be sent to the proxy cluster.")
+	assert.Equal(t, "api.example.com:443", tcpProxy.GetTunnelingConfig().GetHostname(), "Tunneling hostname mismatch.")
+	assert.Equal(t, "Basic YWRtaW46YWRtaW4=", tcpProxy.GetTunnelingConfig().GetHeadersToAdd()[0].GetHeader().GetValue(),
+		"Proxy authorization header mismatch.")
+
+	sameTunnelCluster, _, _ := processEndpoints("anotherCluster", endpointCluster, nil, nil, proxy, 20, "")
+	assert.Equal(t, proxiedEndpoint.GetEndpoint().GetAddress().GetSocketAddress().GetPortValue(),
+		sameTunnelCluster.GetLoadAssignment().GetEndpoints()[0].GetLbEndpoints()[0].GetEndpoint().GetAddress().
+			GetSocketAddress().GetPortValue(), "Tunnel should be reused for the same endpoint and proxy.")
+}
+
+func TestUpstreamProxyTunnelPortAllocation(t *testing.T) {
+	enabled := true
+	proxy := &model.ProxyConfig{Enabled: &enabled, Host: "proxy.example.com", Port: 3128}
+	defer RestoreUpstreamProxyTunnels(TakeUpstreamProxyTunnelsSnapshot())
+	getTunnelPorts := func(hosts ...string) map[string]uint32 {
+		RestoreUpstreamProxyTunnels(UpstreamProxyTunnelsSnapshot{})
+		ports := make(map[string]uint32)
+		for _, host := range hosts {
+			tunnel, err := getUpstreamProxyTunnel(proxy, host, 443)
+			assert.Nil(t, err, "Error while creating the tunnel.")
+			ports[host] = tunnel.port
+		}
+		return ports
+	}
+
+	ports := getTunnelPorts("a.example.com", "b.example.com", "c.example.com")
+	assert.Equal(t, ports, getTunnelPorts("c.example.com", "b.example.com", "a.example.com"),
+		"Tunnel ports should not depend on the order the tunnels are created.")
+	for host, port := range ports {
+		assert.True(t, port >= 15000 && port <= 15999, "Tunnel port %v of %v is out of the port range.", port, host)
+	}
+
+	// The port of a tunnel is released when the tunnel is not referred by the clusters anymore.
+	cluster, _, err := processEndpoints("proxiedCluster", &model.EndpointCluster{
+		Endpoints:    []model.Endpoint{{Host: "a.example.com", Port: 443, URLType: "https", RawURL: "https://a.example.com"}},
+		EndpointType: "loadbalance",
+	}, nil, nil, proxy, 20, "")
+	assert.Nil(t, err, "Error while processing the proxied endpoints.")
+	PruneUpstreamProxyTunnels([]*clusterv3.Cluster{cluster})
+	proxyClusters, tunnelListeners := GetUpstreamProxyTunnelResources([]*clusterv3.Cluster{cluster})
+	assert.Equal(t, 1, len(proxyClusters), "Proxy cluster of the referred tunnel should be kept.")
+	assert.Equal(t, 1, len(tunnelListeners), "Referred tunnel should be kept.")
+	assert.Equal(t, 1, len(upstreamProxyTunnels), "Tunnels which are not referred should be removed.")
+	PruneUpstreamProxyTunnels(nil)
+	_, tunnelListeners = GetUpstreamProxyTunnelResources([]*clusterv3.Cluster{cluster})
+	assert.Equal(t, 0, len(tunnelListeners), "Tunnels should be removed when they are not referred.")
+
+	for port := uint32(15000); port <= 15002; port++ {
+		upstreamProxyTunnels[fmt.Sprint(port)] = &upstreamProxyTunnel{port: port}
+	}
+	_, err = allocateUpstreamProxyTunnelPort(1, 15000, 15002)
+	assert.Error(t, err, "A port should not be allocated when all the ports of the range are allocated.")
+	delete(upstreamProxyTunnels, "15001")
+	port, err := allocateUpstreamProxyTunnelPort(0, 15000, 15002)
+	assert.Nil(t, err, "Error while allocating the released port.")
+	assert.Equal(t, uint32(15001), port, "The next released port should be allocated on a collision.")
+}
+
+func TestProcessEndpointsWithConnectionSettings(t *testing.T) {
+	endpointCluster := &model.EndpointCluster{
+		Endpoints: []model.Endpoint{
+			{Host: "abc.com", Port: 443, URLType: "https", RawURL: "https://abc.com"},
+		},
+		EndpointType: "loadbalance",
+		Config: &model.EndpointConfig{
+			MaxConnectionDurationInSeconds:  600,
+			ConnectionIdleTimeoutInSeconds:  30,
+			MaxRequestsPerConnection:        100,
+			PerConnectionBufferLimitInBytes: 65536,
+			TCPKeepalive:                    &model.TCPKeepalive{Probes: 3, TimeInSeconds: 60},
+			PreserveHeaderCase:              true,
+			UpstreamProtocol:                "auto",
+		},
+	}
+	httpProtocolOptionsKey := "envoy.extensions.upstreams.http.v3.HttpProtocolOptions"
+	cluster, _, err := processEndpoints("tunedCluster", endpointCluster, nil, nil, nil, 20, "")
+	assert.Nil(t, err, "Error while processing the endpoints.")
+	assert.Equal(t, uint32(65536), cluster.GetPerConnectionBufferLimitBytes().GetValue(), "Buffer limit mismatch.")
+	tcpKeepalive := cluster.GetUpstreamConnectionOptions().GetTcpKeepalive()
+	assert.Equal(t, uint32(3), tcpKeepalive.GetKeepaliveProbes().GetValue(), "Keepalive probes mismatch.")
+	assert.Equal(t, uint32(60), tcpKeepalive.GetKeepaliveTime().GetValue(), "Keepalive time mismatch.")
+	assert.Nil(t, tcpKeepalive.GetKeepaliveInterval(), "Keepalive interval should not be set.")
+
+	httpProtocolOptions := &upstreams_http_v3.HttpProtocolOptions{}
+	err = proto.Unmarshal(cluster.GetTypedExtensionProtocolOptions()[httpProtocolOptionsKey].GetValue(), httpProtocolOptions)
+	assert.Nil(t, err, "Error while parsing the http protocol options.")
+	commonOptions := httpProtocolOptions.GetCommonHttpProtocolOptions()
+	assert.Equal(t, int64(600), commonOptions.GetMaxConnectionDuration().GetSeconds(), "Max connection duration mismatch.")
+	assert.Equal(t, int64(30), commonOptions.GetIdleTimeout().GetSeconds(), "Idle timeout mismatch.")
+	assert.Equal(t, uint32(100), commonOptions.GetMaxRequestsPerConnection().GetValue(), "Max requests mismatch.")
+	autoConfig := httpProtocolOptions.GetAutoConfig()
+	assert.NotNil(t, autoConfig, "Upstream protocol should be selected automatically.")
+	assert.NotNil(t, autoConfig.GetHttp2ProtocolOptions(), "HTTP/2 protocol options should be available.")
+	assert.Equal(t, preserveCaseHeaderFormatterName, autoConfig.GetHttpProtocolOptions().GetHeaderKeyFormat().
+		GetStatefulFormatter().GetName(), "Header case should be preserved.")
+
+	upstreamTLSContext := &tlsv3.UpstreamTlsContext{}
+	err = ptypes.UnmarshalAny(cluster.GetTransportSocketMatches()[0].GetTransportSocket().GetTypedConfig(), upstreamTLSContext)
+	assert.Nil(t, err, "Error while parsing the upstream TLS context.")
+	assert.Equal(t, []string{"h2", "http/1.1"}, upstreamTLSContext.GetCommonTlsContext().GetAlpnProtocols(),
+		"ALPN protocols mismatch.")
+
+	endpointCluster.HTTP2BackendEnabled = true
+	endpointCluster.Config = &model.EndpointConfig{UpstreamProtocol: "h1"}
+	cluster, _, err = processEndpoints("http1Cluster", endpointCluster, nil, nil, nil, 20, "")
+	assert.Nil(t, err, "Error while processing the endpoints.")
+	httpProtocolOptions = &upstreams_http_v3.HttpProtocolOptions{}
+	err = proto.Unmarshal(cluster.GetTypedExtensionProtocolOptions()[httpProtocolOptionsKey].GetValue(), httpProtocolOptions)
+	assert.Nil(t, err, "Error while parsing the http protocol options.")
+	assert.NotNil(t, httpProtocolOptions.GetExplicitHttpConfig().GetHttpProtocolOptions(),
+		"Upstream protocol should override the HTTP/2 backend extension.")
+	assert.Nil(t, httpProtocolOptions.GetCommonHttpProtocolOptions(), "Common protocol options should not be set.")
+	assert.Nil(t, cluster.GetUpstreamConnectionOptions(), "TCP keepalive should not be set.")
+}
+
 func TestGetCorsPolicy(t *testing.T) {
 
 	corsConfigModel1 := &model.CorsConfig{
@@ -651,6 +1131,29 @@ func TestGetCorsPolicy(t *testing.T) {
 		"Cors Allowed Origin Header mismatch")
 	assert.Empty(t, corsPolicy3.GetAllowCredentials(), "Allow Credential property should not be assigned.")
 
+	// Test the configuration with wildcard origins, origin regexes and max age.
+	corsConfigModel4 := &model.CorsConfig{
+		Enabled:                         true,
+		AccessControlAllowOrigins:       []string{"*", "https://*.test.com", "http://localhost:*"},
+		AccessControlAllowOriginRegexes: []string{"https://(foo|bar)\\.test\\.com"},
+		AccessControlMaxAge:             3600,
+	}
+	corsPolicy4 := getCorsPolicy(corsConfigModel4)
+	originRegexes := []string{}
+	for _, originMatcher := range corsPolicy4.GetAllowOriginStringMatch() {
+		originRegexes = append(originRegexes, originMatcher.GetSafeRegex().GetRegex())
+	}
+	assert.Equal(t, []string{"\\*", "https://[a-zA-Z0-9.-]+\\.test\\.com", "http://localhost:[a-zA-Z0-9.-]+",
+		"https://(foo|bar)\\.test\\.com"}, originRegexes, "Cors Allowed Origin regexes mismatch")
+	for _, originRegex := range originRegexes[1:] {
+		assert.Nil(t, regexp.MustCompile("^"+originRegex+"$").FindStringIndex("http://test.com"),
+			"Origin regex %v should not match other origins", originRegex)
+	}
+	assert.True(t, regexp.MustCompile("^"+originRegexes[1]+"$").MatchString("https://foo.bar.test.com"),
+		"Wildcard origin should match the subdomains")
+	assert.Equal(t, "3600", corsPolicy4.GetMaxAge(), "Cors max age mismatch")
+	assert.Empty(t, corsPolicy3.GetMaxAge(), "Cors max age should not be assigned.")
+
 	// Route without CORS configuration
 	routeWithoutCors := createRoute(generateRouteCreateParamsForUnitTests("test", "HTTP", "localhost", "/test", "1.0.0", "/test",
 		"/testPath", []string{"GET"}, "test-cluster", "", nil, false))
@@ -680,3 +1183,83 @@ func generateRouteCreateParamsForUnitTests(title string, apiType string, vhost s
 		isDefaultVersion:  isDefaultVersion,
 	}
 }
+
+func TestGetRouterJWTProviders(t *testing.T) {
+	conf, _ := config.ReadConfigs()
+	tokenServices := conf.Enforcer.Security.TokenService
+	defer func() {
+		conf.Enforcer.Security.TokenService = tokenServices
+	}()
+	certTokenService := tokenServices[0]
+	certTokenService.Name = "Cert Issuer"
+	certTokenService.Issuer = "https://cert.issuer.com"
+	certTokenService.JwksURL = ""
+	certTokenService.ValidateSubscription = false
+	certTokenService.RouterValidation.Enabled = true
+	certTokenService.RouterValidation.CertificateFilePath = config.GetMgwHome() +
+		"/../adapter/test-resources/envoycodegen/certs/testcrt.crt"
+	certTokenService.RouterValidation.Audiences = []string{"petstore"}
+	jwksTokenService := certTokenService
+	jwksTokenService.Name = "JWKS Issuer"
+	jwksTokenService.Issuer = "https://jwks.issuer.com"
+	jwksTokenService.JwksURL = "https://jwks.issuer.com:8443/oauth2/jwks"
+	jwksTokenService.RouterValidation.Enabled = false
+	jwksTokenService.RouterValidation.CertificateFilePath = ""
+	subscriptionTokenService := jwksTokenService
+	subscriptionTokenService.Name = "Subscription Issuer"
+	subscriptionTokenService.ValidateSubscription = true
+	invalidCertTokenService := certTokenService
+	invalidCertTokenService.Name = "Invalid Cert Issuer"
+	invalidCertTokenService.RouterValidation.CertificateFilePath = "/non/existing/cert.pem"
+	conf.Enforcer.Security.TokenService = append(append(tokenServices[:0:0], tokenServices...), certTokenService,
+		jwksTokenService, subscriptionTokenService, invalidCertTokenService)
+
+	providers, defaultProviders := getRouterJWTProviders(conf)
+	assert.Equal(t, 2, len(providers), "Only the token services which could be validated at the router should "+
+		"be providers.")
+	assert.Equal(t, []string{"Cert Issuer"}, defaultProviders, "Default providers mismatch.")
+
+	certProvider := providers["Cert Issuer"]
+	assert.NotNil(t, certProvider, "Certificate based provider should be available.")
+	assert.Equal(t, "https://cert.issuer.com", certProvider.GetIssuer(), "Issuer mismatch.")
+	assert.Equal(t, []string{"petstore"}, certProvider.GetAudiences(), "Audiences mismatch.")
+	assert.Equal(t, jwtPayloadMetadataKey, certProvider.GetPayloadInMetadata(), "Payload metadata key mismatch.")
+	localJwks := certProvider.GetLocalJwks().GetInlineString()
+	assert.Contains(t, localJwks, `"kty":"RSA"`, "Local JWKS should contain the public key of the certificate.")
+
+	jwksProvider := providers["JWKS Issuer"]
+	assert.NotNil(t, jwksProvider, "JWKS based provider should be available.")
+	assert.Equal(t, "https://jwks.issuer.com:8443/oauth2/jwks", jwksProvider.GetRemoteJwks().GetHttpUri().GetUri(),
+		"JWKS URL mismatch.")
+	assert.Equal(t, "wso2_cc_jwks_JWKS_Issuer", jwksProvider.GetRemoteJwks().GetHttpUri().GetCluster(),
+		"JWKS cluster mismatch.")
+
+	jwksClusters, _ := CreateJWKSClusters(conf)
+	assert.Equal(t, 1, len(jwksClusters), "Only the JWKS URL based providers should have clusters.")
+	assert.Equal(t, "wso2_cc_jwks_JWKS_Issuer", jwksClusters[0].GetName(), "JWKS cluster name mismatch.")
+	assert.Equal(t, uint32(8443), jwksClusters[0].GetLoadAssignment().GetEndpoints()[0].GetLbEndpoints()[0].
+		GetEndpoint().GetAddress().GetSocketAddress().GetPortValue(), "JWKS cluster port mismatch.")
+
+	jwtAuthnFilter := getJwtAuthnFilter()
+	assert.NotNil(t, jwtAuthnFilter, "jwt_authn filter should be available.")
+	var jwtAuthn jwtauthnv3.JwtAuthentication
+	err := ptypes.UnmarshalAny(jwtAuthnFilter.GetTypedConfig(), &jwtAuthn)
+	assert.Nil(t, err, "Error while parsing jwt_authn filter config.")
+	assert.Nil(t, jwtAuthn.Validate(), "jwt_authn filter config validation failed.")
+	assert.Equal(t, "Cert Issuer", jwtAuthn.GetRequirementMap()[routerJWTDefaultRequirement].GetProviderName(),
+		"Default requirement mismatch.")
+	assert.Equal(t, 2, len(jwtAuthn.GetRequirementMap()[routerJWTAllRequirement].GetRequiresAny().GetRequirements()),
+		"All providers requirement mismatch.")
+	httpFilters := getHTTPFilters()
+	assert.Equal(t, jwtAuthnFilterName, httpFilters[1].GetName(), "jwt_authn filter should be after the CORS filter.")
+	assert.Equal(t, wellknown.HTTPRoleBasedAccessControl, httpFilters[2].GetName(),
+		"jwt_authn filter should be before the RBAC filter.")
+
+	assert.Equal(t, routerJWTDefaultRequirement, getRouterJWTRequirementName(conf, nil),
+		"Default requirement should be used if the API level extension is not provided.")
+	enabled, disabled := true, false
+	assert.Equal(t, routerJWTAllRequirement, getRouterJWTRequirementName(conf, &enabled),
+		"All providers should be used if the router validation is enabled for the API.")
+	assert.Empty(t, getRouterJWTRequirementName(conf, &disabled),
+		"Tokens should not be validated at the router if the router validation is disabled for the API.")
+}
@@ -23,8 +23,11 @@ import (
 	"time"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
 	ext_authv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	luav3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	rbacv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	routerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
 	wasm_filter_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/wasm/v3"
 	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
@@ -52,12 +55,23 @@ func getHTTPFilters() []*hcmv3.HttpFilter {
 		ConfigType: &hcmv3.HttpFilter_TypedConfig{},
 	}
 
+	rbac := getRBACFilter()
+	buffer := getBufferFilter()
+	fault := getFaultFilter()
+
 	httpFilters := []*hcmv3.HttpFilter{
 		cors,
+		rbac,
+		buffer,
 		extAauth,
+		fault,
 		lua,
 		router,
 	}
+	// The tokens are validated before the RBAC filter, which denies the tokens not having the required scopes.
+	if jwtAuthn := getJwtAuthnFilter(); jwtAuthn != nil {
+		httpFilters = append([]*hcmv3.HttpFilter{cors, jwtAuthn}, httpFilters[1:]...)
+	}
 	return httpFilters
 }
 
@@ -90,11 +104,13 @@ func getUpgradeFilters() []*hcmv3.HttpFilter {
 		Name:       wellknown.CORS,
 		ConfigType: &hcmv3.HttpFilter_TypedConfig{},
 	}
+	rbac := getRBACFilter()
 	extAauth := getExtAuthzHTTPFilter()
 	mgwWebSocketWASM := getMgwWebSocketWASMFilter()
 	router := getRouterHTTPFilter()
 	upgradeFilters := []*hcmv3.HttpFilter{
 		cors,
+		rbac,
 		extAauth,
 		mgwWebSocketWASM,
 		router,
@@ -102,6 +118,60 @@ func getUpgradeFilters() []*hcmv3.HttpFilter {
 	return upgradeFilters
 }
 
+// getRBACFilter gets RBAC http filter. The filter does not contain any rules at the listener level,
+// the client ip filters are applied using the per route configurations.
+func getRBACFilter() *hcmv3.HttpFilter {
+	rbacConfig := &rbacv3.RBAC{}
+	ext, err := ptypes.MarshalAny(rbacConfig)
+	if err != nil {
+		logger.LoggerOasparser.Error(err)
+	}
+	rbacFilter := hcmv3.HttpFilter{
+		Name: wellknown.HTTPRoleBasedAccessControl,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{
+			TypedConfig: ext,
+		},
+	}
+	return &rbacFilter
+}
+
+// getFaultFilter gets fault http filter. The filter does not inject any faults at the listener level,
+// the faults are injected using the per route configurations.
+func getFaultFilter() *hcmv3.HttpFilter {
+	faultConfig := &faultv3.HTTPFault{}
+	ext, err := ptypes.MarshalAny(faultConfig)
+	if err != nil {
+		logger.LoggerOasparser.Error(err)
+	}
+	faultFilter := hcmv3.HttpFilter{
+		Name: wellknown.Fault,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{
+			TypedConfig: ext,
+		},
+	}
+	return &faultFilter
+}
+
+// getBufferFilter gets buffer http filter. The filter is disabled using the per route configurations for the
+// routes without a request body size limit, hence the listener level limit is applied only to the requests not
+// matching any route. A small limit is used for them, so that their bodies are not buffered in memory.
+func getBufferFilter() *hcmv3.HttpFilter {
+	bufferConfig := &bufferv3.Buffer{
+		MaxRequestBytes: &wrappers.UInt32Value{Value: unmatchedRequestMaxBytes},
+	}
+	ext, err := ptypes.MarshalAny(bufferConfig)
+	if err != nil {
+		logger.LoggerOasparser.Error(err)
+	}
+	bufferFilter := hcmv3.HttpFilter{
+		Name: wellknown.Buffer,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{
+			TypedConfig: ext,
+		},
+	}
+	return &bufferFilter
+}
+
 // getExtAuthzHTTPFilter gets ExtAuthz http filter.
 func getExtAuthzHTTPFilter() *hcmv3.HttpFilter {
 	conf, _ := config.ReadConfigs()
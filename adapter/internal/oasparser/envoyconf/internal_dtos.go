@@ -43,6 +43,46 @@ type routeCreateParams struct {
 	rewritePath                  string
 	rewriteMethod                bool
 	passRequestPayloadToEnforcer bool
+	maxRequestBodySize           uint32 // 0 if the request body size is not limited
 	isDefaultVersion             bool
 	isSandbox                    bool
+	ipFilters                    map[string]*model.IPFilterConfig // http method -> client ip filter
+	routingClusterName           string                           // cluster of the content based routing rule
+	routingConditions            []model.RoutingCondition
+	routingClaims                []string // JWT claims matched by the content based routing rules of the resource
+	mirrorPolicy                 *mirrorPolicy
+	faultInjection               *model.FaultInjectionConfig
+	staticMutations              *model.StaticMutations
+	timeoutConfig                *model.TimeoutConfig
+	retryPolicy                  *model.RetryPolicy
+	routerJWTRequirement         *routerJWTRequirement
+}
+
+// mirrorPolicy holds the cluster and the percentage of the requests mirrored from a route
+type mirrorPolicy struct {
+	clusterName string
+	percentage  float64
+}
+
+// routerJWTRequirement holds the jwt_authn requirement and the scopes of the operations of which the tokens are
+// validated at the router, without calling the enforcer
+type routerJWTRequirement struct {
+	name   string
+	scopes []string // the token should have at least one of the scopes, if provided
+}
+
+// methodGroup holds the http methods of a resource which share the same mirror policy, fault injection,
+// static header and query mutations, CORS policy, timeouts, retry policy, router JWT validation requirement and
+// request body settings
+type methodGroup struct {
+	methods                      []string
+	mirrorPolicy                 *mirrorPolicy
+	faultInjection               *model.FaultInjectionConfig
+	staticMutations              *model.StaticMutations
+	corsPolicy                   *model.CorsConfig
+	timeoutConfig                *model.TimeoutConfig
+	retryPolicy                  *model.RetryPolicy
+	routerJWTRequirement         *routerJWTRequirement
+	maxRequestBodySize           uint32 // operation level size, which overrides the API level size
+	passRequestPayloadToEnforcer *bool  // operation level setting, which overrides the API level setting
 }
@@ -0,0 +1,509 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package envoyconf
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	rbacconfigv3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	jwtauthnv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_type_matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/wso2/product-microgateway/adapter/config"
+	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/constants"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
+)
+
+// getRouterJWTProviders returns the jwt_authn providers of the token services of which the tokens could be
+// validated at the router, against the token service names, along with the names of the providers enabled for
+// all the APIs. The tokens of a token service could be validated at the router if the subscription validation is
+// disabled, and the JWKS URL or a certificate readable by the adapter is provided.
+func getRouterJWTProviders(conf *config.Config) (map[string]*jwtauthnv3.JwtProvider, []string) {
+	providers := make(map[string]*jwtauthnv3.JwtProvider)
+	var defaultProviders []string
+	for _, tokenService := range conf.Enforcer.Security.TokenService {
+		routerValidation := tokenService.RouterValidation
+		if tokenService.ValidateSubscription || (tokenService.JwksURL == "" && routerValidation.CertificateFilePath == "") {
+			continue
+		}
+		provider := &jwtauthnv3.JwtProvider{
+			Issuer:    tokenService.Issuer,
+			Audiences: routerValidation.Audiences,
+			Forward:   conf.Enforcer.Security.AuthHeader.EnableOutboundAuthHeader,
+			FromHeaders: []*jwtauthnv3.JwtHeader{{
+				Name:        conf.Enforcer.Security.AuthHeader.AuthorizationHeader,
+				ValuePrefix: bearerTokenPrefix,
+			}},
+			PayloadInMetadata: jwtPayloadMetadataKey,
+		}
+		if tokenService.JwksURL != "" {
+			if _, err := getJWKSEndpoint(tokenService.JwksURL); err != nil {
+				logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+					Message: fmt.Sprintf("The tokens of the token service %s are not validated at the router "+
+						"due to the invalid JWKS URL %s. %v", tokenService.Name, tokenService.JwksURL, err.Error()),
+					Severity:  logging.MINOR,
+					ErrorCode: 2228,
+				})
+				continue
+			}
+			provider.JwksSourceSpecifier = &jwtauthnv3.JwtProvider_RemoteJwks{
+				RemoteJwks: &jwtauthnv3.RemoteJwks{
+					HttpUri: &corev3.HttpUri{
+						Uri: tokenService.JwksURL,
+						HttpUpstreamType: &corev3.HttpUri_Cluster{
+							Cluster: getJWKSClusterName(tokenService.Name),
+						},
+						Timeout: durationpb.New(conf.Envoy.ClusterTimeoutInSeconds * time.Second),
+					},
+				},
+			}
+		} else {
+			jwks, err := createJWKSFromCertificate(routerValidation.CertificateFilePath)
+			if err != nil {
+				logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+					Message: fmt.Sprintf("The tokens of the token service %s are not validated at the router "+
+						"due to the invalid certificate %s. %v", tokenService.Name,
+						routerValidation.CertificateFilePath, err.Error()),
+					Severity:  logging.MINOR,
+					ErrorCode: 2233,
+				})
+				continue
+			}
+			provider.JwksSourceSpecifier = &jwtauthnv3.JwtProvider_LocalJwks{
+				LocalJwks: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: jwks,
+					},
+				},
+			}
+		}
+		providers[tokenService.Name] = provider
+		if routerValidation.Enabled {
+			defaultProviders = append(defaultProviders, tokenService.Name)
+		}
+	}
+	return providers, defaultProviders
+}
+
+// getJwtAuthnFilter gets the jwt_authn http filter, which validates the tokens at the router. The filter does not
+// validate any tokens at the listener level, the tokens are validated using the per route configurations.
+// nil is returned if the tokens of none of the token services could be validated at the router.
+func getJwtAuthnFilter() *hcmv3.HttpFilter {
+	conf, _ := config.ReadConfigs()
+	providers, defaultProviders := getRouterJWTProviders(conf)
+	if len(providers) == 0 {
+		return nil
+	}
+	allProviders := make([]string, 0, len(providers))
+	for providerName := range providers {
+		allProviders = append(allProviders, providerName)
+	}
+	sort.Strings(allProviders)
+	requirementMap := map[string]*jwtauthnv3.JwtRequirement{
+		routerJWTAllRequirement: getJwtRequirement(allProviders),
+	}
+	if len(defaultProviders) > 0 {
+		requirementMap[routerJWTDefaultRequirement] = getJwtRequirement(defaultProviders)
+	}
+	jwtAuthn := &jwtauthnv3.JwtAuthentication{
+		Providers:      providers,
+		RequirementMap: requirementMap,
+		// The preflight requests are served by the CORS filter.
+		BypassCorsPreflight: true,
+	}
+	// The filter is marshalled deterministically to avoid updating the listeners when the config is not changed.
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	if err := b.Marshal(jwtAuthn); err != nil {
+		logger.LoggerOasparser.Error("Error marshaling jwt_authn filter configs. ", err)
+	}
+	return &hcmv3.HttpFilter{
+		Name: jwtAuthnFilterName,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{
+			TypedConfig: &any.Any{
+				TypeUrl: jwtAuthnName,
+				Value:   b.Bytes(),
+			},
+		},
+	}
+}
+
+// getJwtRequirement returns the requirement which accepts a token of any of the given providers.
+func getJwtRequirement(providerNames []string) *jwtauthnv3.JwtRequirement {
+	if len(providerNames) == 1 {
+		return &jwtauthnv3.JwtRequirement{
+			RequiresType: &jwtauthnv3.JwtRequirement_ProviderName{ProviderName: providerNames[0]},
+		}
+	}
+	requirements := make([]*jwtauthnv3.JwtRequirement, 0, len(providerNames))
+	for _, providerName := range providerNames {
+		requirements = append(requirements, getJwtRequirement([]string{providerName}))
+	}
+	return &jwtauthnv3.JwtRequirement{
+		RequiresType: &jwtauthnv3.JwtRequirement_RequiresAny{
+			RequiresAny: &jwtauthnv3.JwtRequirementOrList{Requirements: requirements},
+		},
+	}
+}
+
+// getJwtAuthnPerRouteConfig generates the jwt_authn filter per route configuration, which validates the tokens
+// using the given requirement.
+func getJwtAuthnPerRouteConfig(requirementName string) *any.Any {
+	jwtAuthnPerRoute := &jwtauthnv3.PerRouteConfig{
+		RequirementSpecifier: &jwtauthnv3.PerRouteConfig_RequirementName{
+			RequirementName: requirementName,
+		},
+	}
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	_ = b.Marshal(jwtAuthnPerRoute)
+	return &any.Any{
+		TypeUrl: jwtAuthnPerRouteName,
+		Value:   b.Bytes(),
+	}
+}
+
+// getRouterJWTRequirementName returns the name of the jwt_authn requirement which validates the tokens of the API
+// at the router, or an empty string if the tokens of the API are not validated at the router. The tokens of all
+// the eligible token services are validated if the router validation is enabled via the API level extension, and
+// the tokens of the token services which enabled the router validation are validated if the extension is not
+// provided.
+func getRouterJWTRequirementName(conf *config.Config, routerJWTValidation *bool) string {
+	providers, defaultProviders := getRouterJWTProviders(conf)
+	if routerJWTValidation != nil {
+		if *routerJWTValidation && len(providers) > 0 {
+			return routerJWTAllRequirement
+		}
+		return ""
+	}
+	if len(defaultProviders) > 0 {
+		return routerJWTDefaultRequirement
+	}
+	return ""
+}
+
+// getRouterJWTRequirements returns the router JWT validation requirements of the operations of the resource,
+// against the http methods. The tokens of an operation are validated at the router only if the operation is
+// secured only by OAuth2, and the enforcer is not required to validate the subscriptions, apply the throttling
+// policies, generate the backend JWTs, add the backend credentials, publish the analytics, serve the mock
+// responses, validate the client certificates, apply the operation policies, or to select the sandbox or the
+// content based routing clusters.
+func getRouterJWTRequirements(swagger *model.MgwSwagger, resource *model.Resource) map[string]*routerJWTRequirement {
+	conf, _ := config.ReadConfigs()
+	requirementName := getRouterJWTRequirementName(conf, swagger.GetXWso2RouterJWTValidation())
+	if requirementName == "" {
+		return nil
+	}
+	if swagger.GetAPIType() != constants.HTTP ||
+		swagger.EndpointImplementationType == constants.MockedOASEndpointType ||
+		conf.Enforcer.JwtGenerator.Enabled || conf.Analytics.Enabled ||
+		(swagger.GetXWSO2MutualSSL() != "" && swagger.GetXWSO2MutualSSL() != constants.NotDefined) ||
+		(swagger.GetXWSO2AuthHeader() != "" &&
+			!strings.EqualFold(swagger.GetXWSO2AuthHeader(), conf.Enforcer.Security.AuthHeader.AuthorizationHeader)) ||
+		hasEndpoints(swagger.GetSandEndpoints()) || hasEndpoints(resource.GetSandEndpoints()) ||
+		swagger.GetXWso2Routing() != nil || resource.GetRoutingConfig() != nil ||
+		// The endpoint level retries are applied by the enforcer via headers.
+		hasRetryConfig(swagger.GetProdEndpoints(), resource.GetProdEndpoints()) ||
+		// The backend credentials of the endpoint security are added by the enforcer.
+		hasEndpointSecurity(swagger.GetProdEndpoints(), resource.GetProdEndpoints()) ||
+		!isUnlimitedThrottlingTier(swagger.GetXWso2ThrottlingTier()) ||
+		swagger.GetDisableSecurity() {
+		return nil
+	}
+
+	requirements := make(map[string]*routerJWTRequirement)
+	for _, operation := range resource.GetMethod() {
+		if operation.GetDisableSecurity() || !isUnlimitedThrottlingTier(operation.GetTier()) ||
+			hasEnforcerPolicies(operation) {
+			continue
+		}
+		if scopes, isOAuth2 := getRouterJWTScopes(swagger, operation); isOAuth2 {
+			requirements[operation.GetMethod()] = &routerJWTRequirement{name: requirementName, scopes: scopes}
+		}
+	}
+	return requirements
+}
+
+// getRouterJWTScopes returns the scopes of the operation, of which the token should have at least one, and
+// whether the operation is secured only by OAuth2. The operation is secured by the default OAuth2 security
+// scheme if no security is defined.
+func getRouterJWTScopes(swagger *model.MgwSwagger, operation *model.Operation) ([]string, bool) {
+	security := operation.GetSecurity()
+	if len(security) == 0 {
+		security = swagger.GetSecurity()
+	}
+	var scopes []string
+	hasUnscopedRequirement := len(security) == 0
+	for _, requirement := range security {
+		for schemeName, requirementScopes := range requirement {
+			if !isOAuth2SecurityScheme(swagger, schemeName) {
+				return nil, false
+			}
+			if len(requirementScopes) == 0 {
+				hasUnscopedRequirement = true
+			}
+			for _, scope := range requirementScopes {
+				if !model.ArrayContains(scopes, scope) {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	// Any token is accepted if one of the security requirements does not require scopes.
+	if hasUnscopedRequirement {
+		return nil, true
+	}
+	sort.Strings(scopes)
+	return scopes, true
+}
+
+// isOAuth2SecurityScheme returns true if the security scheme of the given name is an OAuth2 security scheme.
+func isOAuth2SecurityScheme(swagger *model.MgwSwagger, schemeName string) bool {
+	for _, securityScheme := range swagger.GetSecurityScheme() {
+		if securityScheme.DefinitionName == schemeName {
+			return securityScheme.Type == constants.APIMOauth2Type
+		}
+	}
+	return schemeName == constants.APIMDefaultOauth2Security
+}
+
+// hasEnforcerPolicies returns true if any of the policies of the operation is applied by the enforcer.
+func hasEnforcerPolicies(operation *model.Operation) bool {
+	policies := operation.GetPolicies()
+	for _, policyList := range []model.PolicyList{policies.Request, policies.Response, policies.Fault} {
+		for _, policy := range policyList {
+			if policy.IsPassToEnforcer {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isUnlimitedThrottlingTier returns true if no throttling policy is applied for the given tier.
+func isUnlimitedThrottlingTier(tier string) bool {
+	return tier == "" || tier == unlimitedThrottlingTier
+}
+
+// hasEndpoints returns true if the endpoint cluster has at least one endpoint.
+func hasEndpoints(endpointCluster *model.EndpointCluster) bool {
+	return endpointCluster != nil && len(endpointCluster.Endpoints) > 0
+}
+
+// hasEndpointSecurity returns true if the endpoint security is enabled for any of the endpoint clusters.
+func hasEndpointSecurity(endpointClusters ...*model.EndpointCluster) bool {
+	for _, endpointCluster := range endpointClusters {
+		if endpointCluster != nil && endpointCluster.SecurityConfig.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// getRouterJWTPolicies returns the RBAC policies which deny the requests of the sandbox tokens and the tokens not
+// having any of the scopes of the operation, validated at the router.
+func getRouterJWTPolicies(requirement *routerJWTRequirement) map[string]*rbacconfigv3.Policy {
+	anyPermission := []*rbacconfigv3.Permission{{
+		Rule: &rbacconfigv3.Permission_Any{Any: true},
+	}}
+	// The sandbox tokens are rejected as the requests are always routed to the production endpoints.
+	policies := map[string]*rbacconfigv3.Policy{
+		"router-jwt-sandbox-key": {
+			Permissions: anyPermission,
+			Principals: []*rbacconfigv3.Principal{getJWTClaimPrincipal(jwtKeyTypeClaim,
+				&envoy_type_matcherv3.StringMatcher{
+					MatchPattern: &envoy_type_matcherv3.StringMatcher_Exact{Exact: sandboxKeyType},
+				})},
+		},
+	}
+	if len(requirement.scopes) > 0 {
+		quotedScopes := make([]string, 0, len(requirement.scopes))
+		for _, scope := range requirement.scopes {
+			quotedScopes = append(quotedScopes, regexp.QuoteMeta(scope))
+		}
+		// The scope claim is a space separated list of the scopes of the token.
+		scopeMatcher := &envoy_type_matcherv3.StringMatcher{
+			MatchPattern: &envoy_type_matcherv3.StringMatcher_SafeRegex{
+				SafeRegex: &envoy_type_matcherv3.RegexMatcher{
+					EngineType: &envoy_type_matcherv3.RegexMatcher_GoogleRe2{
+						GoogleRe2: &envoy_type_matcherv3.RegexMatcher_GoogleRE2{
+							MaxProgramSize: nil,
+						},
+					},
+					Regex: "(^|.* )(" + strings.Join(quotedScopes, "|") + ")( .*|$)",
+				},
+			},
+		}
+		policies["router-jwt-scopes"] = &rbacconfigv3.Policy{
+			Permissions: anyPermission,
+			Principals: []*rbacconfigv3.Principal{{
+				Identifier: &rbacconfigv3.Principal_NotId{
+					NotId: getJWTClaimPrincipal(jwtScopeClaim, scopeMatcher),
+				},
+			}},
+		}
+	}
+	return policies
+}
+
+// getJWTClaimPrincipal returns the RBAC principal which matches the claim of the token validated at the router.
+func getJWTClaimPrincipal(claim string, matcher *envoy_type_matcherv3.StringMatcher) *rbacconfigv3.Principal {
+	return &rbacconfigv3.Principal{
+		Identifier: &rbacconfigv3.Principal_Metadata{
+			Metadata: &envoy_type_matcherv3.MetadataMatcher{
+				Filter: jwtAuthnFilterName,
+				Path: []*envoy_type_matcherv3.MetadataMatcher_PathSegment{
+					{Segment: &envoy_type_matcherv3.MetadataMatcher_PathSegment_Key{Key: jwtPayloadMetadataKey}},
+					{Segment: &envoy_type_matcherv3.MetadataMatcher_PathSegment_Key{Key: claim}},
+				},
+				Value: &envoy_type_matcherv3.ValueMatcher{
+					MatchPattern: &envoy_type_matcherv3.ValueMatcher_StringMatch{StringMatch: matcher},
+				},
+			},
+		},
+	}
+}
+
+// CreateJWKSClusters creates the clusters of the JWKS endpoints of the token services, of which the tokens are
+// validated at the router.
+func CreateJWKSClusters(conf *config.Config) ([]*clusterv3.Cluster, []*corev3.Address) {
+	var clusters []*clusterv3.Cluster
+	var addresses []*corev3.Address
+	providers, _ := getRouterJWTProviders(conf)
+	for _, tokenService := range conf.Enforcer.Security.TokenService {
+		if _, found := providers[tokenService.Name]; !found || tokenService.JwksURL == "" {
+			continue
+		}
+		endpoint, _ := getJWKSEndpoint(tokenService.JwksURL)
+		basePath := strings.TrimSuffix(endpoint.Basepath, "/")
+		cluster, clusterAddresses, err := processEndpoints(getJWKSClusterName(tokenService.Name),
+			&model.EndpointCluster{Endpoints: []model.Endpoint{*endpoint}}, nil, nil, nil,
+			conf.Envoy.ClusterTimeoutInSeconds, basePath)
+		if err != nil {
+			logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Error while creating the JWKS cluster of the token service %s. %v",
+					tokenService.Name, err.Error()),
+				Severity:  logging.MAJOR,
+				ErrorCode: 2229,
+			})
+			continue
+		}
+		clusters = append(clusters, cluster)
+		addresses = append(addresses, clusterAddresses...)
+	}
+	return clusters, addresses
+}
+
+// getJWKSClusterName returns the name of the JWKS cluster of the token service.
+func getJWKSClusterName(tokenServiceName string) string {
+	return jwksClusterPrefix + strings.ReplaceAll(tokenServiceName, " ", "_")
+}
+
+// getJWKSEndpoint returns the endpoint of the JWKS URL.
+func getJWKSEndpoint(jwksURL string) (*model.Endpoint, error) {
+	parsedURL, err := url.Parse(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsedURL.Hostname() == "" {
+		return nil, errors.New("host is not provided")
+	}
+	var port uint64 = 80
+	switch parsedURL.Scheme {
+	case httpsURLType:
+		port = 443
+	case "http":
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", parsedURL.Scheme)
+	}
+	if parsedURL.Port() != "" {
+		if port, err = strconv.ParseUint(parsedURL.Port(), 10, 16); err != nil {
+			return nil, fmt.Errorf("invalid port %s", parsedURL.Port())
+		}
+	}
+	return &model.Endpoint{
+		Host:     parsedURL.Hostname(),
+		Basepath: parsedURL.Path,
+		URLType:  parsedURL.Scheme,
+		Port:     uint32(port),
+		RawURL:   jwksURL,
+	}, nil
+}
+
+// createJWKSFromCertificate creates a JWKS with the public key of the PEM encoded certificate in the given file,
+// as the local JWKS of the jwt_authn filter accepts only the public keys.
+func createJWKSFromCertificate(certificatePath string) (string, error) {
+	certificatePEM, err := ioutil.ReadFile(certificatePath)
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(certificatePEM)
+	if block == nil {
+		return "", errors.New("no PEM encoded certificate is found")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+	encode := base64.RawURLEncoding.EncodeToString
+	var jwk map[string]string
+	switch publicKey := certificate.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk = map[string]string{
+			"kty": "RSA",
+			"n":   encode(publicKey.N.Bytes()),
+			"e":   encode(big.NewInt(int64(publicKey.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		// The coordinates are padded to the size of the curve.
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		jwk = map[string]string{
+			"kty": "EC",
+			"crv": publicKey.Curve.Params().Name,
+			"x":   encode(publicKey.X.FillBytes(make([]byte, size))),
+			"y":   encode(publicKey.Y.FillBytes(make([]byte, size))),
+		}
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", certificate.PublicKey)
+	}
+	jwks, err := json.Marshal(map[string][]map[string]string{"keys": {jwk}})
+	return string(jwks), err
+}
@@ -65,21 +65,29 @@ func CreateRoutesConfigForRds(vHosts []*routev3.VirtualHost) *routev3.RouteConfi
 // The relevant private keys and certificates (for securedListener) are fetched from the filepath
 // mentioned in the adapter configuration. These certificate, key values are added
 // as inline records (base64 encoded).
-func CreateListenersWithRds() []*listenerv3.Listener {
+//
+// The local reply configuration contains the provided API level error response mappers, followed by
+// the error response templates configured for the environment and the default error responses.
+func CreateListenersWithRds(environment string, apiErrorResponseMappers []*hcmv3.ResponseMapper) []*listenerv3.Listener {
 	conf, errReadConfig := config.ReadConfigs()
 	if errReadConfig != nil {
 		logger.LoggerOasparser.Fatal("Error loading configuration. ", errReadConfig)
 	}
-	return createListeners(conf)
+	return createListeners(conf, environment, apiErrorResponseMappers)
 }
 
-func createListeners(conf *config.Config) []*listenerv3.Listener {
+func createListeners(conf *config.Config, environment string, apiErrorResponseMappers []*hcmv3.ResponseMapper) []*listenerv3.Listener {
 	httpFilters := getHTTPFilters()
 	upgradeFilters := getUpgradeFilters()
 	accessLogs := getAccessLogs()
 	var filters []*listenerv3.Filter
 	var listeners []*listenerv3.Listener
 
+	var errorResponseMappers []*hcmv3.ResponseMapper
+	errorResponseMappers = append(errorResponseMappers, apiErrorResponseMappers...)
+	errorResponseMappers = append(errorResponseMappers, getEnvironmentErrorResponseMappers(conf, environment)...)
+	errorResponseMappers = append(errorResponseMappers, getErrorResponseMappers()...)
+
 	manager := &hcmv3.HttpConnectionManager{
 		CodecType:  getListenerCodecType(conf.Envoy.ListenerCodecType),
 		StatPrefix: httpConManagerStartPrefix,
@@ -102,7 +110,7 @@ func createListeners(conf *config.Config) []*listenerv3.Listener {
 		},
 		HttpFilters: httpFilters,
 		LocalReplyConfig: &hcmv3.LocalReplyConfig{
-			Mappers: getErrorResponseMappers(),
+			Mappers: errorResponseMappers,
 		},
 		RequestTimeout:        ptypes.DurationProto(conf.Envoy.Connection.Timeouts.RequestTimeoutInSeconds * time.Second),        // default disabled
 		RequestHeadersTimeout: ptypes.DurationProto(conf.Envoy.Connection.Timeouts.RequestHeadersTimeoutInSeconds * time.Second), // default disabled
@@ -113,7 +121,12 @@ func createListeners(conf *config.Config) []*listenerv3.Listener {
 		HttpProtocolOptions: &corev3.Http1ProtocolOptions{
 			EnableTrailers: config.GetWireLogConfig().LogTrailersEnabled,
 		},
-		UseRemoteAddress: &wrappers.BoolValue{Value: conf.Envoy.UseRemoteAddress},
+		UseRemoteAddress:  &wrappers.BoolValue{Value: conf.Envoy.UseRemoteAddress},
+		XffNumTrustedHops: conf.Envoy.XffNumTrustedHops,
+	}
+
+	if conf.Envoy.Downstream.PreserveHeaderCase {
+		manager.HttpProtocolOptions.HeaderKeyFormat = createPreserveCaseHeaderKeyFormat()
 	}
 
 	if len(accessLogs) > 0 {
@@ -27,7 +27,7 @@ import (
 
 func TestCreateListenerWithRds(t *testing.T) {
 	// TODO: (Vajira) Add more test scenarios
-	listeners := CreateListenersWithRds()
+	listeners := CreateListenersWithRds("Default", nil)
 	assert.NotEmpty(t, listeners, "Listeners creation has been failed")
 	assert.Equal(t, 2, len(listeners), "Two listeners are not created.")
 
@@ -56,6 +56,46 @@ func TestCreateListenerWithRds(t *testing.T) {
 		"Transport Socket should be null for non-secured listener")
 }
 
+func TestCreateAPIErrorResponseMappers(t *testing.T) {
+	templates := []model.ErrorResponseTemplate{
+		{
+			Flags:       []string{"UAEX", "UF", "INVALID"},
+			Accept:      "application/xml",
+			ContentType: "application/xml",
+			Body:        "<error><code>${code}</code><status>%RESPONSE_CODE%</status><id>%REQ(X-REQUEST-ID)%</id></error>",
+		},
+		{
+			Flags:      []string{"NR"},
+			StatusCode: 410,
+			Body:       "100% not found",
+		},
+	}
+	mappers := CreateAPIErrorResponseMappers(templates, "mg.wso2.com", "/petstore/v1")
+	assert.Equal(t, 2, len(mappers), "Mappers should be created only for the valid flags of the valid templates.")
+
+	uaexMapper := mappers[0]
+	assert.Equal(t, uint32(500), uaexMapper.GetStatusCode().GetValue(), "Default status code of the flag should be used.")
+	assert.Equal(t, "application/xml", uaexMapper.GetBodyFormatOverride().GetContentType(), "Content type mismatch.")
+	assert.Equal(t, "<error><code>102500</code><status>%RESPONSE_CODE%</status><id>%REQ(X-REQUEST-ID)%</id></error>",
+		uaexMapper.GetBodyFormatOverride().GetTextFormatSource().GetInlineString(), "Body template mismatch.")
+	filters := uaexMapper.GetFilter().GetAndFilter().GetFilters()
+	assert.Equal(t, 4, len(filters), "Flag, basepath, vhost and accept header filters should be available.")
+	assert.Equal(t, []string{"UAEX"}, filters[0].GetResponseFlagFilter().GetFlags(), "Response flag mismatch.")
+	assert.Equal(t, `^/petstore/v1([/?].*)?$`,
+		filters[1].GetOrFilter().GetFilters()[0].GetHeaderFilter().GetHeader().GetSafeRegexMatch().GetRegex(), "Basepath regex mismatch.")
+	assert.Equal(t, `^mg\.wso2\.com(:[0-9]+)?$`,
+		filters[2].GetHeaderFilter().GetHeader().GetSafeRegexMatch().GetRegex(), "Vhost regex mismatch.")
+	assert.Equal(t, "application/xml",
+		filters[3].GetHeaderFilter().GetHeader().GetStringMatch().GetContains(), "Accept header mismatch.")
+	assert.Equal(t, []string{"UF"}, mappers[1].GetFilter().GetAndFilter().GetFilters()[0].GetResponseFlagFilter().GetFlags(),
+		"Response flag mismatch.")
+
+	listeners := CreateListenersWithRds("Default", mappers)
+	for _, listener := range listeners {
+		assert.Nil(t, listener.Validate(), "Listener validation failed")
+	}
+}
+
 func TestCreateVirtualHost(t *testing.T) {
 	// TODO: (Vajira) Add more test scenarios
 
@@ -18,34 +18,249 @@
 package envoyconf
 
 import (
+	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 
 	access_logv3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_type_matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/wso2/product-microgateway/adapter/config"
 	"github.com/wso2/product-microgateway/adapter/internal/err"
+	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
+// errorResponse holds the default error response returned for an envoy response flag.
+type errorResponse struct {
+	flag        string
+	statusCode  uint32
+	errorCode   int32
+	message     string
+	description string
+}
+
+// defaultErrorResponses is ordered the same way the response mappers are added to the listener.
+var defaultErrorResponses = []errorResponse{
+	{"NR", err.NotFoundCode, err.NotFoundCode, err.NotFoundMessage, err.NotFoundDescription},
+	{"UAEX", 500, err.UaexCode, err.UaexMessage, err.UaexDecription},
+	{"UF", 503, err.UfCode, err.UfMessage, "%LOCAL_REPLY_BODY%"},
+	{"UT", 504, err.UtCode, err.UtMessage, "%LOCAL_REPLY_BODY%"},
+	{"UO", 503, err.UoCode, err.UoMessage, "%LOCAL_REPLY_BODY%"},
+	{"URX", 500, err.UrxCode, err.UrxMessage, "%LOCAL_REPLY_BODY%"},
+	{"NC", 500, err.NcCode, err.NcMessage, "%LOCAL_REPLY_BODY%"},
+	{"UH", 503, err.UhCode, err.UhMessage, "%LOCAL_REPLY_BODY%"},
+	{"UR", 503, err.UrCode, err.UrMessage, "%LOCAL_REPLY_BODY%"},
+	{"UC", 503, err.UcCode, err.UcMessage, "%LOCAL_REPLY_BODY%"},
+	{"LR", 503, err.LrCode, err.LrMessage, "%LOCAL_REPLY_BODY%"},
+	{"IH", 400, err.IhCode, err.IhMessage, "%LOCAL_REPLY_BODY%"},
+	{"SI", 500, err.SiCode, err.SiMessage, "%LOCAL_REPLY_BODY%"},
+	{"DPE", 500, err.DpeCode, err.DpeMessage, "%LOCAL_REPLY_BODY%"},
+	{"UPE", 500, err.UpeCode, err.UpeMessage, "%LOCAL_REPLY_BODY%"},
+	{"UMSDR", 500, err.UmsdrCode, err.UmsdrMessage, "%LOCAL_REPLY_BODY%"},
+}
+
+// envoyCommandOperatorRegex matches the escaped percent sign and the envoy command operators
+// (eg: %RESPONSE_CODE%, %REQ(X-REQUEST-ID):10%) allowed within an error response body template.
+var envoyCommandOperatorRegex = regexp.MustCompile(`%%|%[A-Z_]+(\([^%]*\))?(:[0-9]+)?%`)
+
 func getErrorResponseMappers() []*hcmv3.ResponseMapper {
-	return []*hcmv3.ResponseMapper{
-		genErrorResponseMapper(err.NotFoundCode, err.NotFoundCode, err.NotFoundMessage, err.NotFoundDescription, "NR"),
-		genErrorResponseMapper(500, err.UaexCode, err.UaexMessage, err.UaexDecription, "UAEX"),
-		genErrorResponseMapper(503, err.UfCode, err.UfMessage, "%LOCAL_REPLY_BODY%", "UF"),
-		genErrorResponseMapper(504, err.UtCode, err.UtMessage, "%LOCAL_REPLY_BODY%", "UT"),
-		genErrorResponseMapper(503, err.UoCode, err.UoMessage, "%LOCAL_REPLY_BODY%", "UO"),
-		genErrorResponseMapper(500, err.UrxCode, err.UrxMessage, "%LOCAL_REPLY_BODY%", "URX"),
-		genErrorResponseMapper(500, err.NcCode, err.NcMessage, "%LOCAL_REPLY_BODY%", "NC"),
-		genErrorResponseMapper(503, err.UhCode, err.UhMessage, "%LOCAL_REPLY_BODY%", "UH"),
-		genErrorResponseMapper(503, err.UrCode, err.UrMessage, "%LOCAL_REPLY_BODY%", "UR"),
-		genErrorResponseMapper(503, err.UcCode, err.UcMessage, "%LOCAL_REPLY_BODY%", "UC"),
-		genErrorResponseMapper(503, err.LrCode, err.LrMessage, "%LOCAL_REPLY_BODY%", "LR"),
-		genErrorResponseMapper(400, err.IhCode, err.IhMessage, "%LOCAL_REPLY_BODY%", "IH"),
-		genErrorResponseMapper(500, err.SiCode, err.SiMessage, "%LOCAL_REPLY_BODY%", "SI"),
-		genErrorResponseMapper(500, err.DpeCode, err.DpeMessage, "%LOCAL_REPLY_BODY%", "DPE"),
-		genErrorResponseMapper(500, err.UpeCode, err.UpeMessage, "%LOCAL_REPLY_BODY%", "UPE"),
-		genErrorResponseMapper(500, err.UmsdrCode, err.UmsdrMessage, "%LOCAL_REPLY_BODY%", "UMSDR"),
+	mappers := make([]*hcmv3.ResponseMapper, 0, len(defaultErrorResponses))
+	for _, errResponse := range defaultErrorResponses {
+		mappers = append(mappers, genErrorResponseMapper(errResponse.statusCode, errResponse.errorCode,
+			errResponse.message, errResponse.description, errResponse.flag))
+	}
+	return mappers
+}
+
+// getEnvironmentErrorResponseMappers returns the response mappers for the error response templates
+// configured for the given gateway environment in the config.toml.
+func getEnvironmentErrorResponseMappers(conf *config.Config, environment string) []*hcmv3.ResponseMapper {
+	var mappers []*hcmv3.ResponseMapper
+	for _, errResponse := range conf.Envoy.ErrorResponses {
+		if len(errResponse.Environments) > 0 && !model.ArrayContains(errResponse.Environments, environment) {
+			continue
+		}
+		template := model.ErrorResponseTemplate{
+			Flags:       errResponse.Flags,
+			Accept:      errResponse.Accept,
+			StatusCode:  errResponse.StatusCode,
+			ContentType: errResponse.ContentType,
+			Body:        errResponse.Body,
+		}
+		mappers = append(mappers, genCustomErrorResponseMappers(template, nil)...)
+	}
+	return mappers
+}
+
+// CreateAPIErrorResponseMappers generates the response mappers for the custom error response templates of an API.
+// The mappers are scoped to the API by matching the vhost and the basepath of the request, hence these should be
+// placed before the environment level and default response mappers.
+func CreateAPIErrorResponseMappers(templates []model.ErrorResponseTemplate, vHost string, basePath string) []*hcmv3.ResponseMapper {
+	var mappers []*hcmv3.ResponseMapper
+	scopeFilters := []*access_logv3.AccessLogFilter{genBasePathFilter(basePath)}
+	if vHost != "" && vHost != "*" {
+		scopeFilters = append(scopeFilters, genVHostFilter(vHost))
+	}
+	for _, template := range templates {
+		mappers = append(mappers, genCustomErrorResponseMappers(template, scopeFilters)...)
+	}
+	return mappers
+}
+
+// genCustomErrorResponseMappers generates a response mapper per response flag of the template.
+// Invalid templates are logged and ignored, as an invalid substitution format would be rejected by the router.
+func genCustomErrorResponseMappers(template model.ErrorResponseTemplate, scopeFilters []*access_logv3.AccessLogFilter) []*hcmv3.ResponseMapper {
+	if invalidOperator := envoyCommandOperatorRegex.ReplaceAllString(template.Body, ""); strings.Contains(invalidOperator, "%") {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Ignoring the error response template for the flags %v as the body contains an invalid command operator.", template.Flags),
+			Severity:  logging.MINOR,
+			ErrorCode: 2214,
+		})
+		return nil
+	}
+	var mappers []*hcmv3.ResponseMapper
+	for _, flag := range template.Flags {
+		defaultResponse, found := getDefaultErrorResponse(flag)
+		if !found {
+			logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Ignoring the unsupported response flag %q in the error response template.", flag),
+				Severity:  logging.MINOR,
+				ErrorCode: 2215,
+			})
+			continue
+		}
+		statusCode := template.StatusCode
+		if statusCode == 0 {
+			statusCode = defaultResponse.statusCode
+		}
+		contentType := template.ContentType
+		if contentType == "" {
+			contentType = template.Accept
+		}
+		body := strings.NewReplacer(
+			"${code}", strconv.FormatInt(int64(defaultResponse.errorCode), 10),
+			"${message}", defaultResponse.message,
+			"${description}", defaultResponse.description,
+		).Replace(template.Body)
+
+		filters := []*access_logv3.AccessLogFilter{genResponseFlagFilter(flag)}
+		filters = append(filters, scopeFilters...)
+		if template.Accept != "" {
+			filters = append(filters, genHeaderFilter(&routev3.HeaderMatcher{
+				Name: acceptHeaderName,
+				HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+					StringMatch: &envoy_type_matcherv3.StringMatcher{
+						MatchPattern: &envoy_type_matcherv3.StringMatcher_Contains{
+							Contains: template.Accept,
+						},
+						IgnoreCase: true,
+					},
+				},
+			}))
+		}
+
+		mappers = append(mappers, &hcmv3.ResponseMapper{
+			Filter:     genAndFilter(filters),
+			StatusCode: wrapperspb.UInt32(statusCode),
+			BodyFormatOverride: &corev3.SubstitutionFormatString{
+				Format: &corev3.SubstitutionFormatString_TextFormatSource{
+					TextFormatSource: &corev3.DataSource{
+						Specifier: &corev3.DataSource_InlineString{
+							InlineString: body,
+						},
+					},
+				},
+				ContentType: contentType,
+			},
+		})
+	}
+	return mappers
+}
+
+func getDefaultErrorResponse(flag string) (errorResponse, bool) {
+	for _, errResponse := range defaultErrorResponses {
+		if errResponse.flag == flag {
+			return errResponse, true
+		}
+	}
+	return errorResponse{}, false
+}
+
+// genBasePathFilter matches the requests to the basepath of the API. As the path could be rewritten
+// by the time an upstream error occurs, the original path header is also checked.
+func genBasePathFilter(basePath string) *access_logv3.AccessLogFilter {
+	regex := "^" + regexp.QuoteMeta(strings.TrimSuffix(basePath, "/")) + "([/?].*)?$"
+	return &access_logv3.AccessLogFilter{
+		FilterSpecifier: &access_logv3.AccessLogFilter_OrFilter{
+			OrFilter: &access_logv3.OrFilter{
+				Filters: []*access_logv3.AccessLogFilter{
+					genHeaderFilter(genSafeRegexHeaderMatcher(pathHeaderName, regex)),
+					genHeaderFilter(genSafeRegexHeaderMatcher(originalPathHeaderName, regex)),
+				},
+			},
+		},
+	}
+}
+
+// genVHostFilter matches the requests to the vhost with or without the port.
+func genVHostFilter(vHost string) *access_logv3.AccessLogFilter {
+	regex := "^" + strings.ReplaceAll(regexp.QuoteMeta(vHost), `\*`, ".*") + "(:[0-9]+)?$"
+	return genHeaderFilter(genSafeRegexHeaderMatcher(authorityHeaderName, regex))
+}
+
+func genSafeRegexHeaderMatcher(headerName string, regex string) *routev3.HeaderMatcher {
+	return &routev3.HeaderMatcher{
+		Name: headerName,
+		HeaderMatchSpecifier: &routev3.HeaderMatcher_SafeRegexMatch{
+			SafeRegexMatch: &envoy_type_matcherv3.RegexMatcher{
+				EngineType: &envoy_type_matcherv3.RegexMatcher_GoogleRe2{
+					GoogleRe2: &envoy_type_matcherv3.RegexMatcher_GoogleRE2{},
+				},
+				Regex: regex,
+			},
+		},
+	}
+}
+
+func genHeaderFilter(headerMatcher *routev3.HeaderMatcher) *access_logv3.AccessLogFilter {
+	return &access_logv3.AccessLogFilter{
+		FilterSpecifier: &access_logv3.AccessLogFilter_HeaderFilter{
+			HeaderFilter: &access_logv3.HeaderFilter{
+				Header: headerMatcher,
+			},
+		},
+	}
+}
+
+func genResponseFlagFilter(flag string) *access_logv3.AccessLogFilter {
+	return &access_logv3.AccessLogFilter{
+		// TODO: (VirajSalaka) Decide if the status code needs to be checked in addition to flags
+		FilterSpecifier: &access_logv3.AccessLogFilter_ResponseFlagFilter{
+			ResponseFlagFilter: &access_logv3.ResponseFlagFilter{
+				Flags: []string{flag},
+			},
+		},
+	}
+}
+
+// genAndFilter combines the filters, as the AndFilter requires at least two filters.
+func genAndFilter(filters []*access_logv3.AccessLogFilter) *access_logv3.AccessLogFilter {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	return &access_logv3.AccessLogFilter{
+		FilterSpecifier: &access_logv3.AccessLogFilter_AndFilter{
+			AndFilter: &access_logv3.AndFilter{
+				Filters: filters,
+			},
+		},
 	}
 }
 
@@ -56,14 +271,7 @@ func genErrorResponseMapper(statusCode uint32, errorCode int32, message string,
 	errorMsgMap["description"] = structpb.NewStringValue(description)
 
 	mapper := &hcmv3.ResponseMapper{
-		Filter: &access_logv3.AccessLogFilter{
-			// TODO: (VirajSalaka) Decide if the status code needs to be checked in addition to flags
-			FilterSpecifier: &access_logv3.AccessLogFilter_ResponseFlagFilter{
-				ResponseFlagFilter: &access_logv3.ResponseFlagFilter{
-					Flags: []string{flag},
-				},
-			},
-		},
+		Filter:     genResponseFlagFilter(flag),
 		StatusCode: wrapperspb.UInt32(statusCode),
 		BodyFormatOverride: &corev3.SubstitutionFormatString{
 			Format: &corev3.SubstitutionFormatString_JsonFormat{
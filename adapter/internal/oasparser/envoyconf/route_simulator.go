@@ -0,0 +1,317 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package envoyconf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	extAuthService "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	envoy_type_matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+)
+
+// RouteMatchRequest is a request evaluated against the route configuration to simulate the route
+// matching of the router.
+type RouteMatchRequest struct {
+	Host   string
+	Method string
+	// Path is the path of the request without the query string.
+	Path    string
+	Headers map[string]string
+	Query   map[string]string
+	// ClusterName is the value of the cluster header, which is set by the enforcer before the route is reselected.
+	ClusterName string
+}
+
+// RouteContext contains the API details of a route, which are passed to the enforcer as context extensions.
+type RouteContext struct {
+	APIName         string
+	APIVersion      string
+	VHost           string
+	BasePath        string
+	ResourcePath    string
+	Methods         string
+	ProdClusterName string
+	SandClusterName string
+}
+
+// FindVirtualHost returns the virtual host the router selects for the given host, in the order of the exact domain
+// match, the longest suffix wildcard match, the longest prefix wildcard match and the catch all wildcard.
+// The port of the host is stripped before matching, similar to the router.
+func FindVirtualHost(routeConfig *routev3.RouteConfiguration, host string) *routev3.VirtualHost {
+	host = strings.ToLower(stripHostPort(host))
+	var (
+		suffixMatch, prefixMatch, wildcardMatch *routev3.VirtualHost
+		suffixLength, prefixLength              int
+	)
+	for _, virtualHost := range routeConfig.GetVirtualHosts() {
+		for _, domain := range virtualHost.GetDomains() {
+			domain = strings.ToLower(domain)
+			switch {
+			case domain == host:
+				return virtualHost
+			case domain == "*":
+				if wildcardMatch == nil {
+					wildcardMatch = virtualHost
+				}
+			case strings.HasPrefix(domain, "*"):
+				if len(domain) > suffixLength && len(host) >= len(domain) && strings.HasSuffix(host, domain[1:]) {
+					suffixMatch, suffixLength = virtualHost, len(domain)
+				}
+			case strings.HasSuffix(domain, "*"):
+				if len(domain) > prefixLength && len(host) >= len(domain) &&
+					strings.HasPrefix(host, domain[:len(domain)-1]) {
+					prefixMatch, prefixLength = virtualHost, len(domain)
+				}
+			}
+		}
+	}
+	if suffixMatch != nil {
+		return suffixMatch
+	}
+	if prefixMatch != nil {
+		return prefixMatch
+	}
+	return wildcardMatch
+}
+
+// stripHostPort removes the port from the host (ex: localhost:9095 and [::1]:9095). An IPv6 address which is not
+// enclosed in square brackets is returned as it is, since it does not have a port.
+func stripHostPort(host string) string {
+	portIndex := strings.LastIndex(host, ":")
+	if portIndex < 0 {
+		return host
+	}
+	if strings.HasPrefix(host, "[") {
+		if strings.HasSuffix(host[:portIndex], "]") {
+			return host[:portIndex]
+		}
+		return host
+	}
+	if strings.Contains(host[:portIndex], ":") {
+		return host
+	}
+	return host[:portIndex]
+}
+
+// MatchRoute returns the first route of the virtual host which matches the request. If none of the routes match
+// the request, the reasons for each route not matching the request are returned.
+// The routes matching the dynamic metadata set by the enforcer (ex: the JWT claims of the content based routing
+// rules) cannot be evaluated, hence they are returned separately as the routes which may serve the request
+// instead of the returned route, and the matching continues with the following routes.
+func MatchRoute(virtualHost *routev3.VirtualHost, request *RouteMatchRequest) (*routev3.Route, []string, []string) {
+	var mismatches, notEvaluable []string
+	for _, route := range virtualHost.GetRoutes() {
+		if reason := getRouteMismatch(route.GetMatch(), request); reason != "" {
+			mismatches = append(mismatches, fmt.Sprintf("route %q : %v", getRouteDisplayName(route), reason))
+			continue
+		}
+		if len(route.GetMatch().GetDynamicMetadata()) > 0 {
+			notEvaluable = append(notEvaluable, fmt.Sprintf("route %q : the route matches the dynamic metadata "+
+				"set by the enforcer, which cannot be simulated", getRouteDisplayName(route)))
+			continue
+		}
+		return route, nil, notEvaluable
+	}
+	return nil, mismatches, notEvaluable
+}
+
+// GetRouteContext returns the API details of the route. Nil is returned for the routes which are not created
+// for APIs, such as the system routes.
+func GetRouteContext(route *routev3.Route) *RouteContext {
+	extAuthzConfig, found := route.GetTypedPerFilterConfig()[wellknown.HTTPExternalAuthorization]
+	if !found {
+		return nil
+	}
+	extAuthzPerRoute := &extAuthService.ExtAuthzPerRoute{}
+	if err := extAuthzConfig.UnmarshalTo(extAuthzPerRoute); err != nil {
+		return nil
+	}
+	contextExtensions := extAuthzPerRoute.GetCheckSettings().GetContextExtensions()
+	if contextExtensions == nil {
+		return nil
+	}
+	return &RouteContext{
+		APIName:         contextExtensions[apiNameContextExtension],
+		APIVersion:      contextExtensions[apiVersionContextExtension],
+		VHost:           contextExtensions[vHostContextExtension],
+		BasePath:        contextExtensions[basePathContextExtension],
+		ResourcePath:    contextExtensions[pathContextExtension],
+		Methods:         contextExtensions[methodContextExtension],
+		ProdClusterName: contextExtensions[prodClusterNameContextExtension],
+		SandClusterName: contextExtensions[sandClusterNameContextExtension],
+	}
+}
+
+// GetRouteCluster returns the cluster the route forwards the request to.
+func GetRouteCluster(route *routev3.Route, request *RouteMatchRequest) string {
+	action := route.GetRoute()
+	if action == nil {
+		return ""
+	}
+	if action.GetClusterHeader() != "" {
+		return request.ClusterName
+	}
+	return action.GetCluster()
+}
+
+// GetRewrittenPath returns the path of the request (without the query string) after the path rewrite of the route
+// is applied.
+func GetRewrittenPath(route *routev3.Route, path string) (string, error) {
+	action := route.GetRoute()
+	if action == nil {
+		return path, nil
+	}
+	if prefixRewrite := action.GetPrefixRewrite(); prefixRewrite != "" {
+		return prefixRewrite + strings.TrimPrefix(path, route.GetMatch().GetPrefix()), nil
+	}
+	regexRewrite := action.GetRegexRewrite()
+	if regexRewrite == nil {
+		return path, nil
+	}
+	pattern, err := regexp.Compile(regexRewrite.GetPattern().GetRegex())
+	if err != nil {
+		return "", err
+	}
+	// The capture groups are referred as \N in the envoy substitutions.
+	substitution := regexp.MustCompile(`\\(\d)`).ReplaceAllString(regexRewrite.GetSubstitution(), "$${$1}")
+	return pattern.ReplaceAllString(path, substitution), nil
+}
+
+func getRouteMismatch(match *routev3.RouteMatch, request *RouteMatchRequest) string {
+	switch {
+	case match.GetSafeRegex() != nil:
+		if !isFullRegexMatch(match.GetSafeRegex().GetRegex(), request.Path) {
+			return fmt.Sprintf("path %v does not match the regex %v", request.Path, match.GetSafeRegex().GetRegex())
+		}
+	case match.GetPath() != "":
+		if request.Path != match.GetPath() {
+			return fmt.Sprintf("path %v is not %v", request.Path, match.GetPath())
+		}
+	default:
+		if !strings.HasPrefix(request.Path, match.GetPrefix()) {
+			return fmt.Sprintf("path %v does not have the prefix %v", request.Path, match.GetPrefix())
+		}
+	}
+	for _, headerMatcher := range match.GetHeaders() {
+		if !isHeaderMatch(headerMatcher, request) {
+			return fmt.Sprintf("header %v does not match", headerMatcher.GetName())
+		}
+	}
+	for _, queryMatcher := range match.GetQueryParameters() {
+		value, found := request.Query[queryMatcher.GetName()]
+		if !found {
+			return fmt.Sprintf("query parameter %v is not present", queryMatcher.GetName())
+		}
+		if queryMatcher.GetStringMatch() != nil && !isStringMatch(queryMatcher.GetStringMatch(), value) {
+			return fmt.Sprintf("query parameter %v does not match", queryMatcher.GetName())
+		}
+	}
+	return ""
+}
+
+func isHeaderMatch(headerMatcher *routev3.HeaderMatcher, request *RouteMatchRequest) bool {
+	value, found := getRequestHeader(request, headerMatcher.GetName())
+	if !found {
+		// Only an inverted present match matches a missing header.
+		_, isPresentMatch := headerMatcher.GetHeaderMatchSpecifier().(*routev3.HeaderMatcher_PresentMatch)
+		return headerMatcher.GetInvertMatch() && isPresentMatch
+	}
+	var matched bool
+	switch specifier := headerMatcher.GetHeaderMatchSpecifier().(type) {
+	case *routev3.HeaderMatcher_StringMatch:
+		matched = isStringMatch(specifier.StringMatch, value)
+	case *routev3.HeaderMatcher_ExactMatch:
+		matched = value == specifier.ExactMatch
+	case *routev3.HeaderMatcher_SafeRegexMatch:
+		matched = isFullRegexMatch(specifier.SafeRegexMatch.GetRegex(), value)
+	case *routev3.HeaderMatcher_PrefixMatch:
+		matched = strings.HasPrefix(value, specifier.PrefixMatch)
+	case *routev3.HeaderMatcher_SuffixMatch:
+		matched = strings.HasSuffix(value, specifier.SuffixMatch)
+	case *routev3.HeaderMatcher_ContainsMatch:
+		matched = strings.Contains(value, specifier.ContainsMatch)
+	case *routev3.HeaderMatcher_PresentMatch:
+		matched = specifier.PresentMatch
+	default:
+		matched = true
+	}
+	return matched != headerMatcher.GetInvertMatch()
+}
+
+func getRequestHeader(request *RouteMatchRequest, name string) (string, bool) {
+	name = strings.ToLower(name)
+	switch name {
+	case httpMethodHeader:
+		return request.Method, true
+	case authorityHeaderName:
+		return request.Host, true
+	case clusterHeaderName:
+		return request.ClusterName, request.ClusterName != ""
+	}
+	for headerName, value := range request.Headers {
+		if strings.ToLower(headerName) == name {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func isStringMatch(matcher *envoy_type_matcherv3.StringMatcher, value string) bool {
+	if matcher.GetIgnoreCase() {
+		value = strings.ToLower(value)
+	}
+	toMatch := func(pattern string) string {
+		if matcher.GetIgnoreCase() {
+			return strings.ToLower(pattern)
+		}
+		return pattern
+	}
+	switch pattern := matcher.GetMatchPattern().(type) {
+	case *envoy_type_matcherv3.StringMatcher_Exact:
+		return value == toMatch(pattern.Exact)
+	case *envoy_type_matcherv3.StringMatcher_Prefix:
+		return strings.HasPrefix(value, toMatch(pattern.Prefix))
+	case *envoy_type_matcherv3.StringMatcher_Suffix:
+		return strings.HasSuffix(value, toMatch(pattern.Suffix))
+	case *envoy_type_matcherv3.StringMatcher_Contains:
+		return strings.Contains(value, toMatch(pattern.Contains))
+	case *envoy_type_matcherv3.StringMatcher_SafeRegex:
+		return isFullRegexMatch(pattern.SafeRegex.GetRegex(), value)
+	}
+	return false
+}
+
+// isFullRegexMatch checks whether the regex matches the whole value, as the router does.
+func isFullRegexMatch(regex, value string) bool {
+	compiledRegex, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return false
+	}
+	return compiledRegex.MatchString(value)
+}
+
+func getRouteDisplayName(route *routev3.Route) string {
+	if route.GetDecorator() != nil {
+		return route.GetDecorator().GetOperation()
+	}
+	return route.GetName()
+}
@@ -18,10 +18,15 @@
 package envoyconf
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,12 +39,20 @@ import (
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	rbacconfigv3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	faultcommonv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
 	extAuthService "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	rbacv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	preserve_casev3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/header_formatters/preserve_case/v3"
+	previous_hosts "github.com/envoyproxy/go-control-plane/envoy/extensions/retry/host/previous_hosts/v3"
 	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 
 	envoy_type_matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 
 	"github.com/wso2/product-microgateway/adapter/config"
@@ -92,6 +105,8 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 
 	conf, _ := config.ReadConfigs()
 	timeout := conf.Envoy.ClusterTimeoutInSeconds
+	upstreamClientCerts := mgwSwagger.GetUpstreamClientCerts()
+	upstreamProxy := mgwSwagger.GetXWso2Proxy()
 
 	// The any upstream endpoint's basepath.
 	apiLevelBasePathProd := ""
@@ -113,7 +128,7 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 			apiVersion, "")
 		if !strings.Contains(apiLevelEndpointProd.EndpointPrefix, xWso2EPClustersConfigNamePrefix) {
 			cluster, address, err := processEndpoints(apiLevelClusterNameProd, apiLevelEndpointProd,
-				upstreamCerts, timeout, apiLevelBasePathProd)
+				upstreamCerts, upstreamClientCerts, upstreamProxy, timeout, apiLevelBasePathProd)
 			if err != nil {
 				apiLevelClusterNameProd = ""
 				logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
@@ -149,7 +164,7 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 			apiTitle, apiVersion, "")
 		if !strings.Contains(apiLevelEndpointSand.EndpointPrefix, xWso2EPClustersConfigNamePrefix) {
 			cluster, address, err := processEndpoints(apiLevelClusterNameSand, apiLevelEndpointSand,
-				upstreamCerts, timeout, selectedBasePathSand)
+				upstreamCerts, upstreamClientCerts, upstreamProxy, timeout, selectedBasePathSand)
 			if err != nil {
 				apiLevelClusterNameSand = ""
 				logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
@@ -167,6 +182,8 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 	}
 	// check if x-wso2-endpoints are available
 	xWso2Endpoints := mgwSwagger.GetXWso2Endpoints()
+	// x-wso2-endpoints name -> successfully created cluster name
+	xWso2EPClusterNames := make(map[string]string)
 	if len(xWso2Endpoints) > 0 {
 		logger.LoggerOasparser.Debugf("x-wso2-endpoints clusters found for %v : %v", apiTitle, apiVersion)
 		for epName, endpointCluster := range xWso2Endpoints {
@@ -175,17 +192,36 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 			}
 			epClusterName := getClusterName(endpointCluster.EndpointPrefix, organizationID, vHost, apiTitle,
 				apiVersion, "")
-			cluster, addresses, err := processEndpoints(epClusterName, endpointCluster, upstreamCerts, timeout, apiLevelBasePathProd)
+			cluster, addresses, err := processEndpoints(epClusterName, endpointCluster, upstreamCerts, upstreamClientCerts, upstreamProxy, timeout, apiLevelBasePathProd)
 			if err != nil {
 				logger.LoggerOasparser.Errorf("Error while adding x-wso2-endpoints cluster %v for %s. %v ", epName, apiTitle, err.Error())
 			} else {
 				strictBasePath = true
+				xWso2EPClusterNames[epName] = epClusterName
 				clusters = append(clusters, cluster)
 				endpoints = append(endpoints, addresses...)
 			}
 		}
 	}
 
+	// check if API level mirror endpoints are available
+	var apiLevelMirrorPolicy *mirrorPolicy
+	if mgwSwagger.GetXWso2Mirror() != nil && mgwSwagger.GetAPIType() != constants.WS {
+		apiLevelMirror := mgwSwagger.GetXWso2Mirror()
+		mirrorClusterName := getClusterName(apiLevelMirror.EndpointCluster.EndpointPrefix, organizationID, vHost, apiTitle,
+			apiVersion, "")
+		// The mirrored request has the same path as the request sent to the production endpoint.
+		cluster, addresses, err := processEndpoints(mirrorClusterName, apiLevelMirror.EndpointCluster, upstreamCerts, upstreamClientCerts, upstreamProxy, timeout,
+			apiLevelBasePathProd)
+		if err != nil {
+			logger.LoggerOasparser.Errorf("Error while adding api level mirror endpoints for %s. %v", apiTitle, err.Error())
+		} else {
+			apiLevelMirrorPolicy = &mirrorPolicy{clusterName: mirrorClusterName, percentage: *apiLevelMirror.Percentage}
+			clusters = append(clusters, cluster)
+			endpoints = append(endpoints, addresses...)
+		}
+	}
+
 	apiRequestInterceptor = mgwSwagger.GetInterceptor(mgwSwagger.GetVendorExtensions(), xWso2requestInterceptor, APILevelInterceptor)
 	// if lua filter exists on api level, add cluster
 	if apiRequestInterceptor.Enable {
@@ -262,7 +298,7 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 			if !strings.Contains(endpointProd.EndpointPrefix, xWso2EPClustersConfigNamePrefix) {
 				clusterNameProd = getClusterName(endpointProd.EndpointPrefix, organizationID, vHost,
 					mgwSwagger.GetTitle(), apiVersion, resource.GetID())
-				clusterProd, addressProd, err := processEndpoints(clusterNameProd, endpointProd, upstreamCerts, timeout, resourceBasePath)
+				clusterProd, addressProd, err := processEndpoints(clusterNameProd, endpointProd, upstreamCerts, upstreamClientCerts, upstreamProxy, timeout, resourceBasePath)
 				if err != nil {
 					clusterNameProd = apiLevelClusterNameProd
 					// reverting resource base path setting as production cluster creation has failed
@@ -296,7 +332,7 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 			if !strings.Contains(endpointSand.EndpointPrefix, xWso2EPClustersConfigNamePrefix) {
 				clusterNameSand = getClusterName(endpointSand.EndpointPrefix, organizationID, vHost, apiTitle,
 					apiVersion, resource.GetID())
-				clusterSand, addressSand, err := processEndpoints(clusterNameSand, endpointSand, upstreamCerts, timeout, resourceBasePathSand)
+				clusterSand, addressSand, err := processEndpoints(clusterNameSand, endpointSand, upstreamCerts, upstreamClientCerts, upstreamProxy, timeout, resourceBasePathSand)
 				if err != nil {
 					clusterNameSand = apiLevelClusterNameSand
 					// reverting resource base path setting as sandbox cluster creation has failed
@@ -426,20 +462,241 @@ func CreateRoutesWithClusters(mgwSwagger model.MgwSwagger, upstreamCerts map[str
 			}
 		}
 
-		routeP := createRoute(genRouteCreateParams(&mgwSwagger, resource, vHost, resourceBasePath, clusterNameProd,
-			clusterNameSand, operationalReqInterceptors, operationalRespInterceptorVal, organizationID, false))
-		if apiLevelBasePathSand != "" || isResourceBasePathSandAvailable {
-			logger.LoggerOasparser.Debugf("Creating sandbox route for : %v:%v:%v - %v", apiTitle, apiVersion, resource.GetPath(), resourceBasePathSand)
-			routeS := createRoute(genRouteCreateParams(&mgwSwagger, resource, vHost, resourceBasePathSand, clusterNameProd,
-				clusterNameSand, operationalReqInterceptors, operationalRespInterceptorVal, organizationID, true))
-			// Sandbox route should be appended before to prod route to have the expected header based sandbox routing.
-			routes = append(routes, routeS)
+		// resource level content based routing overrides the API level content based routing
+		routingConfig := resource.GetRoutingConfig()
+		if routingConfig == nil {
+			routingConfig = mgwSwagger.GetXWso2Routing()
+		}
+		routingClaims := getRoutingClaims(routingConfig)
+		if routingConfig != nil && routingConfig.DefaultEndpoint != "" {
+			if defaultClusterName, found := xWso2EPClusterNames[routingConfig.DefaultEndpoint]; found {
+				clusterNameProd = defaultClusterName
+			}
+		}
+
+		// create operation level mirror clusters
+		operationalMirrorPolicies := make(map[string]*mirrorPolicy)
+		for _, operation := range resource.GetMethod() {
+			operationalMirrorPolicies[operation.GetMethod()] = apiLevelMirrorPolicy
+			opMirror := operation.GetMirrorConfig()
+			if opMirror == nil {
+				continue
+			}
+			mirrorClusterName := getClusterName(opMirror.EndpointCluster.EndpointPrefix, organizationID, vHost, apiTitle,
+				apiVersion, operation.GetID())
+			cluster, addresses, err := processEndpoints(mirrorClusterName, opMirror.EndpointCluster, upstreamCerts, upstreamClientCerts, upstreamProxy, timeout,
+				resourceBasePath)
+			if err != nil {
+				logger.LoggerOasparser.Errorf("Error while adding operation level mirror endpoints for %s:%v-%v-%v. %v",
+					apiTitle, apiVersion, resourcePath, operation.GetMethod(), err.Error())
+			} else {
+				operationalMirrorPolicies[operation.GetMethod()] = &mirrorPolicy{clusterName: mirrorClusterName,
+					percentage: *opMirror.Percentage}
+				clusters = append(clusters, cluster)
+				endpoints = append(endpoints, addresses...)
+			}
+		}
+		methodGroups := getMethodGroups(resource, operationalMirrorPolicies, apiLevelMirrorPolicy,
+			mgwSwagger.GetCorsConfig(), hasRetryConfig(mgwSwagger.GetProdEndpoints(), mgwSwagger.GetSandEndpoints(),
+				resource.GetProdEndpoints(), resource.GetSandEndpoints()), getRouterJWTRequirements(&mgwSwagger, resource))
+
+		genMethodGroupRouteParams := func(methodGroup *methodGroup, endpointBasePath string, prodClusterName string,
+			isSandbox bool) *routeCreateParams {
+			params := genRouteCreateParams(&mgwSwagger, resource, vHost, endpointBasePath, prodClusterName,
+				clusterNameSand, operationalReqInterceptors, operationalRespInterceptorVal, organizationID, isSandbox)
+			if len(methodGroups) > 1 {
+				params.resourceMethods = methodGroup.methods
+			}
+			// only the production traffic is mirrored
+			if !isSandbox {
+				params.mirrorPolicy = methodGroup.mirrorPolicy
+			}
+			params.faultInjection = methodGroup.faultInjection
+			params.staticMutations = methodGroup.staticMutations
+			params.corsPolicy = methodGroup.corsPolicy
+			params.timeoutConfig = methodGroup.timeoutConfig
+			params.retryPolicy = methodGroup.retryPolicy
+			params.routerJWTRequirement = methodGroup.routerJWTRequirement
+			params.routingClaims = routingClaims
+			if methodGroup.maxRequestBodySize > 0 {
+				params.maxRequestBodySize = methodGroup.maxRequestBodySize
+			}
+			if methodGroup.passRequestPayloadToEnforcer != nil {
+				params.passRequestPayloadToEnforcer = *methodGroup.passRequestPayloadToEnforcer
+			}
+			return params
+		}
+
+		// A preflight request is served by the first route which allows the OPTIONS method. Hence, if the method
+		// groups have different CORS policies, the preflight requests are routed based on the requested method,
+		// before the other routes of the resource, to be served with the CORS policy of the method group.
+		if hasDistinctCorsPolicies(methodGroups) {
+			for _, methodGroup := range methodGroups {
+				route := createRoute(genMethodGroupRouteParams(methodGroup, resourceBasePath, clusterNameProd, false))
+				routes = append(routes, getPreflightRoute(route, methodGroup.methods))
+			}
+		}
+
+		// A separate set of routes is created for each group of methods with a different mirror policy, fault
+		// injection, static mutations, CORS policy, timeouts, retry policy, router JWT validation requirement or
+		// request body settings, as they cannot be applied to a subset of the methods of a route.
+		for _, methodGroup := range methodGroups {
+			genResourceRouteParams := func(endpointBasePath string, prodClusterName string, isSandbox bool) *routeCreateParams {
+				return genMethodGroupRouteParams(methodGroup, endpointBasePath, prodClusterName, isSandbox)
+			}
+
+			routeP := createRoute(genResourceRouteParams(resourceBasePath, clusterNameProd, false))
+			// The sandbox requests are served by a separate route if the production traffic is mirrored, as the
+			// route reselected based on the cluster header set by the enforcer does not mirror the requests.
+			if apiLevelBasePathSand != "" || isResourceBasePathSandAvailable ||
+				(methodGroup.mirrorPolicy != nil && clusterNameSand != "") {
+				logger.LoggerOasparser.Debugf("Creating sandbox route for : %v:%v:%v - %v", apiTitle, apiVersion, resource.GetPath(), resourceBasePathSand)
+				routeS := createRoute(genResourceRouteParams(resourceBasePathSand, clusterNameProd, true))
+				// Sandbox route should be appended before to prod route to have the expected header based sandbox routing.
+				routes = append(routes, routeS)
+			}
+			if routingConfig != nil {
+				// Content based routes should be appended before to prod route as the prod route is the fallback route.
+				for _, rule := range routingConfig.Rules {
+					routingClusterName, found := xWso2EPClusterNames[rule.Endpoint]
+					if !found {
+						logger.LoggerOasparser.Errorf("Ignoring the content based routing rule of the endpoint %v for %s:%v-%v as the cluster is not available",
+							rule.Endpoint, apiTitle, apiVersion, resourcePath)
+						continue
+					}
+					params := genResourceRouteParams(resourceBasePath, routingClusterName, false)
+					params.prodRouteConfig = xWso2Endpoints[rule.Endpoint].Config
+					params.routingClusterName = routingClusterName
+					params.routingConditions = rule.Conditions
+					routes = append(routes, createRoute(params))
+				}
+			}
+			routes = append(routes, routeP)
 		}
-		routes = append(routes, routeP)
 	}
 	return routes, clusters, endpoints
 }
 
+// getMethodGroups groups the methods of the resource by the mirror policy, the fault injection, the static
+// mutations, the CORS policy, the timeouts, the retry policy, the router JWT validation requirement, the maximum
+// request body size and whether the request body is passed to the enforcer.
+// Non-idempotent methods are not retried by default, hence they are grouped with a retry policy without retries
+// if the endpoints have a retry configuration. If the method based route matching is disabled for the resource due
+// to method rewriting, the API level mirror policy and the resource level CORS policy are applied to all the
+// methods and the operation level fault injections, static response mutations, CORS policies, timeouts, retry
+// policies, router JWT validations and request body settings are not applied.
+func getMethodGroups(resource *model.Resource, operationalMirrorPolicies map[string]*mirrorPolicy,
+	apiLevelMirrorPolicy *mirrorPolicy, apiLevelCorsPolicy *model.CorsConfig, hasEndpointRetries bool,
+	routerJWTRequirements map[string]*routerJWTRequirement) []*methodGroup {
+	resourceCorsPolicy := apiLevelCorsPolicy
+	if corsPolicy := resource.GetCorsConfig(); corsPolicy != nil {
+		resourceCorsPolicy = corsPolicy
+	}
+	var methodGroups []*methodGroup
+	for _, operation := range resource.GetMethod() {
+		policy := operationalMirrorPolicies[operation.GetMethod()]
+		faultInjection := operation.GetFaultInjection()
+		staticMutations := operation.GetStaticMutations()
+		corsPolicy := operation.GetCorsConfig()
+		if corsPolicy == nil {
+			corsPolicy = resourceCorsPolicy
+		}
+		timeoutConfig := operation.GetTimeoutConfig()
+		retryPolicy := operation.GetRetryPolicy()
+		if retryPolicy == nil && hasEndpointRetries && !operation.IsIdempotent() {
+			retryPolicy = &model.RetryPolicy{}
+		}
+		jwtRequirement := routerJWTRequirements[operation.GetMethod()]
+		maxRequestBodySize := operation.GetMaxRequestBodySize()
+		passRequestPayloadToEnforcer := operation.GetRequestBodyPass()
+		var group *methodGroup
+		for _, existingGroup := range methodGroups {
+			if reflect.DeepEqual(existingGroup.mirrorPolicy, policy) &&
+				reflect.DeepEqual(existingGroup.faultInjection, faultInjection) &&
+				reflect.DeepEqual(existingGroup.staticMutations, staticMutations) &&
+				reflect.DeepEqual(existingGroup.corsPolicy, corsPolicy) &&
+				reflect.DeepEqual(existingGroup.timeoutConfig, timeoutConfig) &&
+				reflect.DeepEqual(existingGroup.retryPolicy, retryPolicy) &&
+				reflect.DeepEqual(existingGroup.routerJWTRequirement, jwtRequirement) &&
+				existingGroup.maxRequestBodySize == maxRequestBodySize &&
+				reflect.DeepEqual(existingGroup.passRequestPayloadToEnforcer, passRequestPayloadToEnforcer) {
+				group = existingGroup
+				break
+			}
+		}
+		if group == nil {
+			group = &methodGroup{mirrorPolicy: policy, faultInjection: faultInjection, staticMutations: staticMutations,
+				corsPolicy: corsPolicy, timeoutConfig: timeoutConfig, retryPolicy: retryPolicy,
+				routerJWTRequirement: jwtRequirement, maxRequestBodySize: maxRequestBodySize,
+				passRequestPayloadToEnforcer: passRequestPayloadToEnforcer}
+			methodGroups = append(methodGroups, group)
+		}
+		group.methods = append(group.methods, operation.GetMethod())
+	}
+	if len(methodGroups) == 0 {
+		return []*methodGroup{{mirrorPolicy: apiLevelMirrorPolicy, corsPolicy: resourceCorsPolicy}}
+	}
+	if _, rewriteMethod := resource.GetRewriteResource(); rewriteMethod && len(methodGroups) > 1 {
+		// The response header mutations are validated to be the same for all the operations of the resource.
+		logger.LoggerOasparser.Errorf("Operation level mirror policies, fault injections, CORS policies, timeouts, "+
+			"retry policies, router JWT validations and request body settings are not applied for the "+
+			"resource %v as the method rewrite is enabled for the resource", resource.GetPath())
+		return []*methodGroup{{mirrorPolicy: apiLevelMirrorPolicy, corsPolicy: resourceCorsPolicy,
+			staticMutations: resource.GetMethod()[0].GetStaticMutations()}}
+	}
+	return methodGroups
+}
+
+// hasDistinctCorsPolicies returns true if the CORS policies of the method groups are not the same.
+func hasDistinctCorsPolicies(methodGroups []*methodGroup) bool {
+	for _, group := range methodGroups[1:] {
+		if !reflect.DeepEqual(group.corsPolicy, methodGroups[0].corsPolicy) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPreflightRoute creates a route which matches only the CORS preflight requests of the given methods, from the
+// given route. The preflight requests are served by the CORS filter, using the CORS policy of the route.
+func getPreflightRoute(route *routev3.Route, methods []string) *routev3.Route {
+	preflightRoute := proto.Clone(route).(*routev3.Route)
+	headers := []*routev3.HeaderMatcher{
+		{
+			Name: httpMethodHeader,
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+				StringMatch: &envoy_type_matcherv3.StringMatcher{
+					MatchPattern: &envoy_type_matcherv3.StringMatcher_Exact{Exact: "OPTIONS"},
+				},
+			},
+		},
+		{
+			Name: corsRequestMethodHeader,
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+				StringMatch: &envoy_type_matcherv3.StringMatcher{
+					MatchPattern: &envoy_type_matcherv3.StringMatcher_SafeRegex{
+						SafeRegex: &envoy_type_matcherv3.RegexMatcher{
+							EngineType: &envoy_type_matcherv3.RegexMatcher_GoogleRe2{
+								GoogleRe2: &envoy_type_matcherv3.RegexMatcher_GoogleRE2{
+									MaxProgramSize: nil,
+								},
+							},
+							Regex: "^(" + strings.Join(methods, "|") + ")$",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, header := range preflightRoute.Match.Headers {
+		if header.Name != httpMethodHeader {
+			headers = append(headers, header)
+		}
+	}
+	preflightRoute.Match.Headers = headers
+	return preflightRoute
+}
+
 func getClusterName(epPrefix string, organizationID string, vHost string, swaggerTitle string, swaggerVersion string,
 	resourceID string) string {
 	if resourceID != "" {
@@ -453,7 +710,7 @@ func getClusterName(epPrefix string, organizationID string, vHost string, swagge
 // CreateLuaCluster creates lua cluster configuration.
 func CreateLuaCluster(interceptorCerts map[string][]byte, endpoint model.InterceptEndpoint) (*clusterv3.Cluster, []*corev3.Address, error) {
 	logger.LoggerOasparser.Debug("creating a lua cluster ", endpoint.ClusterName)
-	return processEndpoints(endpoint.ClusterName, &endpoint.EndpointCluster, interceptorCerts, endpoint.ClusterTimeout, endpoint.EndpointCluster.Endpoints[0].Basepath)
+	return processEndpoints(endpoint.ClusterName, &endpoint.EndpointCluster, interceptorCerts, nil, nil, endpoint.ClusterTimeout, endpoint.EndpointCluster.Endpoints[0].Basepath)
 }
 
 // CreateTracingCluster creates a cluster definition for router's tracing server.
@@ -488,14 +745,16 @@ func CreateTracingCluster(conf *config.Config) (*clusterv3.Cluster, []*corev3.Ad
 	epCluster.Endpoints[0].Port = epPort
 	epCluster.Endpoints[0].Basepath = epPath
 
-	return processEndpoints(tracingClusterName, epCluster, nil, epTimeout, epPath)
+	return processEndpoints(tracingClusterName, epCluster, nil, nil, nil, epTimeout, epPath)
 }
 
 // processEndpoints creates cluster configuration. AddressConfiguration, cluster name and
 // urlType (http or https) is required to be provided.
 // timeout cluster timeout
+// upstreamClientCerts SDS secret names of the client certificates presented to the endpoints (endpoint url -> secret)
 func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster, upstreamCerts map[string][]byte,
-	timeout time.Duration, basePath string) (*clusterv3.Cluster, []*corev3.Address, error) {
+	upstreamClientCerts map[string]string, upstreamProxy *model.ProxyConfig, timeout time.Duration,
+	basePath string) (*clusterv3.Cluster, []*corev3.Address, error) {
 	// tls configs
 	var transportSocketMatches []*clusterv3.Cluster_TransportSocketMatch
 	// create loadbalanced/failover endpoints
@@ -506,6 +765,7 @@ func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster,
 	epType := clusterDetails.EndpointType
 
 	addresses := []*corev3.Address{}
+	upstreamProtocol := getUpstreamProtocol(clusterDetails)
 
 	for i, ep := range clusterDetails.Endpoints {
 		// validating the basepath to be same for all upstreams of an api
@@ -516,15 +776,29 @@ func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster,
 		address := createAddress(ep.Host, ep.Port)
 		addresses = append(addresses, address)
 
+		endpoint := &endpointv3.Endpoint{
+			Address: address,
+		}
+		endpointMetadata := make(map[string]*structpb.Struct)
+		if endpointProxy := getEndpointProxy(clusterDetails, upstreamProxy); endpointProxy.IsProxied(ep.Host) {
+			tunnel, err := getUpstreamProxyTunnel(endpointProxy, ep.Host, ep.Port)
+			if err != nil {
+				return nil, nil, err
+			}
+			// The connections are sent to the tunnel listener, while the endpoint host is kept as the
+			// hostname to be used as the host header.
+			endpoint.Address = createAddress(upstreamProxyTunnelHost, tunnel.port)
+			endpoint.Hostname = ep.Host
+			endpointMetadata[upstreamProxyTunnelMetadataKey] = createUpstreamProxyTunnelMetadata(tunnel)
+		}
+
 		// create loadbalance / failover endpoints
 		localityLbEndpoints := &endpointv3.LocalityLbEndpoints{
 			Priority: uint32(priority),
 			LbEndpoints: []*endpointv3.LbEndpoint{
 				{
 					HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
-						Endpoint: &endpointv3.Endpoint{
-							Address: address,
-						},
+						Endpoint: endpoint,
 					},
 				},
 			},
@@ -539,7 +813,8 @@ func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster,
 				epCert = defaultCerts
 			}
 
-			upstreamtlsContext := createUpstreamTLSContext(epCert, address, clusterDetails.HTTP2BackendEnabled)
+			upstreamtlsContext := createUpstreamTLSContext(epCert, upstreamClientCerts[ep.RawURL], address,
+				upstreamProtocol != constants.UpstreamProtocolHTTP1)
 			marshalledTLSContext, err := anypb.New(upstreamtlsContext)
 			if err != nil {
 				return nil, nil, errors.New("internal Error while marshalling the upstream TLS Context")
@@ -559,16 +834,17 @@ func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster,
 				},
 			}
 			transportSocketMatches = append(transportSocketMatches, transportSocketMatch)
-			localityLbEndpoints.LbEndpoints[0].Metadata = &corev3.Metadata{
-				FilterMetadata: map[string]*structpb.Struct{
-					"envoy.transport_socket_match": {
-						Fields: map[string]*structpb.Value{
-							"lb_id": structpb.NewStringValue(strconv.Itoa(i)),
-						},
-					},
+			endpointMetadata["envoy.transport_socket_match"] = &structpb.Struct{
+				Fields: map[string]*structpb.Value{
+					"lb_id": structpb.NewStringValue(strconv.Itoa(i)),
 				},
 			}
 		}
+		if len(endpointMetadata) > 0 {
+			localityLbEndpoints.LbEndpoints[0].Metadata = &corev3.Metadata{
+				FilterMetadata: endpointMetadata,
+			}
+		}
 		lbEPs = append(lbEPs, localityLbEndpoints)
 
 		// set priority for next endpoint
@@ -578,30 +854,45 @@ func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster,
 	}
 	conf, _ := config.ReadConfigs()
 
-	httpProtocolOptions := &upstreams_http_v3.HttpProtocolOptions{
-		UpstreamProtocolOptions: &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
-			ExplicitHttpConfig: &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
-				ProtocolConfig: &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{
-					HttpProtocolOptions: &corev3.Http1ProtocolOptions{
-						EnableTrailers: config.GetWireLogConfig().LogTrailersEnabled,
-					},
-				},
-			},
+	http1ProtocolOptions := &corev3.Http1ProtocolOptions{
+		EnableTrailers: config.GetWireLogConfig().LogTrailersEnabled,
+	}
+	http2ProtocolOptions := &corev3.Http2ProtocolOptions{
+		HpackTableSize: &wrapperspb.UInt32Value{
+			Value: conf.Envoy.Upstream.HTTP2.HpackTableSize,
 		},
+		MaxConcurrentStreams: &wrapperspb.UInt32Value{
+			Value: conf.Envoy.Upstream.HTTP2.MaxConcurrentStreams,
+		},
+	}
+	if clusterDetails.Config != nil && clusterDetails.Config.PreserveHeaderCase {
+		http1ProtocolOptions.HeaderKeyFormat = createPreserveCaseHeaderKeyFormat()
 	}
 
-	if clusterDetails.HTTP2BackendEnabled {
+	httpProtocolOptions := &upstreams_http_v3.HttpProtocolOptions{
+		CommonHttpProtocolOptions: createUpstreamCommonHTTPProtocolOptions(clusterDetails.Config),
+	}
+	switch upstreamProtocol {
+	case constants.UpstreamProtocolAuto:
+		httpProtocolOptions.UpstreamProtocolOptions = &upstreams_http_v3.HttpProtocolOptions_AutoConfig{
+			AutoConfig: &upstreams_http_v3.HttpProtocolOptions_AutoHttpConfig{
+				HttpProtocolOptions:  http1ProtocolOptions,
+				Http2ProtocolOptions: http2ProtocolOptions,
+			},
+		}
+	case constants.UpstreamProtocolHTTP2:
 		httpProtocolOptions.UpstreamProtocolOptions = &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
 			ExplicitHttpConfig: &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
 				ProtocolConfig: &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
-					Http2ProtocolOptions: &corev3.Http2ProtocolOptions{
-						HpackTableSize: &wrapperspb.UInt32Value{
-							Value: conf.Envoy.Upstream.HTTP2.HpackTableSize,
-						},
-						MaxConcurrentStreams: &wrapperspb.UInt32Value{
-							Value: conf.Envoy.Upstream.HTTP2.MaxConcurrentStreams,
-						},
-					},
+					Http2ProtocolOptions: http2ProtocolOptions,
+				},
+			},
+		}
+	default:
+		httpProtocolOptions.UpstreamProtocolOptions = &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{
+					HttpProtocolOptions: http1ProtocolOptions,
 				},
 			},
 		}
@@ -637,6 +928,17 @@ func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster,
 		cluster.HealthChecks = createHealthCheck()
 	}
 
+	if clusterDetails.Config != nil {
+		if clusterDetails.Config.PerConnectionBufferLimitInBytes > 0 {
+			cluster.PerConnectionBufferLimitBytes = wrapperspb.UInt32(clusterDetails.Config.PerConnectionBufferLimitInBytes)
+		}
+		if clusterDetails.Config.TCPKeepalive != nil {
+			cluster.UpstreamConnectionOptions = &clusterv3.UpstreamConnectionOptions{
+				TcpKeepalive: createTCPKeepalive(clusterDetails.Config.TCPKeepalive),
+			}
+		}
+	}
+
 	if clusterDetails.Config != nil && clusterDetails.Config.CircuitBreakers != nil {
 		config := clusterDetails.Config.CircuitBreakers
 		thresholds := &clusterv3.CircuitBreakers_Thresholds{}
@@ -675,6 +977,69 @@ func processEndpoints(clusterName string, clusterDetails *model.EndpointCluster,
 	return &cluster, addresses, nil
 }
 
+// getUpstreamProtocol returns the upstream protocol of the endpoint cluster. The protocol is derived from the
+// x-wso2-http2-backend-enabled extension, unless it is provided in the endpoint config.
+func getUpstreamProtocol(clusterDetails *model.EndpointCluster) string {
+	if clusterDetails.Config != nil && clusterDetails.Config.UpstreamProtocol != "" {
+		return clusterDetails.Config.UpstreamProtocol
+	}
+	if clusterDetails.HTTP2BackendEnabled {
+		return constants.UpstreamProtocolHTTP2
+	}
+	return constants.UpstreamProtocolHTTP1
+}
+
+// createUpstreamCommonHTTPProtocolOptions creates the connection level protocol options of the endpoint cluster.
+// nil is returned if none of the options are provided, to keep the envoy defaults.
+func createUpstreamCommonHTTPProtocolOptions(endpointConfig *model.EndpointConfig) *corev3.HttpProtocolOptions {
+	if endpointConfig == nil || (endpointConfig.MaxConnectionDurationInSeconds == 0 &&
+		endpointConfig.ConnectionIdleTimeoutInSeconds == 0 && endpointConfig.MaxRequestsPerConnection == 0) {
+		return nil
+	}
+	commonHTTPProtocolOptions := &corev3.HttpProtocolOptions{}
+	if endpointConfig.MaxConnectionDurationInSeconds > 0 {
+		commonHTTPProtocolOptions.MaxConnectionDuration = durationpb.New(
+			time.Duration(endpointConfig.MaxConnectionDurationInSeconds) * time.Second)
+	}
+	if endpointConfig.ConnectionIdleTimeoutInSeconds > 0 {
+		commonHTTPProtocolOptions.IdleTimeout = durationpb.New(
+			time.Duration(endpointConfig.ConnectionIdleTimeoutInSeconds) * time.Second)
+	}
+	if endpointConfig.MaxRequestsPerConnection > 0 {
+		commonHTTPProtocolOptions.MaxRequestsPerConnection = wrapperspb.UInt32(endpointConfig.MaxRequestsPerConnection)
+	}
+	return commonHTTPProtocolOptions
+}
+
+// createTCPKeepalive creates the TCP keepalive settings of the upstream connections.
+func createTCPKeepalive(tcpKeepalive *model.TCPKeepalive) *corev3.TcpKeepalive {
+	keepalive := &corev3.TcpKeepalive{}
+	if tcpKeepalive.Probes > 0 {
+		keepalive.KeepaliveProbes = wrapperspb.UInt32(tcpKeepalive.Probes)
+	}
+	if tcpKeepalive.TimeInSeconds > 0 {
+		keepalive.KeepaliveTime = wrapperspb.UInt32(tcpKeepalive.TimeInSeconds)
+	}
+	if tcpKeepalive.IntervalInSeconds > 0 {
+		keepalive.KeepaliveInterval = wrapperspb.UInt32(tcpKeepalive.IntervalInSeconds)
+	}
+	return keepalive
+}
+
+// createPreserveCaseHeaderKeyFormat creates the HTTP/1 header key format which preserves the case of the
+// header names.
+func createPreserveCaseHeaderKeyFormat() *corev3.Http1ProtocolOptions_HeaderKeyFormat {
+	preserveCaseFormatter, _ := anypb.New(&preserve_casev3.PreserveCaseFormatterConfig{})
+	return &corev3.Http1ProtocolOptions_HeaderKeyFormat{
+		HeaderFormat: &corev3.Http1ProtocolOptions_HeaderKeyFormat_StatefulFormatter{
+			StatefulFormatter: &corev3.TypedExtensionConfig{
+				Name:        preserveCaseHeaderFormatterName,
+				TypedConfig: preserveCaseFormatter,
+			},
+		},
+	}
+}
+
 func createHealthCheck() []*corev3.HealthCheck {
 	conf, _ := config.ReadConfigs()
 	return []*corev3.HealthCheck{
@@ -689,14 +1054,14 @@ func createHealthCheck() []*corev3.HealthCheck {
 	}
 }
 
-func createUpstreamTLSContext(upstreamCerts []byte, address *corev3.Address, hTTP2BackendEnabled bool) *tlsv3.UpstreamTlsContext {
+func createUpstreamTLSContext(upstreamCerts []byte, clientCertSecretName string, address *corev3.Address,
+	hTTP2BackendEnabled bool) *tlsv3.UpstreamTlsContext {
 	conf, errReadConfig := config.ReadConfigs()
 	//TODO: (VirajSalaka) Error Handling
 	if errReadConfig != nil {
 		logger.LoggerOasparser.Fatal("Error loading configuration. ", errReadConfig)
 		return nil
 	}
-	tlsCert := generateTLSCert(conf.Envoy.KeyStore.KeyPath, conf.Envoy.KeyStore.CertPath)
 	// Convert the cipher string to a string array
 	ciphersArray := strings.Split(conf.Envoy.Upstream.TLS.Ciphers, ",")
 	for i := range ciphersArray {
@@ -710,10 +1075,29 @@ func createUpstreamTLSContext(upstreamCerts []byte, address *corev3.Address, hTT
 				TlsMaximumProtocolVersion: createTLSProtocolVersion(conf.Envoy.Upstream.TLS.MaximumProtocolVersion),
 				CipherSuites:              ciphersArray,
 			},
-			TlsCertificates: []*tlsv3.TlsCertificate{tlsCert},
 		},
 	}
 
+	// The client certificate of the endpoint, or else the default client certificate, is delivered via SDS
+	// to avoid embedding the private key in the cluster configuration.
+	if clientCertSecretName == "" && isDefaultUpstreamClientCertConfigured(conf) {
+		clientCertSecretName = defaultUpstreamClientCertSecretName
+	}
+	if clientCertSecretName != "" {
+		upstreamTLSContext.CommonTlsContext.TlsCertificateSdsSecretConfigs = []*tlsv3.SdsSecretConfig{{
+			Name: clientCertSecretName,
+			SdsConfig: &corev3.ConfigSource{
+				ConfigSourceSpecifier: &corev3.ConfigSource_Ads{
+					Ads: &corev3.AggregatedConfigSource{},
+				},
+				ResourceApiVersion: corev3.ApiVersion_V3,
+			},
+		}}
+	} else {
+		tlsCert := generateTLSCert(conf.Envoy.KeyStore.KeyPath, conf.Envoy.KeyStore.CertPath)
+		upstreamTLSContext.CommonTlsContext.TlsCertificates = []*tlsv3.TlsCertificate{tlsCert}
+	}
+
 	if hTTP2BackendEnabled {
 		upstreamTLSContext.CommonTlsContext.AlpnProtocols = []string{"h2", "http/1.1"}
 	}
@@ -767,6 +1151,72 @@ func createUpstreamTLSContext(upstreamCerts []byte, address *corev3.Address, hTT
 	return upstreamTLSContext
 }
 
+func isDefaultUpstreamClientCertConfigured(conf *config.Config) bool {
+	return conf.Envoy.Upstream.TLS.ClientCertPath != "" && conf.Envoy.Upstream.TLS.ClientKeyPath != ""
+}
+
+// CreateUpstreamClientCertSecret creates the SDS secret of a client certificate presented to the endpoints which
+// require mutual TLS. The secret name is derived from the certificate and the key, hence the endpoints with the
+// same client certificate share the same secret.
+func CreateUpstreamClientCertSecret(clientCert []byte, clientKey []byte) *tlsv3.Secret {
+	hash := sha256.Sum256(append(append([]byte{}, clientCert...), clientKey...))
+	return &tlsv3.Secret{
+		Name: upstreamClientCertSecretNamePrefix + hex.EncodeToString(hash[:]),
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineBytes{InlineBytes: clientCert},
+				},
+				PrivateKey: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineBytes{InlineBytes: clientKey},
+				},
+			},
+		},
+	}
+}
+
+// CreateDefaultUpstreamClientCertSecret creates the SDS secret of the default client certificate presented to the
+// endpoints which require mutual TLS. nil is returned if the default client certificate is not configured.
+func CreateDefaultUpstreamClientCertSecret() *tlsv3.Secret {
+	conf, _ := config.ReadConfigs()
+	if !isDefaultUpstreamClientCertConfigured(conf) {
+		return nil
+	}
+	return &tlsv3.Secret{
+		Name: defaultUpstreamClientCertSecretName,
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: generateTLSCert(conf.Envoy.Upstream.TLS.ClientKeyPath, conf.Envoy.Upstream.TLS.ClientCertPath),
+		},
+	}
+}
+
+// IsDefaultUpstreamClientCertSecret returns true if the secret name is the name of the default client certificate.
+func IsDefaultUpstreamClientCertSecret(secretName string) bool {
+	return secretName == defaultUpstreamClientCertSecretName
+}
+
+// GetClusterSecretNames returns the names of the SDS secrets referred by the transport sockets of the cluster.
+func GetClusterSecretNames(cluster *clusterv3.Cluster) []string {
+	var secretNames []string
+	transportSockets := []*corev3.TransportSocket{cluster.GetTransportSocket()}
+	for _, transportSocketMatch := range cluster.GetTransportSocketMatches() {
+		transportSockets = append(transportSockets, transportSocketMatch.GetTransportSocket())
+	}
+	for _, transportSocket := range transportSockets {
+		if transportSocket.GetTypedConfig() == nil {
+			continue
+		}
+		upstreamTLSContext := &tlsv3.UpstreamTlsContext{}
+		if err := transportSocket.GetTypedConfig().UnmarshalTo(upstreamTLSContext); err != nil {
+			continue
+		}
+		for _, sdsSecretConfig := range upstreamTLSContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs() {
+			secretNames = append(secretNames, sdsSecretConfig.GetName())
+		}
+	}
+	return secretNames
+}
+
 func createTLSProtocolVersion(tlsVersion string) tlsv3.TlsParameters_TlsProtocol {
 	switch tlsVersion {
 	case "TLS1_0":
@@ -876,6 +1326,26 @@ func createRoute(params *routeCreateParams) *routev3.Route {
 		}
 	}
 
+	if params.routingClusterName != "" {
+		headerMatchers, queryParamMatchers, metadataMatchers := getContentRoutingMatchers(basePath,
+			params.routingConditions)
+		match.Headers = append(match.Headers, headerMatchers...)
+		match.QueryParameters = queryParamMatchers
+		match.DynamicMetadata = metadataMatchers
+		// sandbox requests are not content routed, hence they should be served by the sandbox route or the prod route.
+		if sandClusterName != "" {
+			match.Headers = append(match.Headers, &routev3.HeaderMatcher{
+				Name: clusterHeaderName,
+				HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+					StringMatch: &envoy_type_matcherv3.StringMatcher{
+						MatchPattern: &envoy_type_matcherv3.StringMatcher_Exact{Exact: sandClusterName},
+					},
+				},
+				InvertMatch: true,
+			})
+		}
+	}
+
 	hostRewriteSpecifier := &routev3.RouteAction_AutoHostRewrite{
 		AutoHostRewrite: &wrapperspb.BoolValue{
 			Value: true,
@@ -908,6 +1378,15 @@ func createRoute(params *routeCreateParams) *routev3.Route {
 	// to validate the key type component in the token.
 	contextExtensions[prodClusterNameContextExtension] = prodClusterName
 	contextExtensions[sandClusterNameContextExtension] = sandClusterName
+	if params.timeoutConfig != nil {
+		contextExtensions[routeTimeoutContextExtension] = "true"
+	}
+	if params.retryPolicy != nil {
+		contextExtensions[routeRetryPolicyContextExtension] = "true"
+	}
+	if len(params.routingClaims) > 0 {
+		contextExtensions[routingClaimsContextExtension] = strings.Join(params.routingClaims, ",")
+	}
 
 	extAuthPerFilterConfig := extAuthService.ExtAuthzPerRoute{
 		Override: &extAuthService.ExtAuthzPerRoute_CheckSettings{
@@ -918,6 +1397,14 @@ func createRoute(params *routeCreateParams) *routev3.Route {
 			},
 		},
 	}
+	// The tokens are validated at the router without calling the enforcer, only for the production routes.
+	routerJWTRequirement := params.routerJWTRequirement
+	if params.isSandbox || params.routingClusterName != "" {
+		routerJWTRequirement = nil
+	}
+	if routerJWTRequirement != nil {
+		extAuthPerFilterConfig.Override = &extAuthService.ExtAuthzPerRoute_Disabled{Disabled: true}
+	}
 
 	b := proto.NewBuffer(nil)
 	b.SetDeterministic(true)
@@ -987,6 +1474,19 @@ end`
 		}
 	}
 
+	if params.staticMutations != nil && len(params.staticMutations.QueryMutations) > 0 {
+		luaPerFilterConfig = lua.LuaPerRoute{
+			Override: &lua.LuaPerRoute_SourceCode{
+				SourceCode: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: getQueryMutationLuaScript(luaPerFilterConfig.GetSourceCode().GetInlineString(),
+							params.staticMutations.QueryMutations),
+					},
+				},
+			},
+		}
+	}
+
 	luaMarshelled := proto.NewBuffer(nil)
 	luaMarshelled.SetDeterministic(true)
 	_ = luaMarshelled.Marshal(&luaPerFilterConfig)
@@ -996,6 +1496,22 @@ end`
 		Value:   luaMarshelled.Bytes(),
 	}
 
+	perFilterConfig := map[string]*any.Any{
+		wellknown.HTTPExternalAuthorization: extAuthzFilter,
+		wellknown.Lua:                       luaFilter,
+		wellknown.Buffer:                    getBufferPerRouteConfig(params.maxRequestBodySize),
+	}
+	if len(params.ipFilters) > 0 || routerJWTRequirement != nil {
+		perFilterConfig[wellknown.HTTPRoleBasedAccessControl] = getRBACPerRouteConfig(params.ipFilters, resourceMethods,
+			routerJWTRequirement)
+	}
+	if routerJWTRequirement != nil {
+		perFilterConfig[jwtAuthnFilterName] = getJwtAuthnPerRouteConfig(routerJWTRequirement.name)
+	}
+	if params.faultInjection != nil {
+		perFilterConfig[wellknown.Fault] = getFaultPerRouteConfig(params.faultInjection)
+	}
+
 	pathRegex := basePath
 	substitutionString := endpointBasepath
 	if params.rewritePath != "" {
@@ -1049,6 +1565,22 @@ end`
 	}
 	action.Route.ClusterSpecifier = headerBasedClusterSpecifier
 	logger.LoggerOasparser.Debug("added header based cluster")
+	if params.routingClusterName != "" {
+		// The route could be reselected after the ext_authz filter (ex: JWT claim based routes), hence the
+		// cluster is not resolved from the cluster header set by the enforcer for the initially selected route.
+		action.Route.ClusterSpecifier = &routev3.RouteAction_Cluster{
+			Cluster: params.routingClusterName,
+		}
+	}
+	if routerJWTRequirement != nil {
+		// The cluster header and the endpoint timeout are not set by the enforcer, as it is not called.
+		action.Route.ClusterSpecifier = &routev3.RouteAction_Cluster{
+			Cluster: prodClusterName,
+		}
+		if prodRouteConfig != nil && prodRouteConfig.TimeoutInMillis > 0 {
+			action.Route.Timeout = durationpb.New(time.Duration(prodRouteConfig.TimeoutInMillis) * time.Millisecond)
+		}
+	}
 
 	if (prodRouteConfig != nil && prodRouteConfig.RetryConfig != nil) ||
 		(sandRouteConfig != nil && sandRouteConfig.RetryConfig != nil) {
@@ -1072,10 +1604,37 @@ end`
 		action.Route.RetryPolicy = commonRetryPolicy
 	}
 
+	// The operation level timeouts and retry policy override the endpoint level configurations, which are applied
+	// by the enforcer via headers.
+	if params.timeoutConfig != nil {
+		if params.timeoutConfig.TimeoutInMillis > 0 {
+			action.Route.Timeout = durationpb.New(time.Duration(params.timeoutConfig.TimeoutInMillis) * time.Millisecond)
+		}
+		if params.timeoutConfig.IdleTimeoutInMillis > 0 {
+			action.Route.IdleTimeout = durationpb.New(time.Duration(params.timeoutConfig.IdleTimeoutInMillis) *
+				time.Millisecond)
+		}
+	}
+	if params.retryPolicy != nil {
+		action.Route.RetryPolicy = getRetryPolicy(params.retryPolicy)
+	}
+
 	if corsPolicy != nil {
 		action.Route.Cors = corsPolicy
 	}
 
+	if params.mirrorPolicy != nil {
+		action.Route.RequestMirrorPolicies = []*routev3.RouteAction_RequestMirrorPolicy{{
+			Cluster: params.mirrorPolicy.clusterName,
+			RuntimeFraction: &corev3.RuntimeFractionalPercent{
+				DefaultValue: &envoy_typev3.FractionalPercent{
+					Numerator:   uint32(params.mirrorPolicy.percentage * 10000),
+					Denominator: envoy_typev3.FractionalPercent_MILLION,
+				},
+			},
+		}}
+	}
+
 	// remove 'x-wso2-cluster-header' and `x-envoy-expected-rq-timeout-ms` headers from the request from router to backend
 	requestHeadersToRemove = append(requestHeadersToRemove, clusterHeaderName)
 	requestHeadersToRemove = append(requestHeadersToRemove, "x-envoy-expected-rq-timeout-ms")
@@ -1083,23 +1642,391 @@ end`
 	// remove the 'x-envoy-upstream-service-time' from the response.
 	responseHeadersToRemove = append(responseHeadersToRemove, upstreamServiceTimeHeader)
 
+	var requestHeadersToAdd, responseHeadersToAdd []*corev3.HeaderValueOption
+	if params.staticMutations != nil {
+		requestHeadersToAdd = getHeadersToAdd(params.staticMutations.RequestHeadersToAdd)
+		requestHeadersToRemove = append(requestHeadersToRemove, params.staticMutations.RequestHeadersToRemove...)
+		responseHeadersToAdd = getHeadersToAdd(params.staticMutations.ResponseHeadersToAdd)
+		responseHeadersToRemove = append(responseHeadersToRemove, params.staticMutations.ResponseHeadersToRemove...)
+	}
+
 	logger.LoggerOasparser.Debug("adding route ", resourcePath)
 	router = routev3.Route{
-		Name:      xWso2Basepath, //Categorize routes with same base path
-		Match:     match,
-		Action:    action,
-		Metadata:  nil,
-		Decorator: decorator,
-		TypedPerFilterConfig: map[string]*any.Any{
-			wellknown.HTTPExternalAuthorization: extAuthzFilter,
-			wellknown.Lua:                       luaFilter,
-		},
+		Name:                    xWso2Basepath, //Categorize routes with same base path
+		Match:                   match,
+		Action:                  action,
+		Metadata:                nil,
+		Decorator:               decorator,
+		TypedPerFilterConfig:    perFilterConfig,
+		RequestHeadersToAdd:     requestHeadersToAdd,
+		ResponseHeadersToAdd:    responseHeadersToAdd,
 		ResponseHeadersToRemove: responseHeadersToRemove,
 		RequestHeadersToRemove:  requestHeadersToRemove,
 	}
 	return &router
 }
 
+// getRetryPolicy generates the route retry policy of an operation level retry policy.
+func getRetryPolicy(retryPolicy *model.RetryPolicy) *routev3.RetryPolicy {
+	retryOn := append([]string{}, retryPolicy.RetryOn...)
+	if len(retryPolicy.StatusCodes) > 0 {
+		retryOn = append(retryOn, retryPolicyRetriableStatusCodes)
+	}
+	routeRetryPolicy := &routev3.RetryPolicy{
+		RetryOn:                       strings.Join(retryOn, ","),
+		NumRetries:                    &wrapperspb.UInt32Value{Value: uint32(retryPolicy.Count)},
+		RetriableStatusCodes:          retryPolicy.StatusCodes,
+		HostSelectionRetryMaxAttempts: retryPolicy.HostSelectionRetryMaxAttempts,
+	}
+	if retryPolicy.PerTryTimeoutInMillis > 0 {
+		routeRetryPolicy.PerTryTimeout = durationpb.New(time.Duration(retryPolicy.PerTryTimeoutInMillis) *
+			time.Millisecond)
+	}
+	if retryPolicy.BaseIntervalInMillis > 0 {
+		routeRetryPolicy.RetryBackOff = &routev3.RetryPolicy_RetryBackOff{
+			BaseInterval: durationpb.New(time.Duration(retryPolicy.BaseIntervalInMillis) * time.Millisecond),
+		}
+		if retryPolicy.MaxIntervalInMillis > 0 {
+			routeRetryPolicy.RetryBackOff.MaxInterval = durationpb.New(time.Duration(retryPolicy.MaxIntervalInMillis) *
+				time.Millisecond)
+		}
+	}
+	if retryPolicy.HostSelectionRetryMaxAttempts > 0 {
+		previousHosts, _ := anypb.New(&previous_hosts.PreviousHostsPredicate{})
+		routeRetryPolicy.RetryHostPredicate = []*routev3.RetryPolicy_RetryHostPredicate{{
+			Name: previousHostsRetryPredicate,
+			ConfigType: &routev3.RetryPolicy_RetryHostPredicate_TypedConfig{
+				TypedConfig: previousHosts,
+			},
+		}}
+	}
+	return routeRetryPolicy
+}
+
+// getHeadersToAdd returns the header value options which set the headers of the static mutations. The values are
+// escaped as the router treats the values starting with % as command operators.
+func getHeadersToAdd(headers []model.NameValuePair) []*corev3.HeaderValueOption {
+	var headersToAdd []*corev3.HeaderValueOption
+	for _, header := range headers {
+		headersToAdd = append(headersToAdd, &corev3.HeaderValueOption{
+			Header: &corev3.HeaderValue{
+				Key:   header.Name,
+				Value: strings.ReplaceAll(header.Value, "%", "%%"),
+			},
+			Append: &wrapperspb.BoolValue{Value: false},
+		})
+	}
+	return headersToAdd
+}
+
+// getQueryMutationLuaScript wraps the envoy_on_request function of the Lua script to apply the static query
+// parameter mutations, since the query parameters cannot be mutated with the route configuration.
+func getQueryMutationLuaScript(luaScript string, queryMutations []model.QueryMutation) string {
+	var queryMutationsTable strings.Builder
+	for _, queryMutation := range queryMutations {
+		queryMutationsTable.WriteString(fmt.Sprintf("\n\t{action = %q, name = %q, value = %q},", queryMutation.Action,
+			url.QueryEscape(queryMutation.Name), url.QueryEscape(queryMutation.Value)))
+	}
+	return luaScript + strings.Replace(queryMutationLuaTemplate, "{{ .QueryMutations }}",
+		queryMutationsTable.String(), 1)
+}
+
+// getRoutingClaims returns the sorted JWT claims matched by the content based routing rules. The enforcer adds
+// the values of these claims to the ext_authz dynamic metadata, which is matched by the routes of the rules.
+func getRoutingClaims(routingConfig *model.RoutingConfig) []string {
+	if routingConfig == nil {
+		return nil
+	}
+	var claims []string
+	for _, rule := range routingConfig.Rules {
+		for _, condition := range rule.Conditions {
+			if condition.Type == constants.RoutingConditionClaim && !model.ArrayContains(claims, condition.Name) {
+				claims = append(claims, condition.Name)
+			}
+		}
+	}
+	sort.Strings(claims)
+	return claims
+}
+
+// getContentRoutingMatchers generates the route matchers for the conditions of a content based routing rule.
+// JWT claims are matched against the ext_authz dynamic metadata as the route is reselected after the
+// ext_authz filter. The path segments are matched after the given base path (regex) of the route, hence the
+// same segment is matched with and without the version of a default version API.
+func getContentRoutingMatchers(basePath string, conditions []model.RoutingCondition) ([]*routev3.HeaderMatcher,
+	[]*routev3.QueryParameterMatcher, []*envoy_type_matcherv3.MetadataMatcher) {
+	var (
+		headerMatchers     []*routev3.HeaderMatcher
+		queryParamMatchers []*routev3.QueryParameterMatcher
+		metadataMatchers   []*envoy_type_matcherv3.MetadataMatcher
+	)
+	for _, condition := range conditions {
+		switch condition.Type {
+		case constants.RoutingConditionHeader:
+			headerMatchers = append(headerMatchers, &routev3.HeaderMatcher{
+				Name: strings.ToLower(condition.Name),
+				HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+					StringMatch: getRoutingConditionStringMatcher(condition),
+				},
+			})
+		case constants.RoutingConditionQuery:
+			queryParamMatchers = append(queryParamMatchers, &routev3.QueryParameterMatcher{
+				Name: condition.Name,
+				QueryParameterMatchSpecifier: &routev3.QueryParameterMatcher_StringMatch{
+					StringMatch: getRoutingConditionStringMatcher(condition),
+				},
+			})
+		case constants.RoutingConditionClaim:
+			metadataMatchers = append(metadataMatchers, &envoy_type_matcherv3.MetadataMatcher{
+				Filter: extAuthzFilterName,
+				Path: []*envoy_type_matcherv3.MetadataMatcher_PathSegment{{
+					Segment: &envoy_type_matcherv3.MetadataMatcher_PathSegment_Key{
+						Key: claimMetadataKeyPrefix + condition.Name,
+					},
+				}},
+				Value: &envoy_type_matcherv3.ValueMatcher{
+					MatchPattern: &envoy_type_matcherv3.ValueMatcher_StringMatch{
+						StringMatch: getRoutingConditionStringMatcher(condition),
+					},
+				},
+			})
+		case constants.RoutingConditionPath:
+			segmentRegex := regexp.QuoteMeta(condition.Value)
+			if condition.Regex != "" {
+				segmentRegex = "(" + condition.Regex + ")"
+			}
+			headerMatchers = append(headerMatchers, &routev3.HeaderMatcher{
+				Name: pathHeaderName,
+				HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+					StringMatch: &envoy_type_matcherv3.StringMatcher{
+						MatchPattern: &envoy_type_matcherv3.StringMatcher_SafeRegex{
+							SafeRegex: &envoy_type_matcherv3.RegexMatcher{
+								EngineType: &envoy_type_matcherv3.RegexMatcher_GoogleRe2{
+									GoogleRe2: &envoy_type_matcherv3.RegexMatcher_GoogleRE2{},
+								},
+								Regex: fmt.Sprintf("^%s(/[^/?]*){%d}/%s([/?].*)?$", basePath, condition.Index,
+									segmentRegex),
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+	return headerMatchers, queryParamMatchers, metadataMatchers
+}
+
+// getRoutingConditionStringMatcher returns an exact matcher for the value or a regex matcher for the
+// regex of the routing condition.
+func getRoutingConditionStringMatcher(condition model.RoutingCondition) *envoy_type_matcherv3.StringMatcher {
+	if condition.Regex != "" {
+		return &envoy_type_matcherv3.StringMatcher{
+			MatchPattern: &envoy_type_matcherv3.StringMatcher_SafeRegex{
+				SafeRegex: &envoy_type_matcherv3.RegexMatcher{
+					EngineType: &envoy_type_matcherv3.RegexMatcher_GoogleRe2{
+						GoogleRe2: &envoy_type_matcherv3.RegexMatcher_GoogleRE2{},
+					},
+					Regex: condition.Regex,
+				},
+			},
+		}
+	}
+	return &envoy_type_matcherv3.StringMatcher{
+		MatchPattern: &envoy_type_matcherv3.StringMatcher_Exact{Exact: condition.Value},
+	}
+}
+
+// getRBACPerRouteConfig generates the RBAC per route configuration which denies the requests from the client IPs
+// not permitted by the ip filters of the operations. If all the operations of the route share the same ip filter,
+// the filter is applied irrespective of the method. The client IP is resolved by the router considering the
+// x-forwarded-for header and the number of trusted hops. If the tokens of the route are validated at the router,
+// the requests of the tokens not permitted by the router JWT validation requirement are denied as well.
+func getRBACPerRouteConfig(ipFilters map[string]*model.IPFilterConfig, resourceMethods []string,
+	routerJWTRequirement *routerJWTRequirement) *any.Any {
+	policies := make(map[string]*rbacconfigv3.Policy)
+	var commonIPFilter *model.IPFilterConfig
+	isCommonIPFilter := len(ipFilters) == len(resourceMethods)
+	for _, ipFilter := range ipFilters {
+		if commonIPFilter == nil {
+			commonIPFilter = ipFilter
+		} else if !reflect.DeepEqual(commonIPFilter, ipFilter) {
+			isCommonIPFilter = false
+			break
+		}
+	}
+
+	if commonIPFilter != nil && isCommonIPFilter {
+		policies["ip-filter"] = &rbacconfigv3.Policy{
+			Permissions: []*rbacconfigv3.Permission{{
+				Rule: &rbacconfigv3.Permission_Any{Any: true},
+			}},
+			Principals: []*rbacconfigv3.Principal{getIPFilterPrincipal(commonIPFilter)},
+		}
+	} else {
+		for method, ipFilter := range ipFilters {
+			policies["ip-filter-"+method] = &rbacconfigv3.Policy{
+				Permissions: []*rbacconfigv3.Permission{{
+					Rule: &rbacconfigv3.Permission_Header{
+						Header: &routev3.HeaderMatcher{
+							Name: httpMethodHeader,
+							HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+								StringMatch: &envoy_type_matcherv3.StringMatcher{
+									MatchPattern: &envoy_type_matcherv3.StringMatcher_Exact{Exact: method},
+								},
+							},
+						},
+					},
+				}},
+				Principals: []*rbacconfigv3.Principal{getIPFilterPrincipal(ipFilter)},
+			}
+		}
+	}
+
+	if routerJWTRequirement != nil {
+		for policyName, policy := range getRouterJWTPolicies(routerJWTRequirement) {
+			policies[policyName] = policy
+		}
+	}
+
+	rbacPerRoute := &rbacv3.RBACPerRoute{
+		Rbac: &rbacv3.RBAC{
+			Rules: &rbacconfigv3.RBAC{
+				Action:   rbacconfigv3.RBAC_DENY,
+				Policies: policies,
+			},
+		},
+	}
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	_ = b.Marshal(rbacPerRoute)
+	return &any.Any{
+		TypeUrl: rbacPerRouteName,
+		Value:   b.Bytes(),
+	}
+}
+
+// getFaultPerRouteConfig generates the fault filter per route configuration which delays and/or aborts the
+// given percentage of the requests. If the header name is provided, the faults are injected only to the
+// requests having the header.
+func getFaultPerRouteConfig(faultInjection *model.FaultInjectionConfig) *any.Any {
+	fault := &faultv3.HTTPFault{}
+	if faultInjection.DelayDuration > 0 {
+		fault.Delay = &faultcommonv3.FaultDelay{
+			FaultDelaySecifier: &faultcommonv3.FaultDelay_FixedDelay{
+				FixedDelay: durationpb.New(time.Duration(faultInjection.DelayDuration) * time.Millisecond),
+			},
+			Percentage: getFaultPercentage(faultInjection.DelayPercentage),
+		}
+	}
+	if faultInjection.AbortStatusCode > 0 {
+		fault.Abort = &faultv3.FaultAbort{
+			ErrorType:  &faultv3.FaultAbort_HttpStatus{HttpStatus: faultInjection.AbortStatusCode},
+			Percentage: getFaultPercentage(faultInjection.AbortPercentage),
+		}
+	}
+	if faultInjection.HeaderName != "" {
+		headerMatcher := &routev3.HeaderMatcher{
+			Name:                 strings.ToLower(faultInjection.HeaderName),
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_PresentMatch{PresentMatch: true},
+		}
+		if faultInjection.HeaderValue != "" {
+			headerMatcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_StringMatch{
+				StringMatch: &envoy_type_matcherv3.StringMatcher{
+					MatchPattern: &envoy_type_matcherv3.StringMatcher_Exact{Exact: faultInjection.HeaderValue},
+				},
+			}
+		}
+		fault.Headers = []*routev3.HeaderMatcher{headerMatcher}
+	}
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	_ = b.Marshal(fault)
+	return &any.Any{
+		TypeUrl: faultPerRouteName,
+		Value:   b.Bytes(),
+	}
+}
+
+// getBufferPerRouteConfig generates the buffer filter per route configuration which rejects the requests having
+// a body larger than the given size with 413 Payload Too Large. The buffer filter is disabled for the route if the
+// size is not limited, as the requests could be streamed to the backend.
+func getBufferPerRouteConfig(maxRequestBytes uint32) *any.Any {
+	bufferPerRoute := &bufferv3.BufferPerRoute{
+		Override: &bufferv3.BufferPerRoute_Disabled{Disabled: true},
+	}
+	if maxRequestBytes > 0 {
+		bufferPerRoute.Override = &bufferv3.BufferPerRoute_Buffer{
+			Buffer: &bufferv3.Buffer{
+				MaxRequestBytes: wrapperspb.UInt32(maxRequestBytes),
+			},
+		}
+	}
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	_ = b.Marshal(bufferPerRoute)
+	return &any.Any{
+		TypeUrl: bufferPerRouteName,
+		Value:   b.Bytes(),
+	}
+}
+
+// getFaultPercentage returns the fraction of the requests the fault is injected to. The fault is injected
+// to all the requests if the percentage is not provided.
+func getFaultPercentage(percentage *float64) *envoy_typev3.FractionalPercent {
+	numerator := uint32(1000000)
+	if percentage != nil {
+		numerator = uint32(*percentage * 10000)
+	}
+	return &envoy_typev3.FractionalPercent{
+		Numerator:   numerator,
+		Denominator: envoy_typev3.FractionalPercent_MILLION,
+	}
+}
+
+// getIPFilterPrincipal returns the principal matching the clients which are in the deny list or
+// not in the allow list (if the allow list is available).
+func getIPFilterPrincipal(ipFilter *model.IPFilterConfig) *rbacconfigv3.Principal {
+	var deniedPrincipals []*rbacconfigv3.Principal
+	for _, cidr := range ipFilter.Deny {
+		deniedPrincipals = append(deniedPrincipals, getRemoteIPPrincipal(cidr))
+	}
+	if len(ipFilter.Allow) > 0 {
+		var allowedPrincipals []*rbacconfigv3.Principal
+		for _, cidr := range ipFilter.Allow {
+			allowedPrincipals = append(allowedPrincipals, getRemoteIPPrincipal(cidr))
+		}
+		deniedPrincipals = append(deniedPrincipals, &rbacconfigv3.Principal{
+			Identifier: &rbacconfigv3.Principal_NotId{
+				NotId: &rbacconfigv3.Principal{
+					Identifier: &rbacconfigv3.Principal_OrIds{
+						OrIds: &rbacconfigv3.Principal_Set{Ids: allowedPrincipals},
+					},
+				},
+			},
+		})
+	}
+	return &rbacconfigv3.Principal{
+		Identifier: &rbacconfigv3.Principal_OrIds{
+			OrIds: &rbacconfigv3.Principal_Set{Ids: deniedPrincipals},
+		},
+	}
+}
+
+// getRemoteIPPrincipal returns the principal for the given CIDR range. The CIDR range is already
+// validated when the ip filter is parsed.
+func getRemoteIPPrincipal(cidr string) *rbacconfigv3.Principal {
+	cidrParts := strings.Split(cidr, "/")
+	prefixLen, _ := strconv.ParseUint(cidrParts[1], 10, 32)
+	return &rbacconfigv3.Principal{
+		Identifier: &rbacconfigv3.Principal_RemoteIp{
+			RemoteIp: &corev3.CidrRange{
+				AddressPrefix: cidrParts[0],
+				PrefixLen:     wrapperspb.UInt32(uint32(prefixLen)),
+			},
+		},
+	}
+}
+
 func getInlineLuaScript(requestInterceptor map[string]model.InterceptEndpoint, responseInterceptor map[string]model.InterceptEndpoint,
 	requestContext *interceptor.InvocationContext) string {
 
@@ -1218,6 +2145,7 @@ func CreateTokenRoute() *routev3.Route {
 		Decorator: decorator,
 		TypedPerFilterConfig: map[string]*any.Any{
 			wellknown.HTTPExternalAuthorization: filter,
+			wellknown.Buffer:                    getBufferPerRouteConfig(0),
 		},
 	}
 	return &router
@@ -1273,6 +2201,7 @@ func CreateHealthEndpoint() *routev3.Route {
 		Decorator: decorator,
 		TypedPerFilterConfig: map[string]*any.Any{
 			wellknown.HTTPExternalAuthorization: filter,
+			wellknown.Buffer:                    getBufferPerRouteConfig(0),
 		},
 	}
 	return &router
@@ -1328,6 +2257,7 @@ func CreateReadyEndpoint() *routev3.Route {
 		Decorator: decorator,
 		TypedPerFilterConfig: map[string]*any.Any{
 			wellknown.HTTPExternalAuthorization: filter,
+			wellknown.Buffer:                    getBufferPerRouteConfig(0),
 		},
 	}
 	return &router
@@ -1434,8 +2364,14 @@ func getCorsPolicy(corsConfig *model.CorsConfig) *routev3.CorsPolicy {
 		return nil
 	}
 
-	stringMatcherArray := []*envoy_type_matcherv3.StringMatcher{}
+	originRegexes := []string{}
 	for _, origin := range corsConfig.AccessControlAllowOrigins {
+		originRegexes = append(originRegexes, getOriginRegex(origin))
+	}
+	originRegexes = append(originRegexes, corsConfig.AccessControlAllowOriginRegexes...)
+
+	stringMatcherArray := []*envoy_type_matcherv3.StringMatcher{}
+	for _, originRegex := range originRegexes {
 		regexMatcher := &envoy_type_matcherv3.StringMatcher{
 			MatchPattern: &envoy_type_matcherv3.StringMatcher_SafeRegex{
 				SafeRegex: &envoy_type_matcherv3.RegexMatcher{
@@ -1444,8 +2380,7 @@ func getCorsPolicy(corsConfig *model.CorsConfig) *routev3.CorsPolicy {
 							MaxProgramSize: nil,
 						},
 					},
-					// adds escape character when necessary
-					Regex: regexp.QuoteMeta(origin),
+					Regex: originRegex,
 				},
 			},
 		}
@@ -1470,9 +2405,37 @@ func getCorsPolicy(corsConfig *model.CorsConfig) *routev3.CorsPolicy {
 	if len(corsConfig.AccessControlExposeHeaders) > 0 {
 		corsPolicy.ExposeHeaders = strings.Join(corsConfig.AccessControlExposeHeaders, ", ")
 	}
+	if corsConfig.AccessControlMaxAge > 0 {
+		corsPolicy.MaxAge = strconv.Itoa(corsConfig.AccessControlMaxAge)
+	}
 	return corsPolicy
 }
 
+// getOriginRegex converts the allowed origin to a regex. Each wildcard "*" within the origin matches a part of the
+// host or the port (eg: https://*.example.com matches https://foo.example.com and https://foo.bar.example.com).
+// The origin "*", which allows all the origins, is matched as it is by the CORS filter.
+func getOriginRegex(origin string) string {
+	if origin == "*" || !strings.Contains(origin, "*") {
+		// adds escape character when necessary
+		return regexp.QuoteMeta(origin)
+	}
+	parts := strings.Split(origin, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return strings.Join(parts, originWildcardRegex)
+}
+
+// hasRetryConfig returns true if any of the endpoint clusters has a retry configuration.
+func hasRetryConfig(endpointClusters ...*model.EndpointCluster) bool {
+	for _, endpointCluster := range endpointClusters {
+		if endpointCluster != nil && endpointCluster.Config != nil && endpointCluster.Config.RetryConfig != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func genRouteCreateParams(swagger *model.MgwSwagger, resource *model.Resource, vHost, endpointBasePath string,
 	prodClusterName string, sandClusterName string, requestInterceptor map[string]model.InterceptEndpoint,
 	responseInterceptor map[string]model.InterceptEndpoint, organizationID string, isSandbox bool) *routeCreateParams {
@@ -1495,6 +2458,7 @@ func genRouteCreateParams(swagger *model.MgwSwagger, resource *model.Resource, v
 		rewritePath:                  "",
 		rewriteMethod:                false,
 		passRequestPayloadToEnforcer: swagger.GetXWso2RequestBodyPass(),
+		maxRequestBodySize:           swagger.GetXWso2MaxRequestBodySize(),
 		isDefaultVersion:             swagger.IsDefaultVersion,
 		isSandbox:                    isSandbox,
 	}
@@ -1503,6 +2467,15 @@ func genRouteCreateParams(swagger *model.MgwSwagger, resource *model.Resource, v
 		params.resourceMethods = resource.GetMethodList()
 		params.resourcePathParam = resource.GetPath()
 		params.rewritePath, params.rewriteMethod = resource.GetRewriteResource()
+		params.ipFilters = make(map[string]*model.IPFilterConfig)
+		for _, operation := range resource.GetMethod() {
+			// operation level ip filter overrides the API level ip filter
+			if ipFilter := operation.GetIPFilter(); ipFilter != nil {
+				params.ipFilters[operation.GetMethod()] = ipFilter
+			} else if swagger.GetXWso2IPFilter() != nil {
+				params.ipFilters[operation.GetMethod()] = swagger.GetXWso2IPFilter()
+			}
+		}
 	}
 
 	if swagger.GetProdEndpoints() != nil {
@@ -19,9 +19,18 @@ package envoyconf_test
 import (
 	"encoding/json"
 	"io/ioutil"
+	"regexp"
 	"strings"
 	"testing"
 
+	rbacconfigv3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	extAuthService "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	jwtauthnv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	rbacv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
@@ -241,6 +250,205 @@ func TestCreateRoutesWithClustersForEndpointRef(t *testing.T) {
 		"The route regex for the two routes should not be the same")
 }
 
+func TestCreateRoutesWithClustersForContentBasedRouting(t *testing.T) {
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_content_routing.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	assert.Nil(t, err, "Error while reading the openapi file : "+openapiFilePath)
+	mgwSwaggerForOpenapi := model.MgwSwagger{}
+	err = mgwSwaggerForOpenapi.GetMgwSwagger(openapiByteArr)
+	assert.Nil(t, err, "Error should not be present when openAPI definition is converted to a MgwSwagger object")
+	mgwSwaggerForOpenapi.IsDefaultVersion = true
+	routes, clusters, _ := envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+
+	defaultCluster := "carbon.super_default-endpoints_xwso2cluster_localhost_SwaggerPetstore1.0.0"
+	tenantACluster := "carbon.super_tenant-a-endpoints_xwso2cluster_localhost_SwaggerPetstore1.0.0"
+	tenantBCluster := "carbon.super_tenant-b-endpoints_xwso2cluster_localhost_SwaggerPetstore1.0.0"
+	assert.Equal(t, 3, len(clusters), "Number of clusters created is incorrect.")
+	for _, cluster := range clusters {
+		assert.Contains(t, []string{defaultCluster, tenantACluster, tenantBCluster}, cluster.GetName(), "Cluster name mismatch.")
+	}
+	// API level routing rules: 2 content based routes + fallback route,
+	// resource level routing rule: 1 content based route + fallback route
+	assert.Equal(t, 5, len(routes), "Created number of routes are incorrect.")
+
+	for _, route := range routes {
+		assert.Nil(t, route.Validate(), "Route validation failed.")
+		regex := route.GetMatch().GetSafeRegex().GetRegex()
+		if strings.Contains(regex, "/pets/([^/]+)") {
+			// resource level routing overrides API level routing
+			if route.GetRoute().GetCluster() != "" {
+				assert.Equal(t, tenantBCluster, route.GetRoute().GetCluster(), "Content based route cluster mismatch.")
+				pathMatcher := route.GetMatch().GetHeaders()[1]
+				assert.Equal(t, ":path", pathMatcher.GetName(), "Path segment should be matched using the path header.")
+				// The path segment index is relative to the base path, with or without the default version.
+				pathRegex := regexp.MustCompile(pathMatcher.GetStringMatch().GetSafeRegex().GetRegex())
+				for path, isMatched := range map[string]bool{
+					"/petstore/1.0.0/pets/b-1":          true,
+					"/petstore/pets/b-1":                true,
+					"/petstore/1.0.0/pets/b-1?name=tom": true,
+					"/petstore/1.0.0/pets/a-1":          false,
+					"/petstore/pets/a-1":                false,
+					"/petstore/b-1/pets/a-1":            false,
+				} {
+					assert.Equal(t, isMatched, pathRegex.MatchString(path), "Path segment match mismatch for "+path)
+				}
+			} else {
+				assert.Equal(t, "x-wso2-cluster-header", route.GetRoute().GetClusterHeader(), "Fallback route should use cluster header.")
+			}
+			continue
+		}
+		switch route.GetRoute().GetCluster() {
+		case tenantACluster:
+			assert.Equal(t, "x-tenant", route.GetMatch().GetHeaders()[1].GetName(), "Header condition mismatch.")
+			assert.Equal(t, "a", route.GetMatch().GetHeaders()[1].GetStringMatch().GetExact(), "Header condition mismatch.")
+			assert.Equal(t, "eu-.*", route.GetMatch().GetQueryParameters()[0].GetStringMatch().GetSafeRegex().GetRegex(),
+				"Query parameter condition mismatch.")
+		case tenantBCluster:
+			metadataMatcher := route.GetMatch().GetDynamicMetadata()[0]
+			assert.Equal(t, "envoy.filters.http.ext_authz", metadataMatcher.GetFilter(), "Claim metadata namespace mismatch.")
+			assert.Equal(t, "x-wso2-claim-tenant", metadataMatcher.GetPath()[0].GetKey(), "Claim metadata key mismatch.")
+		default:
+			assert.Equal(t, "x-wso2-cluster-header", route.GetRoute().GetClusterHeader(), "Fallback route should use cluster header.")
+		}
+	}
+
+	// The enforcer adds the claims matched by the API level routing rules to the dynamic metadata, which are not
+	// matched by the resource level routing rules.
+	for _, route := range routes {
+		var extAuthzPerRoute extAuthService.ExtAuthzPerRoute
+		err = ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.HTTPExternalAuthorization], &extAuthzPerRoute)
+		assert.Nil(t, err, "Error while parsing ext_authz per route config.")
+		routingClaims, found := extAuthzPerRoute.GetCheckSettings().GetContextExtensions()["routingClaims"]
+		if strings.Contains(route.GetMatch().GetSafeRegex().GetRegex(), "/pets/([^/]+)") {
+			assert.False(t, found, "Routing claims should not be passed to the enforcer for the resource level rules.")
+		} else {
+			assert.Equal(t, "tenant", routingClaims, "Routing claims mismatch.")
+		}
+	}
+
+	var defaultRouteIndex, routingRouteIndex int
+	for i, route := range routes {
+		if route.GetRoute().GetCluster() == tenantACluster {
+			routingRouteIndex = i
+		} else if !strings.Contains(route.GetMatch().GetSafeRegex().GetRegex(), "/pets/([^/]+)") &&
+			route.GetRoute().GetCluster() == "" {
+			defaultRouteIndex = i
+		}
+	}
+	assert.Less(t, routingRouteIndex, defaultRouteIndex, "Content based routes should be placed before the fallback route.")
+}
+
+func TestCreateRoutesWithClustersForMirroring(t *testing.T) {
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_mirror.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	assert.Nil(t, err, "Error while reading the openapi file : "+openapiFilePath)
+	mgwSwaggerForOpenapi := model.MgwSwagger{}
+	err = mgwSwaggerForOpenapi.GetMgwSwagger(openapiByteArr)
+	assert.Nil(t, err, "Error should not be present when openAPI definition is converted to a MgwSwagger object")
+	routes, clusters, _ := envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+
+	apiLevelMirrorCluster := "carbon.super_clusterMirror_localhost_SwaggerPetstore1.0.0"
+	// production cluster, API level mirror cluster and operation level mirror cluster
+	assert.Equal(t, 3, len(clusters), "Number of clusters created is incorrect.")
+	assert.Equal(t, apiLevelMirrorCluster, clusters[1].GetName(), "API level mirror cluster name mismatch.")
+	assert.Contains(t, clusters[2].GetName(), "carbon.super_clusterMirror_localhost_SwaggerPetstore1.0.0_",
+		"Operation level mirror cluster name mismatch.")
+
+	// separate routes for the GET and POST methods as the mirror policies are different
+	assert.Equal(t, 2, len(routes), "Created number of routes are incorrect.")
+	for _, route := range routes {
+		assert.Nil(t, route.Validate(), "Route validation failed.")
+		methodRegex := route.GetMatch().GetHeaders()[0].GetStringMatch().GetSafeRegex().GetRegex()
+		mirrorPolicies := route.GetRoute().GetRequestMirrorPolicies()
+		assert.Equal(t, 1, len(mirrorPolicies), "Mirror policy should be available in the route.")
+		if methodRegex == "^(GET|OPTIONS)$" {
+			assert.Equal(t, apiLevelMirrorCluster, mirrorPolicies[0].GetCluster(), "API level mirror cluster should be applied.")
+			assert.Equal(t, uint32(125000), mirrorPolicies[0].GetRuntimeFraction().GetDefaultValue().GetNumerator(),
+				"Mirror percentage mismatch.")
+		} else {
+			assert.Equal(t, "^(POST|OPTIONS)$", methodRegex, "Method regex mismatch.")
+			assert.Equal(t, clusters[2].GetName(), mirrorPolicies[0].GetCluster(), "Operation level mirror cluster should be applied.")
+			assert.Equal(t, uint32(1000000), mirrorPolicies[0].GetRuntimeFraction().GetDefaultValue().GetNumerator(),
+				"All the requests should be mirrored by default.")
+		}
+	}
+}
+
+func TestCreateRoutesWithClustersForSandboxMirroring(t *testing.T) {
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_sandbox_mirror.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	assert.Nil(t, err, "Error while reading the openapi file : "+openapiFilePath)
+	mgwSwaggerForOpenapi := model.MgwSwagger{}
+	err = mgwSwaggerForOpenapi.GetMgwSwagger(openapiByteArr)
+	assert.Nil(t, err, "Error should not be present when openAPI definition is converted to a MgwSwagger object")
+	routes, _, _ := envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+
+	// The sandbox requests are served by a separate route, which does not mirror the requests.
+	assert.Equal(t, 2, len(routes), "Created number of routes are incorrect.")
+	sandboxRoute, prodRoute := routes[0], routes[1]
+	assert.Equal(t, 2, len(sandboxRoute.GetMatch().GetHeaders()), "Sandbox route should match the cluster header.")
+	assert.Equal(t, "x-wso2-cluster-header", sandboxRoute.GetMatch().GetHeaders()[1].GetName(),
+		"Sandbox route should match the cluster header.")
+	assert.Empty(t, sandboxRoute.GetRoute().GetRequestMirrorPolicies(), "Sandbox requests should not be mirrored.")
+	mirrorPolicies := prodRoute.GetRoute().GetRequestMirrorPolicies()
+	assert.Equal(t, 1, len(mirrorPolicies), "Production requests should be mirrored.")
+	assert.Equal(t, uint32(0), mirrorPolicies[0].GetRuntimeFraction().GetDefaultValue().GetNumerator(),
+		"An explicit zero percentage should not mirror the requests.")
+}
+
+func TestCreateRoutesWithClustersForOperationCors(t *testing.T) {
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_cors.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	assert.Nil(t, err, "Error while reading the openapi file : "+openapiFilePath)
+	mgwSwaggerForOpenapi := model.MgwSwagger{}
+	err = mgwSwaggerForOpenapi.GetMgwSwagger(openapiByteArr)
+	assert.Nil(t, err, "Error should not be present when openAPI definition is converted to a MgwSwagger object")
+	routes, _, _ := envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+
+	// preflight routes for the GET and POST methods of /pets, followed by the routes of the GET and POST methods
+	// as the CORS policies are different, and the route of /pets/{petId}
+	assert.Equal(t, 5, len(routes), "Created number of routes are incorrect.")
+	for _, route := range routes {
+		assert.Nil(t, route.Validate(), "Route validation failed.")
+	}
+
+	// The preflight routes are followed by the other routes. The routes of the methods of /pets are looked up by
+	// the method regex, as the order of the operations is not preserved.
+	routesByMethodRegex := make(map[string]*routev3.Route)
+	for _, route := range routes[2:4] {
+		routesByMethodRegex[route.GetMatch().GetHeaders()[0].GetStringMatch().GetSafeRegex().GetRegex()] = route
+	}
+	for _, preflightRoute := range routes[:2] {
+		preflightHeaders := preflightRoute.GetMatch().GetHeaders()
+		assert.Equal(t, "OPTIONS", preflightHeaders[0].GetStringMatch().GetExact(), "Preflight method mismatch.")
+		assert.Equal(t, "access-control-request-method", preflightHeaders[1].GetName(), "Preflight header mismatch.")
+		method := strings.TrimSuffix(strings.TrimPrefix(preflightHeaders[1].GetStringMatch().GetSafeRegex().GetRegex(),
+			"^("), ")$")
+		methodRoute, found := routesByMethodRegex["^("+method+"|OPTIONS)$"]
+		assert.True(t, found, "Route of the preflight requested method should be available.")
+		assert.Equal(t, methodRoute.GetRoute().GetCors(), preflightRoute.GetRoute().GetCors(),
+			"Preflight route should have the CORS policy of the method.")
+	}
+
+	getRoute, found := routesByMethodRegex["^(GET|OPTIONS)$"]
+	assert.True(t, found, "GET route should be available.")
+	apiCors := getRoute.GetRoute().GetCors()
+	assert.Equal(t, `https://[a-zA-Z0-9.-]+\.example\.com`, apiCors.GetAllowOriginStringMatch()[0].GetSafeRegex().GetRegex(),
+		"API level wildcard origin mismatch.")
+	assert.Equal(t, "600", apiCors.GetMaxAge(), "API level max age mismatch.")
+
+	postRoute, found := routesByMethodRegex["^(POST|OPTIONS)$"]
+	assert.True(t, found, "POST route should be available.")
+	operationCors := postRoute.GetRoute().GetCors()
+	assert.Equal(t, 1, len(operationCors.GetAllowOriginStringMatch()), "Operation level origins mismatch.")
+	assert.Equal(t, `https://(admin|console)\.example\.com`,
+		operationCors.GetAllowOriginStringMatch()[0].GetSafeRegex().GetRegex(), "Operation level origin regex mismatch.")
+	assert.Equal(t, "POST", operationCors.GetAllowMethods(), "Operation level allowed methods mismatch.")
+	assert.Empty(t, operationCors.GetMaxAge(), "Operation level max age should not be set.")
+
+	assert.Nil(t, routes[4].GetRoute().GetCors(), "CORS should be disabled for the resource.")
+}
+
 func testCreateRoutesWithClustersWebsocket(t *testing.T, apiYamlFilePath string) {
 	// If the asyncAPI definition contains the production and sandbox endpoints, they are prioritized over
 	// the api.yaml. If the asyncAPI definition does not have any of them, api.yaml's value is assigned.
@@ -589,3 +797,205 @@ func commonTestForClusterPrioritiesInWebSocketAPIWithEnvProps(t *testing.T, apiY
 	assert.Equal(t, sandBoxClusterHost0, "env.sand.websocket.org", "Sandbox cluster host mismatch")
 	assert.Equal(t, sandBoxClusterPort0, uint32(80), "Sandbox cluster port mismatch")
 }
+
+func TestCreateRoutesWithClustersForOperationTimeoutAndRetry(t *testing.T) {
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_retry.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	assert.Nil(t, err, "Error while reading the openapi file : "+openapiFilePath)
+	mgwSwaggerForOpenapi := model.MgwSwagger{}
+	err = mgwSwaggerForOpenapi.GetMgwSwagger(openapiByteArr)
+	assert.Nil(t, err, "Error should not be present when openAPI definition is converted to a MgwSwagger object")
+	routes, _, _ := envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+
+	// GET has an operation level timeout and retry policy, POST is not retried as it is not idempotent
+	// and PUT uses the retry configuration of the endpoint.
+	assert.Equal(t, 3, len(routes), "Created number of routes are incorrect.")
+	// The routes are looked up by the method regex, as the order of the operations is not preserved.
+	routesByMethodRegex := make(map[string]*routev3.Route)
+	for _, route := range routes {
+		assert.Nil(t, route.Validate(), "Route validation failed.")
+		routesByMethodRegex[route.GetMatch().GetHeaders()[0].GetStringMatch().GetSafeRegex().GetRegex()] = route
+	}
+
+	getRoute := routesByMethodRegex["^(GET|OPTIONS)$"].GetRoute()
+	assert.NotNil(t, getRoute, "GET route should be available.")
+	assert.Equal(t, int64(10), getRoute.GetTimeout().GetSeconds(), "Route timeout mismatch.")
+	retryPolicy := getRoute.GetRetryPolicy()
+	assert.Equal(t, "5xx,reset", retryPolicy.GetRetryOn(), "Retry conditions mismatch.")
+	assert.Equal(t, uint32(3), retryPolicy.GetNumRetries().GetValue(), "Retry count mismatch.")
+	assert.Empty(t, retryPolicy.GetRetriableStatusCodes(), "Retriable status codes should not be set.")
+	assert.Equal(t, int64(2), retryPolicy.GetPerTryTimeout().GetSeconds(), "Per try timeout mismatch.")
+	assert.Equal(t, int32(100000000), retryPolicy.GetRetryBackOff().GetBaseInterval().GetNanos(),
+		"Back-off base interval mismatch.")
+	assert.Equal(t, int64(1), retryPolicy.GetRetryBackOff().GetMaxInterval().GetSeconds(),
+		"Back-off max interval mismatch.")
+	assert.Equal(t, int64(3), retryPolicy.GetHostSelectionRetryMaxAttempts(), "Host selection attempts mismatch.")
+	assert.Equal(t, 1, len(retryPolicy.GetRetryHostPredicate()), "Retry host predicate should be available.")
+	assert.Equal(t, "envoy.retry_host_predicates.previous_hosts", retryPolicy.GetRetryHostPredicate()[0].GetName(),
+		"Retry host predicate mismatch.")
+
+	postRoute := routesByMethodRegex["^(POST|OPTIONS)$"].GetRoute()
+	assert.NotNil(t, postRoute, "POST route should be available.")
+	assert.Equal(t, uint32(0), postRoute.GetRetryPolicy().GetNumRetries().GetValue(),
+		"Non-idempotent method should not be retried.")
+
+	putRoute := routesByMethodRegex["^(PUT|OPTIONS)$"].GetRoute()
+	assert.NotNil(t, putRoute, "PUT route should be available.")
+	assert.Equal(t, "retriable-status-codes", putRoute.GetRetryPolicy().GetRetryOn(), "Retry conditions mismatch.")
+
+	for methodRegex, route := range routesByMethodRegex {
+		var extAuthzPerRoute extAuthService.ExtAuthzPerRoute
+		err = ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.HTTPExternalAuthorization], &extAuthzPerRoute)
+		assert.Nil(t, err, "Error while parsing ext_authz per route config.")
+		contextExtensions := extAuthzPerRoute.GetCheckSettings().GetContextExtensions()
+		_, hasRouteTimeout := contextExtensions["routeTimeout"]
+		_, hasRouteRetryPolicy := contextExtensions["routeRetryPolicy"]
+		assert.Equal(t, methodRegex == "^(GET|OPTIONS)$", hasRouteTimeout,
+			"Route timeout context extension mismatch.")
+		assert.Equal(t, methodRegex != "^(PUT|OPTIONS)$", hasRouteRetryPolicy,
+			"Route retry policy context extension mismatch.")
+	}
+}
+
+func TestCreateRoutesWithClustersForRouterJWTValidation(t *testing.T) {
+	conf, _ := config.ReadConfigs()
+	tokenServices := conf.Enforcer.Security.TokenService
+	jwtGeneratorEnabled := conf.Enforcer.JwtGenerator.Enabled
+	analyticsEnabled := conf.Analytics.Enabled
+	defer func() {
+		conf.Enforcer.Security.TokenService = tokenServices
+		conf.Enforcer.JwtGenerator.Enabled = jwtGeneratorEnabled
+		conf.Analytics.Enabled = analyticsEnabled
+	}()
+	jwksTokenService := tokenServices[0]
+	jwksTokenService.Name = "JWKS Issuer"
+	jwksTokenService.JwksURL = "https://jwks.issuer.com/oauth2/jwks"
+	jwksTokenService.ValidateSubscription = false
+	conf.Enforcer.Security.TokenService = append(append(tokenServices[:0:0], tokenServices...), jwksTokenService)
+	conf.Enforcer.JwtGenerator.Enabled = false
+	conf.Analytics.Enabled = false
+
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_router_jwt_validation.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	assert.Nil(t, err, "Error while reading the openapi file : "+openapiFilePath)
+	mgwSwaggerForOpenapi := model.MgwSwagger{}
+	err = mgwSwaggerForOpenapi.GetMgwSwagger(openapiByteArr)
+	assert.Nil(t, err, "Error should not be present when openAPI definition is converted to a MgwSwagger object")
+	routes, _, _ := envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+
+	// GET and PUT are secured by OAuth2 with different scopes, while POST is secured by an API key.
+	assert.Equal(t, 3, len(routes), "Created number of routes are incorrect.")
+	routesByMethodRegex := make(map[string]*routev3.Route)
+	for _, route := range routes {
+		assert.Nil(t, route.Validate(), "Route validation failed.")
+		routesByMethodRegex[route.GetMatch().GetHeaders()[0].GetStringMatch().GetSafeRegex().GetRegex()] = route
+	}
+
+	getRoute := routesByMethodRegex["^(GET|OPTIONS)$"]
+	assert.NotNil(t, getRoute, "GET route should be available.")
+	assertRouterJWTValidation(t, getRoute, true)
+	assert.NotEmpty(t, getRoute.GetRoute().GetCluster(), "Route should be routed to the production cluster.")
+	assert.Equal(t, int64(5), getRoute.GetRoute().GetTimeout().GetSeconds(), "Route timeout mismatch.")
+	rbacPolicies := getRBACPolicies(t, getRoute)
+	scopePolicy, found := rbacPolicies["router-jwt-scopes"]
+	assert.True(t, found, "Scope policy should be available.")
+	scopeRegex := regexp.MustCompile("^(?:" + scopePolicy.GetPrincipals()[0].GetNotId().GetMetadata().GetValue().
+		GetStringMatch().GetSafeRegex().GetRegex() + ")$")
+	assert.True(t, scopeRegex.MatchString("openid read:pets"), "Token having a required scope should be allowed.")
+	assert.True(t, scopeRegex.MatchString("admin"), "Token having a required scope should be allowed.")
+	assert.False(t, scopeRegex.MatchString("read:pets:all openid"), "Token not having a required scope should be denied.")
+	_, found = rbacPolicies["router-jwt-sandbox-key"]
+	assert.True(t, found, "Sandbox key policy should be available.")
+
+	putRoute := routesByMethodRegex["^(PUT|OPTIONS)$"]
+	assert.NotNil(t, putRoute, "PUT route should be available.")
+	assertRouterJWTValidation(t, putRoute, true)
+	_, found = getRBACPolicies(t, putRoute)["router-jwt-scopes"]
+	assert.False(t, found, "Scope policy should not be available if the scopes are not required.")
+
+	postRoute := routesByMethodRegex["^(POST|OPTIONS)$"]
+	assert.NotNil(t, postRoute, "POST route should be available.")
+	assertRouterJWTValidation(t, postRoute, false)
+	assert.NotEmpty(t, postRoute.GetRoute().GetClusterHeader(), "Route should be routed using the cluster header.")
+
+	// The backend credentials of the endpoint security are added by the enforcer, hence the tokens are not
+	// validated at the router.
+	mgwSwaggerForOpenapi.GetProdEndpoints().SecurityConfig = model.EndpointSecurity{Enabled: true, Type: "basic",
+		Username: "admin", Password: "admin"}
+	routes, _, _ = envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+	assert.NotEmpty(t, routes, "Routes should be created.")
+	for _, route := range routes {
+		assertRouterJWTValidation(t, route, false)
+	}
+}
+
+func TestCreateRoutesWithClustersForRequestBodyLimits(t *testing.T) {
+	conf, _ := config.ReadConfigs()
+	passRequestPayload := conf.Envoy.PayloadPassingToEnforcer.PassRequestPayload
+	conf.Envoy.PayloadPassingToEnforcer.PassRequestPayload = true
+	defer func() {
+		conf.Envoy.PayloadPassingToEnforcer.PassRequestPayload = passRequestPayload
+	}()
+
+	openapiFilePath := config.GetMgwHome() + "/../adapter/test-resources/envoycodegen/openapi_with_request_body_limits.yaml"
+	openapiByteArr, err := ioutil.ReadFile(openapiFilePath)
+	assert.Nil(t, err, "Error while reading the openapi file : "+openapiFilePath)
+	mgwSwaggerForOpenapi := model.MgwSwagger{}
+	err = mgwSwaggerForOpenapi.GetMgwSwagger(openapiByteArr)
+	assert.Nil(t, err, "Error should not be present when openAPI definition is converted to a MgwSwagger object")
+	routes, _, _ := envoy.CreateRoutesWithClusters(mgwSwaggerForOpenapi, nil, nil, "localhost", "carbon.super")
+
+	// GET uses the API level size limit, POST has an operation level size limit and the request body of
+	// PUT is not passed to the enforcer.
+	assert.Equal(t, 3, len(routes), "Created number of routes are incorrect.")
+	expectedMaxRequestBytes := map[string]uint32{
+		"^(GET|OPTIONS)$":  1048576,
+		"^(POST|OPTIONS)$": 10240,
+		"^(PUT|OPTIONS)$":  1048576,
+	}
+	for _, route := range routes {
+		assert.Nil(t, route.Validate(), "Route validation failed.")
+		methodRegex := route.GetMatch().GetHeaders()[0].GetStringMatch().GetSafeRegex().GetRegex()
+		expectedSize, found := expectedMaxRequestBytes[methodRegex]
+		assert.True(t, found, "Unexpected route for the methods "+methodRegex)
+
+		var bufferPerRoute bufferv3.BufferPerRoute
+		err = ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.Buffer], &bufferPerRoute)
+		assert.Nil(t, err, "Error while parsing buffer per route config.")
+		assert.Nil(t, bufferPerRoute.Validate(), "Buffer per route config validation failed.")
+		assert.False(t, bufferPerRoute.GetDisabled(), "Buffer filter should be enabled for "+methodRegex)
+		assert.Equal(t, expectedSize, bufferPerRoute.GetBuffer().GetMaxRequestBytes().GetValue(),
+			"Max request bytes mismatch for "+methodRegex)
+
+		var extAuthzPerRoute extAuthService.ExtAuthzPerRoute
+		err = ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.HTTPExternalAuthorization], &extAuthzPerRoute)
+		assert.Nil(t, err, "Error while parsing ext_authz per route config.")
+		assert.Equal(t, methodRegex == "^(PUT|OPTIONS)$",
+			extAuthzPerRoute.GetCheckSettings().GetDisableRequestBodyBuffering(),
+			"Request body buffering for the enforcer mismatch for "+methodRegex)
+	}
+}
+
+func assertRouterJWTValidation(t *testing.T, route *routev3.Route, isValidatedAtRouter bool) {
+	var extAuthzPerRoute extAuthService.ExtAuthzPerRoute
+	err := ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.HTTPExternalAuthorization], &extAuthzPerRoute)
+	assert.Nil(t, err, "Error while parsing ext_authz per route config.")
+	assert.Equal(t, isValidatedAtRouter, extAuthzPerRoute.GetDisabled(), "ext_authz should be disabled only if "+
+		"the tokens are validated at the router.")
+	jwtAuthnPerRouteConfig, found := route.GetTypedPerFilterConfig()["envoy.filters.http.jwt_authn"]
+	assert.Equal(t, isValidatedAtRouter, found, "jwt_authn per route config mismatch.")
+	if found {
+		var jwtAuthnPerRoute jwtauthnv3.PerRouteConfig
+		err = ptypes.UnmarshalAny(jwtAuthnPerRouteConfig, &jwtAuthnPerRoute)
+		assert.Nil(t, err, "Error while parsing jwt_authn per route config.")
+		assert.Equal(t, "router_jwt_all", jwtAuthnPerRoute.GetRequirementName(), "Requirement name mismatch.")
+	}
+}
+
+func getRBACPolicies(t *testing.T, route *routev3.Route) map[string]*rbacconfigv3.Policy {
+	var rbacPerRoute rbacv3.RBACPerRoute
+	err := ptypes.UnmarshalAny(route.GetTypedPerFilterConfig()[wellknown.HTTPRoleBasedAccessControl], &rbacPerRoute)
+	assert.Nil(t, err, "Error while parsing RBAC per route config.")
+	assert.Equal(t, rbacconfigv3.RBAC_DENY, rbacPerRoute.GetRbac().GetRules().GetAction(), "RBAC action mismatch.")
+	return rbacPerRoute.GetRbac().GetRules().GetPolicies()
+}
@@ -0,0 +1,274 @@
+/*
+ *  Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package envoyconf
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	tcpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/wso2/product-microgateway/adapter/config"
+	"github.com/wso2/product-microgateway/adapter/internal/oasparser/model"
+)
+
+// upstreamProxyTunnel is a loopback listener of the router, which tunnels the connections to an upstream host
+// through an HTTP CONNECT proxy. The endpoints reached through the proxy are pointed to the tunnel listener.
+type upstreamProxyTunnel struct {
+	name         string
+	port         uint32
+	listener     *listenerv3.Listener
+	proxyCluster *clusterv3.Cluster
+}
+
+var (
+	upstreamProxyTunnels         = make(map[string]*upstreamProxyTunnel) // tunnel name -> tunnel
+	mutexForUpstreamProxyTunnels sync.Mutex
+)
+
+// getUpstreamProxyTunnel returns the tunnel to the given upstream host and port through the proxy. The tunnel
+// is created with a loopback port derived from the tunnel name if it is not available already.
+func getUpstreamProxyTunnel(proxy *model.ProxyConfig, host string, port uint32) (*upstreamProxyTunnel, error) {
+	target := net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10))
+	tunnelHash := sha256.Sum256([]byte(fmt.Sprintf("%s:%d|%s:%s|%s", proxy.Host, proxy.Port, proxy.Username,
+		proxy.Password, target)))
+	tunnelName := upstreamProxyTunnelNamePrefix + hex.EncodeToString(tunnelHash[:8])
+
+	mutexForUpstreamProxyTunnels.Lock()
+	defer mutexForUpstreamProxyTunnels.Unlock()
+	if tunnel, found := upstreamProxyTunnels[tunnelName]; found {
+		return tunnel, nil
+	}
+	conf, _ := config.ReadConfigs()
+	tunnelPort, err := allocateUpstreamProxyTunnelPort(binary.BigEndian.Uint64(tunnelHash[:8]),
+		conf.Envoy.Upstream.Proxy.TunnelPortRangeStart, conf.Envoy.Upstream.Proxy.TunnelPortRangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("no loopback port is available to tunnel the connections to %s through the proxy : %v",
+			target, err)
+	}
+
+	headersToAdd := []*corev3.HeaderValueOption{}
+	if proxy.Username != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.Username + ":" + proxy.Password))
+		headersToAdd = append(headersToAdd, &corev3.HeaderValueOption{
+			Header: &corev3.HeaderValue{
+				Key:   proxyAuthorizationHeader,
+				Value: "Basic " + credentials,
+			},
+		})
+	}
+	proxyCluster := createUpstreamProxyCluster(proxy, time.Duration(conf.Envoy.ClusterTimeoutInSeconds))
+	// The tcp proxy uses HTTP/1.1 CONNECT requests, as the proxy cluster does not enable HTTP/2.
+	tcpProxy := &tcpproxyv3.TcpProxy{
+		StatPrefix: tunnelName,
+		ClusterSpecifier: &tcpproxyv3.TcpProxy_Cluster{
+			Cluster: proxyCluster.Name,
+		},
+		TunnelingConfig: &tcpproxyv3.TcpProxy_TunnelingConfig{
+			Hostname:     target,
+			HeadersToAdd: headersToAdd,
+		},
+	}
+	marshalledTCPProxy, err := anypb.New(tcpProxy)
+	if err != nil {
+		return nil, err
+	}
+	listener := &listenerv3.Listener{
+		Name:    tunnelName,
+		Address: createAddress(upstreamProxyTunnelHost, tunnelPort),
+		FilterChains: []*listenerv3.FilterChain{{
+			Filters: []*listenerv3.Filter{{
+				Name: wellknown.TCPProxy,
+				ConfigType: &listenerv3.Filter_TypedConfig{
+					TypedConfig: marshalledTCPProxy,
+				},
+			}},
+		}},
+	}
+	tunnel := &upstreamProxyTunnel{
+		name:         tunnelName,
+		port:         tunnelPort,
+		listener:     listener,
+		proxyCluster: proxyCluster,
+	}
+	upstreamProxyTunnels[tunnelName] = tunnel
+	return tunnel, nil
+}
+
+// allocateUpstreamProxyTunnelPort returns the port of a new tunnel within the given port range. The port is derived
+// from the hash of the tunnel, so that the same port is allocated to a tunnel regardless of the order the APIs are
+// deployed. The following ports are probed if the port is allocated to another tunnel. The caller must hold the
+// lock of the tunnels.
+func allocateUpstreamProxyTunnelPort(tunnelHash uint64, portRangeStart, portRangeEnd uint32) (uint32, error) {
+	if portRangeEnd > 65535 {
+		portRangeEnd = 65535
+	}
+	if portRangeStart == 0 || portRangeStart > portRangeEnd {
+		return 0, fmt.Errorf("invalid tunnel port range %d - %d", portRangeStart, portRangeEnd)
+	}
+	allocatedPorts := make(map[uint32]struct{}, len(upstreamProxyTunnels))
+	for _, tunnel := range upstreamProxyTunnels {
+		allocatedPorts[tunnel.port] = struct{}{}
+	}
+	rangeSize := uint64(portRangeEnd-portRangeStart) + 1
+	for i := uint64(0); i < rangeSize; i++ {
+		port := portRangeStart + uint32((tunnelHash+i)%rangeSize)
+		if _, allocated := allocatedPorts[port]; !allocated {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("all the ports of the tunnel port range %d - %d are allocated", portRangeStart,
+		portRangeEnd)
+}
+
+// PruneUpstreamProxyTunnels removes the tunnels which are not referred by the endpoints of the given clusters, hence
+// their ports are released to be allocated to new tunnels. The given clusters should be the clusters of all the
+// deployed APIs.
+func PruneUpstreamProxyTunnels(clusters []*clusterv3.Cluster) {
+	referredTunnels := make(map[string]struct{})
+	for _, cluster := range clusters {
+		for _, localityLbEndpoints := range cluster.GetLoadAssignment().GetEndpoints() {
+			for _, lbEndpoint := range localityLbEndpoints.GetLbEndpoints() {
+				tunnelMetadata := lbEndpoint.GetMetadata().GetFilterMetadata()[upstreamProxyTunnelMetadataKey]
+				tunnelName := tunnelMetadata.GetFields()[upstreamProxyTunnelMetadataName].GetStringValue()
+				if tunnelName != "" {
+					referredTunnels[tunnelName] = struct{}{}
+				}
+			}
+		}
+	}
+	mutexForUpstreamProxyTunnels.Lock()
+	defer mutexForUpstreamProxyTunnels.Unlock()
+	for tunnelName := range upstreamProxyTunnels {
+		if _, found := referredTunnels[tunnelName]; !found {
+			delete(upstreamProxyTunnels, tunnelName)
+		}
+	}
+}
+
+// UpstreamProxyTunnelsSnapshot holds the tunnels at a point in time, to be restored when a batch of API updates
+// is rolled back.
+type UpstreamProxyTunnelsSnapshot map[string]*upstreamProxyTunnel
+
+// TakeUpstreamProxyTunnelsSnapshot returns a snapshot of the current tunnels.
+func TakeUpstreamProxyTunnelsSnapshot() UpstreamProxyTunnelsSnapshot {
+	mutexForUpstreamProxyTunnels.Lock()
+	defer mutexForUpstreamProxyTunnels.Unlock()
+	snapshot := make(UpstreamProxyTunnelsSnapshot, len(upstreamProxyTunnels))
+	for tunnelName, tunnel := range upstreamProxyTunnels {
+		snapshot[tunnelName] = tunnel
+	}
+	return snapshot
+}
+
+// RestoreUpstreamProxyTunnels replaces the current tunnels with the tunnels of the snapshot.
+func RestoreUpstreamProxyTunnels(snapshot UpstreamProxyTunnelsSnapshot) {
+	mutexForUpstreamProxyTunnels.Lock()
+	defer mutexForUpstreamProxyTunnels.Unlock()
+	upstreamProxyTunnels = snapshot
+}
+
+// createUpstreamProxyCluster creates the cluster of the proxy server.
+func createUpstreamProxyCluster(proxy *model.ProxyConfig, timeout time.Duration) *clusterv3.Cluster {
+	clusterName := upstreamProxyClusterNamePrefix + proxy.Host + "_" + strconv.FormatUint(uint64(proxy.Port), 10)
+	return &clusterv3.Cluster{
+		Name:                 clusterName,
+		ConnectTimeout:       durationpb.New(timeout * time.Second),
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STRICT_DNS},
+		DnsLookupFamily:      clusterv3.Cluster_V4_ONLY,
+		LbPolicy:             clusterv3.Cluster_ROUND_ROBIN,
+		LoadAssignment: &endpointv3.ClusterLoadAssignment{
+			ClusterName: clusterName,
+			Endpoints: []*endpointv3.LocalityLbEndpoints{{
+				LbEndpoints: []*endpointv3.LbEndpoint{{
+					HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+						Endpoint: &endpointv3.Endpoint{
+							Address: createAddress(proxy.Host, proxy.Port),
+						},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+// getEndpointProxy returns the proxy of the endpoint cluster. The endpoint level proxy overrides the
+// API level proxy.
+func getEndpointProxy(clusterDetails *model.EndpointCluster, upstreamProxy *model.ProxyConfig) *model.ProxyConfig {
+	if clusterDetails.Config != nil && clusterDetails.Config.Proxy != nil {
+		return clusterDetails.Config.Proxy
+	}
+	return upstreamProxy
+}
+
+// createUpstreamProxyTunnelMetadata creates the endpoint metadata referring the tunnel.
+func createUpstreamProxyTunnelMetadata(tunnel *upstreamProxyTunnel) *structpb.Struct {
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			upstreamProxyTunnelMetadataName: structpb.NewStringValue(tunnel.name),
+		},
+	}
+}
+
+// GetUpstreamProxyTunnelResources returns the proxy clusters and the tunnel listeners referred by the endpoints
+// of the given clusters.
+func GetUpstreamProxyTunnelResources(clusters []*clusterv3.Cluster) ([]*clusterv3.Cluster, []*listenerv3.Listener) {
+	proxyClusters := make([]*clusterv3.Cluster, 0)
+	listeners := make([]*listenerv3.Listener, 0)
+	addedProxyClusters := make(map[string]struct{})
+	addedTunnels := make(map[string]struct{})
+
+	mutexForUpstreamProxyTunnels.Lock()
+	defer mutexForUpstreamProxyTunnels.Unlock()
+	for _, cluster := range clusters {
+		for _, localityLbEndpoints := range cluster.GetLoadAssignment().GetEndpoints() {
+			for _, lbEndpoint := range localityLbEndpoints.GetLbEndpoints() {
+				tunnelMetadata := lbEndpoint.GetMetadata().GetFilterMetadata()[upstreamProxyTunnelMetadataKey]
+				tunnelName := tunnelMetadata.GetFields()[upstreamProxyTunnelMetadataName].GetStringValue()
+				tunnel, found := upstreamProxyTunnels[tunnelName]
+				if !found {
+					continue
+				}
+				if _, added := addedTunnels[tunnelName]; !added {
+					addedTunnels[tunnelName] = struct{}{}
+					listeners = append(listeners, tunnel.listener)
+				}
+				if _, added := addedProxyClusters[tunnel.proxyCluster.Name]; !added {
+					addedProxyClusters[tunnel.proxyCluster.Name] = struct{}{}
+					proxyClusters = append(proxyClusters, tunnel.proxyCluster)
+				}
+			}
+		}
+	}
+	return proxyClusters, listeners
+}
@@ -22,6 +22,7 @@ package model
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -31,8 +32,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/wso2/product-microgateway/adapter/config"
 	"github.com/wso2/product-microgateway/adapter/internal/interceptor"
+	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
 	"github.com/wso2/product-microgateway/adapter/internal/oasparser/constants"
 	"github.com/wso2/product-microgateway/adapter/pkg/discovery/api/wso2/discovery/api"
+	"github.com/wso2/product-microgateway/adapter/pkg/logging"
 )
 
 // Operation type object holds data about each http method in the REST API.
@@ -45,6 +48,11 @@ type Operation struct {
 	vendorExtensions map[string]interface{}
 	policies         OperationPolicies
 	mockedAPIConfig  *api.MockedApiConfig
+	timeoutConfig    *TimeoutConfig
+	retryPolicy      *RetryPolicy
+	// yamlTimeoutAndRetryErr is the error of the invalid timeout or retry policy of the api.yaml operation, which
+	// rejects the API when it is validated.
+	yamlTimeoutAndRetryErr error
 }
 
 // SetMockedAPIConfigOAS3 generate mock impl endpoint configurations
@@ -272,10 +280,283 @@ func (operation *Operation) GetCallInterceptorService(isIn bool) InterceptEndpoi
 	return InterceptEndpoint{}
 }
 
+// GetIPFilter returns the operation level client IP filter. The IP_FILTER policy takes precedence
+// over the x-wso2-ip-filter extension of the operation. nil is returned if neither is available.
+// An invalid IP filter is rejected when the API is validated.
+func (operation *Operation) GetIPFilter() *IPFilterConfig {
+	for _, policy := range operation.policies.Request {
+		if strings.EqualFold(constants.IPFilterAction, policy.Action) {
+			if paramMap, isMap := policy.Parameters.(map[string]interface{}); isMap {
+				if ipFilter, err := getIPFilterFromPolicyParams(paramMap); err == nil {
+					return ipFilter
+				}
+			}
+		}
+	}
+	ipFilter, err := getXWso2IPFilter(operation.vendorExtensions)
+	if err != nil {
+		return nil
+	}
+	return ipFilter
+}
+
+// validateIPFilter validates the IP_FILTER policies and the x-wso2-ip-filter extension of the operation. As the
+// IP filter restricts the clients of the operation, an invalid filter is not ignored.
+func (operation *Operation) validateIPFilter() error {
+	for _, policy := range operation.policies.Request {
+		if !strings.EqualFold(constants.IPFilterAction, policy.Action) {
+			continue
+		}
+		paramMap, isMap := policy.Parameters.(map[string]interface{})
+		if !isMap {
+			return fmt.Errorf("invalid parameters of the %v policy of the operation %v", constants.IPFilterAction,
+				operation.method)
+		}
+		if _, err := getIPFilterFromPolicyParams(paramMap); err != nil {
+			return fmt.Errorf("invalid %v policy of the operation %v. %v", constants.IPFilterAction,
+				operation.method, err)
+		}
+	}
+	if _, err := getXWso2IPFilter(operation.vendorExtensions); err != nil {
+		return fmt.Errorf("invalid %v extension of the operation %v. %v", constants.XWso2IPFilter,
+			operation.method, err)
+	}
+	return nil
+}
+
+// GetMirrorConfig returns the operation level traffic mirroring configuration provided with the
+// x-wso2-mirror extension of the operation. nil is returned if it is not available.
+func (operation *Operation) GetMirrorConfig() *MirrorConfig {
+	mirror, err := getXWso2Mirror(operation.vendorExtensions)
+	if err != nil {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Ignoring the %v extension of the operation %v. %v", constants.XWso2Mirror, operation.method, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2221,
+		})
+		return nil
+	}
+	return mirror
+}
+
+// GetCorsConfig returns the operation level CORS configuration provided with the x-wso2-cors extension of the
+// operation. nil is returned if it is not available. An invalid configuration is rejected when the API is validated.
+func (operation *Operation) GetCorsConfig() *CorsConfig {
+	if corsConfig, err := getXWso2Cors(operation.vendorExtensions); err == nil {
+		return corsConfig
+	}
+	return nil
+}
+
+// GetTimeoutConfig returns the operation level timeouts. The timeout of the api.yaml operation takes precedence over
+// the x-wso2-timeout extension of the operation. nil is returned if neither is available.
+func (operation *Operation) GetTimeoutConfig() *TimeoutConfig {
+	if operation.timeoutConfig != nil {
+		return operation.timeoutConfig
+	}
+	timeoutConfig, err := getXWso2Timeout(operation.vendorExtensions)
+	if err != nil {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Ignoring the %v extension of the operation %v. %v", constants.XWso2Timeout, operation.method, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2225,
+		})
+		return nil
+	}
+	return timeoutConfig
+}
+
+// GetRetryPolicy returns the operation level retry policy. The retry policy of the api.yaml operation takes
+// precedence over the x-wso2-retry extension of the operation. nil is returned if neither is available.
+func (operation *Operation) GetRetryPolicy() *RetryPolicy {
+	if operation.retryPolicy != nil {
+		return operation.retryPolicy
+	}
+	retryPolicy, err := getXWso2Retry(operation.vendorExtensions)
+	if err != nil {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Ignoring the %v extension of the operation %v. %v", constants.XWso2Retry, operation.method, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2226,
+		})
+		return nil
+	}
+	return retryPolicy
+}
+
+// GetMaxRequestBodySize returns the maximum request body size of the operation in bytes, set via the
+// x-wso2-max-request-body-size extension. 0 is returned if the extension is not available.
+func (operation *Operation) GetMaxRequestBodySize() uint32 {
+	maxRequestBodySize, err := getXWso2MaxRequestBodySize(operation.vendorExtensions)
+	if err != nil {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message: fmt.Sprintf("Ignoring the %v extension of the operation %v. %v", constants.XWso2MaxRequestBodySize,
+				operation.method, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2234,
+		})
+		return 0
+	}
+	return maxRequestBodySize
+}
+
+// GetRequestBodyPass returns whether the request body of the operation is passed to the enforcer, set via the
+// x-wso2-pass-request-payload-to-enforcer extension. nil is returned if the extension is not available. The
+// request body is never passed if the payload passing is disabled in the config.
+func (operation *Operation) GetRequestBodyPass() *bool {
+	if _, isBool := operation.vendorExtensions[constants.XWso2PassRequestPayloadToEnforcer].(bool); !isBool {
+		return nil
+	}
+	passRequestBody := getRequestBodyBufferConfig(operation.vendorExtensions)
+	return &passRequestBody
+}
+
+// setTimeoutAndRetryPolicy sets the timeout and the retry policy of the api.yaml operation, if available. An invalid
+// timeout or retry policy is reported when the API is validated.
+func (operation *Operation) setTimeoutAndRetryPolicy(yamlOperation OperationYaml) {
+	operation.yamlTimeoutAndRetryErr = nil
+	if yamlOperation.Timeout != nil {
+		timeoutConfig, err := decodeTimeoutConfig(yamlOperation.Timeout)
+		if err != nil {
+			operation.yamlTimeoutAndRetryErr = fmt.Errorf("invalid timeout of the operation %v %v in api.yaml. %v",
+				operation.method, yamlOperation.Target, err)
+			return
+		}
+		operation.timeoutConfig = timeoutConfig
+	}
+	if yamlOperation.Retry != nil {
+		retryPolicy, err := decodeRetryPolicy(yamlOperation.Retry)
+		if err != nil {
+			operation.yamlTimeoutAndRetryErr = fmt.Errorf("invalid retry policy of the operation %v %v in api.yaml. %v",
+				operation.method, yamlOperation.Target, err)
+			return
+		}
+		operation.retryPolicy = retryPolicy
+	}
+}
+
+// validateTimeoutAndRetryPolicy validates the timeout and the retry policy of the api.yaml operation, and the
+// x-wso2-timeout and x-wso2-retry extensions of the operation.
+func (operation *Operation) validateTimeoutAndRetryPolicy() error {
+	if operation.yamlTimeoutAndRetryErr != nil {
+		return operation.yamlTimeoutAndRetryErr
+	}
+	if _, err := getXWso2Timeout(operation.vendorExtensions); err != nil {
+		return fmt.Errorf("invalid %v extension of the operation %v. %v", constants.XWso2Timeout, operation.method, err)
+	}
+	if _, err := getXWso2Retry(operation.vendorExtensions); err != nil {
+		return fmt.Errorf("invalid %v extension of the operation %v. %v", constants.XWso2Retry, operation.method, err)
+	}
+	return nil
+}
+
+// IsIdempotent returns true if the method of the operation is idempotent, hence the requests can be retried
+// without side effects.
+func (operation *Operation) IsIdempotent() bool {
+	return !strings.EqualFold(operation.method, "POST") && !strings.EqualFold(operation.method, "PATCH")
+}
+
+// GetFaultInjection returns the faults injected to the requests of the operation by the FAULT_INJECTION
+// policy. nil is returned if the policy is not available.
+func (operation *Operation) GetFaultInjection() *FaultInjectionConfig {
+	for _, policy := range operation.policies.Request {
+		if strings.EqualFold(constants.FaultInjectionAction, policy.Action) {
+			if paramMap, isMap := policy.Parameters.(map[string]interface{}); isMap {
+				fault, err := getFaultInjectionFromPolicyParams(paramMap)
+				if err == nil {
+					return fault
+				}
+				logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+					Message:   fmt.Sprintf("Ignoring the %v policy of the operation %v. %v", constants.FaultInjectionAction, operation.method, err.Error()),
+					Severity:  logging.MINOR,
+					ErrorCode: 2222,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// GetStaticMutations returns the header and query parameter mutations of the operation which are applied by the
+// router. nil is returned if the operation does not have such mutations.
+//
+// The router removes the headers before adding the headers, hence only the last mutation of a header is kept,
+// so that the result is the same as applying the mutations in the policy order.
+func (operation *Operation) GetStaticMutations() *StaticMutations {
+	mutations := &StaticMutations{}
+	hasMutations := false
+	for _, flow := range []struct {
+		isRequestFlow   bool
+		policies        PolicyList
+		headersToAdd    *[]NameValuePair
+		headersToRemove *[]string
+	}{
+		{true, operation.policies.Request, &mutations.RequestHeadersToAdd, &mutations.RequestHeadersToRemove},
+		{false, operation.policies.Response, &mutations.ResponseHeadersToAdd, &mutations.ResponseHeadersToRemove},
+	} {
+		for _, policy := range flow.policies {
+			if policy.IsPassToEnforcer || !isMutationAction(policy.Action) {
+				continue
+			}
+			params, _ := policy.Parameters.(map[string]interface{})
+			switch policy.Action {
+			case constants.SetHeaderAction:
+				headerName := fmt.Sprint(params[constants.HeaderName])
+				removeHeaderMutations(headerName, flow.headersToAdd, flow.headersToRemove)
+				*flow.headersToAdd = append(*flow.headersToAdd, NameValuePair{
+					Name:  headerName,
+					Value: fmt.Sprint(params[constants.HeaderValue]),
+				})
+			case constants.RemoveHeaderAction:
+				headerName := fmt.Sprint(params[constants.HeaderName])
+				removeHeaderMutations(headerName, flow.headersToAdd, flow.headersToRemove)
+				*flow.headersToRemove = append(*flow.headersToRemove, headerName)
+			case constants.AddQueryAction, constants.RemoveQueryAction, constants.RenameQueryAction:
+				if !flow.isRequestFlow {
+					// query parameters are not applicable for the response flow
+					continue
+				}
+				queryMutation := QueryMutation{Action: policy.Action, Name: fmt.Sprint(params[constants.QueryParamName])}
+				if policy.Action == constants.AddQueryAction {
+					queryMutation.Value = fmt.Sprint(params[constants.QueryParamValue])
+				} else if policy.Action == constants.RenameQueryAction {
+					queryMutation.Name = fmt.Sprint(params[constants.CurrentQueryParamName])
+					queryMutation.Value = fmt.Sprint(params[constants.UpdatedQueryParamName])
+				}
+				mutations.QueryMutations = append(mutations.QueryMutations, queryMutation)
+			}
+			hasMutations = true
+		}
+	}
+	if !hasMutations {
+		return nil
+	}
+	return mutations
+}
+
+// removeHeaderMutations removes the previous mutations of the given header, as header names are case insensitive.
+func removeHeaderMutations(headerName string, headersToAdd *[]NameValuePair, headersToRemove *[]string) {
+	var remainingHeadersToAdd []NameValuePair
+	for _, header := range *headersToAdd {
+		if !strings.EqualFold(header.Name, headerName) {
+			remainingHeadersToAdd = append(remainingHeadersToAdd, header)
+		}
+	}
+	*headersToAdd = remainingHeadersToAdd
+	var remainingHeadersToRemove []string
+	for _, header := range *headersToRemove {
+		if !strings.EqualFold(header, headerName) {
+			remainingHeadersToRemove = append(remainingHeadersToRemove, header)
+		}
+	}
+	*headersToRemove = remainingHeadersToRemove
+}
+
 // NewOperation Creates and returns operation type object
 func NewOperation(method string, security []map[string][]string, extensions map[string]interface{}) *Operation {
 	tier := ResolveThrottlingTier(extensions)
 	disableSecurity := ResolveDisableSecurity(extensions)
 	id := uuid.New().String()
-	return &Operation{id, method, security, tier, disableSecurity, extensions, OperationPolicies{}, &api.MockedApiConfig{}}
+	return &Operation{id, method, security, tier, disableSecurity, extensions, OperationPolicies{}, &api.MockedApiConfig{},
+		nil, nil, nil}
 }
@@ -62,6 +62,7 @@ type APIYaml struct {
 			SandBoxEndpoints             []EndpointInfo
 			SandboxFailoverEndpoints     []EndpointInfo `json:"sandbox_failovers,omitempty"`
 			ImplementationStatus         string         `json:"implementation_status,omitempty"`
+			MirrorEndpoints              *MirrorInfo    `json:"mirror_endpoints,omitempty"`
 		} `json:"endpointConfig,omitempty"`
 		Operations []OperationYaml `json:"Operations,omitempty"`
 	} `json:"data"`
@@ -91,11 +92,21 @@ type EndpointInfo struct {
 	} `json:"config,omitempty"`
 }
 
-// OperationYaml holds attributes of APIM operations
+// MirrorInfo holds the endpoint to which the traffic is mirrored and the percentage of
+// the requests to be mirrored
+type MirrorInfo struct {
+	Endpoint   string   `json:"url,omitempty"`
+	Percentage *float64 `json:"percentage,omitempty"`
+}
+
+// OperationYaml holds attributes of APIM operations. The timeout and the retry policy have the same
+// structure as the x-wso2-timeout and x-wso2-retry extensions of an operation.
 type OperationYaml struct {
 	Target            string            `json:"target,omitempty"`
 	Verb              string            `json:"verb,omitempty"`
 	OperationPolicies OperationPolicies `json:"operationPolicies,omitempty"`
+	Timeout           interface{}       `json:"timeout,omitempty"`
+	Retry             interface{}       `json:"retry,omitempty"`
 }
 
 // OperationPolicies holds policies of the APIM operations
@@ -114,6 +125,7 @@ type Policy struct {
 	PolicyVersion    string      `json:"policyVersion,omitempty"`
 	Action           string      `json:"-"` // This is a meta value used in CC, not included in API YAML
 	IsPassToEnforcer bool        `json:"-"` // This is a meta value used in CC, not included in API YAML
+	FilterClass      string      `json:"-"` // This is a meta value used in CC, not included in API YAML
 	Parameters       interface{} `json:"parameters,omitempty"`
 }
 
@@ -23,17 +23,22 @@ package model
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
+	parser "github.com/mitchellh/mapstructure"
+	"github.com/wso2/product-microgateway/adapter/config"
 	logger "github.com/wso2/product-microgateway/adapter/internal/loggers"
 	"github.com/wso2/product-microgateway/adapter/internal/oasparser/constants"
 )
 
-func arrayContains(a []string, x string) bool {
+// ArrayContains returns true if the given string is available in the array.
+func ArrayContains(a []string, x string) bool {
 	for _, n := range a {
 		if x == n {
 			return true
@@ -78,6 +83,508 @@ func getXWso2HTTP2BackendEnabled(vendorExtensions map[string]interface{}) bool {
 	return xWso2HTTP2BackendEnabled
 }
 
+// getXWso2RouterJWTValidation extracts the value of x-wso2-router-jwt-validation extension.
+// if the property is not available, nil is returned.
+func getXWso2RouterJWTValidation(vendorExtensions map[string]interface{}) *bool {
+	if y, found := vendorExtensions[constants.XWso2RouterJWTValidation]; found {
+		if val, ok := y.(bool); ok {
+			return &val
+		}
+	}
+	return nil
+}
+
+// getXWso2MaxRequestBodySize extracts the value of x-wso2-max-request-body-size extension in bytes.
+// if the property is not available, 0 is returned.
+func getXWso2MaxRequestBodySize(vendorExtensions map[string]interface{}) (uint32, error) {
+	y, found := vendorExtensions[constants.XWso2MaxRequestBodySize]
+	if !found {
+		return 0, nil
+	}
+	var size float64
+	switch val := y.(type) {
+	case float64:
+		size = val
+	case int:
+		size = float64(val)
+	default:
+		return 0, fmt.Errorf("the maximum request body size %v is not a number", y)
+	}
+	if size < 1 || size > math.MaxUint32 || size != math.Trunc(size) {
+		return 0, fmt.Errorf("the maximum request body size %v should be a positive integer not exceeding %d bytes",
+			y, uint32(math.MaxUint32))
+	}
+	return uint32(size), nil
+}
+
+// getXWso2IPFilter extracts the value of x-wso2-ip-filter extension.
+// if the property is not available, nil is returned.
+func getXWso2IPFilter(vendorExtensions map[string]interface{}) (*IPFilterConfig, error) {
+	y, found := vendorExtensions[constants.XWso2IPFilter]
+	if !found {
+		return nil, nil
+	}
+	ipFilter := &IPFilterConfig{}
+	if err := parser.Decode(y, ipFilter); err != nil {
+		return nil, err
+	}
+	return ipFilter, ipFilter.validate()
+}
+
+// getIPFilterFromPolicyParams creates the ip filter from the parameters of the IP_FILTER policy.
+// The allow and deny lists can be provided either as a list or as a comma separated string.
+func getIPFilterFromPolicyParams(params map[string]interface{}) (*IPFilterConfig, error) {
+	ipFilter := &IPFilterConfig{
+		Allow: getStringListParam(params, constants.IPFilterAllow),
+		Deny:  getStringListParam(params, constants.IPFilterDeny),
+	}
+	return ipFilter, ipFilter.validate()
+}
+
+func getStringListParam(params map[string]interface{}, key string) []string {
+	var values []string
+	switch value := params[key].(type) {
+	case string:
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if itemStr, ok := item.(string); ok && strings.TrimSpace(itemStr) != "" {
+				values = append(values, strings.TrimSpace(itemStr))
+			}
+		}
+	}
+	return values
+}
+
+// validate checks the CIDR ranges of the ip filter. Single IP addresses are converted to
+// CIDR ranges with the full prefix length.
+func (ipFilter *IPFilterConfig) validate() error {
+	if len(ipFilter.Allow) == 0 && len(ipFilter.Deny) == 0 {
+		return errors.New("at least one of the allow or deny lists is required for the ip filter")
+	}
+	for _, ipList := range [][]string{ipFilter.Allow, ipFilter.Deny} {
+		for i, cidr := range ipList {
+			if !strings.Contains(cidr, "/") {
+				ip := net.ParseIP(cidr)
+				if ip == nil {
+					return fmt.Errorf("invalid IP address %q in the ip filter", cidr)
+				}
+				if ip.To4() != nil {
+					ipList[i] = cidr + "/32"
+				} else {
+					ipList[i] = cidr + "/128"
+				}
+				continue
+			}
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("invalid CIDR range %q in the ip filter", cidr)
+			}
+		}
+	}
+	return nil
+}
+
+// getXWso2Routing extracts the value of x-wso2-routing extension and validates the endpoint
+// references against the x-wso2-endpoints. if the property is not available, nil is returned.
+func getXWso2Routing(vendorExtensions map[string]interface{}, xWso2Endpoints map[string]*EndpointCluster) (*RoutingConfig, error) {
+	y, found := vendorExtensions[constants.XWso2Routing]
+	if !found {
+		return nil, nil
+	}
+	routing := &RoutingConfig{}
+	if err := parser.Decode(y, routing); err != nil {
+		return nil, err
+	}
+	return routing, routing.validate(xWso2Endpoints)
+}
+
+// validate checks the routing rules and whether the referred endpoints are defined under x-wso2-endpoints.
+func (routing *RoutingConfig) validate(xWso2Endpoints map[string]*EndpointCluster) error {
+	if len(routing.Rules) == 0 {
+		return errors.New("at least one routing rule is required")
+	}
+	if _, found := xWso2Endpoints[routing.DefaultEndpoint]; routing.DefaultEndpoint != "" && !found {
+		return fmt.Errorf("default endpoint %q is not defined under %v", routing.DefaultEndpoint, constants.XWso2endpoints)
+	}
+	for _, rule := range routing.Rules {
+		if _, found := xWso2Endpoints[rule.Endpoint]; !found {
+			return fmt.Errorf("endpoint %q is not defined under %v", rule.Endpoint, constants.XWso2endpoints)
+		}
+		if len(rule.Conditions) == 0 {
+			return fmt.Errorf("at least one condition is required for the routing rule of the endpoint %q", rule.Endpoint)
+		}
+		for _, condition := range rule.Conditions {
+			if err := condition.validate(); err != nil {
+				return fmt.Errorf("invalid condition in the routing rule of the endpoint %q. %v", rule.Endpoint, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (condition *RoutingCondition) validate() error {
+	switch condition.Type {
+	case constants.RoutingConditionHeader, constants.RoutingConditionQuery, constants.RoutingConditionClaim:
+		if condition.Name == "" {
+			return fmt.Errorf("name is required for the %v condition", condition.Type)
+		}
+		// the claim names are passed to the enforcer as a comma separated list
+		if condition.Type == constants.RoutingConditionClaim && strings.Contains(condition.Name, ",") {
+			return fmt.Errorf("invalid claim name %q", condition.Name)
+		}
+	case constants.RoutingConditionPath:
+		if condition.Index < 0 {
+			return fmt.Errorf("invalid path segment index %v", condition.Index)
+		}
+	default:
+		return fmt.Errorf("unsupported condition type %q", condition.Type)
+	}
+	if (condition.Value == "") == (condition.Regex == "") {
+		return errors.New("either value or regex should be provided")
+	}
+	if condition.Regex != "" {
+		if _, err := regexp.Compile(condition.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q", condition.Regex)
+		}
+	}
+	return nil
+}
+
+// getXWso2Mirror extracts the value of x-wso2-mirror extension and creates the mirror endpoint cluster.
+// if the property is not available, nil is returned.
+func getXWso2Mirror(vendorExtensions map[string]interface{}) (*MirrorConfig, error) {
+	y, found := vendorExtensions[constants.XWso2Mirror]
+	if !found {
+		return nil, nil
+	}
+	mirror := &MirrorConfig{}
+	if err := parser.Decode(y, mirror); err != nil {
+		return nil, err
+	}
+	return mirror, mirror.validate()
+}
+
+// getXWso2Cors extracts the value of x-wso2-cors extension of a resource or an operation.
+// if the property is not available, nil is returned. CORS is enabled unless it is disabled explicitly.
+func getXWso2Cors(vendorExtensions map[string]interface{}) (*CorsConfig, error) {
+	y, found := vendorExtensions[constants.XWso2Cors]
+	if !found {
+		return nil, nil
+	}
+	corsConfig := &CorsConfig{Enabled: true}
+	if err := parser.Decode(y, corsConfig); err != nil {
+		return nil, err
+	}
+	return corsConfig, corsConfig.validate()
+}
+
+// validate checks whether the origin regexes are valid regular expressions.
+func (corsConfig *CorsConfig) validate() error {
+	for _, originRegex := range corsConfig.AccessControlAllowOriginRegexes {
+		if _, err := regexp.Compile(originRegex); err != nil {
+			return fmt.Errorf("invalid origin regex %v. %v", originRegex, err.Error())
+		}
+	}
+	if corsConfig.AccessControlMaxAge < 0 {
+		return fmt.Errorf("invalid max age %v, the value should not be negative", corsConfig.AccessControlMaxAge)
+	}
+	return nil
+}
+
+// getXWso2Proxy extracts the value of x-wso2-proxy extension of an API.
+// if the property is not available, nil is returned.
+func getXWso2Proxy(vendorExtensions map[string]interface{}) (*ProxyConfig, error) {
+	y, found := vendorExtensions[constants.XWso2Proxy]
+	if !found {
+		return nil, nil
+	}
+	proxy := &ProxyConfig{}
+	if err := parser.Decode(y, proxy); err != nil {
+		return nil, err
+	}
+	return proxy, proxy.validate()
+}
+
+// getGlobalProxyConfig returns the upstream proxy configured in the config toml, or nil if it is not enabled.
+func getGlobalProxyConfig() *ProxyConfig {
+	conf, _ := config.ReadConfigs()
+	proxyConf := conf.Envoy.Upstream.Proxy
+	if !proxyConf.Enabled {
+		return nil
+	}
+	enabled := true
+	return &ProxyConfig{
+		Enabled:  &enabled,
+		Host:     proxyConf.Host,
+		Port:     proxyConf.Port,
+		Username: proxyConf.Username,
+		Password: proxyConf.Password,
+		NoProxy:  proxyConf.NoProxy,
+	}
+}
+
+// validate checks whether the proxy host and port are provided when the proxy is enabled.
+func (proxy *ProxyConfig) validate() error {
+	if !proxy.IsEnabled() {
+		return nil
+	}
+	if proxy.Host == "" {
+		return errors.New("proxy host is required")
+	}
+	if proxy.Port == 0 || proxy.Port > 65535 {
+		return fmt.Errorf("invalid proxy port %d", proxy.Port)
+	}
+	return nil
+}
+
+// IsEnabled returns true if the proxy is available and not disabled explicitly.
+func (proxy *ProxyConfig) IsEnabled() bool {
+	return proxy != nil && (proxy.Enabled == nil || *proxy.Enabled)
+}
+
+// IsProxied returns true if the given endpoint host should be reached through the proxy. An entry of the
+// no proxy list starting with "." or "*." matches the subdomains, and "*" matches all the hosts.
+func (proxy *ProxyConfig) IsProxied(host string) bool {
+	if !proxy.IsEnabled() {
+		return false
+	}
+	host = strings.ToLower(host)
+	for _, noProxyHost := range proxy.NoProxy {
+		noProxyHost = strings.ToLower(strings.TrimSpace(noProxyHost))
+		if noProxyHost == "*" || noProxyHost == host {
+			return false
+		}
+		if domain := strings.TrimPrefix(noProxyHost, "*"); strings.HasPrefix(domain, ".") &&
+			strings.HasSuffix(host, domain) {
+			return false
+		}
+	}
+	return true
+}
+
+// getXWso2Timeout extracts the value of x-wso2-timeout extension of an operation.
+// if the property is not available, nil is returned.
+func getXWso2Timeout(vendorExtensions map[string]interface{}) (*TimeoutConfig, error) {
+	y, found := vendorExtensions[constants.XWso2Timeout]
+	if !found {
+		return nil, nil
+	}
+	return decodeTimeoutConfig(y)
+}
+
+// decodeTimeoutConfig creates the timeout config from the value of x-wso2-timeout extension or the timeout of an
+// api.yaml operation.
+func decodeTimeoutConfig(value interface{}) (*TimeoutConfig, error) {
+	timeoutConfig := &TimeoutConfig{}
+	if err := parser.Decode(value, timeoutConfig); err != nil {
+		return nil, err
+	}
+	return timeoutConfig, timeoutConfig.validate()
+}
+
+// validate checks whether a timeout is provided. The timeouts are limited to the maximum route timeout.
+func (timeoutConfig *TimeoutConfig) validate() error {
+	if timeoutConfig.TimeoutInMillis == 0 && timeoutConfig.IdleTimeoutInMillis == 0 {
+		return errors.New("at least one of the timeout or the idle timeout is required")
+	}
+	conf, _ := config.ReadConfigs()
+	maxTimeoutInMillis := conf.Envoy.Upstream.Timeouts.MaxRouteTimeoutInSeconds * 1000
+	if timeoutConfig.TimeoutInMillis > maxTimeoutInMillis {
+		timeoutConfig.TimeoutInMillis = maxTimeoutInMillis
+	}
+	if timeoutConfig.IdleTimeoutInMillis > maxTimeoutInMillis {
+		timeoutConfig.IdleTimeoutInMillis = maxTimeoutInMillis
+	}
+	return nil
+}
+
+// getXWso2Retry extracts the value of x-wso2-retry extension of an operation.
+// if the property is not available, nil is returned.
+func getXWso2Retry(vendorExtensions map[string]interface{}) (*RetryPolicy, error) {
+	y, found := vendorExtensions[constants.XWso2Retry]
+	if !found {
+		return nil, nil
+	}
+	return decodeRetryPolicy(y)
+}
+
+// decodeRetryPolicy creates the retry policy from the value of x-wso2-retry extension or the retry policy of an
+// api.yaml operation. The requests are retried once unless the count is provided.
+func decodeRetryPolicy(value interface{}) (*RetryPolicy, error) {
+	retryPolicy := &RetryPolicy{Count: 1}
+	if err := parser.Decode(value, retryPolicy); err != nil {
+		return nil, err
+	}
+	return retryPolicy, retryPolicy.validate()
+}
+
+// validate checks the retry count, the retry conditions and the status codes of the retry policy. The status codes
+// and the back-off base interval default to the global retry configuration.
+func (retryPolicy *RetryPolicy) validate() error {
+	conf, _ := config.ReadConfigs()
+	retryConfig := conf.Envoy.Upstream.Retry
+	if retryPolicy.Count < 0 || retryPolicy.Count > int32(retryConfig.MaxRetryCount) {
+		return fmt.Errorf("invalid retry count %v, the value should be within the range 0 - %v",
+			retryPolicy.Count, retryConfig.MaxRetryCount)
+	}
+	for _, retryOn := range retryPolicy.RetryOn {
+		if !ArrayContains([]string{constants.RetryOn5xx, constants.RetryOnGatewayError, constants.RetryOnReset,
+			constants.RetryOnConnectFailure}, retryOn) {
+			return fmt.Errorf("invalid retry condition %q", retryOn)
+		}
+	}
+	for _, statusCode := range retryPolicy.StatusCodes {
+		if statusCode > 598 || statusCode < 401 {
+			return fmt.Errorf("invalid status code %v, the value should be within the range 401 - 598", statusCode)
+		}
+	}
+	if len(retryPolicy.RetryOn) == 0 && len(retryPolicy.StatusCodes) == 0 {
+		retryPolicy.StatusCodes = retryConfig.StatusCodes
+	}
+	if retryPolicy.BaseIntervalInMillis == 0 {
+		retryPolicy.BaseIntervalInMillis = retryConfig.BaseIntervalInMillis
+	}
+	if retryPolicy.MaxIntervalInMillis != 0 && retryPolicy.MaxIntervalInMillis < retryPolicy.BaseIntervalInMillis {
+		return fmt.Errorf("invalid max interval %v, the value should not be less than the base interval %v",
+			retryPolicy.MaxIntervalInMillis, retryPolicy.BaseIntervalInMillis)
+	}
+	if retryPolicy.HostSelectionRetryMaxAttempts < 0 {
+		return fmt.Errorf("invalid host selection retry max attempts %v, the value should not be negative",
+			retryPolicy.HostSelectionRetryMaxAttempts)
+	}
+	return nil
+}
+
+// validate checks the mirror percentage and creates the endpoint cluster from the mirror urls.
+// The percentage defaults to 100 if it is not provided.
+func (mirror *MirrorConfig) validate() error {
+	if mirror.Percentage == nil {
+		percentage := float64(100)
+		mirror.Percentage = &percentage
+	}
+	if *mirror.Percentage < 0 || *mirror.Percentage > 100 {
+		return fmt.Errorf("invalid mirror percentage %v, the value should be within 0 and 100", *mirror.Percentage)
+	}
+	var urls []interface{}
+	for _, url := range mirror.URLs {
+		if strings.TrimSpace(url) != "" {
+			urls = append(urls, strings.TrimSpace(url))
+		}
+	}
+	if len(urls) == 0 {
+		return errors.New("at least one mirror url is required")
+	}
+	endpoints, err := processEndpointUrls(urls)
+	if err != nil {
+		return err
+	}
+	mirror.EndpointCluster = generateEndpointCluster(constants.MirrorClustersConfigNamePrefix, endpoints, constants.LoadBalance)
+	return nil
+}
+
+// getFaultInjectionFromPolicyParams creates the fault injection config from the parameters of the
+// FAULT_INJECTION policy. The numeric parameters can be provided either as numbers or as strings.
+func getFaultInjectionFromPolicyParams(params map[string]interface{}) (*FaultInjectionConfig, error) {
+	fault := &FaultInjectionConfig{}
+	delayDuration, err := getNumberParam(params, constants.FaultDelayDuration)
+	if err != nil {
+		return nil, err
+	}
+	if delayDuration < 0 || delayDuration != float64(uint32(delayDuration)) {
+		return nil, fmt.Errorf("invalid delay duration %v, the value should be a positive number of milliseconds",
+			delayDuration)
+	}
+	fault.DelayDuration = uint32(delayDuration)
+	abortStatusCode, err := getNumberParam(params, constants.FaultAbortStatusCode)
+	if err != nil {
+		return nil, err
+	}
+	if abortStatusCode < 0 || abortStatusCode != float64(uint32(abortStatusCode)) {
+		return nil, fmt.Errorf("invalid abort status code %v", abortStatusCode)
+	}
+	fault.AbortStatusCode = uint32(abortStatusCode)
+	if fault.DelayPercentage, err = getOptionalNumberParam(params, constants.FaultDelayPercentage); err != nil {
+		return nil, err
+	}
+	if fault.AbortPercentage, err = getOptionalNumberParam(params, constants.FaultAbortPercentage); err != nil {
+		return nil, err
+	}
+	if headerName, ok := params[constants.FaultHeaderName].(string); ok {
+		fault.HeaderName = strings.TrimSpace(headerName)
+	}
+	if headerValue, ok := params[constants.FaultHeaderValue].(string); ok {
+		fault.HeaderValue = headerValue
+	}
+	return fault, fault.validate()
+}
+
+// getOptionalNumberParam returns nil if the parameter is not provided, so that an explicit zero could be
+// distinguished from a missing value.
+func getOptionalNumberParam(params map[string]interface{}, key string) (*float64, error) {
+	if value, ok := params[key].(string); params[key] == nil || (ok && strings.TrimSpace(value) == "") {
+		return nil, nil
+	}
+	number, err := getNumberParam(params, key)
+	if err != nil {
+		return nil, err
+	}
+	return &number, nil
+}
+
+func getNumberParam(params map[string]interface{}, key string) (float64, error) {
+	switch value := params[key].(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return value, nil
+	case int:
+		return float64(value), nil
+	case string:
+		if strings.TrimSpace(value) == "" {
+			return 0, nil
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for the parameter %v", value, key)
+		}
+		return number, nil
+	default:
+		return 0, fmt.Errorf("invalid value %v for the parameter %v", value, key)
+	}
+}
+
+// validate checks the delay and abort configurations of the fault. The percentages default to 100 if
+// the delay or the abort is provided without the percentage.
+func (fault *FaultInjectionConfig) validate() error {
+	if fault.DelayDuration == 0 && fault.AbortStatusCode == 0 {
+		return errors.New("at least one of the delay duration or the abort status code is required for the fault injection")
+	}
+	if fault.DelayDuration > 0 && fault.DelayPercentage == nil {
+		percentage := float64(100)
+		fault.DelayPercentage = &percentage
+	}
+	if fault.AbortStatusCode > 0 && fault.AbortPercentage == nil {
+		percentage := float64(100)
+		fault.AbortPercentage = &percentage
+	}
+	for _, percentage := range []*float64{fault.DelayPercentage, fault.AbortPercentage} {
+		if percentage != nil && (*percentage < 0 || *percentage > 100) {
+			return fmt.Errorf("invalid fault percentage %v, the value should be within 0 and 100", *percentage)
+		}
+	}
+	if fault.AbortStatusCode > 0 && (fault.AbortStatusCode < 200 || fault.AbortStatusCode > 599) {
+		return fmt.Errorf("invalid abort status code %v, the value should be within 200 and 599", fault.AbortStatusCode)
+	}
+	if fault.HeaderName == "" && fault.HeaderValue != "" {
+		return errors.New("header name is required when the header value is provided for the fault injection")
+	}
+	return nil
+}
+
 // ResolveThrottlingTier extracts the value of x-wso2-throttling-tier and
 // x-throttling-tier extension. if x-wso2-throttling-tier is available it
 // will be prioritized.
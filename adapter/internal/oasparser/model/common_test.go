@@ -115,3 +115,444 @@ func TestGetPathParamToIndexMap(t *testing.T) {
 		assert.Equal(t, test.indexMap, indexMap)
 	}
 }
+
+func TestGetIPFilterFromPolicyParams(t *testing.T) {
+	tests := []struct {
+		params     map[string]interface{}
+		ipFilter   *IPFilterConfig
+		isExpError bool
+		message    string
+	}{
+		{
+			params:   map[string]interface{}{"allow": "10.0.0.0/8, 192.168.1.10"},
+			ipFilter: &IPFilterConfig{Allow: []string{"10.0.0.0/8", "192.168.1.10/32"}},
+			message:  `Comma separated allow list with a bare ip`,
+		},
+		{
+			params:   map[string]interface{}{"deny": []interface{}{"2001:db8::1", "172.16.0.0/12"}},
+			ipFilter: &IPFilterConfig{Deny: []string{"2001:db8::1/128", "172.16.0.0/12"}},
+			message:  `Deny list as an array with an ipv6 address`,
+		},
+		{
+			params:     map[string]interface{}{"allow": "10.0.0.0/33"},
+			isExpError: true,
+			message:    `Invalid CIDR range`,
+		},
+		{
+			params:     map[string]interface{}{},
+			isExpError: true,
+			message:    `Neither allow nor deny list`,
+		},
+	}
+
+	for _, test := range tests {
+		ipFilter, err := getIPFilterFromPolicyParams(test.params)
+		if test.isExpError {
+			assert.Error(t, err, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+			assert.Equal(t, test.ipFilter, ipFilter, test.message)
+		}
+	}
+}
+
+func TestGetFaultInjectionFromPolicyParams(t *testing.T) {
+	tests := []struct {
+		params         map[string]interface{}
+		faultInjection *FaultInjectionConfig
+		isExpError     bool
+		message        string
+	}{
+		{
+			params: map[string]interface{}{"delayDuration": "2000", "delayPercentage": 25.5, "headerName": "x-fault"},
+			faultInjection: &FaultInjectionConfig{DelayDuration: 2000, DelayPercentage: floatPointer(25.5),
+				HeaderName: "x-fault"},
+			message: `Delay with a string duration and header activation`,
+		},
+		{
+			params:         map[string]interface{}{"abortStatusCode": float64(503)},
+			faultInjection: &FaultInjectionConfig{AbortStatusCode: 503, AbortPercentage: floatPointer(100)},
+			message:        `Abort without the percentage`,
+		},
+		{
+			params:         map[string]interface{}{"abortStatusCode": float64(503), "abortPercentage": float64(0)},
+			faultInjection: &FaultInjectionConfig{AbortStatusCode: 503, AbortPercentage: floatPointer(0)},
+			message:        `Abort with an explicit zero percentage`,
+		},
+		{
+			params:         map[string]interface{}{"delayDuration": 500, "delayPercentage": "0"},
+			faultInjection: &FaultInjectionConfig{DelayDuration: 500, DelayPercentage: floatPointer(0)},
+			message:        `Delay with an explicit zero percentage`,
+		},
+		{
+			params:         map[string]interface{}{"delayDuration": 500, "delayPercentage": " "},
+			faultInjection: &FaultInjectionConfig{DelayDuration: 500, DelayPercentage: floatPointer(100)},
+			message:        `Delay with an empty percentage`,
+		},
+		{
+			params:     map[string]interface{}{"abortStatusCode": "700"},
+			isExpError: true,
+			message:    `Invalid abort status code`,
+		},
+		{
+			params:     map[string]interface{}{"delayDuration": "100", "delayPercentage": "150"},
+			isExpError: true,
+			message:    `Invalid delay percentage`,
+		},
+		{
+			params:     map[string]interface{}{"headerName": "x-fault"},
+			isExpError: true,
+			message:    `Neither delay nor abort`,
+		},
+	}
+
+	for _, test := range tests {
+		faultInjection, err := getFaultInjectionFromPolicyParams(test.params)
+		if test.isExpError {
+			assert.Error(t, err, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+			assert.Equal(t, test.faultInjection, faultInjection, test.message)
+		}
+	}
+}
+
+func TestGetXWso2Routing(t *testing.T) {
+	xWso2Endpoints := map[string]*EndpointCluster{"tenant-a": {}, "tenant-b": {}}
+	tests := []struct {
+		routing    interface{}
+		isExpError bool
+		message    string
+	}{
+		{
+			routing: map[string]interface{}{
+				"defaultEndpoint": "tenant-b",
+				"rules": []interface{}{
+					map[string]interface{}{
+						"endpoint": "tenant-a",
+						"conditions": []interface{}{
+							map[string]interface{}{"type": "header", "name": "x-tenant", "value": "a"},
+							map[string]interface{}{"type": "path", "index": 1, "regex": "a-[0-9]+"},
+						},
+					},
+				},
+			},
+			isExpError: false,
+			message:    `Valid routing configuration`,
+		},
+		{
+			routing: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"endpoint":   "tenant-c",
+						"conditions": []interface{}{map[string]interface{}{"type": "query", "name": "tenant", "value": "c"}},
+					},
+				},
+			},
+			isExpError: true,
+			message:    `Undefined endpoint`,
+		},
+		{
+			routing: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"endpoint":   "tenant-a",
+						"conditions": []interface{}{map[string]interface{}{"type": "claim", "value": "a"}},
+					},
+				},
+			},
+			isExpError: true,
+			message:    `Claim name is not provided`,
+		},
+		{
+			routing: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"endpoint": "tenant-a",
+						"conditions": []interface{}{
+							map[string]interface{}{"type": "claim", "name": "tenant,region", "value": "a"}},
+					},
+				},
+			},
+			isExpError: true,
+			message:    `Claim name with a comma`,
+		},
+		{
+			routing: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"endpoint":   "tenant-a",
+						"conditions": []interface{}{map[string]interface{}{"type": "cookie", "name": "tenant", "value": "a"}},
+					},
+				},
+			},
+			isExpError: true,
+			message:    `Unsupported condition type`,
+		},
+		{
+			routing: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"endpoint":   "tenant-a",
+						"conditions": []interface{}{map[string]interface{}{"type": "header", "name": "x-tenant"}},
+					},
+				},
+			},
+			isExpError: true,
+			message:    `Neither value nor regex`,
+		},
+	}
+
+	for _, test := range tests {
+		routing, err := getXWso2Routing(map[string]interface{}{"x-wso2-routing": test.routing}, xWso2Endpoints)
+		if test.isExpError {
+			assert.Error(t, err, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+			assert.Equal(t, 2, len(routing.Rules[0].Conditions), test.message)
+		}
+	}
+}
+
+func TestGetXWso2Mirror(t *testing.T) {
+	tests := []struct {
+		mirror     interface{}
+		percentage float64
+		isExpError bool
+		message    string
+	}{
+		{
+			mirror:     map[string]interface{}{"urls": []interface{}{"http://new-backend:8080/v1"}, "percentage": 20},
+			percentage: 20,
+			message:    `Mirror with percentage`,
+		},
+		{
+			mirror:     map[string]interface{}{"urls": []interface{}{"https://new-backend/v1"}},
+			percentage: 100,
+			message:    `Mirror without percentage`,
+		},
+		{
+			mirror:     map[string]interface{}{"urls": []interface{}{"http://new-backend/v1"}, "percentage": 0},
+			percentage: 0,
+			message:    `Mirror with zero percentage`,
+		},
+		{
+			mirror:     map[string]interface{}{"urls": []interface{}{"http://new-backend/v1"}, "percentage": 120},
+			isExpError: true,
+			message:    `Invalid percentage`,
+		},
+		{
+			mirror:     map[string]interface{}{"percentage": 50},
+			isExpError: true,
+			message:    `Mirror urls are not provided`,
+		},
+	}
+
+	for _, test := range tests {
+		mirror, err := getXWso2Mirror(map[string]interface{}{"x-wso2-mirror": test.mirror})
+		if test.isExpError {
+			assert.Error(t, err, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+			assert.Equal(t, test.percentage, *mirror.Percentage, test.message)
+			assert.Equal(t, "clusterMirror", mirror.EndpointCluster.EndpointPrefix, test.message)
+		}
+	}
+}
+
+func TestGetXWso2Cors(t *testing.T) {
+	tests := []struct {
+		cors       interface{}
+		enabled    bool
+		isExpError bool
+		message    string
+	}{
+		{
+			cors: map[string]interface{}{"accessControlAllowOrigins": []interface{}{"https://*.example.com"},
+				"accessControlMaxAge": 600},
+			enabled: true,
+			message: `CORS is enabled by default`,
+		},
+		{
+			cors:    map[string]interface{}{"corsConfigurationEnabled": false},
+			enabled: false,
+			message: `CORS is disabled`,
+		},
+		{
+			cors:       map[string]interface{}{"accessControlAllowOriginRegexes": []interface{}{"https://(foo.example.com"}},
+			isExpError: true,
+			message:    `Invalid origin regex`,
+		},
+		{
+			cors:       map[string]interface{}{"accessControlMaxAge": -1},
+			isExpError: true,
+			message:    `Invalid max age`,
+		},
+	}
+
+	for _, test := range tests {
+		cors, err := getXWso2Cors(map[string]interface{}{"x-wso2-cors": test.cors})
+		if test.isExpError {
+			assert.Error(t, err, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+			assert.Equal(t, test.enabled, cors.Enabled, test.message)
+		}
+	}
+	cors, err := getXWso2Cors(map[string]interface{}{})
+	assert.Nil(t, err, "Error should not be returned if the extension is not available")
+	assert.Nil(t, cors, "CORS configuration should not be available")
+}
+
+func TestGetXWso2Timeout(t *testing.T) {
+	timeout, err := getXWso2Timeout(map[string]interface{}{"x-wso2-timeout": map[string]interface{}{
+		"timeoutInMillis": 120000, "idleTimeoutInMillis": 5000}})
+	assert.Nil(t, err, "Error should not be returned for a valid timeout")
+	assert.Equal(t, uint32(60000), timeout.TimeoutInMillis, "Timeout should be limited to the maximum route timeout")
+	assert.Equal(t, uint32(5000), timeout.IdleTimeoutInMillis, "Idle timeout mismatch")
+
+	_, err = getXWso2Timeout(map[string]interface{}{"x-wso2-timeout": map[string]interface{}{}})
+	assert.Error(t, err, "Error should be returned if a timeout is not provided")
+
+	timeout, err = getXWso2Timeout(map[string]interface{}{})
+	assert.Nil(t, err, "Error should not be returned if the extension is not available")
+	assert.Nil(t, timeout, "Timeout should not be available")
+}
+
+func TestGetXWso2Retry(t *testing.T) {
+	tests := []struct {
+		retry      interface{}
+		expected   *RetryPolicy
+		isExpError bool
+		message    string
+	}{
+		{
+			retry:    map[string]interface{}{},
+			expected: &RetryPolicy{Count: 1, StatusCodes: []uint32{504}, BaseIntervalInMillis: 25},
+			message:  `Defaults of the global retry configuration`,
+		},
+		{
+			retry: map[string]interface{}{"count": 3, "retryOn": []interface{}{"5xx", "reset"},
+				"perTryTimeoutInMillis": 1000, "baseIntervalInMillis": 100, "maxIntervalInMillis": 500,
+				"hostSelectionRetryMaxAttempts": 3},
+			expected: &RetryPolicy{Count: 3, RetryOn: []string{"5xx", "reset"}, PerTryTimeoutInMillis: 1000,
+				BaseIntervalInMillis: 100, MaxIntervalInMillis: 500, HostSelectionRetryMaxAttempts: 3},
+			message: `Retry conditions without status codes`,
+		},
+		{
+			retry:      map[string]interface{}{"count": 6},
+			isExpError: true,
+			message:    `Retry count above the maximum retry count`,
+		},
+		{
+			retry:      map[string]interface{}{"retryOn": []interface{}{"retriable-4xx"}},
+			isExpError: true,
+			message:    `Unsupported retry condition`,
+		},
+		{
+			retry:      map[string]interface{}{"statusCodes": []interface{}{200}},
+			isExpError: true,
+			message:    `Invalid status code`,
+		},
+		{
+			retry:      map[string]interface{}{"baseIntervalInMillis": 100, "maxIntervalInMillis": 50},
+			isExpError: true,
+			message:    `Max interval less than the base interval`,
+		},
+	}
+
+	for _, test := range tests {
+		retry, err := getXWso2Retry(map[string]interface{}{"x-wso2-retry": test.retry})
+		if test.isExpError {
+			assert.Error(t, err, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+			assert.Equal(t, test.expected, retry, test.message)
+		}
+	}
+	retry, err := getXWso2Retry(map[string]interface{}{})
+	assert.Nil(t, err, "Error should not be returned if the extension is not available")
+	assert.Nil(t, retry, "Retry policy should not be available")
+}
+
+func TestGetXWso2Proxy(t *testing.T) {
+	proxy, err := getXWso2Proxy(map[string]interface{}{"x-wso2-proxy": map[string]interface{}{
+		"host": "proxy.example.com", "port": 3128, "noProxy": []interface{}{"localhost", ".internal.example.com"}}})
+	assert.Nil(t, err, "Error should not be returned for a valid proxy")
+	assert.True(t, proxy.IsEnabled(), "Proxy should be enabled unless it is disabled explicitly")
+	assert.True(t, proxy.IsProxied("api.example.com"), "Host not in the no proxy list should be proxied")
+	assert.False(t, proxy.IsProxied("localhost"), "Host in the no proxy list should not be proxied")
+	assert.False(t, proxy.IsProxied("orders.internal.example.com"), "Subdomain should not be proxied")
+	assert.True(t, proxy.IsProxied("internal.example.com.evil.com"), "Host with a different domain should be proxied")
+
+	proxy, err = getXWso2Proxy(map[string]interface{}{"x-wso2-proxy": map[string]interface{}{"enabled": false}})
+	assert.Nil(t, err, "Error should not be returned for a disabled proxy without the host")
+	assert.False(t, proxy.IsProxied("api.example.com"), "Host should not be proxied if the proxy is disabled")
+
+	_, err = getXWso2Proxy(map[string]interface{}{"x-wso2-proxy": map[string]interface{}{"port": 3128}})
+	assert.Error(t, err, "Error should be returned if the proxy host is not provided")
+
+	_, err = getXWso2Proxy(map[string]interface{}{"x-wso2-proxy": map[string]interface{}{"host": "proxy.example.com"}})
+	assert.Error(t, err, "Error should be returned if the proxy port is not provided")
+
+	proxy, err = getXWso2Proxy(map[string]interface{}{})
+	assert.Nil(t, err, "Error should not be returned if the extension is not available")
+	assert.False(t, proxy.IsProxied("api.example.com"), "Host should not be proxied if the proxy is not available")
+}
+
+func TestGetXWso2MaxRequestBodySize(t *testing.T) {
+	tests := []struct {
+		size       interface{}
+		expected   uint32
+		isExpError bool
+		message    string
+	}{
+		{
+			size:     float64(1048576),
+			expected: 1048576,
+			message:  `Size parsed from JSON`,
+		},
+		{
+			size:     2048,
+			expected: 2048,
+			message:  `Size parsed from YAML`,
+		},
+		{
+			size:       float64(0),
+			isExpError: true,
+			message:    `Zero size`,
+		},
+		{
+			size:       1.5,
+			isExpError: true,
+			message:    `Fractional size`,
+		},
+		{
+			size:       float64(5000000000),
+			isExpError: true,
+			message:    `Size exceeding the maximum`,
+		},
+		{
+			size:       "1MB",
+			isExpError: true,
+			message:    `Size which is not a number`,
+		},
+	}
+	for _, test := range tests {
+		size, err := getXWso2MaxRequestBodySize(map[string]interface{}{"x-wso2-max-request-body-size": test.size})
+		if test.isExpError {
+			assert.Error(t, err, test.message)
+			continue
+		}
+		assert.Nil(t, err, test.message)
+		assert.Equal(t, test.expected, size, test.message)
+	}
+
+	size, err := getXWso2MaxRequestBodySize(map[string]interface{}{})
+	assert.Nil(t, err, "Error should not be returned if the extension is not available")
+	assert.Equal(t, uint32(0), size, "Size should not be limited if the extension is not available")
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}
@@ -58,7 +58,14 @@ type MgwSwagger struct {
 	resources                  []*Resource
 	xWso2Basepath              string
 	xWso2HTTP2BackendEnabled   bool
+	xWso2RouterJWTValidation   *bool
+	xWso2MaxRequestBodySize    uint32
 	xWso2Cors                  *CorsConfig
+	xWso2ErrorResponses        []ErrorResponseTemplate
+	xWso2IPFilter              *IPFilterConfig
+	xWso2Routing               *RoutingConfig
+	xWso2Mirror                *MirrorConfig
+	xWso2Proxy                 *ProxyConfig
 	securityScheme             []SecurityScheme
 	security                   []map[string][]string
 	xWso2ThrottlingTier        string
@@ -69,11 +76,16 @@ type MgwSwagger struct {
 	EndpointType               string
 	EndpointImplementationType string
 	LifecycleStatus            string
+	RevisionID                 int
+	DeployedTime               time.Time
 	xWso2RequestBodyPass       bool
 	IsDefaultVersion           bool
 	clientCertificates         []Certificate
 	xWso2MutualSSL             string
 	xWso2ApplicationSecurity   bool
+	upstreamClientCerts        map[string]string // endpoint url -> SDS secret name of the client certificate
+	upstreamCerts              map[string][]byte // endpoint url -> trusted certificates of the endpoint
+	interceptorCerts           map[string][]byte // interceptor url -> trusted certificates of the interceptor
 }
 
 // EndpointCluster represent an upstream cluster
@@ -115,6 +127,27 @@ type EndpointConfig struct {
 	RetryConfig     *RetryConfig     `mapstructure:"retryConfig"`
 	TimeoutInMillis uint32           `mapstructure:"timeoutInMillis"`
 	CircuitBreakers *CircuitBreakers `mapstructure:"circuitBreakers"`
+	Proxy           *ProxyConfig     `mapstructure:"proxy"`
+	// Connection level settings of the upstream connections
+	MaxConnectionDurationInSeconds  uint32        `mapstructure:"maxConnectionDurationInSeconds"`
+	ConnectionIdleTimeoutInSeconds  uint32        `mapstructure:"connectionIdleTimeoutInSeconds"`
+	MaxRequestsPerConnection        uint32        `mapstructure:"maxRequestsPerConnection"`
+	PerConnectionBufferLimitInBytes uint32        `mapstructure:"perConnectionBufferLimitInBytes"`
+	TCPKeepalive                    *TCPKeepalive `mapstructure:"tcpKeepalive"`
+	// PreserveHeaderCase preserves the case of the HTTP/1 response headers of the endpoints. The case of the
+	// request headers is preserved if it is enabled for the router downstream as well.
+	PreserveHeaderCase bool `mapstructure:"preserveHeaderCase"`
+	// UpstreamProtocol enum {auto, h1, h2}. auto selects the protocol via ALPN, and the protocol is derived
+	// from the x-wso2-http2-backend-enabled extension if it is not provided.
+	UpstreamProtocol string `mapstructure:"upstreamProtocol"`
+}
+
+// TCPKeepalive holds the TCP keepalive settings of the upstream connections. The system defaults are used
+// for the values not provided.
+type TCPKeepalive struct {
+	Probes            uint32 `mapstructure:"probes"`
+	TimeInSeconds     uint32 `mapstructure:"timeInSeconds"`
+	IntervalInSeconds uint32 `mapstructure:"intervalInSeconds"`
 }
 
 // RetryConfig holds the parameters for retries done by cc to the EndpointCluster
@@ -132,6 +165,17 @@ type CircuitBreakers struct {
 	MaxConnectionPools int32 `mapstructure:"maxConnectionPools"`
 }
 
+// ProxyConfig holds the HTTP CONNECT proxy through which the upstream endpoints are reached. The proxy is
+// enabled unless it is disabled explicitly. The endpoint hosts in the no proxy list are reached directly.
+type ProxyConfig struct {
+	Enabled  *bool    `mapstructure:"enabled"`
+	Host     string   `mapstructure:"host"`
+	Port     uint32   `mapstructure:"port"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	NoProxy  []string `mapstructure:"noProxy"`
+}
+
 // SecurityScheme represents the structure of an security scheme.
 type SecurityScheme struct {
 	DefinitionName string // Arbitrary name used to define the security scheme. ex: default, myApikey
@@ -140,14 +184,139 @@ type SecurityScheme struct {
 	In             string // Where the api key found in. Valid: query, header
 }
 
-// CorsConfig represents the API level Cors Configuration
+// CorsConfig represents the Cors Configuration of an API, a resource or an operation.
+//
+// An allowed origin may contain the wildcard "*" in place of a part of the host or the port
+// (eg: https://*.example.com). AccessControlAllowOriginRegexes are matched against the whole origin.
+// AccessControlMaxAge is the number of seconds a preflight response can be cached. It is not sent if it is zero.
 type CorsConfig struct {
-	Enabled                       bool     `mapstructure:"corsConfigurationEnabled"`
-	AccessControlAllowCredentials bool     `mapstructure:"accessControlAllowCredentials,omitempty"`
-	AccessControlAllowHeaders     []string `mapstructure:"accessControlAllowHeaders"`
-	AccessControlAllowMethods     []string `mapstructure:"accessControlAllowMethods"`
-	AccessControlAllowOrigins     []string `mapstructure:"accessControlAllowOrigins"`
-	AccessControlExposeHeaders    []string `mapstructure:"accessControlExposeHeaders"`
+	Enabled                         bool     `mapstructure:"corsConfigurationEnabled"`
+	AccessControlAllowCredentials   bool     `mapstructure:"accessControlAllowCredentials,omitempty"`
+	AccessControlAllowHeaders       []string `mapstructure:"accessControlAllowHeaders"`
+	AccessControlAllowMethods       []string `mapstructure:"accessControlAllowMethods"`
+	AccessControlAllowOrigins       []string `mapstructure:"accessControlAllowOrigins"`
+	AccessControlAllowOriginRegexes []string `mapstructure:"accessControlAllowOriginRegexes"`
+	AccessControlExposeHeaders      []string `mapstructure:"accessControlExposeHeaders"`
+	AccessControlMaxAge             int      `mapstructure:"accessControlMaxAge"`
+}
+
+// ErrorResponseTemplate represents a custom error response returned by the router, instead of
+// the default error body, for the given set of envoy response flags.
+//
+// Body is an envoy substitution format string. In addition to the envoy command operators
+// (eg: %RESPONSE_CODE%, %REQ(X-REQUEST-ID)%), ${code}, ${message} and ${description} are replaced with
+// the values of the default error response of the matched flag.
+type ErrorResponseTemplate struct {
+	Flags       []string `mapstructure:"flags" yaml:"flags" json:"flags"`
+	Accept      string   `mapstructure:"accept" yaml:"accept" json:"accept"`
+	StatusCode  uint32   `mapstructure:"statusCode" yaml:"statusCode" json:"statusCode"`
+	ContentType string   `mapstructure:"contentType" yaml:"contentType" json:"contentType"`
+	Body        string   `mapstructure:"body" yaml:"body" json:"body"`
+}
+
+// IPFilterConfig holds the client IP ranges (CIDR notation) allowed to or denied from invoking
+// an API or an operation. If the allow list is not empty, only the clients in the list are allowed.
+// The deny list takes precedence over the allow list.
+type IPFilterConfig struct {
+	Allow []string `mapstructure:"allow"`
+	Deny  []string `mapstructure:"deny"`
+}
+
+// RoutingConfig holds the content based routing rules of an API or a resource. The rules are evaluated
+// in the given order and the request is routed to the x-wso2-endpoints cluster of the first matching rule.
+// If none of the rules match, the request is routed to the default endpoint if provided, otherwise to the
+// production endpoints of the resource.
+type RoutingConfig struct {
+	Rules           []RoutingRule `mapstructure:"rules"`
+	DefaultEndpoint string        `mapstructure:"defaultEndpoint"`
+}
+
+// RoutingRule maps a set of conditions to an endpoint defined under x-wso2-endpoints.
+// All the conditions should match for the rule to be applied.
+type RoutingRule struct {
+	Endpoint   string             `mapstructure:"endpoint"`
+	Conditions []RoutingCondition `mapstructure:"conditions"`
+}
+
+// RoutingCondition holds a single condition of a content based routing rule. The type can be
+// header, query, claim (JWT claim) or path (path segment). For the path type, the index is the zero based
+// position of the segment in the request path after the API base path (ex: 1 for {petId} of /pets/{petId}),
+// which excludes the version of a default version API as well. Either the exact value or the regex should
+// be provided.
+type RoutingCondition struct {
+	Type  string `mapstructure:"type"`
+	Name  string `mapstructure:"name"`
+	Index int    `mapstructure:"index"`
+	Value string `mapstructure:"value"`
+	Regex string `mapstructure:"regex"`
+}
+
+// MirrorConfig holds the traffic mirroring (shadowing) configuration of an API or an operation.
+// The given percentage of the requests is copied to the mirror endpoints and the responses from
+// the mirror endpoints are discarded. If the percentage is not provided, all the requests are mirrored.
+type MirrorConfig struct {
+	URLs            []string         `mapstructure:"urls"`
+	Percentage      *float64         `mapstructure:"percentage"` // all the requests are mirrored if not provided
+	EndpointCluster *EndpointCluster `mapstructure:"-"`
+}
+
+// FaultInjectionConfig holds the faults injected to the requests of an operation. The given percentage
+// of the requests is delayed by the delay duration (milliseconds) and/or aborted with the abort status code.
+// If the header name is provided, faults are injected only to the requests having the header (with the
+// header value, if provided).
+type FaultInjectionConfig struct {
+	DelayDuration   uint32
+	DelayPercentage *float64 // all the requests are delayed if not provided
+	AbortStatusCode uint32
+	AbortPercentage *float64 // all the requests are aborted if not provided
+	HeaderName      string
+	HeaderValue     string
+}
+
+// TimeoutConfig holds the timeouts of the requests of an operation, which override the timeout of the endpoint
+// cluster. The idle timeout is the maximum duration (milliseconds) a request can be idle without any activity.
+type TimeoutConfig struct {
+	TimeoutInMillis     uint32 `mapstructure:"timeoutInMillis"`
+	IdleTimeoutInMillis uint32 `mapstructure:"idleTimeoutInMillis"`
+}
+
+// RetryPolicy holds the retry policy of the requests of an operation, which overrides the retry configuration of
+// the endpoint cluster. The requests are retried on the given conditions and status codes, or on the status codes
+// of the global retry configuration if neither is provided. If the host selection retry max attempts is provided,
+// the retries are sent to the hosts which are not attempted before, if available.
+type RetryPolicy struct {
+	Count                         int32    `mapstructure:"count"`
+	RetryOn                       []string `mapstructure:"retryOn"`
+	StatusCodes                   []uint32 `mapstructure:"statusCodes"`
+	PerTryTimeoutInMillis         uint32   `mapstructure:"perTryTimeoutInMillis"`
+	BaseIntervalInMillis          uint32   `mapstructure:"baseIntervalInMillis"`
+	MaxIntervalInMillis           uint32   `mapstructure:"maxIntervalInMillis"`
+	HostSelectionRetryMaxAttempts int64    `mapstructure:"hostSelectionRetryMaxAttempts"`
+}
+
+// StaticMutations holds the header and query parameter mutations of an operation which do not require the runtime
+// context of the request, hence are applied by the router instead of the enforcer. The mutations are kept in the
+// order of the policies.
+type StaticMutations struct {
+	RequestHeadersToAdd     []NameValuePair
+	RequestHeadersToRemove  []string
+	ResponseHeadersToAdd    []NameValuePair
+	ResponseHeadersToRemove []string
+	QueryMutations          []QueryMutation
+}
+
+// QueryMutation holds a query parameter mutation of the request. The value is the value of the query parameter
+// to be set for the ADD_QUERY action and the new name of the query parameter for the RENAME_QUERY action.
+type QueryMutation struct {
+	Action string
+	Name   string
+	Value  string
+}
+
+// NameValuePair holds the name and the value of a header or a query parameter
+type NameValuePair struct {
+	Name  string
+	Value string
 }
 
 // InterceptEndpoint contains the parameters of endpoint security
@@ -178,6 +347,43 @@ func (swagger *MgwSwagger) GetCorsConfig() *CorsConfig {
 	return swagger.xWso2Cors
 }
 
+// GetXWso2ErrorResponses returns the API level custom error response templates.
+func (swagger *MgwSwagger) GetXWso2ErrorResponses() []ErrorResponseTemplate {
+	return swagger.xWso2ErrorResponses
+}
+
+// SetErrorResponses appends the error response templates provided in the Errors directory of the API project.
+// Templates defined in the API definition take precedence over the templates in the API project.
+func (swagger *MgwSwagger) SetErrorResponses(errorResponses []ErrorResponseTemplate) {
+	for _, errorResponse := range errorResponses {
+		if err := errorResponse.validate(); err != nil {
+			logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Ignoring the error response template of the API %s:%s. %s",
+					swagger.title, swagger.version, err.Error()),
+				Severity:  logging.MINOR,
+				ErrorCode: 2213,
+			})
+			continue
+		}
+		swagger.xWso2ErrorResponses = append(swagger.xWso2ErrorResponses, errorResponse)
+	}
+}
+
+// GetXWso2IPFilter returns the API level client IP filter.
+func (swagger *MgwSwagger) GetXWso2IPFilter() *IPFilterConfig {
+	return swagger.xWso2IPFilter
+}
+
+// GetXWso2Routing returns the API level content based routing configuration.
+func (swagger *MgwSwagger) GetXWso2Routing() *RoutingConfig {
+	return swagger.xWso2Routing
+}
+
+// GetXWso2Mirror returns the API level traffic mirroring configuration.
+func (swagger *MgwSwagger) GetXWso2Mirror() *MirrorConfig {
+	return swagger.xWso2Mirror
+}
+
 // GetAPIType returns the openapi version
 func (swagger *MgwSwagger) GetAPIType() string {
 	return swagger.apiType
@@ -193,6 +399,27 @@ func (swagger *MgwSwagger) GetTitle() string {
 	return swagger.title
 }
 
+// GetXWso2Proxy returns the upstream proxy of the API. The globally configured proxy is returned if the
+// x-wso2-proxy extension is not provided, and nil is returned if no proxy is configured.
+func (swagger *MgwSwagger) GetXWso2Proxy() *ProxyConfig {
+	if swagger.xWso2Proxy != nil {
+		return swagger.xWso2Proxy
+	}
+	return getGlobalProxyConfig()
+}
+
+// GetXWso2RouterJWTValidation returns whether the tokens are validated at the router, set via the vendor
+// extension. nil is returned if the extension is not provided.
+func (swagger *MgwSwagger) GetXWso2RouterJWTValidation() *bool {
+	return swagger.xWso2RouterJWTValidation
+}
+
+// GetXWso2MaxRequestBodySize returns the maximum request body size in bytes, set via the vendor extension.
+// 0 is returned if the request body size is not limited.
+func (swagger *MgwSwagger) GetXWso2MaxRequestBodySize() uint32 {
+	return swagger.xWso2MaxRequestBodySize
+}
+
 // GetXWso2Basepath returns the basepath set via the vendor extension.
 func (swagger *MgwSwagger) GetXWso2Basepath() string {
 	return swagger.xWso2Basepath
@@ -340,8 +567,33 @@ func (swagger *MgwSwagger) GetXWSO2ApplicationSecurity() bool {
 	return swagger.xWso2ApplicationSecurity
 }
 
+// SetUpstreamClientCerts sets the SDS secret names of the client certificates presented to the endpoints which
+// require mutual TLS, against the endpoint urls
+func (swagger *MgwSwagger) SetUpstreamClientCerts(upstreamClientCerts map[string]string) {
+	swagger.upstreamClientCerts = upstreamClientCerts
+}
+
+// GetUpstreamClientCerts returns the SDS secret names of the client certificates of the endpoints
+func (swagger *MgwSwagger) GetUpstreamClientCerts() map[string]string {
+	return swagger.upstreamClientCerts
+}
+
+// SetTrustedCerts sets the certificates trusted by the clusters of the endpoints and the interceptors, so that
+// the clusters can be generated again when the router configuration is changed.
+func (swagger *MgwSwagger) SetTrustedCerts(upstreamCerts, interceptorCerts map[string][]byte) {
+	swagger.upstreamCerts = upstreamCerts
+	swagger.interceptorCerts = interceptorCerts
+}
+
+// GetTrustedCerts returns the certificates trusted by the clusters of the endpoints and the interceptors.
+func (swagger *MgwSwagger) GetTrustedCerts() (upstreamCerts, interceptorCerts map[string][]byte) {
+	return swagger.upstreamCerts, swagger.interceptorCerts
+}
+
 // SetOperationPolicies this will merge operation level policies provided in api yaml
 func (swagger *MgwSwagger) SetOperationPolicies(apiProject ProjectAPI) {
+	apiRequestInterceptor := swagger.GetInterceptor(swagger.GetVendorExtensions(), constants.XWso2RequestInterceptor,
+		constants.APILevelInterceptor)
 	for _, resource := range swagger.resources {
 		path := strings.TrimSuffix(resource.path, "/")
 		for _, operation := range resource.methods {
@@ -349,11 +601,55 @@ func (swagger *MgwSwagger) SetOperationPolicies(apiProject ProjectAPI) {
 			for _, yamlOperation := range apiProject.APIYaml.Data.Operations {
 				if strings.TrimSuffix(yamlOperation.Target, "/") == path && strings.EqualFold(method, yamlOperation.Verb) {
 					operation.policies = apiProject.Policies.GetFormattedOperationalPolicies(yamlOperation.OperationPolicies, swagger)
+					operation.setTimeoutAndRetryPolicy(yamlOperation)
 					break
 				}
 			}
 		}
+
+		_, rewriteMethod := resource.GetRewriteResource()
+		resourceRequestInterceptor := swagger.GetInterceptor(resource.GetVendorExtensions(),
+			constants.XWso2RequestInterceptor, constants.ResourceLevelInterceptor)
+		requestInterceptors := swagger.GetOperationInterceptors(apiRequestInterceptor, resourceRequestInterceptor,
+			resource.methods, true)
+		for _, operation := range resource.methods {
+			// The route of the resource does not match the method when the method is rewritten, hence the
+			// operation level request mutations cannot be applied by the router. The router applies the request
+			// mutations after the request interceptor is called, hence they are applied by the enforcer when the
+			// operation has a request interceptor, for the interceptor to receive the mutated request.
+			// The router applies the query mutations after the enforcer rewrites the path, hence they are applied by
+			// the enforcer as well when the operation has a path rewrite, to keep the policy order.
+			_, hasRequestInterceptor := requestInterceptors[strings.ToUpper(operation.method)]
+			rewritePath := hasPolicyAction(operation.policies.Request, constants.RewritePathAction)
+			for i := range operation.policies.Request {
+				action := operation.policies.Request[i].Action
+				if ((rewriteMethod || hasRequestInterceptor) && isMutationAction(action)) ||
+					(rewritePath && isQueryMutationAction(action)) {
+					operation.policies.Request[i].IsPassToEnforcer = true
+				}
+			}
+		}
+	}
+}
+
+// RemoveFaultInjectionPolicies removes the FAULT_INJECTION policies of all the operations of the API and
+// returns true if any of the policies were removed.
+func (swagger *MgwSwagger) RemoveFaultInjectionPolicies() bool {
+	isRemoved := false
+	for _, resource := range swagger.resources {
+		for _, operation := range resource.methods {
+			var requestPolicies PolicyList
+			for _, policy := range operation.policies.Request {
+				if strings.EqualFold(constants.FaultInjectionAction, policy.Action) {
+					isRemoved = true
+					continue
+				}
+				requestPolicies = append(requestPolicies, policy)
+			}
+			operation.policies.Request = requestPolicies
+		}
 	}
+	return isRemoved
 }
 
 // SanitizeAPISecurity this will validate api level and operation level swagger security
@@ -396,7 +692,7 @@ func (swagger *MgwSwagger) SanitizeAPISecurity(isYamlAPIKey bool, isYamlOauth bo
 	sanitizedAPISecurity := []map[string][]string{}
 	for _, security := range swagger.security {
 		for securityDefName := range security {
-			if arrayContains(apiSecurityDefinitionNames, securityDefName) {
+			if ArrayContains(apiSecurityDefinitionNames, securityDefName) {
 				sanitizedAPISecurity = append(sanitizedAPISecurity, security)
 			} else {
 				logger.LoggerXds.Warnf("A security definition for %v has not found in API %v:%v",
@@ -417,7 +713,7 @@ func (swagger *MgwSwagger) SanitizeAPISecurity(isYamlAPIKey bool, isYamlOauth bo
 			sanitizedOperationSecurity := []map[string][]string{}
 			for _, security := range operation.GetSecurity() {
 				for securityDefName := range security {
-					if arrayContains(apiSecurityDefinitionNames, securityDefName) {
+					if ArrayContains(apiSecurityDefinitionNames, securityDefName) {
 						sanitizedOperationSecurity = append(sanitizedOperationSecurity, security)
 					} else {
 						logger.LoggerXds.Warnf("A security definition for %v has not found in API %v:%v",
@@ -491,10 +787,17 @@ func (swagger *MgwSwagger) SetXWso2Extensions() error {
 	}
 
 	swagger.setXWso2Cors()
+	swagger.setXWso2ErrorResponses()
+	swagger.setXWso2IPFilter()
+	swagger.setXWso2Routing()
+	swagger.setXWso2Mirror()
+	swagger.setXWso2Proxy()
 	swagger.setXWso2ThrottlingTier()
 	swagger.setDisableSecurity()
 	swagger.setXWso2AuthHeader()
 	swagger.setXWso2HTTP2BackendEnabled()
+	swagger.setXWso2RouterJWTValidation()
+	swagger.setXWso2MaxRequestBodySize()
 
 	// Error nil for successful execution
 	return nil
@@ -709,6 +1012,26 @@ func (swagger *MgwSwagger) setDisableSecurity() {
 	swagger.disableSecurity = ResolveDisableSecurity(swagger.vendorExtensions)
 }
 
+// validateMaxRequestBodySize validates that the x-wso2-max-request-body-size extension is not used for WebSocket
+// APIs, as the frames of a WebSocket connection are not a request body limited by the router.
+func (swagger *MgwSwagger) validateMaxRequestBodySize() error {
+	if swagger.apiType != constants.WS {
+		return nil
+	}
+	if _, found := swagger.vendorExtensions[constants.XWso2MaxRequestBodySize]; found {
+		return fmt.Errorf("the %v extension is not supported for WebSocket APIs", constants.XWso2MaxRequestBodySize)
+	}
+	for _, resource := range swagger.resources {
+		for _, operation := range resource.methods {
+			if _, found := operation.vendorExtensions[constants.XWso2MaxRequestBodySize]; found {
+				return fmt.Errorf("the %v extension is not supported for the WebSocket API resource %v",
+					constants.XWso2MaxRequestBodySize, resource.path)
+			}
+		}
+	}
+	return nil
+}
+
 // Validate method confirms that the mgwSwagger has all required fields in the required format.
 // This needs to be checked prior to generate router/enforcer related resources.
 func (swagger *MgwSwagger) Validate() error {
@@ -753,6 +1076,36 @@ func (swagger *MgwSwagger) Validate() error {
 		logger.LoggerOasparser.Errorf("Error while parsing the API %s:%s - %v", swagger.title, swagger.version, err)
 		return err
 	}
+	if err = swagger.validateMaxRequestBodySize(); err != nil {
+		logger.LoggerOasparser.Errorf("Error while parsing the API %s:%s - %v", swagger.title, swagger.version, err)
+		return err
+	}
+	if err = swagger.validateIPFilter(); err != nil {
+		logger.LoggerOasparser.Errorf("Error while parsing the API %s:%s - %v", swagger.title, swagger.version, err)
+		return err
+	}
+	if err = swagger.validateXWso2Proxy(); err != nil {
+		logger.LoggerOasparser.Errorf("Error while parsing the API %s:%s - %v", swagger.title, swagger.version, err)
+		return err
+	}
+	if err = swagger.validateCors(); err != nil {
+		logger.LoggerOasparser.Errorf("Error while parsing the API %s:%s - %v", swagger.title, swagger.version, err)
+		return err
+	}
+	for _, resource := range swagger.resources {
+		if err = resource.validateResponseMutations(); err != nil {
+			logger.LoggerOasparser.Errorf("Error while parsing the API %s:%s - %v", swagger.title, swagger.version,
+				err)
+			return err
+		}
+		for _, operation := range resource.methods {
+			if err = operation.validateTimeoutAndRetryPolicy(); err != nil {
+				logger.LoggerOasparser.Errorf("Error while parsing the resource %s of the API %s:%s - %v",
+					resource.path, swagger.title, swagger.version, err)
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -830,11 +1183,43 @@ func (endpointCluster *EndpointCluster) validateEndpointCluster(endpointName str
 			if endpointCluster.Config.TimeoutInMillis > maxTimeoutInMillis {
 				endpointCluster.Config.TimeoutInMillis = maxTimeoutInMillis
 			}
+			// Validate upstream protocol
+			if err := endpointCluster.validateUpstreamProtocol(); err != nil {
+				logger.LoggerOasparser.Errorf("Error while parsing the %s endpoints. %v", endpointName, err)
+				return err
+			}
+			// Validate proxy
+			if endpointCluster.Config.Proxy != nil {
+				if err := endpointCluster.Config.Proxy.validate(); err != nil {
+					logger.LoggerOasparser.Errorf("Error while parsing the proxy of the %s endpoints. %v",
+						endpointName, err)
+					return err
+				}
+			}
 		}
 	}
 	return nil
 }
 
+// validateUpstreamProtocol checks whether the upstream protocol is supported. The protocol is selected via ALPN
+// when it is auto, hence all the endpoints should use TLS.
+func (endpointCluster *EndpointCluster) validateUpstreamProtocol() error {
+	switch endpointCluster.Config.UpstreamProtocol {
+	case "", constants.UpstreamProtocolHTTP1, constants.UpstreamProtocolHTTP2:
+		return nil
+	case constants.UpstreamProtocolAuto:
+		for _, endpoint := range endpointCluster.Endpoints {
+			if endpoint.URLType != "https" && endpoint.URLType != "wss" {
+				return fmt.Errorf("upstream protocol %v is supported only for the https endpoints, but found %v",
+					constants.UpstreamProtocolAuto, endpoint.RawURL)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported upstream protocol %v", endpointCluster.Config.UpstreamProtocol)
+	}
+}
+
 // getEndpoints extracts and generate the EndpointCluster Object from any yaml map that has the following structure
 //   endpoint-name:
 // 		urls:
@@ -972,6 +1357,24 @@ func (swagger *MgwSwagger) setXWso2HTTP2BackendEnabled() {
 	swagger.xWso2HTTP2BackendEnabled = extHTTP2BackendEnabled
 }
 
+func (swagger *MgwSwagger) setXWso2RouterJWTValidation() {
+	swagger.xWso2RouterJWTValidation = getXWso2RouterJWTValidation(swagger.vendorExtensions)
+}
+
+func (swagger *MgwSwagger) setXWso2MaxRequestBodySize() {
+	maxRequestBodySize, err := getXWso2MaxRequestBodySize(swagger.vendorExtensions)
+	if err != nil {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message: fmt.Sprintf("Ignoring the %v extension of the API %s:%s. %v", constants.XWso2MaxRequestBodySize,
+				swagger.title, swagger.version, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2230,
+		})
+		return
+	}
+	swagger.xWso2MaxRequestBodySize = maxRequestBodySize
+}
+
 func (swagger *MgwSwagger) setXWso2Cors() {
 	if cors, corsFound := swagger.vendorExtensions[constants.XWso2Cors]; corsFound {
 		logger.LoggerOasparser.Debugf("%v configuration is available", constants.XWso2Cors)
@@ -981,6 +1384,9 @@ func (swagger *MgwSwagger) setXWso2Cors() {
 				Enabled: true,
 			}
 			err := parser.Decode(parsedCors, &corsConfig)
+			if err == nil {
+				err = corsConfig.validate()
+			}
 			if err != nil {
 				logger.LoggerOasparser.Errorf("Error while parsing %v: "+err.Error(), constants.XWso2Cors)
 				return
@@ -999,6 +1405,142 @@ func (swagger *MgwSwagger) setXWso2Cors() {
 	}
 }
 
+func (swagger *MgwSwagger) setXWso2ErrorResponses() {
+	errorResponses, found := swagger.vendorExtensions[constants.XWso2ErrorResponses]
+	if !found {
+		return
+	}
+	logger.LoggerOasparser.Debugf("%v configuration is available", constants.XWso2ErrorResponses)
+	var templates []ErrorResponseTemplate
+	if err := parser.Decode(errorResponses, &templates); err != nil {
+		logger.LoggerOasparser.Errorf("Error while parsing %v: %v", constants.XWso2ErrorResponses, err.Error())
+		return
+	}
+	swagger.xWso2ErrorResponses = nil
+	swagger.SetErrorResponses(templates)
+}
+
+// setXWso2IPFilter sets the API level client IP filter. An invalid IP filter is rejected when the API is validated.
+func (swagger *MgwSwagger) setXWso2IPFilter() {
+	swagger.xWso2IPFilter = nil
+	if ipFilter, err := getXWso2IPFilter(swagger.vendorExtensions); err == nil {
+		swagger.xWso2IPFilter = ipFilter
+	}
+}
+
+// validateIPFilter validates the API level and the operation level client IP filters. As the IP filter restricts
+// the clients of the API, an invalid filter is not ignored.
+func (swagger *MgwSwagger) validateIPFilter() error {
+	if _, err := getXWso2IPFilter(swagger.vendorExtensions); err != nil {
+		return fmt.Errorf("invalid %v extension of the API. %v", constants.XWso2IPFilter, err)
+	}
+	for _, resource := range swagger.resources {
+		for _, operation := range resource.methods {
+			if err := operation.validateIPFilter(); err != nil {
+				return fmt.Errorf("invalid client IP filter of the resource %v. %v", resource.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCors validates the resource level and the operation level CORS configurations. An invalid configuration
+// is not ignored, as the preflight requests of the resource would otherwise be handled with the API level policy.
+func (swagger *MgwSwagger) validateCors() error {
+	for _, resource := range swagger.resources {
+		if _, err := getXWso2Cors(resource.vendorExtensions); err != nil {
+			return fmt.Errorf("invalid %v extension of the resource %v. %v", constants.XWso2Cors, resource.path, err)
+		}
+		for _, operation := range resource.methods {
+			if _, err := getXWso2Cors(operation.vendorExtensions); err != nil {
+				return fmt.Errorf("invalid %v extension of the operation %v of the resource %v. %v", constants.XWso2Cors,
+					operation.method, resource.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setXWso2Routing sets the API level and resource level content based routing configurations.
+// Invalid configurations are ignored as the requests could still be served by the default endpoints.
+func (swagger *MgwSwagger) setXWso2Routing() {
+	routing, err := getXWso2Routing(swagger.vendorExtensions, swagger.xWso2Endpoints)
+	if err != nil {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message: fmt.Sprintf("Ignoring the %v extension of the API %s:%s. %v", constants.XWso2Routing,
+				swagger.title, swagger.version, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2219,
+		})
+	} else {
+		swagger.xWso2Routing = routing
+	}
+
+	for i, resource := range swagger.resources {
+		routing, err := getXWso2Routing(resource.vendorExtensions, swagger.xWso2Endpoints)
+		if err != nil {
+			logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Ignoring the %v extension of the resource %s of the API %s:%s. %v", constants.XWso2Routing,
+					resource.path, swagger.title, swagger.version, err.Error()),
+				Severity:  logging.MINOR,
+				ErrorCode: 2231,
+			})
+			continue
+		}
+		swagger.resources[i].routingConfig = routing
+	}
+}
+
+// setXWso2Mirror sets the API level traffic mirroring configuration. The x-wso2-mirror extension
+// overrides the mirror endpoints provided in the api.yaml.
+func (swagger *MgwSwagger) setXWso2Mirror() {
+	mirror, err := getXWso2Mirror(swagger.vendorExtensions)
+	if err != nil {
+		logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+			Message: fmt.Sprintf("Ignoring the %v extension of the API %s:%s. %v", constants.XWso2Mirror,
+				swagger.title, swagger.version, err.Error()),
+			Severity:  logging.MINOR,
+			ErrorCode: 2220,
+		})
+		return
+	}
+	if mirror != nil {
+		swagger.xWso2Mirror = mirror
+	}
+}
+
+// setXWso2Proxy sets the API level upstream proxy, which overrides the globally configured proxy. An invalid
+// proxy is rejected when the API is validated.
+func (swagger *MgwSwagger) setXWso2Proxy() {
+	swagger.xWso2Proxy = nil
+	if proxy, err := getXWso2Proxy(swagger.vendorExtensions); err == nil {
+		swagger.xWso2Proxy = proxy
+	}
+}
+
+// validateXWso2Proxy validates the API level upstream proxy. An invalid proxy is not ignored, as the endpoints
+// would otherwise be reached directly or through the globally configured proxy.
+func (swagger *MgwSwagger) validateXWso2Proxy() error {
+	if _, err := getXWso2Proxy(swagger.vendorExtensions); err != nil {
+		return fmt.Errorf("invalid %v extension of the API. %v", constants.XWso2Proxy, err)
+	}
+	return nil
+}
+
+// validate checks whether the mandatory fields of the error response template are available.
+func (errorResponse *ErrorResponseTemplate) validate() error {
+	if len(errorResponse.Flags) == 0 {
+		return errors.New("at least one response flag is required")
+	}
+	if errorResponse.Body == "" {
+		return errors.New("error response body is required")
+	}
+	if errorResponse.StatusCode != 0 && (errorResponse.StatusCode < 200 || errorResponse.StatusCode > 599) {
+		return fmt.Errorf("invalid status code %d", errorResponse.StatusCode)
+	}
+	return nil
+}
+
 func generateEndpointCluster(endpointPrefix string, endpoints []Endpoint, endpointType string) *EndpointCluster {
 	if len(endpoints) > 0 {
 		endpointCluster := EndpointCluster{
@@ -1015,12 +1557,14 @@ func generateGlobalCors() *CorsConfig {
 	conf, _ := config.ReadConfigs()
 	logger.LoggerOasparser.Debug("CORS policy is applied from global configuration.")
 	return &CorsConfig{
-		Enabled:                       conf.Envoy.Cors.Enabled,
-		AccessControlAllowCredentials: conf.Envoy.Cors.AllowCredentials,
-		AccessControlAllowOrigins:     conf.Envoy.Cors.AllowOrigins,
-		AccessControlAllowHeaders:     conf.Envoy.Cors.AllowHeaders,
-		AccessControlAllowMethods:     conf.Envoy.Cors.AllowMethods,
-		AccessControlExposeHeaders:    conf.Envoy.Cors.ExposeHeaders,
+		Enabled:                         conf.Envoy.Cors.Enabled,
+		AccessControlAllowCredentials:   conf.Envoy.Cors.AllowCredentials,
+		AccessControlAllowOrigins:       conf.Envoy.Cors.AllowOrigins,
+		AccessControlAllowOriginRegexes: conf.Envoy.Cors.AllowOriginRegexes,
+		AccessControlAllowHeaders:       conf.Envoy.Cors.AllowHeaders,
+		AccessControlAllowMethods:       conf.Envoy.Cors.AllowMethods,
+		AccessControlExposeHeaders:      conf.Envoy.Cors.ExposeHeaders,
+		AccessControlMaxAge:             conf.Envoy.Cors.MaxAge,
 	}
 }
 
@@ -1223,6 +1767,7 @@ func (swagger *MgwSwagger) PopulateFromAPIYaml(apiYaml APIYaml) error {
 	// context value in api.yaml is assigned as xWso2Basepath
 	swagger.xWso2Basepath = data.Context + "/" + swagger.version
 	swagger.LifecycleStatus = data.LifeCycleStatus
+	swagger.RevisionID = data.RevisionID
 	swagger.IsDefaultVersion = data.IsDefaultVersion
 
 	// Added with both HTTP and WS APIs. x-throttling-tier is not used with WS.
@@ -1328,6 +1873,23 @@ func (swagger *MgwSwagger) PopulateFromAPIYaml(apiYaml APIYaml) error {
 		swagger.sandboxEndpoints = generateEndpointCluster(constants.SandClustersConfigNamePrefix, endpoints, endpointType)
 	}
 
+	if endpointConfig.MirrorEndpoints != nil && swagger.apiType != constants.WS {
+		mirror := &MirrorConfig{
+			URLs:       []string{endpointConfig.MirrorEndpoints.Endpoint},
+			Percentage: endpointConfig.MirrorEndpoints.Percentage,
+		}
+		if err := mirror.validate(); err != nil {
+			logger.LoggerOasparser.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Ignoring the mirror endpoints of the API %s:%s in api.yaml. %v",
+					swagger.title, swagger.version, err.Error()),
+				Severity:  logging.MINOR,
+				ErrorCode: 2232,
+			})
+		} else {
+			swagger.xWso2Mirror = mirror
+		}
+	}
+
 	// if yaml has production security, setting it
 	if swagger.productionEndpoints != nil && endpointConfig.APIEndpointSecurity.Production.Enabled {
 		if endpointConfig.APIEndpointSecurity.Production.Type != "" && strings.EqualFold("BASIC", endpointConfig.APIEndpointSecurity.Production.Type) {
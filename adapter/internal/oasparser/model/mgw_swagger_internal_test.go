@@ -310,3 +310,216 @@ func TestValidateBasePath(t *testing.T) {
 		assert.Equal(t, item.errorNil, err == nil, item.message)
 	}
 }
+
+func TestValidateMaxRequestBodySize(t *testing.T) {
+	maxRequestBodySize := map[string]interface{}{"x-wso2-max-request-body-size": "1MB"}
+	dataItems := []struct {
+		mgwSwagger MgwSwagger
+		errorNil   bool
+		message    string
+	}{
+		{
+			mgwSwagger: MgwSwagger{apiType: constants.HTTP, vendorExtensions: maxRequestBodySize},
+			errorNil:   true,
+			message:    "max request body size should be allowed for HTTP APIs",
+		},
+		{
+			mgwSwagger: MgwSwagger{apiType: constants.WS},
+			errorNil:   true,
+			message:    "WebSocket API without the max request body size should be valid",
+		},
+		{
+			mgwSwagger: MgwSwagger{apiType: constants.WS, vendorExtensions: maxRequestBodySize},
+			errorNil:   false,
+			message:    "API level max request body size should be rejected for WebSocket APIs",
+		},
+		{
+			mgwSwagger: MgwSwagger{apiType: constants.WS, resources: []*Resource{{path: "/notifications",
+				methods: []*Operation{NewOperation("GET", nil, maxRequestBodySize)}}}},
+			errorNil: false,
+			message:  "operation level max request body size should be rejected for WebSocket APIs",
+		},
+	}
+	for _, item := range dataItems {
+		err := item.mgwSwagger.validateMaxRequestBodySize()
+		assert.Equal(t, item.errorNil, err == nil, item.message)
+	}
+}
+
+func TestValidateIPFilter(t *testing.T) {
+	validIPFilter := map[string]interface{}{"x-wso2-ip-filter": map[string]interface{}{
+		"allow": []interface{}{"10.0.0.0/8"}}}
+	invalidIPFilter := map[string]interface{}{"x-wso2-ip-filter": map[string]interface{}{
+		"allow": []interface{}{"10.0.0.0/33"}}}
+	newIPFilterOperation := func(params interface{}) *Operation {
+		operation := NewOperation("GET", nil, nil)
+		operation.policies.Request = PolicyList{{PolicyName: "ipFilter", Action: constants.IPFilterAction,
+			Parameters: params}}
+		return operation
+	}
+	dataItems := []struct {
+		mgwSwagger MgwSwagger
+		errorNil   bool
+		message    string
+	}{
+		{
+			mgwSwagger: MgwSwagger{vendorExtensions: validIPFilter, resources: []*Resource{{path: "/pets",
+				methods: []*Operation{NewOperation("GET", nil, validIPFilter),
+					newIPFilterOperation(map[string]interface{}{"deny": []interface{}{"192.168.1.10/32"}})}}}},
+			errorNil: true,
+			message:  "valid IP filters should be allowed",
+		},
+		{
+			mgwSwagger: MgwSwagger{vendorExtensions: invalidIPFilter},
+			errorNil:   false,
+			message:    "invalid API level IP filter should be rejected",
+		},
+		{
+			mgwSwagger: MgwSwagger{resources: []*Resource{{path: "/pets",
+				methods: []*Operation{NewOperation("GET", nil, invalidIPFilter)}}}},
+			errorNil: false,
+			message:  "invalid operation level IP filter should be rejected",
+		},
+		{
+			mgwSwagger: MgwSwagger{resources: []*Resource{{path: "/pets",
+				methods: []*Operation{newIPFilterOperation(map[string]interface{}{"allow": "10.0.0"})}}}},
+			errorNil: false,
+			message:  "IP filter policy with an invalid allow list should be rejected",
+		},
+		{
+			mgwSwagger: MgwSwagger{resources: []*Resource{{path: "/pets",
+				methods: []*Operation{newIPFilterOperation(map[string]interface{}{})}}}},
+			errorNil: false,
+			message:  "IP filter policy without the allow and deny lists should be rejected",
+		},
+	}
+	for _, item := range dataItems {
+		err := item.mgwSwagger.validateIPFilter()
+		assert.Equal(t, item.errorNil, err == nil, item.message)
+	}
+}
+
+func TestValidateCors(t *testing.T) {
+	validCors := map[string]interface{}{"x-wso2-cors": map[string]interface{}{
+		"accessControlAllowOriginRegexes": []interface{}{"^https://.*\\.example\\.com$"}}}
+	invalidOriginRegex := map[string]interface{}{"x-wso2-cors": map[string]interface{}{
+		"accessControlAllowOriginRegexes": []interface{}{"^https://(.*$"}}}
+	invalidMaxAge := map[string]interface{}{"x-wso2-cors": map[string]interface{}{"accessControlMaxAge": -1}}
+	dataItems := []struct {
+		mgwSwagger MgwSwagger
+		errorNil   bool
+		message    string
+	}{
+		{
+			mgwSwagger: MgwSwagger{resources: []*Resource{{path: "/pets", vendorExtensions: validCors,
+				methods: []*Operation{NewOperation("GET", nil, validCors), NewOperation("POST", nil, nil)}}}},
+			errorNil: true,
+			message:  "valid CORS configurations should be allowed",
+		},
+		{
+			mgwSwagger: MgwSwagger{resources: []*Resource{{path: "/pets", vendorExtensions: invalidOriginRegex,
+				methods: []*Operation{NewOperation("GET", nil, nil)}}}},
+			errorNil: false,
+			message:  "resource level CORS configuration with an invalid origin regex should be rejected",
+		},
+		{
+			mgwSwagger: MgwSwagger{resources: []*Resource{{path: "/pets",
+				methods: []*Operation{NewOperation("GET", nil, invalidMaxAge)}}}},
+			errorNil: false,
+			message:  "operation level CORS configuration with a negative max age should be rejected",
+		},
+	}
+	for _, item := range dataItems {
+		err := item.mgwSwagger.validateCors()
+		assert.Equal(t, item.errorNil, err == nil, item.message)
+	}
+}
+
+func TestValidateXWso2Proxy(t *testing.T) {
+	dataItems := []struct {
+		vendorExtensions map[string]interface{}
+		errorNil         bool
+		message          string
+	}{
+		{
+			vendorExtensions: map[string]interface{}{"x-wso2-proxy": map[string]interface{}{
+				"host": "proxy.example.com", "port": 3128}},
+			errorNil: true,
+			message:  "valid proxy should be allowed",
+		},
+		{
+			vendorExtensions: map[string]interface{}{},
+			errorNil:         true,
+			message:          "API without a proxy should be allowed",
+		},
+		{
+			vendorExtensions: map[string]interface{}{"x-wso2-proxy": map[string]interface{}{"port": 3128}},
+			errorNil:         false,
+			message:          "proxy without the host should be rejected",
+		},
+		{
+			vendorExtensions: map[string]interface{}{"x-wso2-proxy": "proxy.example.com:3128"},
+			errorNil:         false,
+			message:          "proxy which is not an object should be rejected",
+		},
+	}
+	for _, item := range dataItems {
+		mgwSwagger := MgwSwagger{vendorExtensions: item.vendorExtensions}
+		mgwSwagger.setXWso2Proxy()
+		err := mgwSwagger.validateXWso2Proxy()
+		assert.Equal(t, item.errorNil, err == nil, item.message)
+		if !item.errorNil {
+			assert.Nil(t, mgwSwagger.xWso2Proxy, item.message)
+		}
+	}
+}
+
+func TestValidateTimeoutAndRetryPolicy(t *testing.T) {
+	validTimeout := map[string]interface{}{"timeoutInMillis": 5000}
+	invalidTimeout := map[string]interface{}{"timeoutInMillis": 0}
+	validRetry := map[string]interface{}{"count": 2, "retryOn": []interface{}{"5xx"}}
+	invalidRetry := map[string]interface{}{"retryOn": []interface{}{"always"}}
+	dataItems := []struct {
+		extensions    map[string]interface{}
+		yamlOperation OperationYaml
+		errorNil      bool
+		message       string
+	}{
+		{
+			extensions: map[string]interface{}{"x-wso2-timeout": validTimeout, "x-wso2-retry": validRetry},
+			errorNil:   true,
+			message:    "valid timeout and retry extensions should be allowed",
+		},
+		{
+			extensions: map[string]interface{}{"x-wso2-timeout": invalidTimeout},
+			errorNil:   false,
+			message:    "invalid timeout extension should be rejected",
+		},
+		{
+			extensions: map[string]interface{}{"x-wso2-retry": invalidRetry},
+			errorNil:   false,
+			message:    "invalid retry extension should be rejected",
+		},
+		{
+			yamlOperation: OperationYaml{Target: "/pets", Verb: "GET", Timeout: validTimeout, Retry: validRetry},
+			errorNil:      true,
+			message:       "valid timeout and retry policy of the api.yaml operation should be allowed",
+		},
+		{
+			yamlOperation: OperationYaml{Target: "/pets", Verb: "GET", Timeout: invalidTimeout},
+			errorNil:      false,
+			message:       "invalid timeout of the api.yaml operation should be rejected",
+		},
+		{
+			yamlOperation: OperationYaml{Target: "/pets", Verb: "GET", Retry: invalidRetry},
+			errorNil:      false,
+			message:       "invalid retry policy of the api.yaml operation should be rejected",
+		},
+	}
+	for _, item := range dataItems {
+		operation := NewOperation("GET", nil, item.extensions)
+		operation.setTimeoutAndRetryPolicy(item.yamlOperation)
+		err := operation.validateTimeoutAndRetryPolicy()
+		assert.Equal(t, item.errorNil, err == nil, item.message)
+	}
+}
@@ -199,10 +199,10 @@ func (spec *PolicySpecification) validatePolicy(policy Policy, flow PolicyFlow)
 		return fmt.Errorf("invalid policy specification, spec name %q:%q and policy name %q:%q mismatch",
 			spec.Data.Name, spec.Data.Version, policy.PolicyName, policy.PolicyVersion)
 	}
-	if !arrayContains(spec.Data.ApplicableFlows, string(flow)) {
+	if !ArrayContains(spec.Data.ApplicableFlows, string(flow)) {
 		return fmt.Errorf("policy flow %q not supported", flow)
 	}
-	if !arrayContains(spec.Data.SupportedGateways, policyCCGateway) {
+	if !ArrayContains(spec.Data.SupportedGateways, policyCCGateway) {
 		return errors.New("choreo connect gateway not supported")
 	}
 
@@ -18,9 +18,11 @@
 package model
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wso2/product-microgateway/adapter/config"
 )
 
 func TestPolicySpecificationValidatePolicy(t *testing.T) {
@@ -151,7 +153,7 @@ func TestAPIProjectGetFormattedPolicyFromTemplated(t *testing.T) {
 				PolicyName:       "fooAddRequestHeader",
 				PolicyVersion:    "v1",
 				Action:           "SET_HEADER",
-				IsPassToEnforcer: true,
+				IsPassToEnforcer: false,
 				Parameters: map[string]interface{}{
 					"headerName":  "fooHeaderName",
 					"headerValue": "fooHeaderValue",
@@ -163,6 +165,328 @@ func TestAPIProjectGetFormattedPolicyFromTemplated(t *testing.T) {
 	assert.Equal(t, expFormattedP, actualFormattedP, "Converting operational policies to Choreo Connect format failed")
 }
 
+func TestValidatePolicyAction(t *testing.T) {
+	conf, _ := config.ReadConfigs()
+	customPolicyActions := conf.Enforcer.CustomPolicyActions
+	defer func() {
+		conf.Enforcer.CustomPolicyActions = customPolicyActions
+	}()
+	// The custom policy action config type is not exported, hence it is set from JSON.
+	err := json.Unmarshal([]byte(`[
+		{"Action": "CUSTOM_VALIDATION", "RequiredParams": ["headerName"], "FilterClass": "org.wso2.custom.ValidationFilter"},
+		{"Action": "SET_HEADER", "RequiredParams": [], "FilterClass": "org.wso2.custom.HeaderFilter"},
+		{"Action": "CUSTOM_LOGGING", "RequiredParams": [], "FilterClass": "org.wso2.custom.LoggingFilter"}
+	]`), &conf.Enforcer.CustomPolicyActions)
+	assert.Nil(t, err, "Error while setting the custom policy actions")
+
+	tests := []struct {
+		policy      Policy
+		isValid     bool
+		filterClass string
+		message     string
+	}{
+		{
+			policy:      Policy{Action: "CUSTOM_VALIDATION", Parameters: map[string]interface{}{"headerName": "x-foo"}},
+			isValid:     true,
+			filterClass: "org.wso2.custom.ValidationFilter",
+			message:     "Custom policy action with the required params should be valid",
+		},
+		{
+			policy:  Policy{Action: "CUSTOM_VALIDATION", Parameters: map[string]interface{}{"foo": "bar"}},
+			isValid: false,
+			message: "Custom policy action without the required params should be invalid",
+		},
+		{
+			policy:  Policy{Action: "SET_HEADER", Parameters: map[string]interface{}{"headerName": "x-foo"}},
+			isValid: false,
+			message: "Custom policy action should not override the supported policy action",
+		},
+		{
+			policy:      Policy{Action: "CUSTOM_LOGGING"},
+			isValid:     true,
+			filterClass: "org.wso2.custom.LoggingFilter",
+			message:     "Custom policy action without required params should be passed to the enforcer",
+		},
+		{
+			policy:  Policy{Action: "UNKNOWN_ACTION", Parameters: map[string]interface{}{}},
+			isValid: false,
+			message: "Undeclared policy action should be invalid",
+		},
+	}
+	for _, test := range tests {
+		err := validatePolicyAction(&test.policy)
+		if !test.isValid {
+			assert.Error(t, err, test.message)
+			continue
+		}
+		assert.Nil(t, err, test.message)
+		assert.True(t, test.policy.IsPassToEnforcer, test.message)
+		assert.Equal(t, test.filterClass, test.policy.FilterClass, test.message)
+	}
+}
+
+func TestValidatePolicyActionRuntimeMutation(t *testing.T) {
+	tests := []struct {
+		policy           Policy
+		isPassToEnforcer bool
+		message          string
+	}{
+		{
+			policy:           Policy{Action: "SET_HEADER", Parameters: map[string]interface{}{"headerName": "x-foo", "headerValue": "bar"}},
+			isPassToEnforcer: false,
+			message:          "Static header should be set by the router",
+		},
+		{
+			policy:           Policy{Action: "SET_HEADER", Parameters: map[string]interface{}{"headerName": ":authority", "headerValue": "foo"}},
+			isPassToEnforcer: true,
+			message:          "Pseudo header should be set by the enforcer",
+		},
+		{
+			policy:           Policy{Action: "REMOVE_HEADER", Parameters: map[string]interface{}{"headerName": "x-foo"}},
+			isPassToEnforcer: false,
+			message:          "Header should be removed by the router",
+		},
+		{
+			policy:           Policy{Action: "SET_HEADER", Parameters: map[string]interface{}{"headerName": "Host", "headerValue": "foo"}},
+			isPassToEnforcer: true,
+			message:          "Host header should be set by the enforcer",
+		},
+		{
+			policy:           Policy{Action: "ADD_QUERY", Parameters: map[string]interface{}{"queryParamName": "foo", "queryParamValue": "${claims.sub}"}},
+			isPassToEnforcer: false,
+			message:          "Query parameter should be added by the router, as placeholders are not resolved",
+		},
+	}
+	for _, test := range tests {
+		err := validatePolicyAction(&test.policy)
+		assert.Nil(t, err, test.message)
+		assert.Equal(t, test.isPassToEnforcer, test.policy.IsPassToEnforcer, test.message)
+	}
+}
+
+func TestValidatePolicyActionQueryMutation(t *testing.T) {
+	tests := []struct {
+		policy  Policy
+		isValid bool
+		message string
+	}{
+		{
+			policy:  Policy{Action: "REMOVE_QUERY", Parameters: map[string]interface{}{"queryParamName": "api_key"}},
+			isValid: true,
+			message: "REMOVE_QUERY policy with the query parameter name should be valid",
+		},
+		{
+			policy:  Policy{Action: "REMOVE_QUERY", Parameters: map[string]interface{}{"headerName": "api_key"}},
+			isValid: false,
+			message: "REMOVE_QUERY policy without the query parameter name should be invalid",
+		},
+		{
+			policy: Policy{Action: "RENAME_QUERY", Parameters: map[string]interface{}{
+				"currentQueryParamName": "id", "updatedQueryParamName": "legacyId"}},
+			isValid: true,
+			message: "RENAME_QUERY policy with the current and updated names should be valid",
+		},
+		{
+			policy:  Policy{Action: "RENAME_QUERY", Parameters: map[string]interface{}{"currentQueryParamName": "id"}},
+			isValid: false,
+			message: "RENAME_QUERY policy without the updated name should be invalid",
+		},
+	}
+	for _, test := range tests {
+		err := validatePolicyAction(&test.policy)
+		if !test.isValid {
+			assert.Error(t, err, test.message)
+			continue
+		}
+		assert.Nil(t, err, test.message)
+		assert.False(t, test.policy.IsPassToEnforcer, test.message)
+	}
+}
+
+func TestOperationGetStaticMutations(t *testing.T) {
+	operation := NewOperation("GET", nil, nil)
+	assert.Nil(t, operation.GetStaticMutations(), "Operation without policies should not have static mutations")
+
+	operation.policies = OperationPolicies{
+		Request: PolicyList{
+			{Action: "ADD_QUERY", Parameters: map[string]interface{}{"queryParamName": "foo", "queryParamValue": "bar"}},
+			{Action: "REMOVE_QUERY", Parameters: map[string]interface{}{"queryParamName": "api_key"}},
+			{Action: "RENAME_QUERY", Parameters: map[string]interface{}{
+				"currentQueryParamName": "id", "updatedQueryParamName": "legacyId"}},
+			{Action: "ADD_QUERY", IsPassToEnforcer: true,
+				Parameters: map[string]interface{}{"queryParamName": "user", "queryParamValue": "admin"}},
+		},
+		Response: PolicyList{
+			{Action: "REMOVE_QUERY", Parameters: map[string]interface{}{"queryParamName": "api_key"}},
+		},
+	}
+	expectedMutations := []QueryMutation{
+		{Action: "ADD_QUERY", Name: "foo", Value: "bar"},
+		{Action: "REMOVE_QUERY", Name: "api_key"},
+		{Action: "RENAME_QUERY", Name: "id", Value: "legacyId"},
+	}
+	mutations := operation.GetStaticMutations()
+	assert.NotNil(t, mutations, "Static mutations not found")
+	assert.Equal(t, expectedMutations, mutations.QueryMutations, "Query mutations mismatch")
+}
+
+func TestOperationGetStaticMutationsWithHeaderMutationOrder(t *testing.T) {
+	getSetHeaderPolicy := func(name, value string) Policy {
+		return Policy{Action: "SET_HEADER", Parameters: map[string]interface{}{"headerName": name, "headerValue": value}}
+	}
+	getRemoveHeaderPolicy := func(name string) Policy {
+		return Policy{Action: "REMOVE_HEADER", Parameters: map[string]interface{}{"headerName": name}}
+	}
+	operation := NewOperation("GET", nil, nil)
+	operation.policies = OperationPolicies{
+		Request: PolicyList{
+			getSetHeaderPolicy("x-removed", "foo"),
+			getRemoveHeaderPolicy("X-Removed"),
+			getRemoveHeaderPolicy("x-set"),
+			getSetHeaderPolicy("x-set", "foo"),
+			getSetHeaderPolicy("x-overridden", "foo"),
+			getSetHeaderPolicy("x-overridden", "bar"),
+		},
+		Response: PolicyList{
+			getRemoveHeaderPolicy("x-foo"),
+			getSetHeaderPolicy("x-bar", "bar"),
+		},
+	}
+	mutations := operation.GetStaticMutations()
+	assert.NotNil(t, mutations, "Static mutations not found")
+	assert.Equal(t, []NameValuePair{{Name: "x-set", Value: "foo"}, {Name: "x-overridden", Value: "bar"}},
+		mutations.RequestHeadersToAdd, "Only the last request mutation of a header should be added")
+	assert.Equal(t, []string{"X-Removed"}, mutations.RequestHeadersToRemove,
+		"Only the last request mutation of a header should be removed")
+	assert.Equal(t, []NameValuePair{{Name: "x-bar", Value: "bar"}}, mutations.ResponseHeadersToAdd,
+		"Response headers to add mismatch")
+	assert.Equal(t, []string{"x-foo"}, mutations.ResponseHeadersToRemove, "Response headers to remove mismatch")
+}
+
+func TestSetOperationPoliciesWithRequestInterceptor(t *testing.T) {
+	setHeaderPolicy := Policy{
+		PolicyName:    "fooAddRequestHeader",
+		PolicyVersion: "v1",
+		Parameters:    map[string]interface{}{"fooName": "fooHeaderName", "fooValue": "fooHeaderValue"},
+	}
+	apiYaml := APIYaml{}
+	apiYaml.Data.Operations = []OperationYaml{
+		{Target: "/pets", Verb: "GET", OperationPolicies: OperationPolicies{Request: PolicyList{setHeaderPolicy}}},
+		{Target: "/owners", Verb: "GET", OperationPolicies: OperationPolicies{Request: PolicyList{setHeaderPolicy}}},
+	}
+	proj := ProjectAPI{
+		APIYaml: apiYaml,
+		Policies: map[string]PolicyContainer{
+			"fooAddRequestHeader_v1": {
+				Specification: getSampleTestPolicySpec(),
+				Definition:    PolicyDefinition{RawData: getSampleTestPolicyDef()},
+			},
+		},
+	}
+	interceptedOperation := NewOperation("GET", nil, map[string]interface{}{
+		"x-wso2-request-interceptor": map[string]interface{}{"serviceURL": "https://interceptor:8443"},
+	})
+	operation := NewOperation("GET", nil, nil)
+	swagger := MgwSwagger{resources: []*Resource{
+		{path: "/pets", methods: []*Operation{interceptedOperation}},
+		{path: "/owners", methods: []*Operation{operation}},
+	}}
+
+	swagger.SetOperationPolicies(proj)
+	assert.Len(t, interceptedOperation.policies.Request, 1, "Request policies of the operation not found")
+	assert.True(t, interceptedOperation.policies.Request[0].IsPassToEnforcer,
+		"Request header mutations of an operation with a request interceptor should be applied by the enforcer")
+	assert.Nil(t, interceptedOperation.GetStaticMutations(),
+		"Request header mutations of an operation with a request interceptor should not be applied by the router")
+	assert.Len(t, operation.policies.Request, 1, "Request policies of the operation not found")
+	assert.False(t, operation.policies.Request[0].IsPassToEnforcer,
+		"Request header mutations of an operation without a request interceptor should be applied by the router")
+}
+
+func TestSetOperationPoliciesWithPathRewrite(t *testing.T) {
+	getPolicyContainer := func(name, definition string) PolicyContainer {
+		spec := getSampleTestPolicySpec()
+		spec.Data.Name = name
+		return PolicyContainer{Specification: spec, Definition: PolicyDefinition{RawData: []byte(definition)}}
+	}
+	addQueryPolicy := Policy{PolicyName: "fooAddQuery", PolicyVersion: "v1",
+		Parameters: map[string]interface{}{"fooName": "foo", "fooValue": "bar"}}
+	setHeaderPolicy := Policy{PolicyName: "fooAddRequestHeader", PolicyVersion: "v1",
+		Parameters: map[string]interface{}{"fooName": "fooHeaderName", "fooValue": "fooHeaderValue"}}
+	rewritePathPolicy := Policy{PolicyName: "fooRewritePath", PolicyVersion: "v1",
+		Parameters: map[string]interface{}{"fooName": "false", "fooValue": "/pets"}}
+	apiYaml := APIYaml{}
+	apiYaml.Data.Operations = []OperationYaml{
+		{Target: "/pets", Verb: "GET", OperationPolicies: OperationPolicies{
+			Request: PolicyList{rewritePathPolicy, addQueryPolicy, setHeaderPolicy}}},
+		{Target: "/owners", Verb: "GET", OperationPolicies: OperationPolicies{
+			Request: PolicyList{addQueryPolicy, setHeaderPolicy}}},
+	}
+	proj := ProjectAPI{
+		APIYaml: apiYaml,
+		Policies: map[string]PolicyContainer{
+			"fooAddQuery_v1": getPolicyContainer("fooAddQuery", `
+definition:
+  action: ADD_QUERY
+  parameters:
+    queryParamName: {{ .fooName }}
+    queryParamValue: {{ .fooValue }}
+`),
+			"fooAddRequestHeader_v1": getPolicyContainer("fooAddRequestHeader", string(getSampleTestPolicyDef())),
+			"fooRewritePath_v1": getPolicyContainer("fooRewritePath", `
+definition:
+  action: REWRITE_RESOURCE_PATH
+  parameters:
+    resourcePath: {{ .fooValue }}
+    includeQueryParams: {{ .fooName }}
+`),
+		},
+	}
+	rewrittenOperation := NewOperation("GET", nil, nil)
+	operation := NewOperation("GET", nil, nil)
+	swagger := MgwSwagger{resources: []*Resource{
+		{path: "/pets", methods: []*Operation{rewrittenOperation}},
+		{path: "/owners", methods: []*Operation{operation}},
+	}}
+
+	swagger.SetOperationPolicies(proj)
+	if assert.Len(t, rewrittenOperation.policies.Request, 3, "Request policies of the operation not found") {
+		assert.True(t, rewrittenOperation.policies.Request[1].IsPassToEnforcer,
+			"Query mutations of an operation with a path rewrite should be applied by the enforcer")
+		assert.False(t, rewrittenOperation.policies.Request[2].IsPassToEnforcer,
+			"Header mutations of an operation with a path rewrite should be applied by the router")
+	}
+	if assert.Len(t, operation.policies.Request, 2, "Request policies of the operation not found") {
+		assert.False(t, operation.policies.Request[0].IsPassToEnforcer,
+			"Query mutations of an operation without a path rewrite should be applied by the router")
+	}
+}
+
+func TestResourceValidateResponseMutations(t *testing.T) {
+	rewriteMethodPolicy := Policy{Action: "REWRITE_RESOURCE_METHOD",
+		Parameters: map[string]interface{}{"currentMethod": "GET", "updatedMethod": "POST"}}
+	getSetHeaderPolicy := func(value string) Policy {
+		return Policy{Action: "SET_HEADER", Parameters: map[string]interface{}{"headerName": "x-foo", "headerValue": value}}
+	}
+	getResource := func(rewriteMethod bool, getResponseValue, postResponseValue string) *Resource {
+		getOperation := NewOperation("GET", nil, nil)
+		getOperation.policies.Response = PolicyList{getSetHeaderPolicy(getResponseValue)}
+		if rewriteMethod {
+			getOperation.policies.Request = PolicyList{rewriteMethodPolicy}
+		}
+		postOperation := NewOperation("POST", nil, nil)
+		postOperation.policies.Response = PolicyList{getSetHeaderPolicy(postResponseValue)}
+		return &Resource{path: "/pets", methods: []*Operation{getOperation, postOperation}}
+	}
+
+	assert.Nil(t, getResource(true, "bar", "bar").validateResponseMutations(),
+		"Same response header mutations should be valid for a resource with a method rewrite")
+	assert.Error(t, getResource(true, "bar", "baz").validateResponseMutations(),
+		"Different response header mutations should be invalid for a resource with a method rewrite")
+	assert.Nil(t, getResource(false, "bar", "baz").validateResponseMutations(),
+		"Different response header mutations should be valid for a resource without a method rewrite")
+}
+
 func getSampleTestPolicySpec() PolicySpecification {
 	spec := PolicySpecification{}
 	spec.Data.Name = "fooAddRequestHeader"
@@ -20,23 +20,34 @@ package model
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/wso2/product-microgateway/adapter/config"
 	"github.com/wso2/product-microgateway/adapter/internal/oasparser/constants"
 )
 
 // supportedPoliciesMap maps (policy action name) -> (policy layout)
 var supportedPoliciesMap = map[string]policyLayout{
-	"SET_HEADER": {
-		RequiredParams:   []string{"headerName", "headerValue"},
-		IsPassToEnforcer: true,
+	// Header and query mutations are applied by the router, unless they mutate the pseudo headers or the host header.
+	constants.SetHeaderAction: {
+		RequiredParams:   []string{constants.HeaderName, constants.HeaderValue},
+		IsPassToEnforcer: false,
 	},
-	"REMOVE_HEADER": {
-		RequiredParams:   []string{"headerName"},
-		IsPassToEnforcer: true,
+	constants.RemoveHeaderAction: {
+		RequiredParams:   []string{constants.HeaderName},
+		IsPassToEnforcer: false,
 	},
-	"ADD_QUERY": {
-		RequiredParams:   []string{"queryParamName", "queryParamValue"},
-		IsPassToEnforcer: true,
+	constants.AddQueryAction: {
+		RequiredParams:   []string{constants.QueryParamName, constants.QueryParamValue},
+		IsPassToEnforcer: false,
+	},
+	constants.RemoveQueryAction: {
+		RequiredParams:   []string{constants.QueryParamName},
+		IsPassToEnforcer: false,
+	},
+	constants.RenameQueryAction: {
+		RequiredParams:   []string{constants.CurrentQueryParamName, constants.UpdatedQueryParamName},
+		IsPassToEnforcer: false,
 	},
 	constants.InterceptorServiceAction: {
 		RequiredParams:   []string{constants.InterceptorServiceURL, constants.InterceptorServiceIncludes},
@@ -50,6 +61,17 @@ var supportedPoliciesMap = map[string]policyLayout{
 		RequiredParams:   []string{constants.RewritePathResourcePath, constants.IncludeQueryParams},
 		IsPassToEnforcer: true,
 	},
+	constants.IPFilterAction: {
+		// At least one of the "allow" or "deny" parameters is required, validated when the filter is resolved
+		RequiredParams:   []string{},
+		IsPassToEnforcer: false,
+	},
+	constants.FaultInjectionAction: {
+		// Delay and abort parameters are optional, but at least one of them is required. Validated when the
+		// fault is resolved.
+		RequiredParams:   []string{},
+		IsPassToEnforcer: false,
+	},
 	"OPA": {
 		// Following parameters are not required (optional)
 		// "rule", token", "additionalProperties", "sendAccessToken", "maxOpenConnections", "maxPerRoute"
@@ -63,11 +85,33 @@ var supportedPoliciesMap = map[string]policyLayout{
 type policyLayout struct {
 	RequiredParams   []string
 	IsPassToEnforcer bool
+	// FilterClass is the enforcer filter which handles the policy, available only for the custom policy actions
+	FilterClass string
+}
+
+// getPolicyLayout returns the layout of the policy action. The custom policy actions declared in the configuration
+// are looked up if the action is not supported by Choreo Connect. A custom policy action cannot override a policy
+// action supported by Choreo Connect.
+func getPolicyLayout(action string) (policyLayout, bool) {
+	if layout, ok := supportedPoliciesMap[action]; ok {
+		return layout, true
+	}
+	conf, _ := config.ReadConfigs()
+	for _, customAction := range conf.Enforcer.CustomPolicyActions {
+		if customAction.Action == action {
+			return policyLayout{
+				RequiredParams:   customAction.RequiredParams,
+				IsPassToEnforcer: true,
+				FilterClass:      customAction.FilterClass,
+			}, true
+		}
+	}
+	return policyLayout{}, false
 }
 
 // validatePolicyAction validates policy against the policy definition that supported by Choreo Connect
 func validatePolicyAction(policy *Policy) error {
-	if layout, ok := supportedPoliciesMap[policy.Action]; ok {
+	if layout, ok := getPolicyLayout(policy.Action); ok {
 		for _, requiredParam := range layout.RequiredParams {
 			if params, isMap := policy.Parameters.(map[string]interface{}); isMap {
 				if _, ok := params[requiredParam]; !ok {
@@ -77,9 +121,52 @@ func validatePolicyAction(policy *Policy) error {
 				return errors.New("policy params required in map format")
 			}
 		}
-		policy.IsPassToEnforcer = layout.IsPassToEnforcer
+		policy.IsPassToEnforcer = layout.IsPassToEnforcer || isRuntimeMutation(policy)
+		policy.FilterClass = layout.FilterClass
 	} else {
 		return fmt.Errorf("policy action %q not supported by Choreo Connect gateway", policy.Action)
 	}
 	return nil
 }
+
+// isRuntimeMutation checks whether the header or query mutation policy has to be applied by the enforcer, as the
+// pseudo headers and the host header cannot be mutated by the router.
+func isRuntimeMutation(policy *Policy) bool {
+	if !isMutationAction(policy.Action) {
+		return false
+	}
+	params, _ := policy.Parameters.(map[string]interface{})
+	if headerName, ok := params[constants.HeaderName].(string); ok {
+		headerName = strings.ToLower(headerName)
+		if strings.HasPrefix(headerName, ":") || headerName == "host" {
+			return true
+		}
+	}
+	return false
+}
+
+func isMutationAction(action string) bool {
+	switch action {
+	case constants.SetHeaderAction, constants.RemoveHeaderAction, constants.AddQueryAction,
+		constants.RemoveQueryAction, constants.RenameQueryAction:
+		return true
+	}
+	return false
+}
+
+func isQueryMutationAction(action string) bool {
+	switch action {
+	case constants.AddQueryAction, constants.RemoveQueryAction, constants.RenameQueryAction:
+		return true
+	}
+	return false
+}
+
+func hasPolicyAction(policies PolicyList, action string) bool {
+	for _, policy := range policies {
+		if policy.Action == action {
+			return true
+		}
+	}
+	return false
+}
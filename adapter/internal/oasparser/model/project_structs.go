@@ -33,6 +33,10 @@ type ProjectAPI struct {
 	Policies         PolicyContainerMap // read from policy dir, policyName -> {policy spec, policy definition}
 	DownstreamCerts  map[string][]byte  // cert filename -> cert bytes
 	ClientCerts      []CertificateDetails
+	ErrorResponses   []ErrorResponseTemplate // read from Errors dir
+
+	EndpointClientCerts map[string]EndpointCertificate // url -> endpoint certificate with client cert and key filenames
+	UpstreamClientKeys  map[string][]byte              // key filename -> key bytes
 }
 
 // DeploymentEnvironments represents content of deployment_environments.yaml file
@@ -58,11 +62,22 @@ type EndpointCertificatesDetails struct {
 	Data    []EndpointCertificate `json:"data"`
 }
 
-// EndpointCertificate represents certificate information of an API_CTL project
+// EndpointCertificate represents certificate information of an API_CTL project. The client certificate and
+// the client key are presented to the endpoint if the endpoint requires mutual TLS.
 type EndpointCertificate struct {
-	Alias       string `json:"alias"`
-	Endpoint    string `json:"endpoint"`
-	Certificate string `json:"certificate"`
+	Alias             string `json:"alias"`
+	Endpoint          string `json:"endpoint"`
+	Certificate       string `json:"certificate"`
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	ClientKey         string `json:"clientKey,omitempty"`
+}
+
+// ErrorResponsesDetails represents content of an error response template file inside the Errors
+// directory of an API_CTL Project
+type ErrorResponsesDetails struct {
+	Type    string                  `yaml:"type" json:"type"`
+	Version string                  `yaml:"version" json:"version"`
+	Data    []ErrorResponseTemplate `yaml:"data" json:"data"`
 }
 
 // ClientCertificatesDetails represents content of client_certificates.yaml file
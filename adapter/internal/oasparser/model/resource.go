@@ -21,6 +21,7 @@ package model
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"sort"
 
@@ -48,6 +49,7 @@ type Resource struct {
 	productionEndpoints *EndpointCluster
 	sandboxEndpoints    *EndpointCluster
 	vendorExtensions    map[string]interface{}
+	routingConfig       *RoutingConfig
 }
 
 // GetProdEndpoints returns the production endpoints object of a given resource.
@@ -83,6 +85,20 @@ func (resource *Resource) GetVendorExtensions() map[string]interface{} {
 	return resource.vendorExtensions
 }
 
+// GetCorsConfig returns the resource level CORS configuration provided with the x-wso2-cors extension of the
+// resource. nil is returned if it is not available. An invalid configuration is rejected when the API is validated.
+func (resource *Resource) GetCorsConfig() *CorsConfig {
+	if corsConfig, err := getXWso2Cors(resource.vendorExtensions); err == nil {
+		return corsConfig
+	}
+	return nil
+}
+
+// GetRoutingConfig returns the resource level content based routing configuration.
+func (resource *Resource) GetRoutingConfig() *RoutingConfig {
+	return resource.routingConfig
+}
+
 // GetMethodList returns a list of http Methods as strings which are explicitly defined under
 // a given resource.
 func (resource *Resource) GetMethodList() []string {
@@ -204,6 +220,35 @@ func (a byPath) Less(i, j int) bool {
 	return pathI < pathJ
 }
 
+// validateResponseMutations validates that all the operations of a resource with a method rewrite have the same
+// response header mutations. A single route is created for such a resource, hence the router cannot apply
+// different response header mutations for the operations.
+func (resource *Resource) validateResponseMutations() error {
+	if _, rewriteMethod := resource.GetRewriteResource(); !rewriteMethod {
+		return nil
+	}
+	var headersToAdd []NameValuePair
+	var headersToRemove []string
+	for i, operation := range resource.methods {
+		var operationHeadersToAdd []NameValuePair
+		var operationHeadersToRemove []string
+		if mutations := operation.GetStaticMutations(); mutations != nil {
+			operationHeadersToAdd = mutations.ResponseHeadersToAdd
+			operationHeadersToRemove = mutations.ResponseHeadersToRemove
+		}
+		if i == 0 {
+			headersToAdd, headersToRemove = operationHeadersToAdd, operationHeadersToRemove
+			continue
+		}
+		if !reflect.DeepEqual(headersToAdd, operationHeadersToAdd) ||
+			!reflect.DeepEqual(headersToRemove, operationHeadersToRemove) {
+			return fmt.Errorf("the operations of the resource %v have different response header mutations, which "+
+				"is not supported as the method rewrite is enabled for the resource", resource.path)
+		}
+	}
+	return nil
+}
+
 //Swap Swaps the input parameter values
 func (a byPath) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 
@@ -31,6 +31,7 @@ import (
 
 	"github.com/wso2/product-microgateway/adapter/config"
 	"github.com/wso2/product-microgateway/adapter/internal/common"
+	"github.com/wso2/product-microgateway/adapter/internal/discovery/xds"
 	"github.com/wso2/product-microgateway/adapter/internal/notifier"
 	"github.com/wso2/product-microgateway/adapter/pkg/health"
 
@@ -119,7 +120,7 @@ func PushAPIProjects(payload []byte, environments []string) error {
 			deploymentList = append(deploymentList, deployedRevisionList...)
 		}
 	}
-	notifier.SendRevisionUpdateAck(deploymentList)
+	xds.SendRevisionUpdateAckOnAccept(deploymentList)
 	logger.LoggerSync.Infof("Successfully deployed %d API/s", len(deploymentList))
 	// Error nil for successful execution
 	return nil
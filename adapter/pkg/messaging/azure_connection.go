@@ -50,6 +50,8 @@ var (
 	AzureStepQuotaResetChannel chan []byte
 	// AzureOrganizationPurgeChannel stores the Organization Purge events
 	AzureOrganizationPurgeChannel chan []byte
+	// azureConsumerContext is cancelled to stop the ASB consumers on shutdown
+	azureConsumerContext, cancelAzureConsumers = context.WithCancel(context.Background())
 )
 
 func init() {
@@ -107,6 +109,11 @@ func InitiateConsumers(connectionString string, subscriptionMetaDataList []Subsc
 	}
 }
 
+// CloseAzureConsumers stops receiving messages from the ASB subscriptions and closes the receivers.
+func CloseAzureConsumers() {
+	cancelAzureConsumers()
+}
+
 func retrieveSubscriptionMetadata(metaDataList []Subscription, connectionString string, componentName string,
 	opts *admin.SubscriptionProperties) ([]Subscription, error) {
 	parentContext := context.Background()
@@ -43,9 +43,13 @@ func startBrokerConsumer(connectionString string, sub Subscription, reconnectInt
 	} else if strings.EqualFold(topic, organizationPurge) {
 		dataChannel = AzureOrganizationPurgeChannel
 	}
-	parentContext := context.Background()
+	parentContext := azureConsumerContext
 
 	for {
+		if parentContext.Err() != nil {
+			logger.LoggerMsg.Infof("Stopped the ASB consumer for subscription: %s, topic: %s", subName, topic)
+			return
+		}
 		// initializing the receiver client
 		subClient, err := asb.NewClientFromConnectionString(connectionString, nil)
 		if err != nil {
@@ -65,11 +69,15 @@ func startBrokerConsumer(connectionString string, sub Subscription, reconnectInt
 			defer logger.LoggerMsg.Errorf("ASB consumer has stopped for subscription: %q, topic: %q", subName, topic)
 			ctx, cancel := context.WithCancel(parentContext)
 			defer cancel()
+			defer receiver.Close(context.Background())
 
 			// keep receiving messages from asb
 			for {
 				logger.LoggerMsg.Debugf("Continue processing messages from ASB for subscription: %q, topic: %q", subName, topic)
 				messages, err := receiver.ReceiveMessages(ctx, 10, nil)
+				if ctx.Err() != nil {
+					return
+				}
 				if err != nil {
 					logger.LoggerMsg.Errorf("Failed to receive messages from ASB. %v", err)
 					time.Sleep(reconnectInterval)
@@ -82,7 +90,11 @@ func startBrokerConsumer(connectionString string, sub Subscription, reconnectInt
 					}
 
 					logger.LoggerMsg.Debugf("Message %s from ASB is waiting to be processed.", message.MessageID)
-					dataChannel <- body
+					select {
+					case dataChannel <- body:
+					case <-ctx.Done():
+						return
+					}
 					logger.LoggerMsg.Debugf("Message %s from ASB is processed", message.MessageID)
 
 					err = receiver.CompleteMessage(ctx, message, &asb.CompleteMessageOptions{})
@@ -24,6 +24,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/streadway/amqp"
@@ -74,6 +75,10 @@ func (c *Consumer) reconnect(key string) {
 	var err error
 	shouldReconnect := false
 	connClose := <-c.Conn.NotifyClose(make(chan *amqp.Error))
+	if atomic.LoadInt32(&jmsConnectionClosed) == 1 {
+		logger.LoggerMsg.Infof("Connection closed for %s on shutdown, hence not reconnecting", key)
+		return
+	}
 	connBlocked := c.Conn.NotifyBlocked(make(chan amqp.Blocking))
 	chClose := c.Channel.NotifyClose(make(chan *amqp.Error))
 
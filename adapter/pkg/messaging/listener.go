@@ -20,6 +20,7 @@ package messaging
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/streadway/amqp"
 	logger "github.com/wso2/product-microgateway/adapter/pkg/loggers"
@@ -32,6 +33,8 @@ var (
 	rabbitCloseError chan *amqp.Error
 	// amqpURIArray represents an array of amqpFailoverURL objects
 	amqpURIArray = make([]amqpFailoverURL, 0)
+	// jmsConnectionClosed is set to 1 once the amqp connection is closed on shutdown, to stop reconnecting
+	jmsConnectionClosed int32
 )
 
 const (
@@ -98,6 +101,19 @@ func (c *Consumer) Shutdown() error {
 	return <-c.Done
 }
 
+// CloseJMSConnection closes the amqp connection along with the consumers, without reconnecting.
+func CloseJMSConnection() error {
+	atomic.StoreInt32(&jmsConnectionClosed, 1)
+	if RabbitConn == nil || RabbitConn.IsClosed() {
+		return nil
+	}
+	if err := RabbitConn.Close(); err != nil {
+		return fmt.Errorf("AMQP connection close error: %s", err)
+	}
+	logger.LoggerMsg.Info("AMQP connection is closed")
+	return nil
+}
+
 // Consumer struct represents the structure of a consumer object
 type Consumer struct {
 	Conn    *amqp.Connection
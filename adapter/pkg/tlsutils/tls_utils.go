@@ -100,6 +100,13 @@ func IsPublicCertificate(certContent []byte) bool {
 	return false
 }
 
+// IsPrivateKey checks if the file content represents valid private key in PEM format.
+func IsPrivateKey(keyContent []byte) bool {
+	keyContentPattern := `\-\-\-\-\-BEGIN\s([A-Z]+\s)?PRIVATE\sKEY\-\-\-\-\-((.|\n)*)\-\-\-\-\-END\s([A-Z]+\s)?PRIVATE\sKEY\-\-\-\-\-`
+	regex := regexp.MustCompile(keyContentPattern)
+	return regex.Match(keyContent)
+}
+
 // InvokeControlPlane sends request to the control plane and returns the response
 func InvokeControlPlane(req *http.Request, skipSSL bool) (*http.Response, error) {
 	tr := &http.Transport{}
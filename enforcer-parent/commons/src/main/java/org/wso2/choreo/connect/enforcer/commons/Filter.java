@@ -18,6 +18,7 @@
 package org.wso2.choreo.connect.enforcer.commons;
 
 import org.wso2.choreo.connect.enforcer.commons.model.APIConfig;
+import org.wso2.choreo.connect.enforcer.commons.model.Policy;
 import org.wso2.choreo.connect.enforcer.commons.model.RequestContext;
 
 import java.util.Map;
@@ -31,4 +32,16 @@ public interface Filter {
     default void init(APIConfig apiConfig, Map<String, String> configProperties){};
 
     boolean handleRequest(RequestContext requestContext);
+
+    /**
+     * Applies an operation policy of a custom policy action, which is declared with this filter as the filter class.
+     * The filters handling custom policy actions should override this, as the policy is not applied otherwise.
+     *
+     * @param requestContext request context
+     * @param policy         operation policy of the matched resource
+     * @return false if the request should not be forwarded to the upstream
+     */
+    default boolean handlePolicy(RequestContext requestContext, Policy policy) {
+        return true;
+    }
 }
@@ -26,6 +26,7 @@ public class Policy {
 
     private String action;
     private Map<String, String> parameters;
+    private String filterClass;
 
     public Policy() {
     }
@@ -35,6 +36,12 @@ public class Policy {
         this.parameters = parameters;
     }
 
+    public Policy(String action, Map<String, String> parameters, String filterClass) {
+        this.action = action;
+        this.parameters = parameters;
+        this.filterClass = filterClass;
+    }
+
     public String getAction() {
         return action;
     }
@@ -50,4 +57,18 @@ public class Policy {
     public void setParameters(Map<String, String> parameters) {
         this.parameters = parameters;
     }
+
+    /**
+     * Returns the class name of the custom filter which handles the policy action. It is available only for the
+     * custom policy actions declared in the adapter configuration.
+     *
+     * @return filter class name, or null for the policy actions supported by Choreo Connect
+     */
+    public String getFilterClass() {
+        return filterClass;
+    }
+
+    public void setFilterClass(String filterClass) {
+        this.filterClass = filterClass;
+    }
 }
@@ -52,6 +52,13 @@ public class RequestContext {
     // the openAPI has production endpoints alone.
     private String prodClusterHeader;
     private String sandClusterHeader;
+    // Denotes whether the route has an operation level timeout or retry policy, which should not be
+    // overridden by the endpoint level configurations.
+    private boolean routeTimeoutConfigured;
+    private boolean routeRetryPolicyConfigured;
+    // Denotes the JWT claims matched by the content based routing rules of the route, which should be
+    // passed to the router as dynamic metadata.
+    private List<String> routingClaims;
     //Denotes the specific headers which needs to be passed to response object
     private Map<String, String> addHeaders;
     private Map<String, String> metadataMap = new HashMap<>();
@@ -278,6 +285,36 @@ public class RequestContext {
         return sandClusterHeader;
     }
 
+    /**
+     * Returns true if the matched route has an operation level timeout. In that case, the timeout of the
+     * endpoint should not be applied.
+     *
+     * @return true if the route has an operation level timeout
+     */
+    public boolean isRouteTimeoutConfigured() {
+        return routeTimeoutConfigured;
+    }
+
+    /**
+     * Returns true if the matched route has an operation level retry policy. In that case, the retry
+     * configuration of the endpoint should not be applied.
+     *
+     * @return true if the route has an operation level retry policy
+     */
+    public boolean isRouteRetryPolicyConfigured() {
+        return routeRetryPolicyConfigured;
+    }
+
+    /**
+     * Returns the JWT claims matched by the content based routing rules of the matched route. The values of
+     * these claims should be added to the dynamic metadata for the router to reselect the route.
+     *
+     * @return the claim names, empty if the route does not have claim based routing rules
+     */
+    public List<String> getRoutingClaims() {
+        return routingClaims;
+    }
+
     /**
      * If a certain header needs to be added/modified within the request from enforcer additionally,
      * those header-value pairs  should be added from here.
@@ -402,6 +439,9 @@ public class RequestContext {
         private Map<String, String> headers;
         private String prodClusterHeader;
         private String sandClusterHeader;
+        private boolean routeTimeoutConfigured;
+        private boolean routeRetryPolicyConfigured;
+        private List<String> routingClaims = new ArrayList<>();
         private long requestTimeStamp;
         private Map<String, Object> properties = new HashMap<>();
         private AuthenticationContext authenticationContext = new AuthenticationContext();
@@ -449,6 +489,21 @@ public class RequestContext {
             return this;
         }
 
+        public Builder routeTimeoutConfigured(boolean routeTimeoutConfigured) {
+            this.routeTimeoutConfigured = routeTimeoutConfigured;
+            return this;
+        }
+
+        public Builder routeRetryPolicyConfigured(boolean routeRetryPolicyConfigured) {
+            this.routeRetryPolicyConfigured = routeRetryPolicyConfigured;
+            return this;
+        }
+
+        public Builder routingClaims(List<String> routingClaims) {
+            this.routingClaims = routingClaims;
+            return this;
+        }
+
         public Builder requestTimeStamp(long requestTimeStampInMillies) {
             this.requestTimeStamp = requestTimeStampInMillies;
             return this;
@@ -493,6 +548,9 @@ public class RequestContext {
             requestContext.headers = this.headers;
             requestContext.prodClusterHeader = this.prodClusterHeader;
             requestContext.sandClusterHeader = this.sandClusterHeader;
+            requestContext.routeTimeoutConfigured = this.routeTimeoutConfigured;
+            requestContext.routeRetryPolicyConfigured = this.routeRetryPolicyConfigured;
+            requestContext.routingClaims = this.routingClaims;
             requestContext.properties = this.properties;
             requestContext.requestPathTemplate = this.requestPathTemplate;
             requestContext.requestTimeStamp = this.requestTimeStamp;
@@ -21,6 +21,7 @@ private static final long serialVersionUID = 0L;
   }
   private Policy() {
     action_ = "";
+    filterClass_ = "";
   }
 
   @java.lang.Override
@@ -73,6 +74,12 @@ private static final long serialVersionUID = 0L;
                 parameters__.getKey(), parameters__.getValue());
             break;
           }
+          case 26: {
+            java.lang.String s = input.readStringRequireUtf8();
+
+            filterClass_ = s;
+            break;
+          }
           default: {
             if (!parseUnknownField(
                 input, unknownFields, extensionRegistry, tag)) {
@@ -236,6 +243,54 @@ private static final long serialVersionUID = 0L;
     return map.get(key);
   }
 
+  public static final int FILTERCLASS_FIELD_NUMBER = 3;
+  private volatile java.lang.Object filterClass_;
+  /**
+   * <pre>
+   * Fully qualified class name of the enforcer filter which handles the policy action.
+   * Available only for the custom policy actions declared in the adapter configuration.
+   * </pre>
+   *
+   * <code>string filterClass = 3;</code>
+   * @return The filterClass.
+   */
+  @java.lang.Override
+  public java.lang.String getFilterClass() {
+    java.lang.Object ref = filterClass_;
+    if (ref instanceof java.lang.String) {
+      return (java.lang.String) ref;
+    } else {
+      com.google.protobuf.ByteString bs = 
+          (com.google.protobuf.ByteString) ref;
+      java.lang.String s = bs.toStringUtf8();
+      filterClass_ = s;
+      return s;
+    }
+  }
+  /**
+   * <pre>
+   * Fully qualified class name of the enforcer filter which handles the policy action.
+   * Available only for the custom policy actions declared in the adapter configuration.
+   * </pre>
+   *
+   * <code>string filterClass = 3;</code>
+   * @return The bytes for filterClass.
+   */
+  @java.lang.Override
+  public com.google.protobuf.ByteString
+      getFilterClassBytes() {
+    java.lang.Object ref = filterClass_;
+    if (ref instanceof java.lang.String) {
+      com.google.protobuf.ByteString b = 
+          com.google.protobuf.ByteString.copyFromUtf8(
+              (java.lang.String) ref);
+      filterClass_ = b;
+      return b;
+    } else {
+      return (com.google.protobuf.ByteString) ref;
+    }
+  }
+
   private byte memoizedIsInitialized = -1;
   @java.lang.Override
   public final boolean isInitialized() {
@@ -259,6 +314,9 @@ private static final long serialVersionUID = 0L;
         internalGetParameters(),
         ParametersDefaultEntryHolder.defaultEntry,
         2);
+    if (!getFilterClassBytes().isEmpty()) {
+      com.google.protobuf.GeneratedMessageV3.writeString(output, 3, filterClass_);
+    }
     unknownFields.writeTo(output);
   }
 
@@ -281,6 +339,9 @@ private static final long serialVersionUID = 0L;
       size += com.google.protobuf.CodedOutputStream
           .computeMessageSize(2, parameters__);
     }
+    if (!getFilterClassBytes().isEmpty()) {
+      size += com.google.protobuf.GeneratedMessageV3.computeStringSize(3, filterClass_);
+    }
     size += unknownFields.getSerializedSize();
     memoizedSize = size;
     return size;
@@ -300,6 +361,8 @@ private static final long serialVersionUID = 0L;
         .equals(other.getAction())) return false;
     if (!internalGetParameters().equals(
         other.internalGetParameters())) return false;
+    if (!getFilterClass()
+        .equals(other.getFilterClass())) return false;
     if (!unknownFields.equals(other.unknownFields)) return false;
     return true;
   }
@@ -317,6 +380,8 @@ private static final long serialVersionUID = 0L;
       hash = (37 * hash) + PARAMETERS_FIELD_NUMBER;
       hash = (53 * hash) + internalGetParameters().hashCode();
     }
+    hash = (37 * hash) + FILTERCLASS_FIELD_NUMBER;
+    hash = (53 * hash) + getFilterClass().hashCode();
     hash = (29 * hash) + unknownFields.hashCode();
     memoizedHashCode = hash;
     return hash;
@@ -479,6 +544,8 @@ private static final long serialVersionUID = 0L;
       action_ = "";
 
       internalGetMutableParameters().clear();
+      filterClass_ = "";
+
       return this;
     }
 
@@ -509,6 +576,7 @@ private static final long serialVersionUID = 0L;
       result.action_ = action_;
       result.parameters_ = internalGetParameters();
       result.parameters_.makeImmutable();
+      result.filterClass_ = filterClass_;
       onBuilt();
       return result;
     }
@@ -563,6 +631,10 @@ private static final long serialVersionUID = 0L;
       }
       internalGetMutableParameters().mergeFrom(
           other.internalGetParameters());
+      if (!other.getFilterClass().isEmpty()) {
+        filterClass_ = other.filterClass_;
+        onChanged();
+      }
       this.mergeUnknownFields(other.unknownFields);
       onChanged();
       return this;
@@ -796,6 +868,107 @@ private static final long serialVersionUID = 0L;
           .putAll(values);
       return this;
     }
+
+    private java.lang.Object filterClass_ = "";
+    /**
+     * <pre>
+     * Fully qualified class name of the enforcer filter which handles the policy action.
+     * Available only for the custom policy actions declared in the adapter configuration.
+     * </pre>
+     *
+     * <code>string filterClass = 3;</code>
+     * @return The filterClass.
+     */
+    public java.lang.String getFilterClass() {
+      java.lang.Object ref = filterClass_;
+      if (!(ref instanceof java.lang.String)) {
+        com.google.protobuf.ByteString bs =
+            (com.google.protobuf.ByteString) ref;
+        java.lang.String s = bs.toStringUtf8();
+        filterClass_ = s;
+        return s;
+      } else {
+        return (java.lang.String) ref;
+      }
+    }
+    /**
+     * <pre>
+     * Fully qualified class name of the enforcer filter which handles the policy action.
+     * Available only for the custom policy actions declared in the adapter configuration.
+     * </pre>
+     *
+     * <code>string filterClass = 3;</code>
+     * @return The bytes for filterClass.
+     */
+    public com.google.protobuf.ByteString
+        getFilterClassBytes() {
+      java.lang.Object ref = filterClass_;
+      if (ref instanceof String) {
+        com.google.protobuf.ByteString b = 
+            com.google.protobuf.ByteString.copyFromUtf8(
+                (java.lang.String) ref);
+        filterClass_ = b;
+        return b;
+      } else {
+        return (com.google.protobuf.ByteString) ref;
+      }
+    }
+    /**
+     * <pre>
+     * Fully qualified class name of the enforcer filter which handles the policy action.
+     * Available only for the custom policy actions declared in the adapter configuration.
+     * </pre>
+     *
+     * <code>string filterClass = 3;</code>
+     * @param value The filterClass to set.
+     * @return This builder for chaining.
+     */
+    public Builder setFilterClass(
+        java.lang.String value) {
+      if (value == null) {
+    throw new NullPointerException();
+  }
+  
+      filterClass_ = value;
+      onChanged();
+      return this;
+    }
+    /**
+     * <pre>
+     * Fully qualified class name of the enforcer filter which handles the policy action.
+     * Available only for the custom policy actions declared in the adapter configuration.
+     * </pre>
+     *
+     * <code>string filterClass = 3;</code>
+     * @return This builder for chaining.
+     */
+    public Builder clearFilterClass() {
+      
+      filterClass_ = getDefaultInstance().getFilterClass();
+      onChanged();
+      return this;
+    }
+    /**
+     * <pre>
+     * Fully qualified class name of the enforcer filter which handles the policy action.
+     * Available only for the custom policy actions declared in the adapter configuration.
+     * </pre>
+     *
+     * <code>string filterClass = 3;</code>
+     * @param value The bytes for filterClass to set.
+     * @return This builder for chaining.
+     */
+    public Builder setFilterClassBytes(
+        com.google.protobuf.ByteString value) {
+      if (value == null) {
+    throw new NullPointerException();
+  }
+  checkByteStringIsUtf8(value);
+      
+      filterClass_ = value;
+      onChanged();
+      return this;
+    }
     @java.lang.Override
     public final Builder setUnknownFields(
         final com.google.protobuf.UnknownFieldSet unknownFields) {
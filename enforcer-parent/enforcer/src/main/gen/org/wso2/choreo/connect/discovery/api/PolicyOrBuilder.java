@@ -52,4 +52,26 @@ public interface PolicyOrBuilder extends
 
   java.lang.String getParametersOrThrow(
       java.lang.String key);
+
+  /**
+   * <pre>
+   * Fully qualified class name of the enforcer filter which handles the policy action.
+   * Available only for the custom policy actions declared in the adapter configuration.
+   * </pre>
+   *
+   * <code>string filterClass = 3;</code>
+   * @return The filterClass.
+   */
+  java.lang.String getFilterClass();
+  /**
+   * <pre>
+   * Fully qualified class name of the enforcer filter which handles the policy action.
+   * Available only for the custom policy actions declared in the adapter configuration.
+   * </pre>
+   *
+   * <code>string filterClass = 3;</code>
+   * @return The bytes for filterClass.
+   */
+  com.google.protobuf.ByteString
+      getFilterClassBytes();
 }
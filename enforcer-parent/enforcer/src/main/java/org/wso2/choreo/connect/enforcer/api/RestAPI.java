@@ -308,11 +308,11 @@ public class RestAPI implements API {
         return policyConfig;
     }
 
-    private static ArrayList<Policy> genPolicyList
+    static ArrayList<Policy> genPolicyList
             (List<org.wso2.choreo.connect.discovery.api.Policy> operationPoliciesList) {
         ArrayList<Policy> policyList = new ArrayList<>();
         for (org.wso2.choreo.connect.discovery.api.Policy policy : operationPoliciesList) {
-            policyList.add(new Policy(policy.getAction(), policy.getParametersMap()));
+            policyList.add(new Policy(policy.getAction(), policy.getParametersMap(), policy.getFilterClass()));
         }
         return policyList;
     }
@@ -361,17 +361,24 @@ public class RestAPI implements API {
         throttleFilter.init(apiConfig, null);
         this.filters.add(throttleFilter);
 
-        loadCustomFilters(apiConfig);
+        Map<String, Filter> customFilterImplMap = loadCustomFilters(apiConfig);
 
         // CORS filter is added as the first filter, and it is not customizable.
         CorsFilter corsFilter = new CorsFilter();
         this.filters.add(0, corsFilter);
 
-        MediationPolicyFilter mediationPolicyFilter = new MediationPolicyFilter();
+        // The custom policy actions of the operation policies are handled by the custom filters.
+        MediationPolicyFilter mediationPolicyFilter = new MediationPolicyFilter(customFilterImplMap);
         this.filters.add(mediationPolicyFilter);
     }
 
-    private void loadCustomFilters(APIConfig apiConfig) {
+    /**
+     * Adds the custom filters configured in the enforcer configuration to the filter chain.
+     *
+     * @param apiConfig API configuration
+     * @return all the custom filter implementations available in the classpath, by the class name
+     */
+    private Map<String, Filter> loadCustomFilters(APIConfig apiConfig) {
         FilterDTO[] customFilters = ConfigHolder.getInstance().getConfig().getCustomFilters();
         // Needs to sort the filter in ascending order to position the filter in the given position.
         Arrays.sort(customFilters, Comparator.comparing(FilterDTO::getPosition));
@@ -398,6 +405,7 @@ public class RestAPI implements API {
                         + filterDTO.getClassName());
             }
         }
+        return filterImplMap;
     }
 
     private void populateRemoveAndProtectedHeaders(RequestContext requestContext) {
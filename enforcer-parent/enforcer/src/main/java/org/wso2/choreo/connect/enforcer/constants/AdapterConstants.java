@@ -29,6 +29,12 @@ public class AdapterConstants {
     public static final String PROD_CLUSTER_HEADER_KEY = "prodClusterName";
      // The key which specifies the sandbox cluster name inside the request context
     public static final String SAND_CLUSTER_HEADER_KEY = "sandClusterName";
+    // The key which specifies that the route has an operation level timeout
+    public static final String ROUTE_TIMEOUT_KEY = "routeTimeout";
+    // The key which specifies that the route has an operation level retry policy
+    public static final String ROUTE_RETRY_POLICY_KEY = "routeRetryPolicy";
+    // The key which specifies the comma separated JWT claims matched by the content based routing rules
+    public static final String ROUTING_CLAIMS_KEY = "routingClaims";
     // The common enforcer Label
     public static final String COMMON_ENFORCER_LABEL = "commonEnforcerLabel";
     // The node identifier Key
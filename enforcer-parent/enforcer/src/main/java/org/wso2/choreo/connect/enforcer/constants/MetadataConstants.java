@@ -50,5 +50,8 @@ public class MetadataConstants {
     public static final String USER_AGENT_KEY = WSO2_METADATA_PREFIX + "user-agent";
     public static final String CLIENT_IP_KEY = WSO2_METADATA_PREFIX + "client-ip";
 
+    // The JWT claims matched by the content based routing rules are added with this prefix
+    public static final String CLAIM_KEY_PREFIX = WSO2_METADATA_PREFIX + "claim-";
+
     public static final String ERROR_CODE_KEY = "ErrorCode";
 }
@@ -18,6 +18,7 @@
 package org.wso2.choreo.connect.enforcer.interceptor;
 
 import io.grpc.netty.shaded.io.netty.handler.codec.http.HttpMethod;
+import org.apache.commons.lang3.StringUtils;
 import org.apache.http.NameValuePair;
 import org.apache.http.client.utils.URLEncodedUtils;
 import org.apache.logging.log4j.LogManager;
@@ -36,6 +37,7 @@ import org.wso2.choreo.connect.enforcer.interceptor.opa.OPAClient;
 import org.wso2.choreo.connect.enforcer.util.FilterUtils;
 
 import java.nio.charset.StandardCharsets;
+import java.util.Collections;
 import java.util.List;
 import java.util.Map;
 import java.util.regex.Matcher;
@@ -48,7 +50,15 @@ public class MediationPolicyFilter implements Filter {
     private static final Logger log = LogManager.getLogger(MediationPolicyFilter.class);
     private static final String X_URI_MAPPING_PROPERTY = "x-uri-mapping";
 
+    // custom filters which handle the custom policy actions, by the class name
+    private final Map<String, Filter> policyFilters;
+
     public MediationPolicyFilter() {
+        this(Collections.emptyMap());
+    }
+
+    public MediationPolicyFilter(Map<String, Filter> policyFilters) {
+        this.policyFilters = policyFilters;
         OPAClient.init();
     }
 
@@ -68,6 +78,10 @@ public class MediationPolicyFilter implements Filter {
     }
 
     private boolean applyPolicy(RequestContext requestContext, Policy policy) {
+        // custom policy actions are handled by the filter declared for the action in the adapter configuration
+        if (StringUtils.isNotEmpty(policy.getFilterClass())) {
+            return applyCustomPolicy(requestContext, policy);
+        }
         switch (policy.getAction()) {
             case "SET_HEADER": {
                 addOrModifyHeader(requestContext, policy.getParameters());
@@ -89,6 +103,10 @@ public class MediationPolicyFilter implements Filter {
                 removeQuery(requestContext, policy.getParameters());
                 return true;
             }
+            case "RENAME_QUERY": {
+                renameQuery(requestContext, policy.getParameters());
+                return true;
+            }
             case "REWRITE_RESOURCE_PATH": {
                 removeAllQueries(requestContext, policy.getParameters());
                 pathParamToQueryParamTransform(requestContext, policy.getParameters());
@@ -112,6 +130,20 @@ public class MediationPolicyFilter implements Filter {
         return false;
     }
 
+    private boolean applyCustomPolicy(RequestContext requestContext, Policy policy) {
+        Filter filter = policyFilters.get(policy.getFilterClass());
+        if (filter == null) {
+            log.error("No Filter Implementation is found in the classPath under the name \"{}\" to handle the "
+                    + "operation policy action \"{}\"", policy.getFilterClass(), policy.getAction(),
+                    ErrorDetails.errorLog(LoggingConstants.Severity.MAJOR, 6102));
+            FilterUtils.setErrorToContext(requestContext, GeneralErrorCodeConstants.MEDIATION_POLICY_ERROR_CODE,
+                    APIConstants.StatusCodes.INTERNAL_SERVER_ERROR.getCode(),
+                    APIConstants.INTERNAL_SERVER_ERROR_MESSAGE, null);
+            return false;
+        }
+        return filter.handlePolicy(requestContext, policy);
+    }
+
     private void addOrModifyHeader(RequestContext requestContext, Map<String, String> policyAttrib) {
         String headerName = policyAttrib.get("headerName");
         String headerValue = policyAttrib.get("headerValue");
@@ -138,6 +170,17 @@ public class MediationPolicyFilter implements Filter {
         requestContext.getQueryParamsToRemove().add(queryName);
     }
 
+    private void renameQuery(RequestContext requestContext, Map<String, String> policyAttrib) {
+        String currentQueryName = policyAttrib.get("currentQueryParamName");
+        String updatedQueryName = policyAttrib.get("updatedQueryParamName");
+        if (requestContext.getQueryParameters() != null
+                && requestContext.getQueryParameters().containsKey(currentQueryName)) {
+            String queryValue = requestContext.getQueryParameters().get(currentQueryName);
+            requestContext.getQueryParamsToRemove().add(currentQueryName);
+            requestContext.getQueryParamsToAdd().put(updatedQueryName, queryValue);
+        }
+    }
+
     private void removeAllQueries(RequestContext requestContext, Map<String, String> policyAttrib) {
         // adapter may not pass, booleans with false in the map, hence empty, null or any other strings
         // excepts "true" is considered as false.
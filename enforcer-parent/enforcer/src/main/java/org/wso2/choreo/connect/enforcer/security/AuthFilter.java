@@ -313,18 +313,21 @@ public class AuthFilter implements Filter {
                 resourceConfig.getEndpoints().containsKey(keyType)) {
             EndpointCluster endpointCluster = resourceConfig.getEndpoints().get(keyType);
 
-            // Apply resource level retry headers
-            if (endpointCluster.getRetryConfig() != null) {
+            // Apply resource level retry headers, unless the route has an operation level retry policy
+            if (endpointCluster.getRetryConfig() != null && !requestContext.isRouteRetryPolicyConfigured()) {
                 addRetryConfigHeaders(requestContext, endpointCluster.getRetryConfig());
             }
-            // Apply resource level timeout headers
-            if (endpointCluster.getRouteTimeoutInMillis() != null) {
+            // Apply resource level timeout headers, unless the route has an operation level timeout
+            if (endpointCluster.getRouteTimeoutInMillis() != null && !requestContext.isRouteTimeoutConfigured()) {
                 addTimeoutHeaders(requestContext, endpointCluster.getRouteTimeoutInMillis());
             }
         }
     }
 
     private void addAPILevelRetryConfigHeaders(RequestContext requestContext, String keyType) {
+        if (requestContext.isRouteRetryPolicyConfigured()) {
+            return;
+        }
         RetryConfig apiLevelRetryConfig =
                 requestContext.getMatchedAPI().getEndpoints().get(keyType).getRetryConfig();
         if (apiLevelRetryConfig != null) {
@@ -333,6 +336,9 @@ public class AuthFilter implements Filter {
     }
 
     private void addAPILevelTimeoutHeaders(RequestContext requestContext, String keyType) {
+        if (requestContext.isRouteTimeoutConfigured()) {
+            return;
+        }
         Integer apiLevelTimeout =
                 requestContext.getMatchedAPI().getEndpoints().get(keyType).getRouteTimeoutInMillis();
         if (apiLevelTimeout != null) {
@@ -277,6 +277,7 @@ public class JWTAuthenticator implements Authenticator {
                         }
                     }
                     log.debug("JWT authentication successful.");
+                    FilterUtils.addRoutingClaimsToMetadata(requestContext, validationInfo.getClaims());
 
                     // Generate or get backend JWT
                     String endUserToken = null;
@@ -33,6 +33,8 @@ import org.wso2.choreo.connect.enforcer.constants.AdapterConstants;
 import org.wso2.choreo.connect.enforcer.constants.HttpConstants;
 import org.wso2.choreo.connect.enforcer.util.FilterUtils;
 
+import java.util.ArrayList;
+import java.util.Arrays;
 import java.util.Map;
 
 /**
@@ -79,6 +81,12 @@ public class HttpRequestHandler implements RequestHandler<CheckRequest, Response
                 .get(AdapterConstants.PROD_CLUSTER_HEADER_KEY);
         String sandCluster = request.getAttributes().getContextExtensionsMap()
                 .get(AdapterConstants.SAND_CLUSTER_HEADER_KEY);
+        boolean routeTimeoutConfigured = request.getAttributes().getContextExtensionsMap()
+                .containsKey(AdapterConstants.ROUTE_TIMEOUT_KEY);
+        boolean routeRetryPolicyConfigured = request.getAttributes().getContextExtensionsMap()
+                .containsKey(AdapterConstants.ROUTE_RETRY_POLICY_KEY);
+        String routingClaims = request.getAttributes().getContextExtensionsMap()
+                .getOrDefault(AdapterConstants.ROUTING_CLAIMS_KEY, "");
         long requestTimeInMillis = request.getAttributes().getRequest().getTime().getSeconds() * 1000 +
                 request.getAttributes().getRequest().getTime().getNanos() / 1000000;
         String requestID =  request.getAttributes().getRequest().getHttp().
@@ -107,6 +115,8 @@ public class HttpRequestHandler implements RequestHandler<CheckRequest, Response
         return new RequestContext.Builder(requestPath).matchedResourceConfig(resourceConfig).requestMethod(method)
                 .certificate(certificate).matchedAPI(api.getAPIConfig()).headers(headers).requestID(requestID)
                 .address(address).prodClusterHeader(prodCluster).sandClusterHeader(sandCluster)
+                .routeTimeoutConfigured(routeTimeoutConfigured).routeRetryPolicyConfigured(routeRetryPolicyConfigured)
+                .routingClaims(routingClaims.isEmpty() ? new ArrayList<>() : Arrays.asList(routingClaims.split(",")))
                 .requestTimeStamp(requestTimeInMillis).pathTemplate(pathTemplate).requestPayload(requestPayload)
                 .build();
     }
@@ -51,6 +51,7 @@ import org.wso2.choreo.connect.enforcer.config.dto.MutualSSLDto;
 import org.wso2.choreo.connect.enforcer.constants.APIConstants;
 import org.wso2.choreo.connect.enforcer.constants.APISecurityConstants;
 import org.wso2.choreo.connect.enforcer.constants.JwtConstants;
+import org.wso2.choreo.connect.enforcer.constants.MetadataConstants;
 import org.wso2.choreo.connect.enforcer.dto.APIKeyValidationInfoDTO;
 import org.wso2.choreo.connect.enforcer.throttle.ThrottleConstants;
 
@@ -458,6 +459,27 @@ public class FilterUtils {
         }
     }
 
+    /**
+     * Add the values of the JWT claims matched by the content based routing rules of the route to the dynamic
+     * metadata, as the router reselects the route based on the metadata once the request is authenticated.
+     * Only the string, number and boolean claims are added.
+     *
+     * @param requestContext - The context object holds details about the specific request.
+     * @param claims - The claims of the validated JWT.
+     */
+    public static void addRoutingClaimsToMetadata(RequestContext requestContext, Map<String, Object> claims) {
+        if (claims == null) {
+            return;
+        }
+        for (String claimName : requestContext.getRoutingClaims()) {
+            Object claimValue = claims.get(claimName);
+            if (claimValue instanceof String || claimValue instanceof Number || claimValue instanceof Boolean) {
+                requestContext.addMetadataToMap(MetadataConstants.CLAIM_KEY_PREFIX + claimName,
+                        String.valueOf(claimValue));
+            }
+        }
+    }
+
     private static Map<String, Object> getClaimsFromJWTValidationInfo(JWTInfoDto jwtInfoDto) {
 
         if (jwtInfoDto.getJwtValidationInfo() != null) {
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ * WSO2 Inc. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package org.wso2.choreo.connect.enforcer.api;
+
+import com.google.protobuf.InvalidProtocolBufferException;
+import org.junit.Assert;
+import org.junit.Test;
+import org.wso2.choreo.connect.discovery.api.Policy;
+
+import java.util.Arrays;
+import java.util.List;
+
+public class RestAPITest {
+
+    @Test
+    public void testGenPolicyListWithFilterClass() throws InvalidProtocolBufferException {
+        Policy customPolicy = Policy.newBuilder().setAction("CUSTOM_HEADER_VALIDATION")
+                .putParameters("headerName", "tenant")
+                .setFilterClass("org.wso2.choreo.connect.custom.CCCustomFilter").build();
+        Policy setHeaderPolicy = Policy.newBuilder().setAction("SET_HEADER")
+                .putParameters("headerName", "x-foo").putParameters("headerValue", "bar").build();
+        // The policies are received from the adapter in the wire format.
+        Policy receivedPolicy = Policy.parseFrom(customPolicy.toByteArray());
+        Assert.assertEquals(customPolicy, receivedPolicy);
+
+        List<org.wso2.choreo.connect.enforcer.commons.model.Policy> policies =
+                RestAPI.genPolicyList(Arrays.asList(receivedPolicy, setHeaderPolicy));
+        Assert.assertEquals(2, policies.size());
+        Assert.assertEquals("CUSTOM_HEADER_VALIDATION", policies.get(0).getAction());
+        Assert.assertEquals("tenant", policies.get(0).getParameters().get("headerName"));
+        Assert.assertEquals("org.wso2.choreo.connect.custom.CCCustomFilter", policies.get(0).getFilterClass());
+        Assert.assertTrue("Built-in policy actions should not have a filter class",
+                policies.get(1).getFilterClass().isEmpty());
+    }
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ * WSO2 Inc. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package org.wso2.choreo.connect.enforcer.interceptor;
+
+import org.junit.Assert;
+import org.junit.Test;
+import org.wso2.choreo.connect.enforcer.commons.Filter;
+import org.wso2.choreo.connect.enforcer.commons.model.APIConfig;
+import org.wso2.choreo.connect.enforcer.commons.model.Policy;
+import org.wso2.choreo.connect.enforcer.commons.model.PolicyConfig;
+import org.wso2.choreo.connect.enforcer.commons.model.RequestContext;
+import org.wso2.choreo.connect.enforcer.commons.model.ResourceConfig;
+import org.wso2.choreo.connect.enforcer.constants.APIConstants;
+
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.HashMap;
+import java.util.Map;
+
+public class MediationPolicyFilterTest {
+    private static final APIConfig API_CONFIG = new APIConfig.Builder("Petstore").basePath("/petstore").build();
+    private static final String CUSTOM_ACTION = "CUSTOM_HEADER_VALIDATION";
+
+    @Test
+    public void testCustomPolicyActionIsHandledByFilterClass() {
+        HeaderValidationFilter customFilter = new HeaderValidationFilter();
+        Map<String, Filter> policyFilters = new HashMap<>();
+        policyFilters.put(HeaderValidationFilter.class.getName(), customFilter);
+        MediationPolicyFilter mediationPolicyFilter = new MediationPolicyFilter(policyFilters);
+
+        RequestContext requestContext = getRequestContext(HeaderValidationFilter.class.getName(), "tenant");
+        Assert.assertTrue(mediationPolicyFilter.handleRequest(requestContext));
+        Assert.assertEquals(CUSTOM_ACTION, customFilter.handledPolicy.getAction());
+        Assert.assertEquals("tenant", customFilter.handledPolicy.getParameters().get("headerName"));
+        Assert.assertEquals("Built-in policies after the custom policy should be applied",
+                "bar", requestContext.getAddHeaders().get("x-foo"));
+
+        requestContext = getRequestContext(HeaderValidationFilter.class.getName(), "region");
+        Assert.assertFalse("The request should be rejected if the custom filter rejects the policy",
+                mediationPolicyFilter.handleRequest(requestContext));
+        Assert.assertFalse("Policies after a rejected policy should not be applied",
+                requestContext.getAddHeaders().containsKey("x-foo"));
+    }
+
+    @Test
+    public void testCustomPolicyActionWithUnavailableFilterClass() {
+        MediationPolicyFilter mediationPolicyFilter = new MediationPolicyFilter(Collections.emptyMap());
+        RequestContext requestContext = getRequestContext("org.wso2.choreo.connect.custom.UnknownFilter", "tenant");
+
+        Assert.assertFalse(mediationPolicyFilter.handleRequest(requestContext));
+        Assert.assertEquals(APIConstants.StatusCodes.INTERNAL_SERVER_ERROR.getCode(),
+                requestContext.getProperties().get(APIConstants.MessageFormat.STATUS_CODE));
+    }
+
+    private static RequestContext getRequestContext(String filterClass, String headerName) {
+        Map<String, String> customParams = new HashMap<>();
+        customParams.put("headerName", headerName);
+        Map<String, String> setHeaderParams = new HashMap<>();
+        setHeaderParams.put("headerName", "x-foo");
+        setHeaderParams.put("headerValue", "bar");
+        ArrayList<Policy> requestPolicies = new ArrayList<>();
+        requestPolicies.add(new Policy(CUSTOM_ACTION, customParams, filterClass));
+        requestPolicies.add(new Policy("SET_HEADER", setHeaderParams));
+        PolicyConfig policyConfig = new PolicyConfig();
+        policyConfig.setRequest(requestPolicies);
+        ResourceConfig resourceConfig = new ResourceConfig();
+        resourceConfig.setPolicyConfig(policyConfig);
+        return new RequestContext.Builder("/petstore/pets").matchedAPI(API_CONFIG)
+                .matchedResourceConfig(resourceConfig).headers(new HashMap<>()).build();
+    }
+
+    /**
+     * Custom filter which accepts only the requests validating the tenant header.
+     */
+    private static class HeaderValidationFilter implements Filter {
+        private Policy handledPolicy;
+
+        @Override
+        public boolean handleRequest(RequestContext requestContext) {
+            return true;
+        }
+
+        @Override
+        public boolean handlePolicy(RequestContext requestContext, Policy policy) {
+            handledPolicy = policy;
+            return "tenant".equals(policy.getParameters().get("headerName"));
+        }
+    }
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2022, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+ *
+ * WSO2 Inc. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package org.wso2.choreo.connect.enforcer.util;
+
+import org.junit.Assert;
+import org.junit.Test;
+import org.wso2.choreo.connect.enforcer.commons.model.APIConfig;
+import org.wso2.choreo.connect.enforcer.commons.model.RequestContext;
+
+import java.util.Arrays;
+import java.util.Collections;
+import java.util.HashMap;
+import java.util.Map;
+
+public class FilterUtilsTest {
+    private static final APIConfig API_CONFIG = new APIConfig.Builder("Petstore").basePath("/petstore").build();
+
+    @Test
+    public void testAddRoutingClaimsToMetadata() {
+        // The content based routing rules of the route match the tier and the premium claims.
+        RequestContext requestContext = new RequestContext.Builder("/pets").matchedAPI(API_CONFIG)
+                .routingClaims(Arrays.asList("tier", "premium", "groups", "region")).build();
+        Map<String, Object> claims = new HashMap<>();
+        claims.put("tier", "gold");
+        claims.put("premium", true);
+        claims.put("groups", Collections.singletonList("admin"));
+        claims.put("sub", "admin");
+
+        FilterUtils.addRoutingClaimsToMetadata(requestContext, claims);
+        Map<String, String> metadata = requestContext.getMetadataMap();
+        // The keys should match the ext_authz dynamic metadata keys of the claim conditions of the router.
+        Assert.assertEquals("gold", metadata.get("x-wso2-claim-tier"));
+        Assert.assertEquals("true", metadata.get("x-wso2-claim-premium"));
+        Assert.assertFalse("Non scalar claims should not be added",
+                metadata.containsKey("x-wso2-claim-groups"));
+        Assert.assertFalse("Unavailable claims should not be added",
+                metadata.containsKey("x-wso2-claim-region"));
+        Assert.assertFalse("Claims not matched by the routing rules should not be added",
+                metadata.containsKey("x-wso2-claim-sub"));
+    }
+
+    @Test
+    public void testAddRoutingClaimsToMetadataWithoutRoutingClaims() {
+        RequestContext requestContext = new RequestContext.Builder("/pets").matchedAPI(API_CONFIG).build();
+        Map<String, Object> claims = new HashMap<>();
+        claims.put("tier", "gold");
+
+        FilterUtils.addRoutingClaimsToMetadata(requestContext, claims);
+        Assert.assertTrue(requestContext.getMetadataMap().isEmpty());
+    }
+}